@@ -1,17 +1,25 @@
 package api
 
 import (
+	"context"
 	"database/sql"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	db "github.com/valverdethiago/trading-api/db/sqlc"
 	"github.com/valverdethiago/trading-api/service"
+	"github.com/valverdethiago/trading-api/token"
 )
 
 const (
-	accountsPath     = "/accounts"
-	accountsPathByID = "/accounts/:id"
+	accountsPath          = "/accounts"
+	accountsPathByID      = "/accounts/:id"
+	accountRolePath       = "/accounts/:id/role"
+	accountApprovePath    = "/accounts/:id/approve"
+	accountDeactivatePath = "/accounts/:id/deactivate"
+	loginPath             = "/login"
 )
 
 type accountIDRequest struct {
@@ -21,10 +29,63 @@ type accountIDRequest struct {
 // CreateAccountRequest json request to create account
 type CreateAccountRequest struct {
 	Username string          `json:"username" binding:"required"`
+	Password string          `json:"password" binding:"required,min=6"`
 	Email    string          `json:"email" binding:"required"`
 	Address  *AddressRequest `json:"address" binding:"omitempty"`
 }
 
+// UpdateAccountRoleRequest json request to change the role of an account
+type UpdateAccountRoleRequest struct {
+	Role db.AccountRole `json:"role" binding:"required,oneof=STAFF USER"`
+}
+
+// LoginRequest json request to authenticate an account
+type LoginRequest struct {
+	Username string `json:"username" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+// LoginResponse json response with the access token of an authenticated account
+type LoginResponse struct {
+	AccessToken string          `json:"access_token"`
+	Account     AccountResponse `json:"account"`
+}
+
+// AccountResponse json response for account, without the credentials
+type AccountResponse struct {
+	AccountUuid uuid.UUID        `json:"account_uuid"`
+	Username    string           `json:"username"`
+	Email       string           `json:"email"`
+	Role        db.AccountRole   `json:"role"`
+	Status      db.AccountStatus `json:"status"`
+	CreatedDate sql.NullTime     `json:"created_date"`
+	UpdatedDate sql.NullTime     `json:"updated_date"`
+	CreatedBy   sql.NullString   `json:"created_by"`
+	UpdatedBy   sql.NullString   `json:"updated_by"`
+}
+
+func newAccountResponse(account db.Account) AccountResponse {
+	return AccountResponse{
+		AccountUuid: account.AccountUuid,
+		Username:    account.Username,
+		Email:       account.Email,
+		Role:        account.Role,
+		Status:      account.Status,
+		CreatedDate: account.CreatedDate,
+		UpdatedDate: account.UpdatedDate,
+		CreatedBy:   account.CreatedBy,
+		UpdatedBy:   account.UpdatedBy,
+	}
+}
+
+func newAccountListResponse(accounts []db.Account) []AccountResponse {
+	result := make([]AccountResponse, len(accounts))
+	for i, account := range accounts {
+		result[i] = newAccountResponse(account)
+	}
+	return result
+}
+
 // AddressRequest json request to create address
 type AddressRequest struct {
 	Name    string `json:"name" binding:"required"`
@@ -36,27 +97,38 @@ type AddressRequest struct {
 
 // AccountController controller for accounts object
 type AccountController struct {
-	service *service.AccountService
+	service             *service.AccountService
+	statusService       *service.AccountStatusService
+	tokenMaker          token.Maker
+	accessTokenDuration time.Duration
 }
 
 // NewAccountController builds a new instance of account controller
-func NewAccountController(queries db.Querier) *AccountController {
+func NewAccountController(store db.Store, policy *service.Policy, tokenMaker token.Maker,
+	accessTokenDuration time.Duration, broadcaster service.EventBroadcaster) *AccountController {
 	return &AccountController{
-		service: service.NewAccountService(queries),
+		service:             service.NewAccountService(store, policy),
+		statusService:       service.NewAccountStatusService(store, policy, broadcaster),
+		tokenMaker:          tokenMaker,
+		accessTokenDuration: accessTokenDuration,
 	}
 
 }
 
-func (controller *AccountController) setupRoutes(router *gin.Engine) {
-	router.POST(accountsPath, controller.createAccount)
-	router.GET(accountsPath, controller.listAccounts)
-	router.GET(accountsPathByID, controller.findAccountByID)
+func (controller *AccountController) setupRoutes(router *gin.Engine, authRoutes gin.IRoutes, idempotency gin.HandlerFunc) {
+	router.POST(accountsPath, idempotency, controller.createAccount)
+	router.POST(loginPath, controller.login)
+	authRoutes.GET(accountsPath, controller.listAccounts)
+	authRoutes.GET(accountsPathByID, controller.findAccountByID)
+	authRoutes.PUT(accountRolePath, controller.updateAccountRole)
+	authRoutes.POST(accountApprovePath, controller.approveAccount)
+	authRoutes.POST(accountDeactivatePath, controller.deactivateAccount)
 }
 
 func (controller *AccountController) createAccount(ctx *gin.Context) {
 	var req CreateAccountRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		invalidRequest(ctx, err)
 		return
 	}
 	var account = db.Account{
@@ -73,26 +145,51 @@ func (controller *AccountController) createAccount(ctx *gin.Context) {
 			Zipcode: req.Address.Zipcode,
 		}
 	}
-	dbAccount, _, err := controller.service.CreateAccount(account, address)
+	dbAccount, _, err := controller.service.CreateAccount(ctx.Request.Context(), account, req.Password, address)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		ctx.Error(err)
 		return
 	}
-	ctx.JSON(http.StatusCreated, dbAccount)
+	ctx.JSON(http.StatusCreated, newAccountResponse(dbAccount))
 	return
 }
 
+func (controller *AccountController) login(ctx *gin.Context) {
+	var req LoginRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		invalidRequest(ctx, err)
+		return
+	}
+	dbAccount, err := controller.service.Login(ctx.Request.Context(), req.Username, req.Password)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	accessToken, err := controller.tokenMaker.CreateToken(dbAccount.AccountUuid, dbAccount.Username,
+		string(dbAccount.Role), controller.accessTokenDuration)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, LoginResponse{
+		AccessToken: accessToken,
+		Account:     newAccountResponse(dbAccount),
+	})
+}
+
 func (controller *AccountController) listAccounts(ctx *gin.Context) {
-	accounts, err := controller.service.ListAccounts()
-	if err != nil && err != sql.ErrNoRows {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+	var req listAccountsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		invalidRequest(ctx, err)
 		return
 	}
-	if accounts == nil || len(accounts) == 0 {
-		ctx.JSON(http.StatusNoContent, gin.H{"message": "No accounts found"})
+	page, err := controller.service.SearchAccounts(ctx.Request.Context(), getActor(ctx), req.toFilter())
+	if err != nil {
+		ctx.Error(err)
 		return
 	}
-	ctx.JSON(http.StatusOK, accounts)
+	setAccountPageHeaders(ctx, page)
+	ctx.JSON(http.StatusOK, newAccountListResponse(page.Accounts))
 }
 
 type getAccountRequest struct {
@@ -102,23 +199,69 @@ type getAccountRequest struct {
 func (controller *AccountController) findAccountByID(ctx *gin.Context) {
 	var req getAccountRequest
 	if err := ctx.ShouldBindUri(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		invalidRequest(ctx, err)
 		return
 	}
 	uuid, err := parseUUID(req.ID)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		invalidRequest(ctx, err)
 		return
 	}
-	account, err := controller.service.GetAccountByID(uuid)
+	account, err := controller.service.GetAccountByID(ctx.Request.Context(), uuid)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, gin.H{"message": "No account found for this id"})
-		} else {
-			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
-		}
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, newAccountResponse(account))
+
+}
+
+func (controller *AccountController) updateAccountRole(ctx *gin.Context) {
+	idReq, err := getAccountIDRequest(ctx)
+	if err != nil {
+		return
+	}
+	uuid, err := parseUUID(idReq.ID)
+	if err != nil {
+		invalidRequest(ctx, err)
+		return
+	}
+	var req UpdateAccountRoleRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		invalidRequest(ctx, err)
+		return
+	}
+	account, err := controller.service.UpdateAccountRole(ctx.Request.Context(), getActor(ctx), uuid, req.Role)
+	if err != nil {
+		ctx.Error(err)
 		return
 	}
-	ctx.JSON(http.StatusOK, account)
+	ctx.JSON(http.StatusOK, newAccountResponse(account))
+}
+
+func (controller *AccountController) approveAccount(ctx *gin.Context) {
+	controller.changeAccountStatus(ctx, controller.statusService.ApproveAccount)
+}
 
+func (controller *AccountController) deactivateAccount(ctx *gin.Context) {
+	controller.changeAccountStatus(ctx, controller.statusService.DeactivateAccount)
+}
+
+func (controller *AccountController) changeAccountStatus(ctx *gin.Context,
+	change func(context.Context, service.Actor, uuid.UUID) (db.Account, error)) {
+	idReq, err := getAccountIDRequest(ctx)
+	if err != nil {
+		return
+	}
+	uuid, err := parseUUID(idReq.ID)
+	if err != nil {
+		invalidRequest(ctx, err)
+		return
+	}
+	account, err := change(ctx.Request.Context(), getActor(ctx), uuid)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, newAccountResponse(account))
 }
@@ -2,27 +2,61 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"database/sql"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 	mockdb "github.com/valverdethiago/trading-api/db/mock"
 	db "github.com/valverdethiago/trading-api/db/sqlc"
+	"github.com/valverdethiago/trading-api/service"
 	"github.com/valverdethiago/trading-api/util"
 )
 
+type eqCreateAccountParamsMatcher struct {
+	arg      db.CreateAccountParams
+	password string
+}
+
+func (e eqCreateAccountParamsMatcher) Matches(x interface{}) bool {
+	arg, ok := x.(db.CreateAccountParams)
+	if !ok {
+		return false
+	}
+
+	err := util.CheckPassword(e.password, arg.HashedPassword)
+	if err != nil {
+		return false
+	}
+
+	e.arg.HashedPassword = arg.HashedPassword
+	return reflect.DeepEqual(e.arg, arg)
+}
+
+func (e eqCreateAccountParamsMatcher) String() string {
+	return fmt.Sprintf("matches arg %v and password %v", e.arg, e.password)
+}
+
+func eqCreateAccountParams(arg db.CreateAccountParams, password string) gomock.Matcher {
+	return eqCreateAccountParamsMatcher{arg, password}
+}
+
 func TestCreateAccount(t *testing.T) {
+	password := util.RandomString(8)
 
 	testCases := []struct {
 		name          string
 		buildRequest  func() CreateAccountRequest
-		buildStubs    func(querier *mockdb.MockQuerier)
+		buildStubs    func(store *mockdb.MockStore)
 		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
 	}{
 		{
@@ -30,18 +64,20 @@ func TestCreateAccount(t *testing.T) {
 			buildRequest: func() CreateAccountRequest {
 				return CreateAccountRequest{
 					Username: account.Username,
+					Password: password,
 					Email:    account.Email,
 				}
 			},
-			buildStubs: func(querier *mockdb.MockQuerier) {
-				querier.EXPECT().
-					CreateAccount(gomock.Any(), gomock.Eq(db.CreateAccountParams{
-						Username: account.Username,
-						Email:    account.Email,
-					})).
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateAccount(gomock.Any(), eqCreateAccountParams(db.CreateAccountParams{
+						Username:  account.Username,
+						Email:     account.Email,
+						CreatedBy: sql.NullString{String: service.AnonymousActor, Valid: true},
+					}, password)).
 					Times(1).
 					Return(expectedAccount, nil)
-				querier.EXPECT().
+				store.EXPECT().
 					GetAccountByUsername(gomock.Any(), gomock.Eq(account.Username)).
 					Times(1).
 					Return(db.Account{}, sql.ErrNoRows)
@@ -55,15 +91,16 @@ func TestCreateAccount(t *testing.T) {
 			buildRequest: func() CreateAccountRequest {
 				return CreateAccountRequest{
 					Username: account.Username,
+					Password: password,
 					Email:    account.Email,
 				}
 			},
-			buildStubs: func(querier *mockdb.MockQuerier) {
-				querier.EXPECT().
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
 					GetAccountByUsername(gomock.Any(), account.Username).
 					Times(1).
 					Return(db.Account{}, sql.ErrNoRows)
-				querier.EXPECT().
+				store.EXPECT().
 					CreateAccount(gomock.Any(), gomock.Any()).
 					Times(1).
 					Return(db.Account{}, sql.ErrConnDone)
@@ -71,14 +108,57 @@ func TestCreateAccount(t *testing.T) {
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusInternalServerError, recorder.Code)
 			},
+		}, {
+			name: "Username Lookup Failed",
+			buildRequest: func() CreateAccountRequest {
+				return CreateAccountRequest{
+					Username: account.Username,
+					Password: password,
+					Email:    account.Email,
+				}
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccountByUsername(gomock.Any(), account.Username).
+					Times(1).
+					Return(db.Account{}, sql.ErrConnDone)
+				store.EXPECT().
+					CreateAccount(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				requireProblem(t, recorder, http.StatusInternalServerError, codeInternalError)
+			},
+		}, {
+			name: "Username Taken",
+			buildRequest: func() CreateAccountRequest {
+				return CreateAccountRequest{
+					Username: account.Username,
+					Password: password,
+					Email:    account.Email,
+				}
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccountByUsername(gomock.Any(), account.Username).
+					Times(1).
+					Return(account, nil)
+				store.EXPECT().
+					CreateAccount(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				requireProblem(t, recorder, http.StatusConflict, service.ErrUsernameTaken.Code)
+			},
 		}, {
 			name: "Error Without Address And Username",
 			buildRequest: func() CreateAccountRequest {
 				return CreateAccountRequest{
-					Email: account.Email,
+					Password: password,
+					Email:    account.Email,
 				}
 			},
-			buildStubs: func(querier *mockdb.MockQuerier) {},
+			buildStubs: func(store *mockdb.MockStore) {},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusBadRequest, recorder.Code)
 			},
@@ -87,9 +167,22 @@ func TestCreateAccount(t *testing.T) {
 			buildRequest: func() CreateAccountRequest {
 				return CreateAccountRequest{
 					Username: account.Username,
+					Password: password,
+				}
+			},
+			buildStubs: func(store *mockdb.MockStore) {},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		}, {
+			name: "Error Without Password",
+			buildRequest: func() CreateAccountRequest {
+				return CreateAccountRequest{
+					Username: account.Username,
+					Email:    account.Email,
 				}
 			},
-			buildStubs: func(querier *mockdb.MockQuerier) {},
+			buildStubs: func(store *mockdb.MockStore) {},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusBadRequest, recorder.Code)
 			},
@@ -105,23 +198,25 @@ func TestCreateAccount(t *testing.T) {
 				}
 				return CreateAccountRequest{
 					Username: account.Username,
+					Password: password,
 					Email:    account.Email,
 					Address:  &addressReq,
 				}
 			},
-			buildStubs: func(querier *mockdb.MockQuerier) {
-				querier.EXPECT().
-					CreateAccount(gomock.Any(), gomock.Eq(db.CreateAccountParams{
-						Username: account.Username,
-						Email:    account.Email,
-					})).
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateAccount(gomock.Any(), eqCreateAccountParams(db.CreateAccountParams{
+						Username:  account.Username,
+						Email:     account.Email,
+						CreatedBy: sql.NullString{String: service.AnonymousActor, Valid: true},
+					}, password)).
 					Times(1).
 					Return(expectedAccount, nil)
-				querier.EXPECT().
+				store.EXPECT().
 					GetAccountByUsername(gomock.Any(), gomock.Eq(account.Username)).
 					Times(1).
 					Return(db.Account{}, sql.ErrNoRows)
-				querier.EXPECT().
+				store.EXPECT().
 					CreateAddress(gomock.Any(), gomock.Any()).
 					Times(1).
 					Return(expectedAddress, nil)
@@ -130,6 +225,40 @@ func TestCreateAccount(t *testing.T) {
 				require.Equal(t, http.StatusCreated, recorder.Code)
 				requireBodyMatchAccount(t, recorder.Body, expectedAccount)
 			},
+		}, {
+			name: "Address Failure Rolls Back Account",
+			buildRequest: func() CreateAccountRequest {
+				addressReq := AddressRequest{
+					Name:    expectedAddress.Name,
+					Street:  expectedAddress.Street,
+					City:    expectedAddress.City,
+					State:   string(expectedAddress.State),
+					Zipcode: expectedAddress.Zipcode,
+				}
+				return CreateAccountRequest{
+					Username: account.Username,
+					Password: password,
+					Email:    account.Email,
+					Address:  &addressReq,
+				}
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccountByUsername(gomock.Any(), gomock.Eq(account.Username)).
+					Times(1).
+					Return(db.Account{}, sql.ErrNoRows)
+				store.EXPECT().
+					CreateAccount(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(expectedAccount, nil)
+				store.EXPECT().
+					CreateAddress(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Address{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
 		}, {
 			name: "Error With Invalid Address",
 			buildRequest: func() CreateAccountRequest {
@@ -141,11 +270,12 @@ func TestCreateAccount(t *testing.T) {
 				}
 				return CreateAccountRequest{
 					Username: account.Username,
+					Password: password,
 					Email:    account.Email,
 					Address:  &addressReq,
 				}
 			},
-			buildStubs: func(querier *mockdb.MockQuerier) {},
+			buildStubs: func(store *mockdb.MockStore) {},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusBadRequest, recorder.Code)
 			},
@@ -158,12 +288,13 @@ func TestCreateAccount(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			defer ctrl.Finish()
 
-			querier := mockdb.NewMockQuerier(ctrl)
+			store := newMockStore(ctrl)
 			// build stubs
-			testCase.buildStubs(querier)
+			testCase.buildStubs(store)
+			expectEvents(store)
 
 			// start http server and send the request
-			server := NewServer(querier)
+			server := newTestServer(t, store)
 			recorder := httptest.NewRecorder()
 			request, err := http.NewRequest(http.MethodPost, "/accounts",
 				sendObjectAsRequestBody(t, testCase.buildRequest()))
@@ -179,43 +310,119 @@ func TestCreateAccount(t *testing.T) {
 func TestListAccounts(t *testing.T) {
 	testCases := []struct {
 		name          string
-		buildStubs    func(querier *mockdb.MockQuerier)
+		actor         db.Account
+		query         string
+		buildStubs    func(store *mockdb.MockStore)
 		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
 	}{
 		{
-			name: "Empty List",
-			buildStubs: func(querier *mockdb.MockQuerier) {
-				querier.EXPECT().
-					ListAccounts(gomock.Any()).
+			name:  "Empty List",
+			actor: staffAccount,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CountAccounts(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(int64(0), nil)
+				store.EXPECT().
+					SearchAccounts(gomock.Any(), gomock.Any()).
 					Times(1).
-					Return(nil, sql.ErrNoRows)
+					Return(nil, nil)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
-				require.Equal(t, http.StatusNoContent, recorder.Code)
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, "0", recorder.Header().Get(totalCountHeaderKey))
+				require.JSONEq(t, "[]", recorder.Body.String())
 			},
 		}, {
-			name: "OK",
-			buildStubs: func(querier *mockdb.MockQuerier) {
-				querier.EXPECT().
-					ListAccounts(gomock.Any()).
+			name:  "OK",
+			actor: staffAccount,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CountAccounts(gomock.Any(), gomock.Eq(db.CountAccountsParams{})).
+					Times(1).
+					Return(int64(len(accounts)), nil)
+				store.EXPECT().
+					SearchAccounts(gomock.Any(), gomock.Eq(db.SearchAccountsParams{
+						PageSize: service.DefaultAccountPageSize,
+					})).
 					Times(1).
 					Return(accounts, nil)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, fmt.Sprint(len(accounts)), recorder.Header().Get(totalCountHeaderKey))
+				require.Empty(t, recorder.Header().Get(linkHeaderKey))
 				requireBodyMatchAccountList(t, recorder.Body, accounts)
 			},
 		}, {
-			name: "Internal Server Error",
-			buildStubs: func(querier *mockdb.MockQuerier) {
-				querier.EXPECT().
-					ListAccounts(gomock.Any()).
+			name:  "Filtered Page",
+			actor: staffAccount,
+			query: "username=jo_&email=john@example.com&state=ca&q=john&created_from=2021-01-01T00:00:00Z&page=2&page_size=3",
+			buildStubs: func(store *mockdb.MockStore) {
+				createdFrom := sql.NullTime{Time: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC), Valid: true}
+				store.EXPECT().
+					CountAccounts(gomock.Any(), gomock.Eq(db.CountAccountsParams{
+						UsernamePrefix: `jo\_`,
+						Email:          "john@example.com",
+						CreatedFrom:    createdFrom,
+						State:          string(db.StateCA),
+						Query:          "john",
+					})).
 					Times(1).
-					Return([]db.Account{}, sql.ErrConnDone)
+					Return(int64(10), nil)
+				store.EXPECT().
+					SearchAccounts(gomock.Any(), gomock.Eq(db.SearchAccountsParams{
+						UsernamePrefix: `jo\_`,
+						Email:          "john@example.com",
+						CreatedFrom:    createdFrom,
+						State:          string(db.StateCA),
+						Query:          "john",
+						PageSize:       3,
+						PageOffset:     3,
+					})).
+					Times(1).
+					Return(accounts[:3], nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, "10", recorder.Header().Get(totalCountHeaderKey))
+				link := recorder.Header().Get(linkHeaderKey)
+				require.Contains(t, link, "page=3")
+				require.Contains(t, link, "page_size=3")
+				require.Contains(t, link, `rel="next"`)
+				requireBodyMatchAccountList(t, recorder.Body, accounts[:3])
+			},
+		}, {
+			name:       "Invalid Page Size",
+			actor:      staffAccount,
+			query:      "page_size=1000",
+			buildStubs: func(store *mockdb.MockStore) {},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		}, {
+			name:  "Internal Server Error",
+			actor: staffAccount,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CountAccounts(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(int64(0), sql.ErrConnDone)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusInternalServerError, recorder.Code)
 			},
+		}, {
+			name:  "Forbidden For Users",
+			actor: account,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					SearchAccounts(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
 		},
 	}
 
@@ -225,15 +432,17 @@ func TestListAccounts(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			defer ctrl.Finish()
 
-			querier := mockdb.NewMockQuerier(ctrl)
+			store := newMockStore(ctrl)
 			// build stubs
-			testCase.buildStubs(querier)
+			testCase.buildStubs(store)
+			expectEvents(store)
 
 			// start http server and send the request
-			server := NewServer(querier)
+			server := newTestServer(t, store)
 			recorder := httptest.NewRecorder()
-			request, err := http.NewRequest(http.MethodGet, "/accounts", nil)
+			request, err := http.NewRequest(http.MethodGet, "/accounts?"+testCase.query, nil)
 			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, testCase.actor)
 
 			server.router.ServeHTTP(recorder, request)
 			//check response
@@ -249,14 +458,14 @@ func TestGetAccount(t *testing.T) {
 	testCases := []struct {
 		name          string
 		id            string
-		buildStubs    func(querier *mockdb.MockQuerier)
+		buildStubs    func(store *mockdb.MockStore)
 		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
 	}{
 		{
 			name: "OK",
 			id:   account.AccountUuid.String(),
-			buildStubs: func(querier *mockdb.MockQuerier) {
-				querier.EXPECT().
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
 					GetAccountById(gomock.Any(), gomock.Eq(account.AccountUuid)).
 					Times(1).
 					Return(account, nil)
@@ -268,8 +477,8 @@ func TestGetAccount(t *testing.T) {
 		}, {
 			name: "NOT FOUND",
 			id:   account.AccountUuid.String(),
-			buildStubs: func(querier *mockdb.MockQuerier) {
-				querier.EXPECT().
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
 					GetAccountById(gomock.Any(), gomock.Eq(account.AccountUuid)).
 					Times(1).
 					Return(db.Account{}, sql.ErrNoRows)
@@ -280,8 +489,8 @@ func TestGetAccount(t *testing.T) {
 		}, {
 			name: "DATABASE CONNECTION LOST",
 			id:   account.AccountUuid.String(),
-			buildStubs: func(querier *mockdb.MockQuerier) {
-				querier.EXPECT().
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
 					GetAccountById(gomock.Any(), gomock.Eq(account.AccountUuid)).
 					Times(1).
 					Return(db.Account{}, sql.ErrConnDone)
@@ -292,8 +501,8 @@ func TestGetAccount(t *testing.T) {
 		}, {
 			name: "INVALID ID",
 			id:   util.RandomNumericString(4),
-			buildStubs: func(querier *mockdb.MockQuerier) {
-				querier.EXPECT().
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
 					GetAccountById(gomock.Any(), gomock.Any()).
 					Times(0)
 			},
@@ -309,46 +518,591 @@ func TestGetAccount(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			defer ctrl.Finish()
 
-			querier := mockdb.NewMockQuerier(ctrl)
+			store := newMockStore(ctrl)
 			// build stubs
-			testCase.buildStubs(querier)
+			testCase.buildStubs(store)
+			expectEvents(store)
 
 			// start http server and send the request
-			server := NewServer(querier)
+			server := newTestServer(t, store)
 			recorder := httptest.NewRecorder()
 			urlToTest := fmt.Sprintf("/accounts/%s", testCase.id)
 			request, err := http.NewRequest(http.MethodGet, urlToTest, nil)
 			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, account)
+
+			server.router.ServeHTTP(recorder, request)
+			//check response
+			testCase.checkResponse(t, recorder)
+		})
+
+	}
+
+}
+
+func TestUpdateAccountRole(t *testing.T) {
+	account := createRandomAccount()
+	promotedAccount := account
+	promotedAccount.Role = db.AccountRoleSTAFF
+
+	testCases := []struct {
+		name          string
+		id            string
+		actor         db.Account
+		request       UpdateAccountRoleRequest
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:    "OK",
+			id:      account.AccountUuid.String(),
+			actor:   staffAccount,
+			request: UpdateAccountRoleRequest{Role: db.AccountRoleSTAFF},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccountById(gomock.Any(), gomock.Eq(account.AccountUuid)).
+					Times(1).
+					Return(account, nil)
+				store.EXPECT().
+					UpdateAccountRole(gomock.Any(), gomock.Eq(db.UpdateAccountRoleParams{
+						Role:        db.AccountRoleSTAFF,
+						UpdatedBy:   actedBy(staffAccount),
+						AccountUuid: account.AccountUuid,
+					})).
+					Times(1).
+					Return(promotedAccount, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchAccount(t, recorder.Body, promotedAccount)
+			},
+		}, {
+			name:    "Forbidden For Users",
+			id:      account.AccountUuid.String(),
+			actor:   account,
+			request: UpdateAccountRoleRequest{Role: db.AccountRoleSTAFF},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpdateAccountRole(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		}, {
+			name:    "Invalid Role",
+			id:      account.AccountUuid.String(),
+			actor:   staffAccount,
+			request: UpdateAccountRoleRequest{Role: db.AccountRole("ADMIN")},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpdateAccountRole(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		}, {
+			name:    "NOT FOUND",
+			id:      account.AccountUuid.String(),
+			actor:   staffAccount,
+			request: UpdateAccountRoleRequest{Role: db.AccountRoleSTAFF},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccountById(gomock.Any(), gomock.Eq(account.AccountUuid)).
+					Times(1).
+					Return(db.Account{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := newMockStore(ctrl)
+			// build stubs
+			testCase.buildStubs(store)
+			expectEvents(store)
+
+			// start http server and send the request
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+			urlToTest := fmt.Sprintf("/accounts/%s/role", testCase.id)
+			request, err := http.NewRequest(http.MethodPut, urlToTest,
+				sendObjectAsRequestBody(t, testCase.request))
+			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, testCase.actor)
+
+			server.router.ServeHTTP(recorder, request)
+			//check response
+			testCase.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestApproveAccount(t *testing.T) {
+	pendingAccount := createRandomAccount()
+	pendingAccount.Status = db.AccountStatusPENDING
+	approvedAccount := pendingAccount
+	approvedAccount.Status = db.AccountStatusAPPROVED
+
+	testCases := []struct {
+		name          string
+		actor         db.Account
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "OK",
+			actor: staffAccount,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccountById(gomock.Any(), gomock.Eq(pendingAccount.AccountUuid)).
+					Times(1).
+					Return(pendingAccount, nil)
+				store.EXPECT().
+					GetAddressByAccount(gomock.Any(), gomock.Eq(pendingAccount.AccountUuid)).
+					Times(1).
+					Return(expectedAddress, nil)
+				store.EXPECT().
+					UpdateAccountStatus(gomock.Any(), gomock.Eq(db.UpdateAccountStatusParams{
+						Status:      db.AccountStatusAPPROVED,
+						UpdatedBy:   actedBy(staffAccount),
+						AccountUuid: pendingAccount.AccountUuid,
+					})).
+					Times(1).
+					Return(approvedAccount, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchAccount(t, recorder.Body, approvedAccount)
+			},
+		}, {
+			name:  "Without Address",
+			actor: staffAccount,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccountById(gomock.Any(), gomock.Eq(pendingAccount.AccountUuid)).
+					Times(1).
+					Return(pendingAccount, nil)
+				store.EXPECT().
+					GetAddressByAccount(gomock.Any(), gomock.Eq(pendingAccount.AccountUuid)).
+					Times(1).
+					Return(db.Address{}, sql.ErrNoRows)
+				store.EXPECT().
+					UpdateAccountStatus(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		}, {
+			name:  "Already Approved",
+			actor: staffAccount,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccountById(gomock.Any(), gomock.Eq(pendingAccount.AccountUuid)).
+					Times(1).
+					Return(approvedAccount, nil)
+				store.EXPECT().
+					UpdateAccountStatus(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		}, {
+			name:  "Forbidden For Users",
+			actor: pendingAccount,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpdateAccountStatus(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := newMockStore(ctrl)
+			// build stubs
+			testCase.buildStubs(store)
+			expectEvents(store)
+
+			// start http server and send the request
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+			urlToTest := fmt.Sprintf("/accounts/%s/approve", pendingAccount.AccountUuid)
+			request, err := http.NewRequest(http.MethodPost, urlToTest, nil)
+			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, testCase.actor)
 
 			server.router.ServeHTTP(recorder, request)
 			//check response
 			testCase.checkResponse(t, recorder)
 		})
+	}
+}
+
+func TestDeactivateAccount(t *testing.T) {
+	approvedAccount := createRandomAccount()
+	inactiveAccount := approvedAccount
+	inactiveAccount.Status = db.AccountStatusINACTIVE
+
+	testCases := []struct {
+		name          string
+		actor         db.Account
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "OK",
+			actor: staffAccount,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccountById(gomock.Any(), gomock.Eq(approvedAccount.AccountUuid)).
+					Times(1).
+					Return(approvedAccount, nil)
+				store.EXPECT().
+					ListOpenTradesByAccount(gomock.Any(), gomock.Eq(approvedAccount.AccountUuid)).
+					Times(1).
+					Return([]db.Trade{}, nil)
+				store.EXPECT().
+					UpdateAccountStatus(gomock.Any(), gomock.Eq(db.UpdateAccountStatusParams{
+						Status:      db.AccountStatusINACTIVE,
+						UpdatedBy:   actedBy(staffAccount),
+						AccountUuid: approvedAccount.AccountUuid,
+					})).
+					Times(1).
+					Return(inactiveAccount, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchAccount(t, recorder.Body, inactiveAccount)
+			},
+		}, {
+			name:  "Already Inactive",
+			actor: staffAccount,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccountById(gomock.Any(), gomock.Eq(approvedAccount.AccountUuid)).
+					Times(1).
+					Return(inactiveAccount, nil)
+				store.EXPECT().
+					UpdateAccountStatus(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		}, {
+			name:  "NOT FOUND",
+			actor: staffAccount,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccountById(gomock.Any(), gomock.Eq(approvedAccount.AccountUuid)).
+					Times(1).
+					Return(db.Account{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		}, {
+			name:  "Forbidden For Users",
+			actor: approvedAccount,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpdateAccountStatus(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
 
+			store := newMockStore(ctrl)
+			// build stubs
+			testCase.buildStubs(store)
+			expectEvents(store)
+
+			// start http server and send the request
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+			urlToTest := fmt.Sprintf("/accounts/%s/deactivate", approvedAccount.AccountUuid)
+			request, err := http.NewRequest(http.MethodPost, urlToTest, nil)
+			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, testCase.actor)
+
+			server.router.ServeHTTP(recorder, request)
+			//check response
+			testCase.checkResponse(t, recorder)
+		})
 	}
+}
 
+func TestDeactivateAccountCancelsOpenTrades(t *testing.T) {
+	approvedAccount := createRandomAccount()
+	inactiveAccount := approvedAccount
+	inactiveAccount.Status = db.AccountStatusINACTIVE
+	submittedTrade := createRandomTrade()
+	submittedTrade.AccountUuid = approvedAccount.AccountUuid
+	partiallyFilledTrade := createRandomTrade()
+	partiallyFilledTrade.AccountUuid = approvedAccount.AccountUuid
+	partiallyFilledTrade.Status = db.TradeStatusPARTIALLY_FILLED
+	// filled between the listing and the lock
+	filledTrade := createRandomTrade()
+	filledTrade.AccountUuid = approvedAccount.AccountUuid
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := newMockStoreWithoutAudit(ctrl)
+	store.EXPECT().
+		GetAccountById(gomock.Any(), gomock.Eq(approvedAccount.AccountUuid)).
+		Times(1).
+		Return(approvedAccount, nil)
+	store.EXPECT().
+		ListOpenTradesByAccount(gomock.Any(), gomock.Eq(approvedAccount.AccountUuid)).
+		Times(1).
+		Return([]db.Trade{submittedTrade, partiallyFilledTrade, filledTrade}, nil)
+	store.EXPECT().
+		GetTradeByIdForUpdate(gomock.Any(), gomock.Any()).
+		Times(3).
+		DoAndReturn(func(ctx context.Context, tradeUUID uuid.UUID) (db.Trade, error) {
+			switch tradeUUID {
+			case submittedTrade.TradeUuid:
+				return submittedTrade, nil
+			case partiallyFilledTrade.TradeUuid:
+				return partiallyFilledTrade, nil
+			}
+			filled := filledTrade
+			filled.Status = db.TradeStatusCOMPLETED
+			return filled, nil
+		})
+	var cancelled []uuid.UUID
+	store.EXPECT().
+		UpdateTradeStatus(gomock.Any(), gomock.Any()).
+		Times(2).
+		DoAndReturn(func(ctx context.Context, arg db.UpdateTradeStatusParams) (db.Trade, error) {
+			require.Equal(t, db.TradeStatusCANCELLED, arg.Status)
+			require.Equal(t, actedBy(staffAccount), arg.UpdatedBy)
+			cancelled = append(cancelled, arg.TradeUuid)
+			trade := submittedTrade
+			if arg.TradeUuid == partiallyFilledTrade.TradeUuid {
+				trade = partiallyFilledTrade
+			}
+			trade.Status = arg.Status
+			return trade, nil
+		})
+	store.EXPECT().
+		UpdateAccountStatus(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(inactiveAccount, nil)
+	var audited []db.CreateAuditLogParams
+	store.EXPECT().
+		CreateAuditLog(gomock.Any(), gomock.Any()).
+		Times(3).
+		DoAndReturn(func(ctx context.Context, arg db.CreateAuditLogParams) (db.AuditLog, error) {
+			audited = append(audited, arg)
+			return db.AuditLog{}, nil
+		})
+	expectEvents(store)
+
+	server := newTestServer(t, store)
+	subscription := server.hub.Subscribe(approvedAccount.AccountUuid)
+	defer subscription.Close()
+	recorder := httptest.NewRecorder()
+	urlToTest := fmt.Sprintf("/accounts/%s/deactivate", approvedAccount.AccountUuid)
+	request, err := http.NewRequest(http.MethodPost, urlToTest, nil)
+	require.NoError(t, err)
+	addAuthorization(t, request, server.tokenMaker, staffAccount)
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, []uuid.UUID{submittedTrade.TradeUuid, partiallyFilledTrade.TradeUuid}, cancelled)
+	for i, tradeUUID := range cancelled {
+		require.Equal(t, string(service.AuditUpdate), audited[i].Action)
+		require.Equal(t, service.EntityTrade, audited[i].EntityType)
+		require.Equal(t, tradeUUID.String(), audited[i].EntityID)
+		require.Contains(t, string(audited[i].After), string(db.TradeStatusCANCELLED))
+	}
+	require.Equal(t, service.EntityAccount, audited[2].EntityType)
+	var eventTypes []service.EventType
+	for len(subscription.Messages()) > 0 {
+		message := <-subscription.Messages()
+		var event service.Event
+		require.NoError(t, json.Unmarshal(message.Event, &event))
+		eventTypes = append(eventTypes, event.Type)
+	}
+	require.Equal(t, []service.EventType{service.EventTradeCancelled, service.EventTradeCancelled,
+		service.EventAccountUpdated}, eventTypes)
+}
+
+func TestLogin(t *testing.T) {
+	password := util.RandomString(8)
+	hashedPassword, err := util.HashPassword(password)
+	require.NoError(t, err)
+	account := createRandomAccount()
+	account.HashedPassword = hashedPassword
+
+	testCases := []struct {
+		name          string
+		buildRequest  func() LoginRequest
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildRequest: func() LoginRequest {
+				return LoginRequest{
+					Username: account.Username,
+					Password: password,
+				}
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccountByUsername(gomock.Any(), gomock.Eq(account.Username)).
+					Times(1).
+					Return(account, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				var response LoginResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &response)
+				require.NoError(t, err)
+				require.NotEmpty(t, response.AccessToken)
+				require.Equal(t, newAccountResponse(account), response.Account)
+			},
+		}, {
+			name: "Wrong Password",
+			buildRequest: func() LoginRequest {
+				return LoginRequest{
+					Username: account.Username,
+					Password: util.RandomString(9),
+				}
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccountByUsername(gomock.Any(), gomock.Eq(account.Username)).
+					Times(1).
+					Return(account, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		}, {
+			name: "Unknown Username",
+			buildRequest: func() LoginRequest {
+				return LoginRequest{
+					Username: account.Username,
+					Password: password,
+				}
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccountByUsername(gomock.Any(), gomock.Eq(account.Username)).
+					Times(1).
+					Return(db.Account{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		}, {
+			name: "Internal Server Error",
+			buildRequest: func() LoginRequest {
+				return LoginRequest{
+					Username: account.Username,
+					Password: password,
+				}
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccountByUsername(gomock.Any(), gomock.Eq(account.Username)).
+					Times(1).
+					Return(db.Account{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		}, {
+			name: "Missing Password",
+			buildRequest: func() LoginRequest {
+				return LoginRequest{
+					Username: account.Username,
+				}
+			},
+			buildStubs: func(store *mockdb.MockStore) {},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := newMockStore(ctrl)
+			// build stubs
+			testCase.buildStubs(store)
+			expectEvents(store)
+
+			// start http server and send the request
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodPost, "/login",
+				sendObjectAsRequestBody(t, testCase.buildRequest()))
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			//check response
+			testCase.checkResponse(t, recorder)
+		})
+	}
 }
 
 func requireBodyMatchAccount(t *testing.T, body *bytes.Buffer, account db.Account) {
 	data, err := ioutil.ReadAll(body)
 	require.NoError(t, err)
 
-	var bodyAccount db.Account
+	var bodyAccount AccountResponse
 	err = json.Unmarshal(data, &bodyAccount)
 	require.NoError(t, err)
-	require.Equal(t, account, bodyAccount)
+	require.Equal(t, newAccountResponse(account), bodyAccount)
 }
 
 func requireBodyMatchAccountList(t *testing.T, body *bytes.Buffer, accounts []db.Account) {
 	data, err := ioutil.ReadAll(body)
 	require.NoError(t, err)
 
-	var bodyAccounts []db.Account
+	var bodyAccounts []AccountResponse
 	err = json.Unmarshal(data, &bodyAccounts)
 	require.NoError(t, err)
 	for _, account := range accounts {
-		dbAccount := findAccountInList(bodyAccounts, account)
-		require.Equal(t, dbAccount, account)
+		bodyAccount := findAccountInList(bodyAccounts, account)
+		require.Equal(t, newAccountResponse(account), bodyAccount)
 	}
 }
 
@@ -358,8 +1112,8 @@ func sendObjectAsRequestBody(t *testing.T, obj interface{}) *bytes.Buffer {
 	return bytes.NewBuffer(b)
 }
 
-func findAccountInList(accounts []db.Account, account db.Account) db.Account {
-	var result db.Account
+func findAccountInList(accounts []AccountResponse, account db.Account) AccountResponse {
+	var result AccountResponse
 	for _, element := range accounts {
 		if element.AccountUuid == account.AccountUuid {
 			result = element
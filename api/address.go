@@ -1,7 +1,6 @@
 package api
 
 import (
-	"database/sql"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -19,31 +18,31 @@ type AddressController struct {
 }
 
 // NewAddressController builds a new instance of account controller
-func NewAddressController(queries db.Querier) *AddressController {
-	accountService := service.NewAccountService(queries)
+func NewAddressController(store db.Store, policy *service.Policy) *AddressController {
+	accountService := service.NewAccountService(store, policy)
 	return &AddressController{
 		service: service.NewAddressService(
-			queries, accountService,
+			store, accountService,
 		),
 	}
 
 }
 
-func (controller *AddressController) setupRoutes(router *gin.Engine) {
-	router.POST(addressPath, controller.updateAddressForAccount)
-	router.GET(addressPath, controller.getAddressByAccountID)
-	router.PUT(addressPath, controller.createAddressForAccount)
+func (controller *AddressController) setupRoutes(router *gin.Engine, authRoutes gin.IRoutes) {
+	authRoutes.POST(addressPath, controller.updateAddressForAccount)
+	authRoutes.GET(addressPath, controller.getAddressByAccountID)
+	authRoutes.PUT(addressPath, controller.createAddressForAccount)
 }
 
 func (controller *AddressController) createAddressForAccount(ctx *gin.Context) {
 	idReq, err := getAccountIDRequest(ctx)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		invalidRequest(ctx, err)
 		return
 	}
 	uuid, err := parseUUID(idReq.ID)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		invalidRequest(ctx, err)
 		return
 	}
 	req, err := getAddressRequest(ctx)
@@ -57,13 +56,9 @@ func (controller *AddressController) createAddressForAccount(ctx *gin.Context) {
 		State:   db.State(req.State),
 		Zipcode: req.Zipcode,
 	}
-	dbAddress, err := controller.service.CreateAddressForAccount(uuid, address)
+	dbAddress, err := controller.service.CreateAddressForAccount(ctx.Request.Context(), uuid, address)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
-		} else {
-			ctx.JSON(http.StatusConflict, errorResponse(err))
-		}
+		ctx.Error(err)
 		return
 	}
 	ctx.JSON(http.StatusCreated, dbAddress)
@@ -77,7 +72,7 @@ func (controller *AddressController) updateAddressForAccount(ctx *gin.Context) {
 	}
 	uuid, err := parseUUID(idReq.ID)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		invalidRequest(ctx, err)
 		return
 	}
 	req, err := getAddressRequest(ctx)
@@ -91,15 +86,10 @@ func (controller *AddressController) updateAddressForAccount(ctx *gin.Context) {
 		State:   db.State(req.State),
 		Zipcode: req.Zipcode,
 	}
-	dbAddress, err := controller.service.UpdateAddressForAccount(uuid, address)
+	dbAddress, err := controller.service.UpdateAddressForAccount(ctx.Request.Context(), uuid, address)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
-			return
-		} else {
-			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
-			return
-		}
+		ctx.Error(err)
+		return
 	}
 	ctx.JSON(http.StatusCreated, dbAddress)
 	return
@@ -112,16 +102,12 @@ func (controller *AddressController) getAddressByAccountID(ctx *gin.Context) {
 	}
 	uuid, err := parseUUID(idReq.ID)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		invalidRequest(ctx, err)
 		return
 	}
-	dbAddress, err := controller.service.GetAddressByAccountID(uuid)
+	dbAddress, err := controller.service.GetAddressByAccountID(ctx.Request.Context(), uuid)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
-		} else {
-			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
-		}
+		ctx.Error(err)
 		return
 	}
 	ctx.JSON(http.StatusOK, dbAddress)
@@ -132,7 +118,7 @@ func getAccountIDRequest(ctx *gin.Context) (accountIDRequest, error) {
 	var idReq accountIDRequest
 	var err error
 	if err = ctx.ShouldBindUri(&idReq); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		invalidRequest(ctx, err)
 	}
 	return idReq, err
 }
@@ -141,7 +127,7 @@ func getAddressRequest(ctx *gin.Context) (AddressRequest, error) {
 	var req AddressRequest
 	var err error
 	if err = ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		invalidRequest(ctx, err)
 	}
 	return req, err
 }
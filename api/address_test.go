@@ -22,7 +22,7 @@ func TestCreateAddressForAccount(t *testing.T) {
 		name          string
 		accountID     string
 		buildRequest  func() AddressRequest
-		buildStubs    func(querier *mockdb.MockQuerier)
+		buildStubs    func(store *mockdb.MockStore)
 		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
 	}{
 		{
@@ -37,16 +37,16 @@ func TestCreateAddressForAccount(t *testing.T) {
 					Zipcode: address.Zipcode,
 				}
 			},
-			buildStubs: func(querier *mockdb.MockQuerier) {
-				querier.EXPECT().
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
 					GetAccountById(gomock.Any(), gomock.Eq(account.AccountUuid)).
 					Times(1).
 					Return(expectedAccount, nil)
-				querier.EXPECT().
+				store.EXPECT().
 					GetAddressByAccount(gomock.Any(), gomock.Eq(account.AccountUuid)).
 					Times(1).
 					Return(db.Address{}, sql.ErrNoRows)
-				querier.EXPECT().
+				store.EXPECT().
 					CreateAddress(gomock.Any(), gomock.Any()).
 					Times(1).
 					Return(expectedAddress, nil)
@@ -67,7 +67,7 @@ func TestCreateAddressForAccount(t *testing.T) {
 					Zipcode: address.Zipcode,
 				}
 			},
-			buildStubs: func(querier *mockdb.MockQuerier) {},
+			buildStubs: func(store *mockdb.MockStore) {},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusBadRequest, recorder.Code)
 			},
@@ -77,7 +77,7 @@ func TestCreateAddressForAccount(t *testing.T) {
 			buildRequest: func() AddressRequest {
 				return AddressRequest{}
 			},
-			buildStubs: func(querier *mockdb.MockQuerier) {},
+			buildStubs: func(store *mockdb.MockStore) {},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusBadRequest, recorder.Code)
 			},
@@ -93,8 +93,8 @@ func TestCreateAddressForAccount(t *testing.T) {
 					Zipcode: address.Zipcode,
 				}
 			},
-			buildStubs: func(querier *mockdb.MockQuerier) {
-				querier.EXPECT().
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
 					GetAccountById(gomock.Any(), gomock.Eq(account.AccountUuid)).
 					Times(1).
 					Return(db.Account{}, sql.ErrNoRows)
@@ -114,12 +114,12 @@ func TestCreateAddressForAccount(t *testing.T) {
 					Zipcode: address.Zipcode,
 				}
 			},
-			buildStubs: func(querier *mockdb.MockQuerier) {
-				querier.EXPECT().
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
 					GetAccountById(gomock.Any(), gomock.Eq(account.AccountUuid)).
 					Times(1).
 					Return(account, nil)
-				querier.EXPECT().
+				store.EXPECT().
 					GetAddressByAccount(gomock.Any(), gomock.Eq(account.AccountUuid)).
 					Times(1).
 					Return(address, nil)
@@ -127,6 +127,34 @@ func TestCreateAddressForAccount(t *testing.T) {
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusConflict, recorder.Code)
 			},
+		}, {
+			name:      "Address Lookup Failed",
+			accountID: account.AccountUuid.String(),
+			buildRequest: func() AddressRequest {
+				return AddressRequest{
+					Name:    address.Name,
+					Street:  address.Street,
+					City:    address.City,
+					State:   string(address.State),
+					Zipcode: address.Zipcode,
+				}
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccountById(gomock.Any(), gomock.Eq(account.AccountUuid)).
+					Times(1).
+					Return(account, nil)
+				store.EXPECT().
+					GetAddressByAccount(gomock.Any(), gomock.Eq(account.AccountUuid)).
+					Times(1).
+					Return(db.Address{}, sql.ErrConnDone)
+				store.EXPECT().
+					CreateAddress(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				requireProblem(t, recorder, http.StatusInternalServerError, codeInternalError)
+			},
 		},
 	}
 
@@ -136,17 +164,18 @@ func TestCreateAddressForAccount(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			defer ctrl.Finish()
 
-			querier := mockdb.NewMockQuerier(ctrl)
+			store := newMockStore(ctrl)
 			// build stubs
-			testCase.buildStubs(querier)
+			testCase.buildStubs(store)
 
 			// start http server and send the request
-			server := NewServer(querier)
+			server := newTestServer(t, store)
 			recorder := httptest.NewRecorder()
 			url := fmt.Sprintf("/accounts/%s/address", testCase.accountID)
 			request, err := http.NewRequest(http.MethodPut, url,
 				sendObjectAsRequestBody(t, testCase.buildRequest()))
 			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, account)
 
 			server.router.ServeHTTP(recorder, request)
 			//check response
@@ -160,7 +189,7 @@ func TestUpdateAddressForAccount(t *testing.T) {
 		name          string
 		accountID     string
 		buildRequest  func() AddressRequest
-		buildStubs    func(querier *mockdb.MockQuerier)
+		buildStubs    func(store *mockdb.MockStore)
 		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
 	}{
 		{
@@ -175,16 +204,16 @@ func TestUpdateAddressForAccount(t *testing.T) {
 					Zipcode: address.Zipcode,
 				}
 			},
-			buildStubs: func(querier *mockdb.MockQuerier) {
-				querier.EXPECT().
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
 					GetAccountById(gomock.Any(), gomock.Eq(account.AccountUuid)).
 					Times(1).
 					Return(expectedAccount, nil)
-				querier.EXPECT().
+				store.EXPECT().
 					GetAddressByAccount(gomock.Any(), gomock.Eq(account.AccountUuid)).
 					Times(1).
 					Return(address, nil)
-				querier.EXPECT().
+				store.EXPECT().
 					UpdateAddress(gomock.Any(), gomock.Any()).
 					Times(1).
 					Return(expectedAddress, nil)
@@ -205,7 +234,7 @@ func TestUpdateAddressForAccount(t *testing.T) {
 					Zipcode: address.Zipcode,
 				}
 			},
-			buildStubs: func(querier *mockdb.MockQuerier) {},
+			buildStubs: func(store *mockdb.MockStore) {},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusBadRequest, recorder.Code)
 			},
@@ -215,7 +244,7 @@ func TestUpdateAddressForAccount(t *testing.T) {
 			buildRequest: func() AddressRequest {
 				return AddressRequest{}
 			},
-			buildStubs: func(querier *mockdb.MockQuerier) {},
+			buildStubs: func(store *mockdb.MockStore) {},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusBadRequest, recorder.Code)
 			},
@@ -231,8 +260,8 @@ func TestUpdateAddressForAccount(t *testing.T) {
 					Zipcode: address.Zipcode,
 				}
 			},
-			buildStubs: func(querier *mockdb.MockQuerier) {
-				querier.EXPECT().
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
 					GetAccountById(gomock.Any(), gomock.Eq(account.AccountUuid)).
 					Times(1).
 					Return(db.Account{}, sql.ErrNoRows)
@@ -252,12 +281,12 @@ func TestUpdateAddressForAccount(t *testing.T) {
 					Zipcode: address.Zipcode,
 				}
 			},
-			buildStubs: func(querier *mockdb.MockQuerier) {
-				querier.EXPECT().
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
 					GetAccountById(gomock.Any(), gomock.Eq(account.AccountUuid)).
 					Times(1).
 					Return(account, nil)
-				querier.EXPECT().
+				store.EXPECT().
 					GetAddressByAccount(gomock.Any(), gomock.Eq(account.AccountUuid)).
 					Times(1).
 					Return(db.Address{}, sql.ErrNoRows)
@@ -277,16 +306,16 @@ func TestUpdateAddressForAccount(t *testing.T) {
 					Zipcode: address.Zipcode,
 				}
 			},
-			buildStubs: func(querier *mockdb.MockQuerier) {
-				querier.EXPECT().
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
 					GetAccountById(gomock.Any(), gomock.Eq(account.AccountUuid)).
 					Times(1).
 					Return(account, nil)
-				querier.EXPECT().
+				store.EXPECT().
 					GetAddressByAccount(gomock.Any(), gomock.Eq(account.AccountUuid)).
 					Times(1).
 					Return(address, nil)
-				querier.EXPECT().
+				store.EXPECT().
 					UpdateAddress(gomock.Any(), gomock.Any()).
 					Times(1).
 					Return(db.Address{}, sql.ErrConnDone)
@@ -303,17 +332,18 @@ func TestUpdateAddressForAccount(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			defer ctrl.Finish()
 
-			querier := mockdb.NewMockQuerier(ctrl)
+			store := newMockStore(ctrl)
 			// build stubs
-			testCase.buildStubs(querier)
+			testCase.buildStubs(store)
 
 			// start http server and send the request
-			server := NewServer(querier)
+			server := newTestServer(t, store)
 			recorder := httptest.NewRecorder()
 			url := fmt.Sprintf("/accounts/%s/address", testCase.accountID)
 			request, err := http.NewRequest(http.MethodPost, url,
 				sendObjectAsRequestBody(t, testCase.buildRequest()))
 			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, account)
 
 			server.router.ServeHTTP(recorder, request)
 			//check response
@@ -325,14 +355,14 @@ func TestGetAddressForAccount(t *testing.T) {
 	testCases := []struct {
 		name          string
 		accountID     string
-		buildStubs    func(querier *mockdb.MockQuerier)
+		buildStubs    func(store *mockdb.MockStore)
 		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
 	}{
 		{
 			name:      "OK",
 			accountID: account.AccountUuid.String(),
-			buildStubs: func(querier *mockdb.MockQuerier) {
-				querier.EXPECT().
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
 					GetAddressByAccount(gomock.Any(), gomock.Eq(account.AccountUuid)).
 					Times(1).
 					Return(expectedAddress, nil)
@@ -344,15 +374,15 @@ func TestGetAddressForAccount(t *testing.T) {
 		}, {
 			name:       "InvalidID",
 			accountID:  util.RandomAlphaNumericString(4),
-			buildStubs: func(querier *mockdb.MockQuerier) {},
+			buildStubs: func(store *mockdb.MockStore) {},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusBadRequest, recorder.Code)
 			},
 		}, {
 			name:      "Account Doesn't Have Address",
 			accountID: account.AccountUuid.String(),
-			buildStubs: func(querier *mockdb.MockQuerier) {
-				querier.EXPECT().
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
 					GetAddressByAccount(gomock.Any(), gomock.Eq(account.AccountUuid)).
 					Times(1).
 					Return(db.Address{}, sql.ErrNoRows)
@@ -369,17 +399,18 @@ func TestGetAddressForAccount(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			defer ctrl.Finish()
 
-			querier := mockdb.NewMockQuerier(ctrl)
+			store := newMockStore(ctrl)
 			// build stubs
-			testCase.buildStubs(querier)
+			testCase.buildStubs(store)
 
 			// start http server and send the request
-			server := NewServer(querier)
+			server := newTestServer(t, store)
 			recorder := httptest.NewRecorder()
 			url := fmt.Sprintf("/accounts/%s/address", testCase.accountID)
 			request, err := http.NewRequest(http.MethodGet, url,
 				sendObjectAsRequestBody(t, nil))
 			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, account)
 
 			server.router.ServeHTTP(recorder, request)
 			//check response
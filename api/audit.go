@@ -0,0 +1,99 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	db "github.com/valverdethiago/trading-api/db/sqlc"
+	"github.com/valverdethiago/trading-api/service"
+)
+
+const auditLogPath = "/audit-log"
+
+// listAuditLogRequest query parameters to query the audit log by entity or by actor
+type listAuditLogRequest struct {
+	EntityType string `form:"entity_type" binding:"required_with=EntityID,omitempty,oneof=account address trade instrument risk_limit webhook webhook_dead_letter journal_entry"`
+	EntityID   string `form:"entity_id"`
+	Actor      string `form:"actor"`
+	Page       int32  `form:"page" binding:"omitempty,min=1"`
+	PageSize   int32  `form:"page_size" binding:"omitempty,min=1,max=100"`
+}
+
+func (req listAuditLogRequest) toFilter() service.AuditFilter {
+	return service.AuditFilter{
+		EntityType: req.EntityType,
+		EntityID:   req.EntityID,
+		Actor:      req.Actor,
+		Page:       req.Page,
+		PageSize:   req.PageSize,
+	}
+}
+
+type auditLogResponse struct {
+	AuditLogUUID uuid.UUID       `json:"audit_log_uuid"`
+	Actor        string          `json:"actor"`
+	Action       string          `json:"action"`
+	EntityType   string          `json:"entity_type"`
+	EntityID     string          `json:"entity_id"`
+	Before       json.RawMessage `json:"before"`
+	After        json.RawMessage `json:"after"`
+	RequestID    string          `json:"request_id,omitempty"`
+	IP           string          `json:"ip,omitempty"`
+	CreatedDate  time.Time       `json:"created_date"`
+}
+
+func newAuditLogResponse(entry db.AuditLog) auditLogResponse {
+	return auditLogResponse{
+		AuditLogUUID: entry.AuditLogUuid,
+		Actor:        entry.Actor,
+		Action:       entry.Action,
+		EntityType:   entry.EntityType,
+		EntityID:     entry.EntityID,
+		Before:       entry.Before,
+		After:        entry.After,
+		RequestID:    entry.RequestID.String,
+		IP:           entry.Ip.String,
+		CreatedDate:  entry.CreatedDate,
+	}
+}
+
+// AuditController controller for the audit log of the changes
+type AuditController struct {
+	service *service.AuditService
+}
+
+// NewAuditController builds a new instance of audit controller
+func NewAuditController(store db.Store, policy *service.Policy) *AuditController {
+	return &AuditController{
+		service: service.NewAuditService(store, policy),
+	}
+}
+
+func (controller *AuditController) setupRoutes(router *gin.Engine, authRoutes gin.IRoutes) {
+	authRoutes.GET(auditLogPath, controller.listAuditLog)
+}
+
+func (controller *AuditController) listAuditLog(ctx *gin.Context) {
+	var req listAuditLogRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		invalidRequest(ctx, err)
+		return
+	}
+	page, err := controller.service.ListAuditLog(ctx.Request.Context(), getActor(ctx), req.toFilter())
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	if page.HasNextPage() {
+		setNextPageLink(ctx, pageQueryParam, strconv.Itoa(int(page.Page)+1))
+	}
+	response := make([]auditLogResponse, 0, len(page.Entries))
+	for _, entry := range page.Entries {
+		response = append(response, newAuditLogResponse(entry))
+	}
+	ctx.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,220 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	mockdb "github.com/valverdethiago/trading-api/db/mock"
+	db "github.com/valverdethiago/trading-api/db/sqlc"
+	"github.com/valverdethiago/trading-api/service"
+)
+
+func TestListAuditLog(t *testing.T) {
+	entry := db.AuditLog{
+		AuditLogUuid: uuid.New(),
+		Actor:        staffAccount.AccountUuid.String(),
+		Action:       string(service.AuditUpdate),
+		EntityType:   service.EntityTrade,
+		EntityID:     trade.TradeUuid.String(),
+		Before:       json.RawMessage(`{"status":"SUBMITTED"}`),
+		After:        json.RawMessage(`{"status":"CANCELLED"}`),
+		CreatedDate:  time.Now(),
+	}
+
+	testCases := []struct {
+		name          string
+		query         string
+		actor         db.Account
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "By Entity",
+			query: fmt.Sprintf("?entity_type=trade&entity_id=%s", trade.TradeUuid),
+			actor: staffAccount,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListAuditLog(gomock.Any(), gomock.Eq(db.ListAuditLogParams{
+						EntityType: service.EntityTrade,
+						EntityID:   trade.TradeUuid.String(),
+						PageSize:   service.DefaultAuditPageSize,
+						PageOffset: 0,
+					})).
+					Times(1).
+					Return([]db.AuditLog{entry}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Empty(t, recorder.Header().Get("Link"))
+				var response []auditLogResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.Len(t, response, 1)
+				require.Equal(t, entry.AuditLogUuid, response[0].AuditLogUUID)
+				require.JSONEq(t, string(entry.Before), string(response[0].Before))
+				require.JSONEq(t, string(entry.After), string(response[0].After))
+			},
+		}, {
+			name:  "By Actor Next Page",
+			query: fmt.Sprintf("?actor=%s&page=2&page_size=1", staffAccount.AccountUuid),
+			actor: staffAccount,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListAuditLog(gomock.Any(), gomock.Eq(db.ListAuditLogParams{
+						Actor:      staffAccount.AccountUuid.String(),
+						PageSize:   1,
+						PageOffset: 1,
+					})).
+					Times(1).
+					Return([]db.AuditLog{entry}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Contains(t, recorder.Header().Get("Link"), "page=3")
+			},
+		}, {
+			name:  "Not Staff",
+			query: "",
+			actor: account,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListAuditLog(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		}, {
+			name:  "Invalid Entity Type",
+			query: "?entity_type=ledger",
+			actor: staffAccount,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListAuditLog(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		}, {
+			name:  "Entity ID Without Type",
+			query: fmt.Sprintf("?entity_id=%s", trade.TradeUuid),
+			actor: staffAccount,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListAuditLog(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		testCase := testCases[i]
+
+		t.Run(testCase.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := newMockStore(ctrl)
+			testCase.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodGet, auditLogPath+testCase.query, nil)
+			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, testCase.actor)
+
+			server.router.ServeHTTP(recorder, request)
+			testCase.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestAuditChangeOrigin(t *testing.T) {
+	promotedAccount := account
+	promotedAccount.Role = db.AccountRoleSTAFF
+
+	testCases := []struct {
+		name      string
+		requestID string
+		checkID   func(t *testing.T, requestID string, recorded string)
+	}{
+		{
+			name:      "Client Request ID",
+			requestID: "req-42",
+			checkID: func(t *testing.T, requestID string, recorded string) {
+				require.Equal(t, "req-42", requestID)
+				require.Equal(t, requestID, recorded)
+			},
+		}, {
+			name:      "Generated Request ID",
+			requestID: "",
+			checkID: func(t *testing.T, requestID string, recorded string) {
+				_, err := uuid.Parse(requestID)
+				require.NoError(t, err)
+				require.Equal(t, requestID, recorded)
+			},
+		},
+	}
+
+	for i := range testCases {
+		testCase := testCases[i]
+
+		t.Run(testCase.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			var recorded db.CreateAuditLogParams
+			store := newMockStoreWithoutAudit(ctrl)
+			store.EXPECT().
+				GetAccountById(gomock.Any(), gomock.Eq(account.AccountUuid)).
+				Times(1).
+				Return(account, nil)
+			store.EXPECT().
+				UpdateAccountRole(gomock.Any(), gomock.Any()).
+				Times(1).
+				Return(promotedAccount, nil)
+			expectEvents(store)
+			store.EXPECT().
+				CreateAuditLog(gomock.Any(), gomock.Any()).
+				Times(1).
+				DoAndReturn(func(ctx context.Context, arg db.CreateAuditLogParams) (db.AuditLog, error) {
+					recorded = arg
+					return db.AuditLog{}, nil
+				})
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+			body, err := json.Marshal(UpdateAccountRoleRequest{Role: db.AccountRoleSTAFF})
+			require.NoError(t, err)
+			url := fmt.Sprintf("/accounts/%s/role", account.AccountUuid)
+			request, err := http.NewRequest(http.MethodPut, url, bytes.NewReader(body))
+			require.NoError(t, err)
+			request.RemoteAddr = "192.0.2.1:51234"
+			if testCase.requestID != "" {
+				request.Header.Set(requestIDHeaderKey, testCase.requestID)
+			}
+			addAuthorization(t, request, server.tokenMaker, staffAccount)
+
+			server.router.ServeHTTP(recorder, request)
+			require.Equal(t, http.StatusOK, recorder.Code)
+			require.Equal(t, staffAccount.AccountUuid.String(), recorded.Actor)
+			require.Equal(t, string(service.AuditUpdate), recorded.Action)
+			require.Equal(t, service.EntityAccount, recorded.EntityType)
+			require.Equal(t, account.AccountUuid.String(), recorded.EntityID)
+			require.Equal(t, "192.0.2.1", recorded.Ip.String)
+			testCase.checkID(t, recorder.Header().Get(requestIDHeaderKey), recorded.RequestID.String)
+		})
+	}
+}
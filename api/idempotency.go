@@ -0,0 +1,155 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/valverdethiago/trading-api/openapi"
+	"github.com/valverdethiago/trading-api/service"
+)
+
+const (
+	idempotencyKeyHeader      = "Idempotency-Key"
+	idempotentReplayedHeader  = "Idempotent-Replayed"
+	maxIdempotencyKeyLength   = 255
+	publicIdempotencyScope    = "public"
+	idempotencyStoreTimeout   = 5 * time.Second
+	idempotencyResponseFormat = "application/json; charset=utf-8"
+)
+
+// bodyRecorder keeps a copy of the response body written by the handler
+type bodyRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (recorder *bodyRecorder) Write(data []byte) (int, error) {
+	recorder.body.Write(data)
+	return recorder.ResponseWriter.Write(data)
+}
+
+func (recorder *bodyRecorder) WriteString(data string) (int, error) {
+	recorder.body.WriteString(data)
+	return recorder.ResponseWriter.WriteString(data)
+}
+
+// idempotencyMiddleware runs requests carrying an Idempotency-Key only once per key, replays of the
+// same request get the stored response and replays of a different request get 422
+func idempotencyMiddleware(idempotencyService *service.IdempotencyService) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		key := ctx.GetHeader(idempotencyKeyHeader)
+		if key == "" {
+			ctx.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			invalidRequest(ctx, errors.New("idempotency key is too long"))
+			ctx.Abort()
+			return
+		}
+		body, err := ioutil.ReadAll(ctx.Request.Body)
+		if err != nil {
+			invalidRequest(ctx, err)
+			ctx.Abort()
+			return
+		}
+		ctx.Request.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+		scope := idempotencyScope(ctx, body)
+		stored, err := idempotencyService.Begin(ctx.Request.Context(), scope, key, requestHash(ctx.Request, body))
+		if err != nil {
+			ctx.Error(err)
+			ctx.Abort()
+			return
+		}
+		if stored != nil {
+			ctx.Header(idempotentReplayedHeader, "true")
+			ctx.Data(stored.Status, storedResponseFormat(stored.Status), stored.Body)
+			ctx.Abort()
+			return
+		}
+
+		recorder := &bodyRecorder{ResponseWriter: ctx.Writer}
+		ctx.Writer = recorder
+		ctx.Next()
+		// the errors are rendered now for the stored response to replay them
+		recordContextError(ctx)
+		writeProblem(ctx)
+
+		// the request context may be over already, the key must be settled anyway
+		storeCtx, cancel := context.WithTimeout(context.Background(), idempotencyStoreTimeout)
+		defer cancel()
+		status := recorder.Status()
+		if status >= http.StatusInternalServerError || status == statusClientClosedRequest || ctx.Request.Context().Err() != nil {
+			err = idempotencyService.Release(storeCtx, scope, key)
+		} else {
+			err = idempotencyService.Complete(storeCtx, scope, key, service.StoredResponse{
+				Status: status,
+				Body:   recorder.body.Bytes(),
+			})
+		}
+		if err != nil {
+			log.Printf("Cannot settle idempotency key %q: %v", key, err)
+		}
+	}
+}
+
+// storedResponseFormat is the content type of a stored response, the errors being problems
+func storedResponseFormat(status int) string {
+	if status >= http.StatusBadRequest {
+		return openapi.ContentTypeProblemJSON
+	}
+	return idempotencyResponseFormat
+}
+
+// idempotencyScope keeps the keys of each account apart. The requests without account, like the sign
+// ups, are kept apart per client: by the username they send or, without one, by the client address
+func idempotencyScope(ctx *gin.Context, body []byte) string {
+	if ID := ctx.Param("id"); ID != "" {
+		// the same account written in another case is the same scope
+		if accountUUID, err := uuid.Parse(ID); err == nil {
+			return accountUUID.String()
+		}
+		return ID
+	}
+	var client struct {
+		Username string `json:"username"`
+	}
+	if json.Unmarshal(body, &client) == nil && client.Username != "" {
+		return publicIdempotencyScope + ":username:" + client.Username
+	}
+	return publicIdempotencyScope + ":ip:" + ctx.ClientIP()
+}
+
+// requestHash identifies the request by its route and body, JSON bodies being compared by content
+func requestHash(request *http.Request, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(request.Method + " " + request.URL.Path + "\n"))
+	hash.Write(canonicalJSON(body))
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// canonicalJSON rewrites the body with sorted keys and no spacing, other bodies are left as they are
+func canonicalJSON(body []byte) []byte {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return body
+	}
+	canonical, err := json.Marshal(value)
+	if err != nil {
+		return body
+	}
+	return canonical
+}
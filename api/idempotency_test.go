@@ -0,0 +1,349 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+	mockdb "github.com/valverdethiago/trading-api/db/mock"
+	db "github.com/valverdethiago/trading-api/db/sqlc"
+)
+
+func TestCreateTradeIdempotency(t *testing.T) {
+	key := uuid.New().String()
+	scope := account.AccountUuid.String()
+	url := fmt.Sprintf("/accounts/%s/trades", account.AccountUuid.String())
+	body := fmt.Sprintf(`{"symbol":"%s","quantity":%d,"side":"BUY","price":10.15}`, trade.Symbol, trade.Quantity)
+	hash := requestHash(httptest.NewRequest(http.MethodPost, url, nil), []byte(body))
+	storedBody, err := json.Marshal(expectedSubmittedTrade)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name          string
+		key           string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "First Request",
+			key:  key,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateIdempotencyKey(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(ctx context.Context, arg db.CreateIdempotencyKeyParams) (db.IdempotencyKey, error) {
+						require.Equal(t, scope, arg.Scope)
+						require.Equal(t, key, arg.IdempotencyKey)
+						require.Equal(t, hash, arg.RequestHash)
+						require.Positive(t, arg.TtlSeconds)
+						return db.IdempotencyKey{}, nil
+					})
+				store.EXPECT().
+					GetAccountById(gomock.Any(), gomock.Eq(account.AccountUuid)).
+					Times(1).
+					Return(account, nil)
+				expectBuyingPower(store, decimal.NewFromInt(1000000), 0)
+				expectInstrument(store)
+				store.EXPECT().
+					CreateTrade(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(trade, nil)
+				store.EXPECT().
+					CreateTradeVersion(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.TradeVersion{}, nil)
+				store.EXPECT().
+					UpdateIdempotencyKeyResponse(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(ctx context.Context, arg db.UpdateIdempotencyKeyResponseParams) (db.IdempotencyKey, error) {
+						require.Equal(t, scope, arg.Scope)
+						require.Equal(t, key, arg.IdempotencyKey)
+						require.Equal(t, int32(http.StatusCreated), arg.ResponseStatus.Int32)
+						require.Contains(t, string(arg.ResponseBody), trade.TradeUuid.String())
+						return db.IdempotencyKey{}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+				require.Empty(t, recorder.Header().Get(idempotentReplayedHeader))
+				requireBodyMatchTrade(t, recorder.Body, trade)
+			},
+		}, {
+			name: "Replay",
+			key:  key,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateIdempotencyKey(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.IdempotencyKey{}, sql.ErrNoRows)
+				store.EXPECT().
+					GetIdempotencyKey(gomock.Any(), gomock.Eq(db.GetIdempotencyKeyParams{
+						Scope:          scope,
+						IdempotencyKey: key,
+					})).
+					Times(1).
+					Return(db.IdempotencyKey{
+						Scope:          scope,
+						IdempotencyKey: key,
+						RequestHash:    hash,
+						ResponseStatus: sql.NullInt32{Int32: http.StatusCreated, Valid: true},
+						ResponseBody:   storedBody,
+					}, nil)
+				store.EXPECT().
+					CreateTrade(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+				require.Equal(t, "true", recorder.Header().Get(idempotentReplayedHeader))
+				require.Equal(t, storedBody, recorder.Body.Bytes())
+			},
+		}, {
+			name: "Replay With Different Body",
+			key:  key,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateIdempotencyKey(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.IdempotencyKey{}, sql.ErrNoRows)
+				store.EXPECT().
+					GetIdempotencyKey(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.IdempotencyKey{
+						Scope:          scope,
+						IdempotencyKey: key,
+						RequestHash:    "another hash",
+						ResponseStatus: sql.NullInt32{Int32: http.StatusCreated, Valid: true},
+						ResponseBody:   storedBody,
+					}, nil)
+				store.EXPECT().
+					CreateTrade(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+			},
+		}, {
+			name: "Replay In Progress",
+			key:  key,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateIdempotencyKey(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.IdempotencyKey{}, sql.ErrNoRows)
+				store.EXPECT().
+					GetIdempotencyKey(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.IdempotencyKey{
+						Scope:          scope,
+						IdempotencyKey: key,
+						RequestHash:    hash,
+					}, nil)
+				store.EXPECT().
+					CreateTrade(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		}, {
+			name: "Failed Request Releases The Key",
+			key:  key,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateIdempotencyKey(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.IdempotencyKey{}, nil)
+				store.EXPECT().
+					GetAccountById(gomock.Any(), gomock.Eq(account.AccountUuid)).
+					Times(1).
+					Return(account, nil)
+				expectBuyingPower(store, decimal.NewFromInt(1000000), 0)
+				expectInstrument(store)
+				store.EXPECT().
+					CreateTrade(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Trade{}, sql.ErrConnDone)
+				store.EXPECT().
+					DeleteIdempotencyKey(gomock.Any(), gomock.Eq(db.DeleteIdempotencyKeyParams{
+						Scope:          scope,
+						IdempotencyKey: key,
+					})).
+					Times(1).
+					Return(nil)
+				store.EXPECT().
+					UpdateIdempotencyKeyResponse(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		}, {
+			name: "Key Too Long",
+			key:  string(bytes.Repeat([]byte("k"), maxIdempotencyKeyLength+1)),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateIdempotencyKey(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		}, {
+			name: "Without Key",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateIdempotencyKey(gomock.Any(), gomock.Any()).
+					Times(0)
+				store.EXPECT().
+					GetAccountById(gomock.Any(), gomock.Eq(account.AccountUuid)).
+					Times(1).
+					Return(account, nil)
+				expectBuyingPower(store, decimal.NewFromInt(1000000), 0)
+				expectInstrument(store)
+				store.EXPECT().
+					CreateTrade(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(trade, nil)
+				store.EXPECT().
+					CreateTradeVersion(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.TradeVersion{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		testCase := testCases[i]
+
+		t.Run(testCase.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := newMockStore(ctrl)
+			testCase.buildStubs(store)
+			expectEvents(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewBufferString(body))
+			require.NoError(t, err)
+			if testCase.key != "" {
+				request.Header.Set(idempotencyKeyHeader, testCase.key)
+			}
+			addAuthorization(t, request, server.tokenMaker, account)
+
+			server.router.ServeHTTP(recorder, request)
+			testCase.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestCreateAccountIdempotency(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	key := uuid.New().String()
+	storedBody, err := json.Marshal(newAccountResponse(account))
+	require.NoError(t, err)
+	store := newMockStore(ctrl)
+	store.EXPECT().
+		CreateIdempotencyKey(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(db.IdempotencyKey{}, sql.ErrNoRows)
+	store.EXPECT().
+		GetIdempotencyKey(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(ctx context.Context, arg db.GetIdempotencyKeyParams) (db.IdempotencyKey, error) {
+			require.Equal(t, publicIdempotencyScope+":username:"+account.Username, arg.Scope)
+			request := httptest.NewRequest(http.MethodPost, accountsPath, nil)
+			body := fmt.Sprintf(`{"username":"%s","password":"secret","email":"%s"}`, account.Username, account.Email)
+			return db.IdempotencyKey{
+				RequestHash:    requestHash(request, []byte(body)),
+				ResponseStatus: sql.NullInt32{Int32: http.StatusCreated, Valid: true},
+				ResponseBody:   storedBody,
+			}, nil
+		})
+	store.EXPECT().
+		CreateAccount(gomock.Any(), gomock.Any()).
+		Times(0)
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+	// same content as the stored request, keys in another order
+	body := fmt.Sprintf(`{"email":"%s", "password":"secret", "username":"%s"}`, account.Email, account.Username)
+	request, err := http.NewRequest(http.MethodPost, accountsPath, bytes.NewBufferString(body))
+	require.NoError(t, err)
+	request.Header.Set(idempotencyKeyHeader, key)
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusCreated, recorder.Code)
+	require.Equal(t, storedBody, recorder.Body.Bytes())
+}
+
+func TestIdempotencyScope(t *testing.T) {
+	testCases := []struct {
+		name          string
+		path          string
+		body          string
+		expectedScope string
+	}{
+		{
+			name:          "Account",
+			path:          "/accounts/" + account.AccountUuid.String() + "/trades",
+			expectedScope: account.AccountUuid.String(),
+		}, {
+			name:          "Account In Upper Case",
+			path:          "/accounts/" + strings.ToUpper(account.AccountUuid.String()) + "/trades",
+			expectedScope: account.AccountUuid.String(),
+		}, {
+			name:          "Public With Username",
+			path:          accountsPath,
+			body:          `{"username":"trader"}`,
+			expectedScope: publicIdempotencyScope + ":username:trader",
+		}, {
+			name:          "Public Without Username",
+			path:          accountsPath,
+			body:          `{}`,
+			expectedScope: publicIdempotencyScope + ":ip:192.0.2.1",
+		},
+	}
+
+	for i := range testCases {
+		testCase := testCases[i]
+
+		t.Run(testCase.name, func(t *testing.T) {
+			var scope string
+			router := gin.New()
+			handler := func(ctx *gin.Context) {
+				scope = idempotencyScope(ctx, []byte(testCase.body))
+			}
+			router.POST(accountsPath, handler)
+			router.POST(accountsPath+"/:id/trades", handler)
+
+			request := httptest.NewRequest(http.MethodPost, testCase.path, nil)
+			router.ServeHTTP(httptest.NewRecorder(), request)
+			require.Equal(t, testCase.expectedScope, scope)
+		})
+	}
+}
+
+func TestCanonicalJSON(t *testing.T) {
+	require.Equal(t, canonicalJSON([]byte(`{"b": 10.50, "a": ["x", 1]}`)), canonicalJSON([]byte(`{"a":["x",1],"b":10.50}`)))
+	require.NotEqual(t, canonicalJSON([]byte(`{"price":10.5}`)), canonicalJSON([]byte(`{"price":"10.5"}`)))
+	require.Equal(t, []byte("not json"), canonicalJSON([]byte("not json")))
+}
@@ -0,0 +1,151 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+	db "github.com/valverdethiago/trading-api/db/sqlc"
+	"github.com/valverdethiago/trading-api/service"
+)
+
+const (
+	instrumentsPath         = "/instruments"
+	instrumentsPathBySymbol = "/instruments/:symbol"
+	instrumentsImportPath   = "/instruments/import"
+)
+
+type instrumentSymbolRequest struct {
+	Symbol string `uri:"symbol" binding:"required"`
+}
+
+// instrumentRequest json request with the reference data of an instrument, missing tick size, lot size
+// and currency take the defaults and instruments are tradable unless told otherwise
+type instrumentRequest struct {
+	Name     string           `json:"name" binding:"required"`
+	Exchange string           `json:"exchange" binding:"required"`
+	TickSize *decimal.Decimal `json:"tick_size" binding:"omitempty,gt=0"`
+	LotSize  int64            `json:"lot_size" binding:"omitempty,min=1"`
+	Tradable *bool            `json:"tradable"`
+	Currency string           `json:"currency" binding:"omitempty,len=3"`
+}
+
+// createInstrumentRequest json request to add an instrument to the security master
+type createInstrumentRequest struct {
+	Symbol string `json:"symbol" binding:"required"`
+	instrumentRequest
+}
+
+func (req instrumentRequest) toInstrument(symbol string) db.Instrument {
+	instrument := db.Instrument{
+		Symbol:   symbol,
+		Name:     req.Name,
+		Exchange: req.Exchange,
+		LotSize:  req.LotSize,
+		Tradable: req.Tradable == nil || *req.Tradable,
+		Currency: req.Currency,
+	}
+	if req.TickSize != nil {
+		instrument.TickSize = *req.TickSize
+	}
+	return instrument
+}
+
+type importInstrumentsResponse struct {
+	Imported int `json:"imported"`
+}
+
+// InstrumentController controller for the security master
+type InstrumentController struct {
+	service *service.InstrumentService
+}
+
+// NewInstrumentController builds a new instance of instrument controller
+func NewInstrumentController(store db.Store, policy *service.Policy, defaultTickSize decimal.Decimal) *InstrumentController {
+	return &InstrumentController{
+		service: service.NewInstrumentService(store, policy, defaultTickSize),
+	}
+}
+
+func (controller *InstrumentController) setupRoutes(router *gin.Engine, authRoutes gin.IRoutes) {
+	authRoutes.GET(instrumentsPath, controller.listInstruments)
+	authRoutes.GET(instrumentsPathBySymbol, controller.getInstrument)
+	authRoutes.POST(instrumentsPath, controller.createInstrument)
+	authRoutes.POST(instrumentsImportPath, controller.importInstruments)
+	authRoutes.PUT(instrumentsPathBySymbol, controller.updateInstrument)
+	authRoutes.DELETE(instrumentsPathBySymbol, controller.deleteInstrument)
+}
+
+func (controller *InstrumentController) listInstruments(ctx *gin.Context) {
+	dbInstruments, err := controller.service.ListInstruments(ctx.Request.Context())
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, dbInstruments)
+}
+
+func (controller *InstrumentController) getInstrument(ctx *gin.Context) {
+	var req instrumentSymbolRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		invalidRequest(ctx, err)
+		return
+	}
+	dbInstrument, err := controller.service.GetInstrument(ctx.Request.Context(), req.Symbol)
+	controller.instrumentResponse(ctx, http.StatusOK, dbInstrument, err)
+}
+
+func (controller *InstrumentController) createInstrument(ctx *gin.Context) {
+	var req createInstrumentRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		invalidRequest(ctx, err)
+		return
+	}
+	dbInstrument, err := controller.service.CreateInstrument(ctx.Request.Context(), getActor(ctx),
+		req.toInstrument(req.Symbol))
+	controller.instrumentResponse(ctx, http.StatusCreated, dbInstrument, err)
+}
+
+func (controller *InstrumentController) updateInstrument(ctx *gin.Context) {
+	var uri instrumentSymbolRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		invalidRequest(ctx, err)
+		return
+	}
+	var req instrumentRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		invalidRequest(ctx, err)
+		return
+	}
+	dbInstrument, err := controller.service.UpdateInstrument(ctx.Request.Context(), getActor(ctx),
+		req.toInstrument(uri.Symbol))
+	controller.instrumentResponse(ctx, http.StatusOK, dbInstrument, err)
+}
+
+func (controller *InstrumentController) deleteInstrument(ctx *gin.Context) {
+	var req instrumentSymbolRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		invalidRequest(ctx, err)
+		return
+	}
+	dbInstrument, err := controller.service.DeleteInstrument(ctx.Request.Context(), getActor(ctx), req.Symbol)
+	controller.instrumentResponse(ctx, http.StatusOK, dbInstrument, err)
+}
+
+func (controller *InstrumentController) importInstruments(ctx *gin.Context) {
+	imported, err := controller.service.ImportInstruments(ctx.Request.Context(), getActor(ctx), ctx.Request.Body)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, importInstrumentsResponse{Imported: imported})
+}
+
+func (controller *InstrumentController) instrumentResponse(ctx *gin.Context, status int,
+	dbInstrument db.Instrument, err error) {
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(status, dbInstrument)
+}
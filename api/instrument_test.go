@@ -0,0 +1,450 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+	mockdb "github.com/valverdethiago/trading-api/db/mock"
+	db "github.com/valverdethiago/trading-api/db/sqlc"
+)
+
+var instrument = db.Instrument{
+	Symbol:   "AAPL",
+	Name:     "Apple Inc.",
+	Exchange: "NASDAQ",
+	TickSize: decimal.RequireFromString("0.01"),
+	LotSize:  1,
+	Tradable: true,
+	Currency: "USD",
+}
+
+func TestListInstruments(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := newMockStore(ctrl)
+	store.EXPECT().
+		ListInstruments(gomock.Any()).
+		Times(1).
+		Return([]db.Instrument{instrument}, nil)
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodGet, instrumentsPath, nil)
+	require.NoError(t, err)
+	addAuthorization(t, request, server.tokenMaker, account)
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	var instruments []db.Instrument
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &instruments))
+	require.Len(t, instruments, 1)
+	require.Equal(t, instrument.Symbol, instruments[0].Symbol)
+}
+
+func TestGetInstrument(t *testing.T) {
+	testCases := []struct {
+		name          string
+		symbol        string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "OK",
+			symbol: "aapl",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetInstrument(gomock.Any(), gomock.Eq(instrument.Symbol)).
+					Times(1).
+					Return(instrument, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchInstrument(t, recorder.Body, instrument)
+			},
+		}, {
+			name:   "Not Found",
+			symbol: "ZZZZ",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetInstrument(gomock.Any(), gomock.Eq("ZZZZ")).
+					Times(1).
+					Return(db.Instrument{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		testCase := testCases[i]
+
+		t.Run(testCase.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := newMockStore(ctrl)
+			testCase.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodGet, instrumentsPath+"/"+testCase.symbol, nil)
+			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, account)
+
+			server.router.ServeHTTP(recorder, request)
+			testCase.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestCreateInstrument(t *testing.T) {
+	testCases := []struct {
+		name          string
+		actor         db.Account
+		body          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "OK",
+			actor: staffAccount,
+			body:  `{"symbol":"aapl","name":"Apple Inc.","exchange":"nasdaq"}`,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateInstrument(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(ctx context.Context, arg db.CreateInstrumentParams) (db.Instrument, error) {
+						require.Equal(t, instrument.Symbol, arg.Symbol)
+						require.Equal(t, instrument.Exchange, arg.Exchange)
+						require.True(t, instrument.TickSize.Equal(arg.TickSize))
+						require.Equal(t, int64(1), arg.LotSize)
+						require.True(t, arg.Tradable)
+						require.Equal(t, "USD", arg.Currency)
+						return instrument, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+				requireBodyMatchInstrument(t, recorder.Body, instrument)
+			},
+		}, {
+			name:  "Halted With Lot Size",
+			actor: staffAccount,
+			body:  `{"symbol":"BRK.A","name":"Berkshire","exchange":"NYSE","tick_size":0.05,"lot_size":10,"tradable":false}`,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateInstrument(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(ctx context.Context, arg db.CreateInstrumentParams) (db.Instrument, error) {
+						require.Equal(t, "BRK.A", arg.Symbol)
+						require.Equal(t, "0.05", arg.TickSize.String())
+						require.Equal(t, int64(10), arg.LotSize)
+						require.False(t, arg.Tradable)
+						return db.Instrument{Symbol: arg.Symbol}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+			},
+		}, {
+			name:  "Duplicate Symbol",
+			actor: staffAccount,
+			body:  `{"symbol":"AAPL","name":"Apple Inc.","exchange":"NASDAQ"}`,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateInstrument(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Instrument{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		}, {
+			name:  "Invalid Symbol",
+			actor: staffAccount,
+			body:  `{"symbol":"AA PL","name":"Apple Inc.","exchange":"NASDAQ"}`,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateInstrument(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		}, {
+			name:  "Missing Exchange",
+			actor: staffAccount,
+			body:  `{"symbol":"AAPL","name":"Apple Inc."}`,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateInstrument(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		}, {
+			name:  "Not Staff",
+			actor: account,
+			body:  `{"symbol":"AAPL","name":"Apple Inc.","exchange":"NASDAQ"}`,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateInstrument(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		testCase := testCases[i]
+
+		t.Run(testCase.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := newMockStore(ctrl)
+			testCase.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodPost, instrumentsPath, bytes.NewBufferString(testCase.body))
+			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, testCase.actor)
+
+			server.router.ServeHTTP(recorder, request)
+			testCase.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestUpdateInstrument(t *testing.T) {
+	testCases := []struct {
+		name          string
+		actor         db.Account
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "OK",
+			actor: staffAccount,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetInstrument(gomock.Any(), gomock.Eq(instrument.Symbol)).
+					Times(1).
+					Return(instrument, nil)
+				store.EXPECT().
+					UpdateInstrument(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(ctx context.Context, arg db.UpdateInstrumentParams) (db.Instrument, error) {
+						require.Equal(t, instrument.Symbol, arg.Symbol)
+						require.False(t, arg.Tradable)
+						halted := instrument
+						halted.Tradable = false
+						return halted, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		}, {
+			name:  "Not Found",
+			actor: staffAccount,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetInstrument(gomock.Any(), gomock.Eq(instrument.Symbol)).
+					Times(1).
+					Return(db.Instrument{}, sql.ErrNoRows)
+				store.EXPECT().
+					UpdateInstrument(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		}, {
+			name:  "Not Staff",
+			actor: account,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpdateInstrument(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		testCase := testCases[i]
+
+		t.Run(testCase.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := newMockStore(ctrl)
+			testCase.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+			body := `{"name":"Apple Inc.","exchange":"NASDAQ","tradable":false}`
+			request, err := http.NewRequest(http.MethodPut, instrumentsPath+"/AAPL", bytes.NewBufferString(body))
+			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, testCase.actor)
+
+			server.router.ServeHTTP(recorder, request)
+			testCase.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestDeleteInstrument(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := newMockStore(ctrl)
+	store.EXPECT().
+		DeleteInstrument(gomock.Any(), gomock.Eq(instrument.Symbol)).
+		Times(1).
+		Return(instrument, nil)
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodDelete, instrumentsPath+"/AAPL", nil)
+	require.NoError(t, err)
+	addAuthorization(t, request, server.tokenMaker, staffAccount)
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	requireBodyMatchInstrument(t, recorder.Body, instrument)
+}
+
+func TestImportInstruments(t *testing.T) {
+	testCases := []struct {
+		name          string
+		actor         db.Account
+		body          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "OK",
+			actor: staffAccount,
+			body: "exchange,symbol,name,lot_size,tradable\n" +
+				"NASDAQ,AAPL,Apple Inc.,,\n" +
+				"NYSE,brk.a,\"Berkshire Hathaway, Class A\",10,false\n",
+			buildStubs: func(store *mockdb.MockStore) {
+				var loaded []db.UpsertInstrumentParams
+				store.EXPECT().
+					GetInstrument(gomock.Any(), gomock.Any()).
+					Times(2).
+					Return(db.Instrument{}, sql.ErrNoRows)
+				store.EXPECT().
+					UpsertInstrument(gomock.Any(), gomock.Any()).
+					Times(2).
+					DoAndReturn(func(ctx context.Context, arg db.UpsertInstrumentParams) (db.Instrument, error) {
+						loaded = append(loaded, arg)
+						if len(loaded) == 2 {
+							require.Equal(t, "AAPL", loaded[0].Symbol)
+							require.Equal(t, int64(1), loaded[0].LotSize)
+							require.True(t, loaded[0].Tradable)
+							require.Equal(t, "BRK.A", loaded[1].Symbol)
+							require.Equal(t, "Berkshire Hathaway, Class A", loaded[1].Name)
+							require.Equal(t, int64(10), loaded[1].LotSize)
+							require.False(t, loaded[1].Tradable)
+							require.True(t, decimal.RequireFromString("0.01").Equal(loaded[1].TickSize))
+						}
+						return db.Instrument{Symbol: arg.Symbol}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.JSONEq(t, `{"imported":2}`, recorder.Body.String())
+			},
+		}, {
+			name:  "Invalid Line",
+			actor: staffAccount,
+			body:  "symbol,name,exchange,tick_size\nAAPL,Apple Inc.,NASDAQ,0.01\nMSFT,Microsoft,NASDAQ,cheap\n",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpsertInstrument(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Contains(t, recorder.Body.String(), "line 3")
+			},
+		}, {
+			name:  "Missing Column",
+			actor: staffAccount,
+			body:  "symbol,name\nAAPL,Apple Inc.\n",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpsertInstrument(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		}, {
+			name:  "Not Staff",
+			actor: account,
+			body:  "symbol,name,exchange\nAAPL,Apple Inc.,NASDAQ\n",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpsertInstrument(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		testCase := testCases[i]
+
+		t.Run(testCase.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := newMockStore(ctrl)
+			testCase.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodPost, instrumentsImportPath, bytes.NewBufferString(testCase.body))
+			require.NoError(t, err)
+			request.Header.Set("Content-Type", "text/csv")
+			addAuthorization(t, request, server.tokenMaker, testCase.actor)
+
+			server.router.ServeHTTP(recorder, request)
+			testCase.checkResponse(t, recorder)
+		})
+	}
+}
+
+func requireBodyMatchInstrument(t *testing.T, body *bytes.Buffer, expected db.Instrument) {
+	var actual db.Instrument
+	require.NoError(t, json.Unmarshal(body.Bytes(), &actual))
+	require.Equal(t, expected.Symbol, actual.Symbol)
+	require.Equal(t, expected.Name, actual.Name)
+	require.Equal(t, expected.Exchange, actual.Exchange)
+	require.True(t, expected.TickSize.Equal(actual.TickSize))
+	require.Equal(t, expected.LotSize, actual.LotSize)
+	require.Equal(t, expected.Tradable, actual.Tradable)
+	require.Equal(t, expected.Currency, actual.Currency)
+}
@@ -0,0 +1,176 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	db "github.com/valverdethiago/trading-api/db/sqlc"
+	"github.com/valverdethiago/trading-api/service"
+)
+
+const (
+	balancesPath    = "/accounts/:id/balances"
+	ledgerPath      = "/accounts/:id/ledger"
+	depositsPath    = "/accounts/:id/deposits"
+	withdrawalsPath = "/accounts/:id/withdrawals"
+)
+
+type cashMovementRequest struct {
+	Amount decimal.Decimal `json:"amount" binding:"required"`
+}
+
+// listJournalRequest query parameters to page the journal of an account
+type listJournalRequest struct {
+	Page     int32 `form:"page" binding:"omitempty,min=1"`
+	PageSize int32 `form:"page_size" binding:"omitempty,min=1,max=100"`
+}
+
+type ledgerPostingResponse struct {
+	// AccountUUID is empty on the ledger accounts of the broker
+	AccountUUID *uuid.UUID           `json:"account_uuid,omitempty"`
+	Type        db.LedgerAccountType `json:"type"`
+	Asset       string               `json:"asset"`
+	Amount      decimal.Decimal      `json:"amount"`
+}
+
+type ledgerEntryResponse struct {
+	JournalEntryUUID uuid.UUID               `json:"journal_entry_uuid"`
+	Type             db.JournalEntryType     `json:"type"`
+	TradeUUID        *uuid.UUID              `json:"trade_uuid,omitempty"`
+	Description      string                  `json:"description"`
+	CreatedDate      time.Time               `json:"created_date"`
+	Postings         []ledgerPostingResponse `json:"postings"`
+}
+
+func newLedgerEntryResponse(entry service.LedgerEntry) ledgerEntryResponse {
+	response := ledgerEntryResponse{
+		JournalEntryUUID: entry.Entry.JournalEntryUuid,
+		Type:             entry.Entry.Type,
+		TradeUUID:        optionalUUID(entry.Entry.TradeUuid),
+		Description:      entry.Entry.Description,
+		CreatedDate:      entry.Entry.CreatedDate,
+		Postings:         make([]ledgerPostingResponse, 0, len(entry.Postings)),
+	}
+	for _, posting := range entry.Postings {
+		response.Postings = append(response.Postings, ledgerPostingResponse{
+			AccountUUID: optionalUUID(posting.AccountUuid),
+			Type:        posting.Type,
+			Asset:       posting.Asset,
+			Amount:      posting.Amount,
+		})
+	}
+	return response
+}
+
+func optionalUUID(value uuid.UUID) *uuid.UUID {
+	if value == uuid.Nil {
+		return nil
+	}
+	return &value
+}
+
+// LedgerController controller for the cash movements and the ledger of the accounts
+type LedgerController struct {
+	service *service.LedgerService
+}
+
+// NewLedgerController builds a new instance of ledger controller
+func NewLedgerController(store db.Store, policy *service.Policy, feeRate decimal.Decimal) *LedgerController {
+	accountService := service.NewAccountService(store, policy)
+	return &LedgerController{
+		service: service.NewLedgerService(store, accountService, policy, feeRate),
+	}
+}
+
+func (controller *LedgerController) setupRoutes(router *gin.Engine, authRoutes gin.IRoutes, idempotency gin.HandlerFunc) {
+	authRoutes.GET(balancesPath, controller.listBalances)
+	authRoutes.GET(ledgerPath, controller.listJournal)
+	authRoutes.POST(depositsPath, idempotency, controller.deposit)
+	authRoutes.POST(withdrawalsPath, idempotency, controller.withdraw)
+}
+
+func (controller *LedgerController) listBalances(ctx *gin.Context) {
+	accountUUID, err := getAccountUUID(ctx)
+	if err != nil {
+		return
+	}
+	balances, err := controller.service.ListBalances(ctx.Request.Context(), accountUUID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, balances)
+}
+
+func (controller *LedgerController) listJournal(ctx *gin.Context) {
+	accountUUID, err := getAccountUUID(ctx)
+	if err != nil {
+		return
+	}
+	var req listJournalRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		invalidRequest(ctx, err)
+		return
+	}
+	filter := service.JournalFilter{Page: req.Page, PageSize: req.PageSize}
+	page, err := controller.service.ListJournal(ctx.Request.Context(), accountUUID, filter)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	if page.HasNextPage {
+		setNextPageLink(ctx, pageQueryParam, strconv.Itoa(int(page.Page)+1))
+	}
+	response := make([]ledgerEntryResponse, 0, len(page.Entries))
+	for _, entry := range page.Entries {
+		response = append(response, newLedgerEntryResponse(entry))
+	}
+	ctx.JSON(http.StatusOK, response)
+}
+
+func (controller *LedgerController) deposit(ctx *gin.Context) {
+	controller.moveCash(ctx, func(requestCtx context.Context, accountUUID uuid.UUID, amount decimal.Decimal) (service.LedgerEntry, error) {
+		return controller.service.Deposit(requestCtx, getActor(ctx), accountUUID, amount)
+	})
+}
+
+func (controller *LedgerController) withdraw(ctx *gin.Context) {
+	controller.moveCash(ctx, controller.service.Withdraw)
+}
+
+func (controller *LedgerController) moveCash(ctx *gin.Context,
+	move func(ctx context.Context, accountUUID uuid.UUID, amount decimal.Decimal) (service.LedgerEntry, error)) {
+	accountUUID, err := getAccountUUID(ctx)
+	if err != nil {
+		return
+	}
+	var req cashMovementRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		invalidRequest(ctx, err)
+		return
+	}
+	entry, err := move(ctx.Request.Context(), accountUUID, req.Amount)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, newLedgerEntryResponse(entry))
+}
+
+// getAccountUUID parses the account ID of the path, answering 400 when it's invalid
+func getAccountUUID(ctx *gin.Context) (uuid.UUID, error) {
+	idReq, err := getAccountIDRequest(ctx)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	accountUUID, err := parseUUID(idReq.ID)
+	if err != nil {
+		invalidRequest(ctx, err)
+	}
+	return accountUUID, err
+}
@@ -0,0 +1,453 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+	mockdb "github.com/valverdethiago/trading-api/db/mock"
+	db "github.com/valverdethiago/trading-api/db/sqlc"
+	"github.com/valverdethiago/trading-api/service"
+)
+
+func TestDeposit(t *testing.T) {
+	testCases := []struct {
+		name          string
+		account       db.Account
+		body          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:    "OK",
+			account: staffAccount,
+			body:    `{"amount":"150.25"}`,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccountById(gomock.Any(), gomock.Eq(account.AccountUuid)).
+					Times(1).
+					Return(account, nil)
+				store.EXPECT().
+					CreateJournalEntry(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(ctx context.Context, arg db.CreateJournalEntryParams) (db.JournalEntry, error) {
+						require.Equal(t, db.JournalEntryTypeDEPOSIT, arg.Type)
+						require.Equal(t, uuid.Nil, arg.TradeUuid)
+						return db.JournalEntry{JournalEntryUuid: uuid.New(), AccountUuid: arg.AccountUuid, Type: arg.Type}, nil
+					})
+				expectPostings(t, store, map[db.LedgerAccountType]string{
+					db.LedgerAccountTypeCASH: "150.25",
+					db.LedgerAccountTypeBANK: "-150.25",
+				})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+				var response ledgerEntryResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.Equal(t, db.JournalEntryTypeDEPOSIT, response.Type)
+				require.Nil(t, response.TradeUUID)
+				require.Len(t, response.Postings, 2)
+				require.Equal(t, account.AccountUuid, *response.Postings[0].AccountUUID)
+				require.Nil(t, response.Postings[1].AccountUUID)
+			},
+		}, {
+			name:       "Fractions Of Cents",
+			account:    staffAccount,
+			body:       `{"amount":10.001}`,
+			buildStubs: func(store *mockdb.MockStore) {},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		}, {
+			name:       "Negative Amount",
+			account:    staffAccount,
+			body:       `{"amount":-10}`,
+			buildStubs: func(store *mockdb.MockStore) {},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		}, {
+			name:    "Account Not Found",
+			account: staffAccount,
+			body:    `{"amount":10}`,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccountById(gomock.Any(), gomock.Eq(account.AccountUuid)).
+					Times(1).
+					Return(db.Account{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		}, {
+			name:    "Account Not Approved",
+			account: staffAccount,
+			body:    `{"amount":10}`,
+			buildStubs: func(store *mockdb.MockStore) {
+				pendingAccount := account
+				pendingAccount.Status = db.AccountStatusPENDING
+				store.EXPECT().
+					GetAccountById(gomock.Any(), gomock.Eq(account.AccountUuid)).
+					Times(1).
+					Return(pendingAccount, nil)
+				store.EXPECT().
+					CreateJournalEntry(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				requireProblem(t, recorder, http.StatusConflict, service.ErrDepositAccountNotApproved.Code)
+			},
+		}, {
+			name:    "Owner Not Staff",
+			account: account,
+			body:    `{"amount":10}`,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateJournalEntry(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				requireProblem(t, recorder, http.StatusForbidden, service.ErrForbidden.Code)
+			},
+		}, {
+			name:       "Another Account",
+			account:    createRandomAccount(),
+			body:       `{"amount":10}`,
+			buildStubs: func(store *mockdb.MockStore) {},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		testCase := testCases[i]
+
+		t.Run(testCase.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := newMockStore(ctrl)
+			testCase.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+			url := fmt.Sprintf("/accounts/%s/deposits", account.AccountUuid.String())
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewBufferString(testCase.body))
+			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, testCase.account)
+
+			server.router.ServeHTTP(recorder, request)
+			testCase.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestDepositRecordsAudit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	journalEntryUUID := uuid.New()
+	var recorded db.CreateAuditLogParams
+	store := newMockStoreWithoutAudit(ctrl)
+	store.EXPECT().
+		GetAccountById(gomock.Any(), gomock.Eq(account.AccountUuid)).
+		Times(1).
+		Return(account, nil)
+	store.EXPECT().
+		CreateJournalEntry(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(ctx context.Context, arg db.CreateJournalEntryParams) (db.JournalEntry, error) {
+			return db.JournalEntry{JournalEntryUuid: journalEntryUUID, AccountUuid: arg.AccountUuid, Type: arg.Type}, nil
+		})
+	expectPostings(t, store, map[db.LedgerAccountType]string{
+		db.LedgerAccountTypeCASH: "10",
+		db.LedgerAccountTypeBANK: "-10",
+	})
+	store.EXPECT().
+		CreateAuditLog(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(ctx context.Context, arg db.CreateAuditLogParams) (db.AuditLog, error) {
+			recorded = arg
+			return db.AuditLog{}, nil
+		})
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+	url := fmt.Sprintf("/accounts/%s/deposits", account.AccountUuid.String())
+	request, err := http.NewRequest(http.MethodPost, url, bytes.NewBufferString(`{"amount":10}`))
+	require.NoError(t, err)
+	addAuthorization(t, request, server.tokenMaker, staffAccount)
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusCreated, recorder.Code)
+	require.Equal(t, staffAccount.AccountUuid.String(), recorded.Actor)
+	require.Equal(t, string(service.AuditCreate), recorded.Action)
+	require.Equal(t, service.EntityJournalEntry, recorded.EntityType)
+	require.Equal(t, journalEntryUUID.String(), recorded.EntityID)
+	require.Equal(t, "null", string(recorded.Before))
+	require.Contains(t, string(recorded.After), `"postings"`)
+}
+
+func TestWithdraw(t *testing.T) {
+	testCases := []struct {
+		name          string
+		body          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: `{"amount":100}`,
+			buildStubs: func(store *mockdb.MockStore) {
+				expectBuyingPower(store, decimal.NewFromInt(100), 0)
+				store.EXPECT().
+					CreateJournalEntry(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(ctx context.Context, arg db.CreateJournalEntryParams) (db.JournalEntry, error) {
+						require.Equal(t, db.JournalEntryTypeWITHDRAWAL, arg.Type)
+						return db.JournalEntry{JournalEntryUuid: uuid.New(), AccountUuid: arg.AccountUuid, Type: arg.Type}, nil
+					})
+				store.EXPECT().
+					CreateLedgerPosting(gomock.Any(), gomock.Any()).
+					Times(2).
+					DoAndReturn(func(ctx context.Context, arg db.CreateLedgerPostingParams) (db.LedgerPosting, error) {
+						return db.LedgerPosting{PostingUuid: uuid.New(), Amount: arg.Amount}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+			},
+		}, {
+			name: "Insufficient Funds",
+			body: `{"amount":100.01}`,
+			buildStubs: func(store *mockdb.MockStore) {
+				expectBuyingPower(store, decimal.NewFromInt(100), 0)
+				store.EXPECT().
+					CreateJournalEntry(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		testCase := testCases[i]
+
+		t.Run(testCase.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := newMockStore(ctrl)
+			store.EXPECT().
+				GetAccountById(gomock.Any(), gomock.Eq(account.AccountUuid)).
+				Times(1).
+				Return(account, nil)
+			testCase.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+			url := fmt.Sprintf("/accounts/%s/withdrawals", account.AccountUuid.String())
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewBufferString(testCase.body))
+			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, account)
+
+			server.router.ServeHTTP(recorder, request)
+			testCase.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestListBalances(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	balances := []db.ListLedgerBalancesRow{
+		{Type: db.LedgerAccountTypeCASH, Asset: "USD", Balance: decimal.RequireFromString("998.99")},
+		{Type: db.LedgerAccountTypeSECURITIES, Asset: "AAPL", Balance: decimal.NewFromInt(10)},
+	}
+	store := newMockStore(ctrl)
+	store.EXPECT().
+		GetAccountById(gomock.Any(), gomock.Eq(account.AccountUuid)).
+		Times(1).
+		Return(account, nil)
+	store.EXPECT().
+		ListLedgerBalances(gomock.Any(), gomock.Eq(account.AccountUuid)).
+		Times(1).
+		Return(balances, nil)
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+	url := fmt.Sprintf("/accounts/%s/balances", account.AccountUuid.String())
+	request, err := http.NewRequest(http.MethodGet, url, nil)
+	require.NoError(t, err)
+	addAuthorization(t, request, server.tokenMaker, account)
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.JSONEq(t, `[{"type":"CASH","asset":"USD","balance":998.99},{"type":"SECURITIES","asset":"AAPL","balance":10}]`,
+		recorder.Body.String())
+}
+
+func TestListJournal(t *testing.T) {
+	entries := []db.JournalEntry{
+		{JournalEntryUuid: uuid.New(), AccountUuid: account.AccountUuid, Type: db.JournalEntryTypeDEPOSIT},
+		{JournalEntryUuid: uuid.New(), AccountUuid: account.AccountUuid, Type: db.JournalEntryTypeFILL, TradeUuid: trade.TradeUuid},
+	}
+	postings := []db.ListLedgerPostingsRow{
+		{JournalEntryUuid: entries[0].JournalEntryUuid, AccountUuid: account.AccountUuid, Type: db.LedgerAccountTypeCASH, Amount: decimal.NewFromInt(10)},
+		{JournalEntryUuid: entries[0].JournalEntryUuid, Type: db.LedgerAccountTypeBANK, Amount: decimal.NewFromInt(-10)},
+	}
+
+	testCases := []struct {
+		name          string
+		query         string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "Last Page",
+			query: "",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListJournalEntries(gomock.Any(), gomock.Eq(db.ListJournalEntriesParams{
+						AccountUuid: account.AccountUuid,
+						PageSize:    51,
+						PageOffset:  0,
+					})).
+					Times(1).
+					Return(entries, nil)
+				store.EXPECT().
+					ListLedgerPostings(gomock.Any(), gomock.Eq([]uuid.UUID{entries[0].JournalEntryUuid, entries[1].JournalEntryUuid})).
+					Times(1).
+					Return(postings, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Empty(t, recorder.Header().Get(linkHeaderKey))
+				var response []ledgerEntryResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.Len(t, response, 2)
+				require.Len(t, response[0].Postings, 2)
+				require.Nil(t, response[0].TradeUUID)
+				require.Empty(t, response[1].Postings)
+				require.Equal(t, trade.TradeUuid, *response[1].TradeUUID)
+			},
+		}, {
+			name:  "Next Page",
+			query: "?page=2&page_size=1",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListJournalEntries(gomock.Any(), gomock.Eq(db.ListJournalEntriesParams{
+						AccountUuid: account.AccountUuid,
+						PageSize:    2,
+						PageOffset:  1,
+					})).
+					Times(1).
+					Return(entries, nil)
+				store.EXPECT().
+					ListLedgerPostings(gomock.Any(), gomock.Eq([]uuid.UUID{entries[0].JournalEntryUuid})).
+					Times(1).
+					Return(postings, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Contains(t, recorder.Header().Get(linkHeaderKey), "page=3")
+				var response []ledgerEntryResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.Len(t, response, 1)
+			},
+		}, {
+			name:  "Empty",
+			query: "",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListJournalEntries(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, nil)
+				store.EXPECT().
+					ListLedgerPostings(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, "[]", recorder.Body.String())
+			},
+		}, {
+			name:       "Invalid Page Size",
+			query:      "?page_size=101",
+			buildStubs: func(store *mockdb.MockStore) {},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		testCase := testCases[i]
+
+		t.Run(testCase.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := newMockStore(ctrl)
+			store.EXPECT().
+				GetAccountById(gomock.Any(), gomock.Eq(account.AccountUuid)).
+				AnyTimes().
+				Return(account, nil)
+			testCase.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+			url := fmt.Sprintf("/accounts/%s/ledger%s", account.AccountUuid.String(), testCase.query)
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, account)
+
+			server.router.ServeHTTP(recorder, request)
+			testCase.checkResponse(t, recorder)
+		})
+	}
+}
+
+// expectPostings expects one posting per ledger account type, with the given amount
+func expectPostings(t *testing.T, store *mockdb.MockStore, amounts map[db.LedgerAccountType]string) {
+	ledgerAccounts := make(map[uuid.UUID]db.LedgerAccountType)
+	store.EXPECT().
+		UpsertLedgerAccount(gomock.Any(), gomock.Any()).
+		Times(len(amounts)).
+		DoAndReturn(func(ctx context.Context, arg db.UpsertLedgerAccountParams) (db.LedgerAccount, error) {
+			ledgerAccount := db.LedgerAccount{
+				LedgerAccountUuid: uuid.New(),
+				AccountUuid:       arg.AccountUuid,
+				Type:              arg.Type,
+				Asset:             arg.Asset,
+			}
+			ledgerAccounts[ledgerAccount.LedgerAccountUuid] = arg.Type
+			return ledgerAccount, nil
+		})
+	store.EXPECT().
+		CreateLedgerPosting(gomock.Any(), gomock.Any()).
+		Times(len(amounts)).
+		DoAndReturn(func(ctx context.Context, arg db.CreateLedgerPostingParams) (db.LedgerPosting, error) {
+			accountType := ledgerAccounts[arg.LedgerAccountUuid]
+			require.Equal(t, amounts[accountType], arg.Amount.String())
+			return db.LedgerPosting{
+				PostingUuid:       uuid.New(),
+				JournalEntryUuid:  arg.JournalEntryUuid,
+				LedgerAccountUuid: arg.LedgerAccountUuid,
+				Amount:            arg.Amount,
+			}, nil
+		})
+}
@@ -1,16 +1,199 @@
 package api
 
 import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+	"github.com/valverdethiago/trading-api/calendar"
+	mockdb "github.com/valverdethiago/trading-api/db/mock"
 	db "github.com/valverdethiago/trading-api/db/sqlc"
+	"github.com/valverdethiago/trading-api/execution"
+	mockexecution "github.com/valverdethiago/trading-api/execution/mock"
 	"github.com/valverdethiago/trading-api/util"
 )
 
+func newTestServer(t *testing.T, store db.Store) *Server {
+	venue := mockexecution.NewMockVenue(gomock.NewController(t))
+	venue.EXPECT().
+		Submit(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		Return(nil)
+	return newTestServerWithVenue(t, store, venue)
+}
+
+func newTestServerWithVenue(t *testing.T, store db.Store, venue execution.Venue) *Server {
+	return newTestServerWithCalendar(t, store, venue, calendar.AlwaysOpen())
+}
+
+func newTestServerWithCalendar(t *testing.T, store db.Store, venue execution.Venue, marketCalendar *calendar.Calendar) *Server {
+	server, err := NewServer(newTestConfig(), store, venue, marketCalendar, nil)
+	require.NoError(t, err)
+	server.onServerError = func(err error) {
+		if !isInjectedFault(err) {
+			t.Errorf("unexpected server error: %v", err)
+		}
+	}
+	return server
+}
+
+// isInjectedFault tells whether a server error comes from a failure the test injected, the only
+// ones allowed to be answered with a 5xx
+func isInjectedFault(err error) bool {
+	return errors.Is(err, sql.ErrConnDone) || errors.Is(err, context.DeadlineExceeded) ||
+		errors.Is(err, context.Canceled)
+}
+
+// newClosedCalendar creates a calendar whose market never opens
+func newClosedCalendar(t *testing.T) *calendar.Calendar {
+	marketCalendar, err := calendar.New(calendar.Config{
+		Timezone:    "UTC",
+		TradingDays: []string{},
+		Regular:     calendar.HoursConfig{Open: "09:30", Close: "16:00"},
+	})
+	require.NoError(t, err)
+	return marketCalendar
+}
+
+func newTestConfig() util.Config {
+	return util.Config{
+		TokenSymmetricKey:   util.RandomString(32),
+		AccessTokenDuration: time.Minute,
+		DefaultTickSize:     "0.01",
+		TradeFeeRate:        "0.001",
+		IdempotencyKeyTTL:   time.Hour,
+		OpenAPIValidation:   openAPIValidationResponses,
+	}
+}
+
+// newMockStore creates a mock store that runs transactions against the mock itself and takes any
+// entry of the audit log
+func newMockStore(ctrl *gomock.Controller) *mockdb.MockStore {
+	store := newMockStoreWithoutAudit(ctrl)
+	store.EXPECT().
+		CreateAuditLog(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		Return(db.AuditLog{}, nil)
+	return store
+}
+
+// newMockStoreWithoutAudit creates a mock store that runs transactions against the mock itself and
+// knows the staff account as the only staff, leaving the expectations on the audit log to the test
+func newMockStoreWithoutAudit(ctrl *gomock.Controller) *mockdb.MockStore {
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		ExecTx(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		DoAndReturn(func(ctx context.Context, fn func(db.Querier) error) error {
+			return fn(store)
+		})
+	store.EXPECT().
+		GetAccountRole(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		DoAndReturn(func(ctx context.Context, accountUUID uuid.UUID) (db.AccountRole, error) {
+			if accountUUID == staffAccount.AccountUuid {
+				return db.AccountRoleSTAFF, nil
+			}
+			return db.AccountRoleUSER, nil
+		})
+	return store
+}
+
+// actedBy is the created_by or updated_by recorded for the changes made by the given account
+func actedBy(actor db.Account) sql.NullString {
+	return sql.NullString{String: actor.AccountUuid.String(), Valid: true}
+}
+
+// expectBuyingPower lets the account spend up to cash and sell up to shares of any symbol, with no
+// risk limits of its own and the risk evaluations logged
+func expectBuyingPower(store *mockdb.MockStore, cash decimal.Decimal, shares int64) {
+	cashAccount := db.LedgerAccount{LedgerAccountUuid: uuid.New(), Type: db.LedgerAccountTypeCASH}
+	securitiesAccount := db.LedgerAccount{LedgerAccountUuid: uuid.New(), Type: db.LedgerAccountTypeSECURITIES}
+	store.EXPECT().
+		UpsertLedgerAccount(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		DoAndReturn(func(ctx context.Context, arg db.UpsertLedgerAccountParams) (db.LedgerAccount, error) {
+			if arg.Type == db.LedgerAccountTypeCASH {
+				return cashAccount, nil
+			}
+			return securitiesAccount, nil
+		})
+	store.EXPECT().
+		GetLedgerAccountBalance(gomock.Any(), gomock.Eq(cashAccount.LedgerAccountUuid)).
+		AnyTimes().
+		Return(cash, nil)
+	store.EXPECT().
+		GetLedgerAccountBalance(gomock.Any(), gomock.Eq(securitiesAccount.LedgerAccountUuid)).
+		AnyTimes().
+		Return(decimal.NewFromInt(shares), nil)
+	store.EXPECT().
+		GetOpenBuyNotional(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		Return(decimal.Zero, nil)
+	store.EXPECT().
+		GetOpenSellQuantity(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		Return(int64(0), nil)
+	store.EXPECT().
+		GetRiskLimit(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		Return(db.RiskLimit{}, sql.ErrNoRows)
+	store.EXPECT().
+		ListLatestPrices(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		Return([]db.ListLatestPricesRow{}, nil)
+	store.EXPECT().
+		CreateRiskCheck(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		Return(db.RiskCheck{}, nil)
+}
+
+// expectEvents lets the services publish events to the outbox, delivered to the given webhooks
+func expectEvents(store *mockdb.MockStore, subscriptions ...db.WebhookSubscription) {
+	store.EXPECT().
+		ListWebhookSubscriptionsForEvent(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		Return(subscriptions, nil)
+	store.EXPECT().
+		CreateWebhookDelivery(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		Return(db.WebhookDelivery{}, nil)
+	store.EXPECT().
+		CreateOutboxEvent(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		Return(db.Outbox{}, nil)
+}
+
+// expectInstrument makes every symbol a tradable instrument with a cent tick and no lot size
+func expectInstrument(store *mockdb.MockStore) {
+	store.EXPECT().
+		GetInstrument(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		DoAndReturn(func(ctx context.Context, symbol string) (db.Instrument, error) {
+			return db.Instrument{
+				Symbol:   symbol,
+				TickSize: decimal.RequireFromString("0.01"),
+				LotSize:  1,
+				Tradable: true,
+				Currency: "USD",
+			}, nil
+		})
+}
+
 var account db.Account = createRandomAccount()
+var staffAccount db.Account = createRandomStaffAccount()
 var expectedAccount db.Account = db.Account{
 	AccountUuid: uuid.New(),
 	Username:    account.Username,
 	Email:       account.Email,
+	Role:        db.AccountRoleUSER,
+	Status:      db.AccountStatusPENDING,
 }
 var address db.Address = createRandomAddress()
 var expectedAddress = db.Address{
@@ -30,18 +213,24 @@ var trades = createRandomTradeList(10)
 var expectedCanceledTrade = db.Trade{
 	TradeUuid:   trade.TradeUuid,
 	AccountUuid: account.AccountUuid,
+	Symbol:      trade.Symbol,
 	Quantity:    trade.Quantity,
 	Side:        trade.Side,
 	Price:       trade.Price,
 	Status:      db.TradeStatusCANCELLED,
+	OrderType:   trade.OrderType,
+	TimeInForce: trade.TimeInForce,
 }
 var expectedSubmittedTrade = db.Trade{
 	TradeUuid:   trade.TradeUuid,
 	AccountUuid: account.AccountUuid,
+	Symbol:      trade.Symbol,
 	Quantity:    trade.Quantity,
 	Side:        trade.Side,
 	Price:       trade.Price,
 	Status:      db.TradeStatusSUBMITTED,
+	OrderType:   trade.OrderType,
+	TimeInForce: trade.TimeInForce,
 }
 
 func createRandomAccountList(size int64) []db.Account {
@@ -64,9 +253,17 @@ func createRandomAccount() db.Account {
 		AccountUuid: uuid.New(),
 		Username:    util.RandomUsername(),
 		Email:       util.RandomEmail(),
+		Role:        db.AccountRoleUSER,
+		Status:      db.AccountStatusAPPROVED,
 	}
 }
 
+func createRandomStaffAccount() db.Account {
+	staff := createRandomAccount()
+	staff.Role = db.AccountRoleSTAFF
+	return staff
+}
+
 func createRandomAddress() db.Address {
 	return db.Address{
 		Name:    util.RandomString(10),
@@ -78,11 +275,16 @@ func createRandomAddress() db.Address {
 }
 
 func createRandomTrade() db.Trade {
+	quantity := util.RandomInt(1, 1000)
 	return db.Trade{
-		TradeUuid: uuid.New(),
-		Symbol:    util.RandomNumericString(4),
-		Quantity:  util.RandomInt(1, 1000),
-		Price:     util.RandomFloat(1, 1000),
-		Side:      db.TradeSideBUY,
+		TradeUuid:         uuid.New(),
+		Symbol:            util.RandomSymbol(),
+		Quantity:          quantity,
+		RemainingQuantity: quantity,
+		Price:             decimal.NullDecimal{Decimal: util.RandomPrice(1, 1000), Valid: true},
+		Side:              db.TradeSideBUY,
+		Status:            db.TradeStatusSUBMITTED,
+		OrderType:         db.OrderTypeLIMIT,
+		TimeInForce:       db.TimeInForceGTC,
 	}
 }
@@ -0,0 +1,72 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/valverdethiago/trading-api/calendar"
+)
+
+const marketStatusPath = "/market/status"
+
+// marketStatusRequest query parameters to ask the market status at a given time instead of now
+type marketStatusRequest struct {
+	At time.Time `form:"at" time_format:"2006-01-02T15:04:05Z07:00"`
+}
+
+type marketStatusResponse struct {
+	Time      time.Time        `json:"time"`
+	Timezone  string           `json:"timezone"`
+	Session   calendar.Session `json:"session"`
+	IsOpen    bool             `json:"is_open"`
+	Holiday   string           `json:"holiday,omitempty"`
+	NextOpen  *time.Time       `json:"next_open,omitempty"`
+	NextClose *time.Time       `json:"next_close,omitempty"`
+}
+
+func newMarketStatusResponse(marketCalendar *calendar.Calendar, status calendar.Status) marketStatusResponse {
+	response := marketStatusResponse{
+		Time:     status.Time,
+		Timezone: marketCalendar.Location().String(),
+		Session:  status.Session,
+		IsOpen:   status.Session != calendar.SessionClosed,
+		Holiday:  status.Holiday,
+	}
+	if !status.NextOpen.IsZero() {
+		response.NextOpen = &status.NextOpen
+	}
+	if !status.NextClose.IsZero() {
+		response.NextClose = &status.NextClose
+	}
+	return response
+}
+
+// MarketController controller for the trading calendar of the market
+type MarketController struct {
+	calendar *calendar.Calendar
+}
+
+// NewMarketController builds a new instance of market controller
+func NewMarketController(marketCalendar *calendar.Calendar) *MarketController {
+	return &MarketController{
+		calendar: marketCalendar,
+	}
+}
+
+func (controller *MarketController) setupRoutes(router *gin.Engine) {
+	router.GET(marketStatusPath, controller.getMarketStatus)
+}
+
+func (controller *MarketController) getMarketStatus(ctx *gin.Context) {
+	var req marketStatusRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		invalidRequest(ctx, err)
+		return
+	}
+	at := req.At
+	if at.IsZero() {
+		at = time.Now()
+	}
+	ctx.JSON(http.StatusOK, newMarketStatusResponse(controller.calendar, controller.calendar.Status(at)))
+}
@@ -0,0 +1,100 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/valverdethiago/trading-api/calendar"
+)
+
+func TestGetMarketStatus(t *testing.T) {
+	marketCalendar, err := calendar.New(calendar.Config{
+		Timezone:   "America/New_York",
+		PreMarket:  &calendar.HoursConfig{Open: "04:00", Close: "09:30"},
+		Regular:    calendar.HoursConfig{Open: "09:30", Close: "16:00"},
+		AfterHours: &calendar.HoursConfig{Open: "16:00", Close: "20:00"},
+		Holidays:   []calendar.HolidayConfig{{Date: "2021-07-05", Name: "Independence Day"}},
+	})
+	if err != nil {
+		t.Skip("timezone database not available")
+	}
+	location := marketCalendar.Location()
+
+	testCases := []struct {
+		name          string
+		query         url.Values
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "Regular Session",
+			query: url.Values{"at": []string{time.Date(2021, 3, 1, 12, 0, 0, 0, location).Format(time.RFC3339)}},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				status := requireBodyMatchMarketStatus(t, recorder, calendar.SessionRegular, true)
+				require.Equal(t, "America/New_York", status.Timezone)
+				require.True(t, time.Date(2021, 3, 2, 9, 30, 0, 0, location).Equal(*status.NextOpen))
+				require.True(t, time.Date(2021, 3, 1, 16, 0, 0, 0, location).Equal(*status.NextClose))
+			},
+		}, {
+			name:  "After Hours",
+			query: url.Values{"at": []string{time.Date(2021, 3, 1, 17, 0, 0, 0, location).Format(time.RFC3339)}},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchMarketStatus(t, recorder, calendar.SessionAfterHours, true)
+			},
+		}, {
+			name:  "Holiday",
+			query: url.Values{"at": []string{time.Date(2021, 7, 5, 12, 0, 0, 0, location).Format(time.RFC3339)}},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				status := requireBodyMatchMarketStatus(t, recorder, calendar.SessionClosed, false)
+				require.Equal(t, "Independence Day", status.Holiday)
+				require.True(t, time.Date(2021, 7, 6, 9, 30, 0, 0, location).Equal(*status.NextOpen))
+			},
+		}, {
+			name: "Now",
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				var status marketStatusResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &status))
+				require.WithinDuration(t, time.Now(), status.Time, time.Minute)
+			},
+		}, {
+			name:  "Invalid Time",
+			query: url.Values{"at": []string{"yesterday"}},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			server := newTestServerWithCalendar(t, newMockStore(ctrl), nil, marketCalendar)
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodGet, "/market/status?"+testCase.query.Encode(), nil)
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			testCase.checkResponse(t, recorder)
+		})
+	}
+}
+
+func requireBodyMatchMarketStatus(t *testing.T, recorder *httptest.ResponseRecorder,
+	session calendar.Session, isOpen bool) marketStatusResponse {
+	var status marketStatusResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &status))
+	require.Equal(t, session, status.Session)
+	require.Equal(t, isOpen, status.IsOpen)
+	return status
+}
@@ -0,0 +1,154 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	db "github.com/valverdethiago/trading-api/db/sqlc"
+	"github.com/valverdethiago/trading-api/service"
+	"github.com/valverdethiago/trading-api/token"
+)
+
+const (
+	authorizationHeaderKey  = "authorization"
+	authorizationTypeBearer = "bearer"
+	authorizationPayloadKey = "authorization_payload"
+	accessTokenQueryKey     = "access_token"
+	requestIDHeaderKey      = "X-Request-ID"
+	// maxRequestIDLength bounds the request IDs taken from the clients, longer ones being replaced
+	maxRequestIDLength = 128
+)
+
+// authMiddleware validates the bearer token and stores its payload on the request context, with the
+// current role of the account rather than the one it had when the token was issued
+func authMiddleware(tokenMaker token.Maker, store db.Querier) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		authorizationHeader := ctx.GetHeader(authorizationHeaderKey)
+		if len(authorizationHeader) == 0 {
+			err := errors.New("authorization header is not provided")
+			ctx.Error(unauthorized(err))
+			ctx.Abort()
+			return
+		}
+
+		fields := strings.Fields(authorizationHeader)
+		if len(fields) < 2 {
+			err := errors.New("invalid authorization header format")
+			ctx.Error(unauthorized(err))
+			ctx.Abort()
+			return
+		}
+
+		authorizationType := strings.ToLower(fields[0])
+		if authorizationType != authorizationTypeBearer {
+			err := fmt.Errorf("unsupported authorization type %s", authorizationType)
+			ctx.Error(unauthorized(err))
+			ctx.Abort()
+			return
+		}
+
+		payload, err := tokenMaker.VerifyToken(fields[1])
+		if err != nil {
+			ctx.Error(unauthorized(err))
+			ctx.Abort()
+			return
+		}
+		role, err := store.GetAccountRole(ctx.Request.Context(), payload.AccountUUID)
+		if err == sql.ErrNoRows {
+			ctx.Error(unauthorized(errors.New("the account of the token doesn't exist")))
+			ctx.Abort()
+			return
+		}
+		if err != nil {
+			ctx.Error(err)
+			ctx.Abort()
+			return
+		}
+		payload.Role = string(role)
+
+		ctx.Set(authorizationPayloadKey, payload)
+		origin := service.OriginFromContext(ctx.Request.Context())
+		origin.Actor = payload.AccountUUID.String()
+		setOrigin(ctx, origin)
+		ctx.Next()
+	}
+}
+
+// requestOriginMiddleware tags the request with the X-Request-ID header sent by the client, or a new
+// one, echoed in the response. The request ID and the client IP are recorded with the changes made by
+// the request, which are anonymous until the token is verified
+func requestOriginMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		requestID := ctx.GetHeader(requestIDHeaderKey)
+		if requestID == "" || len(requestID) > maxRequestIDLength {
+			requestID = uuid.New().String()
+		}
+		ctx.Header(requestIDHeaderKey, requestID)
+		setOrigin(ctx, service.Origin{
+			Actor:     service.AnonymousActor,
+			RequestID: requestID,
+			IP:        ctx.ClientIP(),
+		})
+		ctx.Next()
+	}
+}
+
+func setOrigin(ctx *gin.Context, origin service.Origin) {
+	ctx.Request = ctx.Request.WithContext(service.WithOrigin(ctx.Request.Context(), origin))
+}
+
+// queryTokenMiddleware takes the bearer token from the access_token query parameter when the
+// authorization header is missing, for the clients that can't set headers like browsers opening WebSockets
+func queryTokenMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		accessToken := ctx.Query(accessTokenQueryKey)
+		if ctx.GetHeader(authorizationHeaderKey) == "" && accessToken != "" {
+			ctx.Request.Header.Set(authorizationHeaderKey, authorizationTypeBearer+" "+accessToken)
+		}
+		ctx.Next()
+	}
+}
+
+// accountOwnershipMiddleware rejects calls to /accounts/:id/... not allowed by the policy
+func accountOwnershipMiddleware(policy *service.Policy) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ID := ctx.Param("id")
+		if ID == "" {
+			ctx.Next()
+			return
+		}
+		accountUUID, err := parseUUID(ID)
+		if err != nil {
+			invalidRequest(ctx, err)
+			ctx.Abort()
+			return
+		}
+		if err := policy.CanAccessAccount(getActor(ctx), accountUUID); err != nil {
+			ctx.Error(err)
+			ctx.Abort()
+			return
+		}
+		ctx.Next()
+	}
+}
+
+// unauthorized makes a service error of a failed authentication, keeping its message
+func unauthorized(err error) error {
+	return service.NewUnauthorizedError(codeUnauthorized, err.Error())
+}
+
+func getAuthorizationPayload(ctx *gin.Context) *token.Payload {
+	return ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+}
+
+func getActor(ctx *gin.Context) service.Actor {
+	payload := getAuthorizationPayload(ctx)
+	return service.Actor{
+		AccountUUID: payload.AccountUUID,
+		Role:        db.AccountRole(payload.Role),
+	}
+}
@@ -0,0 +1,1096 @@
+package api
+
+import (
+	"net/http"
+	"regexp"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/valverdethiago/trading-api/calendar"
+	db "github.com/valverdethiago/trading-api/db/sqlc"
+	"github.com/valverdethiago/trading-api/openapi"
+	"github.com/valverdethiago/trading-api/service"
+	"github.com/valverdethiago/trading-api/stream"
+)
+
+const (
+	openAPIPath = "/openapi.json"
+	docsPath    = "/docs"
+	bearerAuth  = "bearerAuth"
+	apiTitle    = "Stock Trading API"
+	apiVersion  = "1.0.0"
+	// statusClientClosedRequestText describes the non standard status, unknown to net/http
+	statusClientClosedRequestText = "Client Closed Request"
+)
+
+var (
+	ginPathParamPattern = regexp.MustCompile(`:([A-Za-z]+)`)
+	bearerSecurity      = []openapi.SecurityRequirement{{bearerAuth: {}}}
+	// decimalPattern matches the decimals written as strings, accepted wherever a number is
+	decimalPattern = `^-?[0-9]+(\.[0-9]+)?$`
+)
+
+// pathParameters describes the parameters of the paths, added to the operations of the paths using them
+var pathParameters = map[string]openapi.Parameter{
+	"id":           pathParameter("id", "UUID of the account", openapi.UUID()),
+	"tradeID":      pathParameter("tradeID", "UUID of the trade", openapi.UUID()),
+	"webhookID":    pathParameter("webhookID", "UUID of the webhook", openapi.UUID()),
+	"deadLetterID": pathParameter("deadLetterID", "UUID of the dead letter", openapi.UUID()),
+	"symbol":       pathParameter("symbol", "Ticker of the instrument, case insensitive", openapi.NonEmptyString()),
+}
+
+// newOpenAPIDocument describes every route of the API. Routes and document are checked against each
+// other by the tests, and the requests and responses of the tests against the document
+func newOpenAPIDocument() *openapi.Document {
+	document := openapi.NewDocument(openapi.Info{
+		Title: apiTitle,
+		Description: "Accounts, trades, ledger and risk of a stock trading platform. Every response echoes the " +
+			"X-Request-ID header of the request, or a new one when missing.",
+		Version: apiVersion,
+	})
+	document.Components.SecuritySchemes[bearerAuth] = openapi.SecurityScheme{
+		Type:         "http",
+		Scheme:       "bearer",
+		BearerFormat: "JWT",
+		Description:  "Access token returned by POST /login",
+	}
+	addOpenAPISchemas(document.Components.Schemas)
+
+	addOperation(document, http.MethodGet, openAPIPath, &openapi.Operation{
+		OperationID: "getOpenAPIDocument",
+		Summary:     "OpenAPI document of the API",
+		Tags:        []string{"Documentation"},
+		Responses:   responses(http.StatusOK, jsonResponse("The OpenAPI 3 document", openapi.Any("OpenAPI document"))),
+	})
+	addOperation(document, http.MethodGet, docsPath, &openapi.Operation{
+		OperationID: "getDocs",
+		Summary:     "Browsable documentation of the API",
+		Tags:        []string{"Documentation"},
+		Responses: responses(http.StatusOK, openapi.Response{
+			Description: "HTML page rendering the OpenAPI document",
+			Content:     map[string]openapi.MediaType{"text/html": {Schema: openapi.String()}},
+		}),
+	})
+
+	addAccountOperations(document)
+	addAddressOperations(document)
+	addTradeOperations(document)
+	addLedgerOperations(document)
+	addPositionOperations(document)
+	addInstrumentOperations(document)
+	addWebhookOperations(document)
+	addRiskOperations(document)
+
+	addOperation(document, http.MethodGet, auditLogPath, &openapi.Operation{
+		OperationID: "listAuditLog",
+		Summary:     "Query the audit log, staff only",
+		Description: "Lists the changes newest first, by entity or by actor.",
+		Tags:        []string{"Audit"},
+		Security:    bearerSecurity,
+		Parameters: []openapi.Parameter{
+			queryParameter("entity_type", "Type of the changed entity, required with entity_id",
+				openapi.Enum(service.EntityAccount, service.EntityAddress, service.EntityTrade,
+					service.EntityInstrument, service.EntityRiskLimit, service.EntityWebhook,
+					service.EntityWebhookDeadLetter, service.EntityJournalEntry)),
+			queryParameter("entity_id", "ID of the changed entity", openapi.String()),
+			queryParameter("actor", "Who made the changes: an account UUID, anonymous or system", openapi.String()),
+			pageParameter(),
+			pageSizeParameter(service.DefaultAuditPageSize),
+		},
+		Responses: responses(http.StatusOK,
+			withHeaders(jsonResponse("One page of the changes", openapi.ArrayOf(openapi.Ref("AuditLogEntry"))),
+				linkHeaderKey),
+			http.StatusForbidden),
+	})
+	addOperation(document, http.MethodGet, streamPath, &openapi.Operation{
+		OperationID: "streamEvents",
+		Summary:     "Stream the events of the account over a WebSocket",
+		Description: "Upgrades to a WebSocket sending StreamMessage objects. Browsers may pass the token in " +
+			"the access_token query parameter instead of the Authorization header.",
+		Tags:     []string{"Stream"},
+		Security: bearerSecurity,
+		Parameters: []openapi.Parameter{
+			queryParameter("since", "Resume after this sequence", openapi.Integer().WithMinimum(0)),
+			queryParameter(accessTokenQueryKey, "Access token, when the Authorization header can't be set",
+				openapi.String()),
+		},
+		Responses: responses(http.StatusSwitchingProtocols, emptyResponse("Switched to the WebSocket protocol"),
+			http.StatusNotFound),
+	})
+	addOperation(document, http.MethodGet, marketStatusPath, &openapi.Operation{
+		OperationID: "getMarketStatus",
+		Summary:     "Trading session of the market",
+		Tags:        []string{"Market"},
+		Parameters: []openapi.Parameter{
+			queryParameter("at", "Time of the status, now by default", openapi.DateTime()),
+		},
+		Responses: responses(http.StatusOK, jsonResponse("The market status", openapi.Ref("MarketStatus"))),
+	})
+	return document
+}
+
+func addAccountOperations(document *openapi.Document) {
+	addOperation(document, http.MethodPost, accountsPath, idempotent(&openapi.Operation{
+		OperationID: "createAccount",
+		Summary:     "Sign up",
+		Description: "Creates a pending account, to be approved by the staff once it has an address.",
+		Tags:        []string{"Accounts"},
+		RequestBody: jsonBody(openapi.Ref("CreateAccountRequest")),
+		Responses:   responses(http.StatusCreated, jsonResponse("The account created", openapi.Ref("Account"))),
+	}))
+	addOperation(document, http.MethodPost, loginPath, &openapi.Operation{
+		OperationID: "login",
+		Summary:     "Authenticate an account",
+		Tags:        []string{"Accounts"},
+		RequestBody: jsonBody(openapi.Ref("LoginRequest")),
+		Responses: responses(http.StatusOK, jsonResponse("The access token", openapi.Ref("LoginResponse")),
+			http.StatusUnauthorized),
+	})
+	addOperation(document, http.MethodGet, accountsPath, &openapi.Operation{
+		OperationID: "listAccounts",
+		Summary:     "Search the accounts, staff only",
+		Tags:        []string{"Accounts"},
+		Security:    bearerSecurity,
+		Parameters: []openapi.Parameter{
+			queryParameter("username", "Prefix of the username", openapi.String()),
+			queryParameter("email", "Email", openapi.String()),
+			queryParameter("state", "State of the address", &openapi.Schema{Type: "string", MinLength: 2, MaxLength: 2}),
+			queryParameter("created_from", "Accounts created from this time on", openapi.DateTime()),
+			queryParameter("created_to", "Accounts created before this time", openapi.DateTime()),
+			queryParameter("q", "Full text search on the username and email", openapi.String()),
+			pageParameter(),
+			pageSizeParameter(service.DefaultAccountPageSize),
+		},
+		Responses: responses(http.StatusOK,
+			withHeaders(jsonResponse("One page of the accounts", openapi.ArrayOf(openapi.Ref("Account"))),
+				totalCountHeaderKey, linkHeaderKey),
+			http.StatusForbidden),
+	})
+	addOperation(document, http.MethodGet, accountsPathByID, &openapi.Operation{
+		OperationID: "getAccount",
+		Summary:     "Get an account",
+		Tags:        []string{"Accounts"},
+		Security:    bearerSecurity,
+		Responses: responses(http.StatusOK, jsonResponse("The account", openapi.Ref("Account")),
+			http.StatusNotFound),
+	})
+	addOperation(document, http.MethodPut, accountRolePath, &openapi.Operation{
+		OperationID: "updateAccountRole",
+		Summary:     "Change the role of an account, staff only",
+		Tags:        []string{"Accounts"},
+		Security:    bearerSecurity,
+		RequestBody: jsonBody(openapi.Ref("UpdateAccountRoleRequest")),
+		Responses: responses(http.StatusOK, jsonResponse("The account updated", openapi.Ref("Account")),
+			http.StatusNotFound),
+	})
+	addOperation(document, http.MethodPost, accountApprovePath, &openapi.Operation{
+		OperationID: "approveAccount",
+		Summary:     "Approve a pending account with an address, staff only",
+		Tags:        []string{"Accounts"},
+		Security:    bearerSecurity,
+		Responses: responses(http.StatusOK, jsonResponse("The account approved", openapi.Ref("Account")),
+			http.StatusNotFound, http.StatusConflict),
+	})
+	addOperation(document, http.MethodPost, accountDeactivatePath, &openapi.Operation{
+		OperationID: "deactivateAccount",
+		Summary:     "Deactivate an account and cancel its open trades, staff only",
+		Tags:        []string{"Accounts"},
+		Security:    bearerSecurity,
+		Responses: responses(http.StatusOK, jsonResponse("The account deactivated", openapi.Ref("Account")),
+			http.StatusNotFound, http.StatusConflict),
+	})
+}
+
+func addAddressOperations(document *openapi.Document) {
+	addOperation(document, http.MethodGet, addressPath, &openapi.Operation{
+		OperationID: "getAddress",
+		Summary:     "Get the address of an account",
+		Tags:        []string{"Addresses"},
+		Security:    bearerSecurity,
+		Responses: responses(http.StatusOK, jsonResponse("The address", openapi.Ref("Address")),
+			http.StatusNotFound),
+	})
+	addOperation(document, http.MethodPut, addressPath, &openapi.Operation{
+		OperationID: "createAddress",
+		Summary:     "Create the address of an account",
+		Tags:        []string{"Addresses"},
+		Security:    bearerSecurity,
+		RequestBody: jsonBody(openapi.Ref("AddressRequest")),
+		Responses: responses(http.StatusCreated, jsonResponse("The address created", openapi.Ref("Address")),
+			http.StatusNotFound, http.StatusConflict),
+	})
+	addOperation(document, http.MethodPost, addressPath, &openapi.Operation{
+		OperationID: "updateAddress",
+		Summary:     "Replace the address of an account",
+		Tags:        []string{"Addresses"},
+		Security:    bearerSecurity,
+		RequestBody: jsonBody(openapi.Ref("AddressRequest")),
+		Responses: responses(http.StatusCreated, jsonResponse("The address replaced", openapi.Ref("Address")),
+			http.StatusNotFound),
+	})
+}
+
+func addTradeOperations(document *openapi.Document) {
+	addOperation(document, http.MethodPost, tradesPath, idempotent(&openapi.Operation{
+		OperationID: "createTrade",
+		Summary:     "Place a trade",
+		Description: "Checks the order against the instrument, the trading session and the risk limits before " +
+			"submitting it to the venue.",
+		Tags:        []string{"Trades"},
+		Security:    bearerSecurity,
+		RequestBody: jsonBody(openapi.Ref("TradeRequest")),
+		Responses: responses(http.StatusCreated, jsonResponse("The trade placed", openapi.Ref("Trade")),
+			http.StatusNotFound, http.StatusConflict),
+	}))
+	// a risk rejection lists the failed rules in the violations of the problem
+	createTrade := document.Operation(http.MethodPost, openAPIPathOf(tradesPath))
+	createTrade.Responses[strconv.Itoa(http.StatusUnprocessableEntity)] = problemResponse(
+		"Unknown symbol, closed market, reused idempotency key or order rejected by the risk checks")
+	addOperation(document, http.MethodGet, tradesPath, &openapi.Operation{
+		OperationID: "listTrades",
+		Summary:     "List the trades of an account",
+		Tags:        []string{"Trades"},
+		Security:    bearerSecurity,
+		Parameters: []openapi.Parameter{
+			queryParameter("status", "Status of the trades", tradeStatusSchema()),
+			queryParameter("side", "Side of the trades", tradeSideSchema()),
+			queryParameter("symbol", "Symbol of the trades", openapi.String()),
+			queryParameter("created_from", "Trades created from this time on", openapi.DateTime()),
+			queryParameter("created_to", "Trades created before this time", openapi.DateTime()),
+			queryParameter("sort", "Order of the trades", openapi.Enum("created_date", sortByCreatedDateRev)),
+			queryParameter("limit", "Size of the page", openapi.Integer().WithMinimum(1).WithMaximum(100)),
+			queryParameter(cursorQueryParam, "Cursor of the page, from the X-Next-Cursor header", openapi.String()),
+		},
+		Responses: responses(http.StatusOK,
+			withHeaders(jsonResponse("One page of the trades", openapi.ArrayOf(openapi.Ref("Trade"))),
+				nextCursorHeaderKey, linkHeaderKey),
+			http.StatusNotFound),
+	})
+	addOperation(document, http.MethodGet, tradesPathByID, &openapi.Operation{
+		OperationID: "getTrade",
+		Summary:     "Get a trade",
+		Tags:        []string{"Trades"},
+		Security:    bearerSecurity,
+		Responses: responses(http.StatusOK, jsonResponse("The trade", openapi.Ref("Trade")),
+			http.StatusNotFound),
+	})
+	addOperation(document, http.MethodDelete, tradesPathByID, &openapi.Operation{
+		OperationID: "cancelTrade",
+		Summary:     "Cancel an open trade",
+		Tags:        []string{"Trades"},
+		Security:    bearerSecurity,
+		Responses: responses(http.StatusAccepted, jsonResponse("The trade cancelled", openapi.Ref("Trade")),
+			http.StatusNotFound, http.StatusConflict),
+	})
+	addOperation(document, http.MethodPut, tradesPathByID, &openapi.Operation{
+		OperationID: "replaceTrade",
+		Summary:     "Replace the terms of an open trade",
+		Description: "Missing prices are removed from the trade.",
+		Tags:        []string{"Trades"},
+		Security:    bearerSecurity,
+		RequestBody: jsonBody(openapi.Ref("ReplaceTradeRequest")),
+		Responses: responses(http.StatusOK, jsonResponse("The trade amended", openapi.Ref("Trade")),
+			http.StatusNotFound, http.StatusConflict, http.StatusUnprocessableEntity),
+	})
+	addOperation(document, http.MethodPatch, tradesPathByID, &openapi.Operation{
+		OperationID: "patchTrade",
+		Summary:     "Amend some terms of an open trade",
+		Description: "Missing terms are left unchanged.",
+		Tags:        []string{"Trades"},
+		Security:    bearerSecurity,
+		RequestBody: jsonBody(openapi.Ref("PatchTradeRequest")),
+		Responses: responses(http.StatusOK, jsonResponse("The trade amended", openapi.Ref("Trade")),
+			http.StatusNotFound, http.StatusConflict, http.StatusUnprocessableEntity),
+	})
+	addOperation(document, http.MethodGet, executionsPath, &openapi.Operation{
+		OperationID: "listTradeExecutions",
+		Summary:     "List the fills of a trade, oldest first",
+		Tags:        []string{"Trades"},
+		Security:    bearerSecurity,
+		Responses: responses(http.StatusOK,
+			jsonResponse("The fills of the trade", openapi.ArrayOf(openapi.Ref("TradeExecution"))),
+			http.StatusNotFound),
+	})
+	addOperation(document, http.MethodGet, versionsPath, &openapi.Operation{
+		OperationID: "listTradeVersions",
+		Summary:     "List the successive terms of a trade",
+		Tags:        []string{"Trades"},
+		Security:    bearerSecurity,
+		Responses: responses(http.StatusOK,
+			jsonResponse("The versions of the trade", openapi.ArrayOf(openapi.Ref("TradeVersion"))),
+			http.StatusNotFound),
+	})
+}
+
+func addLedgerOperations(document *openapi.Document) {
+	addOperation(document, http.MethodGet, balancesPath, &openapi.Operation{
+		OperationID: "listBalances",
+		Summary:     "List the balances of an account",
+		Tags:        []string{"Ledger"},
+		Security:    bearerSecurity,
+		Responses: responses(http.StatusOK,
+			jsonResponse("The balances per ledger account", openapi.ArrayOf(openapi.Ref("Balance"))),
+			http.StatusNotFound),
+	})
+	addOperation(document, http.MethodGet, ledgerPath, &openapi.Operation{
+		OperationID: "listJournal",
+		Summary:     "List the journal entries of an account, oldest first",
+		Tags:        []string{"Ledger"},
+		Security:    bearerSecurity,
+		Parameters:  []openapi.Parameter{pageParameter(), pageSizeParameter(service.DefaultJournalPageSize)},
+		Responses: responses(http.StatusOK,
+			withHeaders(jsonResponse("One page of the journal", openapi.ArrayOf(openapi.Ref("LedgerEntry"))),
+				linkHeaderKey),
+			http.StatusNotFound),
+	})
+	addOperation(document, http.MethodPost, depositsPath, idempotent(&openapi.Operation{
+		OperationID: "deposit",
+		Summary:     "Deposit cash into an approved account, staff only",
+		Tags:        []string{"Ledger"},
+		Security:    bearerSecurity,
+		RequestBody: jsonBody(openapi.Ref("CashMovementRequest")),
+		Responses: responses(http.StatusCreated, jsonResponse("The journal entry", openapi.Ref("LedgerEntry")),
+			http.StatusForbidden, http.StatusNotFound),
+	}))
+	addOperation(document, http.MethodPost, withdrawalsPath, idempotent(&openapi.Operation{
+		OperationID: "withdraw",
+		Summary:     "Withdraw cash",
+		Tags:        []string{"Ledger"},
+		Security:    bearerSecurity,
+		RequestBody: jsonBody(openapi.Ref("CashMovementRequest")),
+		Responses: responses(http.StatusCreated, jsonResponse("The journal entry", openapi.Ref("LedgerEntry")),
+			http.StatusNotFound),
+	}))
+	addOperation(document, http.MethodGet, pnlPath, &openapi.Operation{
+		OperationID: "getPnL",
+		Summary:     "Realized and unrealized P&L of an account",
+		Description: pnlDescription,
+		Tags:        []string{"Ledger"},
+		Security:    bearerSecurity,
+		Parameters: []openapi.Parameter{
+			queryParameter("from", "Start of the period", openapi.DateTime()),
+			queryParameter("to", "End of the period", openapi.DateTime()),
+		},
+		Responses: responses(http.StatusOK, jsonResponse("The P&L report", openapi.Ref("PnL")),
+			http.StatusNotFound),
+	})
+}
+
+func addPositionOperations(document *openapi.Document) {
+	addOperation(document, http.MethodGet, positionsPath, &openapi.Operation{
+		OperationID: "listPositions",
+		Summary:     "List the positions of an account",
+		Tags:        []string{"Positions"},
+		Security:    bearerSecurity,
+		Responses: responses(http.StatusOK,
+			jsonResponse("The positions", openapi.ArrayOf(openapi.Ref("Position"))),
+			http.StatusNotFound),
+	})
+	addOperation(document, http.MethodGet, positionsPathBySymbol, &openapi.Operation{
+		OperationID: "getPosition",
+		Summary:     "Get the position of an account in a symbol",
+		Tags:        []string{"Positions"},
+		Security:    bearerSecurity,
+		Responses: responses(http.StatusOK, jsonResponse("The position", openapi.Ref("Position")),
+			http.StatusNotFound),
+	})
+}
+
+func addInstrumentOperations(document *openapi.Document) {
+	addOperation(document, http.MethodGet, instrumentsPath, &openapi.Operation{
+		OperationID: "listInstruments",
+		Summary:     "List the security master",
+		Tags:        []string{"Instruments"},
+		Security:    bearerSecurity,
+		Responses: responses(http.StatusOK,
+			jsonResponse("The instruments", openapi.ArrayOf(openapi.Ref("Instrument")))),
+	})
+	addOperation(document, http.MethodGet, instrumentsPathBySymbol, &openapi.Operation{
+		OperationID: "getInstrument",
+		Summary:     "Get an instrument",
+		Tags:        []string{"Instruments"},
+		Security:    bearerSecurity,
+		Responses: responses(http.StatusOK, jsonResponse("The instrument", openapi.Ref("Instrument")),
+			http.StatusNotFound),
+	})
+	addOperation(document, http.MethodPost, instrumentsPath, &openapi.Operation{
+		OperationID: "createInstrument",
+		Summary:     "Create an instrument, staff only",
+		Tags:        []string{"Instruments"},
+		Security:    bearerSecurity,
+		RequestBody: jsonBody(openapi.Ref("CreateInstrumentRequest")),
+		Responses: responses(http.StatusCreated, jsonResponse("The instrument created", openapi.Ref("Instrument")),
+			http.StatusForbidden, http.StatusConflict),
+	})
+	addOperation(document, http.MethodPost, instrumentsImportPath, &openapi.Operation{
+		OperationID: "importInstruments",
+		Summary:     "Load instruments from a CSV file, staff only",
+		Description: "The header names the columns: symbol, name and exchange are required, tick_size, " +
+			"lot_size, tradable and currency optional. Nothing is loaded when a line is invalid.",
+		Tags:     []string{"Instruments"},
+		Security: bearerSecurity,
+		RequestBody: &openapi.RequestBody{
+			Required: true,
+			Content:  map[string]openapi.MediaType{"text/csv": {Schema: openapi.String()}},
+		},
+		Responses: responses(http.StatusOK,
+			jsonResponse("How many instruments were loaded", openapi.Ref("ImportInstrumentsResponse")),
+			http.StatusForbidden),
+	})
+	addOperation(document, http.MethodPut, instrumentsPathBySymbol, &openapi.Operation{
+		OperationID: "updateInstrument",
+		Summary:     "Replace an instrument, staff only",
+		Tags:        []string{"Instruments"},
+		Security:    bearerSecurity,
+		RequestBody: jsonBody(openapi.Ref("InstrumentRequest")),
+		Responses: responses(http.StatusOK, jsonResponse("The instrument replaced", openapi.Ref("Instrument")),
+			http.StatusForbidden, http.StatusNotFound),
+	})
+	addOperation(document, http.MethodDelete, instrumentsPathBySymbol, &openapi.Operation{
+		OperationID: "deleteInstrument",
+		Summary:     "Delete an instrument, staff only",
+		Tags:        []string{"Instruments"},
+		Security:    bearerSecurity,
+		Responses: responses(http.StatusOK, jsonResponse("The instrument deleted", openapi.Ref("Instrument")),
+			http.StatusForbidden, http.StatusNotFound),
+	})
+}
+
+func addWebhookOperations(document *openapi.Document) {
+	addOperation(document, http.MethodPost, webhooksPath, &openapi.Operation{
+		OperationID: "createWebhook",
+		Summary:     "Subscribe a URL to the events of an account",
+		Description: "The secret signing the deliveries is only returned here.",
+		Tags:        []string{"Webhooks"},
+		Security:    bearerSecurity,
+		RequestBody: jsonBody(openapi.Ref("CreateWebhookRequest")),
+		Responses: responses(http.StatusCreated, jsonResponse("The webhook created", openapi.Ref("Webhook")),
+			http.StatusNotFound),
+	})
+	addOperation(document, http.MethodGet, webhooksPath, &openapi.Operation{
+		OperationID: "listWebhooks",
+		Summary:     "List the webhooks of an account",
+		Tags:        []string{"Webhooks"},
+		Security:    bearerSecurity,
+		Responses: responses(http.StatusOK, jsonResponse("The webhooks", openapi.ArrayOf(openapi.Ref("Webhook"))),
+			http.StatusNotFound),
+	})
+	addOperation(document, http.MethodGet, webhooksPathByID, &openapi.Operation{
+		OperationID: "getWebhook",
+		Summary:     "Get a webhook",
+		Tags:        []string{"Webhooks"},
+		Security:    bearerSecurity,
+		Responses: responses(http.StatusOK, jsonResponse("The webhook", openapi.Ref("Webhook")),
+			http.StatusNotFound),
+	})
+	addOperation(document, http.MethodPut, webhooksPathByID, &openapi.Operation{
+		OperationID: "updateWebhook",
+		Summary:     "Replace a webhook",
+		Tags:        []string{"Webhooks"},
+		Security:    bearerSecurity,
+		RequestBody: jsonBody(openapi.Ref("UpdateWebhookRequest")),
+		Responses: responses(http.StatusOK, jsonResponse("The webhook replaced", openapi.Ref("Webhook")),
+			http.StatusNotFound),
+	})
+	addOperation(document, http.MethodDelete, webhooksPathByID, &openapi.Operation{
+		OperationID: "deleteWebhook",
+		Summary:     "Delete a webhook",
+		Tags:        []string{"Webhooks"},
+		Security:    bearerSecurity,
+		Responses:   responses(http.StatusNoContent, emptyResponse("The webhook was deleted"), http.StatusNotFound),
+	})
+	addOperation(document, http.MethodGet, deadLettersPath, &openapi.Operation{
+		OperationID: "listDeadLetters",
+		Summary:     "List the deliveries of a webhook that ran out of attempts",
+		Tags:        []string{"Webhooks"},
+		Security:    bearerSecurity,
+		Parameters:  []openapi.Parameter{pageSizeParameter(service.DefaultDeadLetterPageSize)},
+		Responses: responses(http.StatusOK,
+			jsonResponse("The dead letters", openapi.ArrayOf(openapi.Ref("DeadLetter"))),
+			http.StatusNotFound),
+	})
+	addOperation(document, http.MethodPost, deadLetterReplayPath, &openapi.Operation{
+		OperationID: "replayDeadLetter",
+		Summary:     "Deliver a dead letter again, once",
+		Tags:        []string{"Webhooks"},
+		Security:    bearerSecurity,
+		Responses: responses(http.StatusAccepted,
+			jsonResponse("The delivery scheduled", openapi.Ref("WebhookDelivery")),
+			http.StatusNotFound, http.StatusConflict),
+	})
+}
+
+func addRiskOperations(document *openapi.Document) {
+	addOperation(document, http.MethodGet, riskLimitsPath, &openapi.Operation{
+		OperationID: "getRiskLimits",
+		Summary:     "Get the risk limits of an account",
+		Tags:        []string{"Risk"},
+		Security:    bearerSecurity,
+		Responses: responses(http.StatusOK,
+			jsonResponse("The limits of the account and those in effect", openapi.Ref("AccountRiskLimits")),
+			http.StatusNotFound),
+	})
+	addOperation(document, http.MethodPut, riskLimitsPath, &openapi.Operation{
+		OperationID: "updateRiskLimits",
+		Summary:     "Replace the risk limits of an account, staff only",
+		Description: "Missing limits fall back to the global ones.",
+		Tags:        []string{"Risk"},
+		Security:    bearerSecurity,
+		RequestBody: jsonBody(openapi.Ref("UpdateRiskLimitsRequest")),
+		Responses: responses(http.StatusOK,
+			jsonResponse("The limits of the account and those in effect", openapi.Ref("AccountRiskLimits")),
+			http.StatusNotFound),
+	})
+	addOperation(document, http.MethodGet, riskChecksPath, &openapi.Operation{
+		OperationID: "listRiskChecks",
+		Summary:     "List the latest risk evaluations of the orders of an account",
+		Tags:        []string{"Risk"},
+		Security:    bearerSecurity,
+		Parameters:  []openapi.Parameter{pageSizeParameter(service.DefaultRiskCheckPageSize)},
+		Responses: responses(http.StatusOK,
+			jsonResponse("The risk checks, newest first", openapi.ArrayOf(openapi.Ref("RiskCheck"))),
+			http.StatusNotFound),
+	})
+}
+
+// addOpenAPISchemas describes the bodies of the requests and responses
+func addOpenAPISchemas(schemas map[string]*openapi.Schema) {
+	schemas["FieldError"] = openapi.Object(map[string]*openapi.Schema{
+		"field":  openapi.String(),
+		"reason": openapi.String(),
+	}, "field", "reason")
+	schemas["Problem"] = openapi.Object(map[string]*openapi.Schema{
+		"type":       openapi.String(),
+		"title":      openapi.String(),
+		"status":     openapi.Integer(),
+		"detail":     openapi.String(),
+		"instance":   openapi.String(),
+		"code":       openapi.NonEmptyString(),
+		"errors":     openapi.ArrayOf(openapi.Ref("FieldError")),
+		"violations": openapi.ArrayOf(openapi.Ref("RiskViolation")),
+	}, "type", "title", "status", "detail", "instance", "code")
+	schemas["RiskViolation"] = openapi.Object(map[string]*openapi.Schema{
+		"rule": openapi.Enum(string(service.RiskRuleMaxOrderNotional), string(service.RiskRuleMaxDailyNotional),
+			string(service.RiskRuleMaxPositionQuantity), string(service.RiskRuleBuyingPower),
+			string(service.RiskRuleAvailableShares)),
+		"message": openapi.String(),
+		"limit":   openapi.Number(),
+		"value":   openapi.Number(),
+	}, "rule", "message", "limit", "value")
+	schemas["NullString"] = openapi.Object(map[string]*openapi.Schema{
+		"String": openapi.String(),
+		"Valid":  openapi.Boolean(),
+	}, "String", "Valid")
+	schemas["NullTime"] = openapi.Object(map[string]*openapi.Schema{
+		"Time":  openapi.DateTime(),
+		"Valid": openapi.Boolean(),
+	}, "Time", "Valid")
+
+	schemas["Account"] = openapi.Object(map[string]*openapi.Schema{
+		"account_uuid": openapi.UUID(),
+		"username":     openapi.String(),
+		"email":        openapi.String(),
+		"role":         openapi.Enum(string(db.AccountRoleSTAFF), string(db.AccountRoleUSER)),
+		"status": openapi.Enum(string(db.AccountStatusPENDING), string(db.AccountStatusAPPROVED),
+			string(db.AccountStatusINACTIVE)),
+		"created_date": openapi.Ref("NullTime"),
+		"updated_date": openapi.Ref("NullTime"),
+		"created_by":   openapi.Ref("NullString"),
+		"updated_by":   openapi.Ref("NullString"),
+	}, "account_uuid", "username", "email", "role", "status", "created_date", "updated_date", "created_by",
+		"updated_by")
+	schemas["CreateAccountRequest"] = openapi.Object(map[string]*openapi.Schema{
+		"username": openapi.NonEmptyString(),
+		"password": &openapi.Schema{Type: "string", MinLength: 6},
+		"email":    openapi.NonEmptyString(),
+		"address":  openapi.OneOf(openapi.Ref("AddressRequest")).WithNullable(),
+	}, "username", "password", "email")
+	schemas["LoginRequest"] = openapi.Object(map[string]*openapi.Schema{
+		"username": openapi.NonEmptyString(),
+		"password": openapi.NonEmptyString(),
+	}, "username", "password")
+	schemas["LoginResponse"] = openapi.Object(map[string]*openapi.Schema{
+		"access_token": openapi.String(),
+		"account":      openapi.Ref("Account"),
+	}, "access_token", "account")
+	schemas["UpdateAccountRoleRequest"] = openapi.Object(map[string]*openapi.Schema{
+		"role": openapi.Enum(string(db.AccountRoleSTAFF), string(db.AccountRoleUSER)),
+	}, "role")
+
+	schemas["AddressRequest"] = openapi.Object(map[string]*openapi.Schema{
+		"name":    openapi.NonEmptyString(),
+		"street":  openapi.NonEmptyString(),
+		"city":    openapi.NonEmptyString(),
+		"state":   openapi.NonEmptyString().WithDescription("Two letters code of the state"),
+		"zipcode": openapi.NonEmptyString(),
+	}, "name", "street", "city", "state", "zipcode")
+	schemas["Address"] = openapi.Object(map[string]*openapi.Schema{
+		"address_uuid": openapi.UUID(),
+		"name":         openapi.String(),
+		"street":       openapi.String(),
+		"city":         openapi.String(),
+		"state":        &openapi.Schema{Type: "string", MinLength: 2, MaxLength: 2},
+		"zipcode":      openapi.String(),
+		"account_uuid": openapi.UUID(),
+		"created_date": openapi.Ref("NullTime"),
+		"updated_date": openapi.Ref("NullTime"),
+		"created_by":   openapi.Ref("NullString"),
+		"updated_by":   openapi.Ref("NullString"),
+	}, "address_uuid", "name", "street", "city", "state", "zipcode", "account_uuid", "created_date",
+		"updated_date", "created_by", "updated_by")
+
+	schemas["Trade"] = openapi.Object(map[string]*openapi.Schema{
+		"trade_uuid":         openapi.UUID(),
+		"account_uuid":       openapi.UUID(),
+		"symbol":             openapi.String(),
+		"quantity":           openapi.Integer(),
+		"side":               tradeSideSchema(),
+		"price":              openapi.Number().WithNullable(),
+		"status":             tradeStatusSchema(),
+		"created_date":       openapi.Ref("NullTime"),
+		"updated_date":       openapi.Ref("NullTime"),
+		"created_by":         openapi.Ref("NullString"),
+		"updated_by":         openapi.Ref("NullString"),
+		"order_type":         orderTypeSchema(),
+		"time_in_force":      timeInForceSchema(),
+		"stop_price":         openapi.Number().WithNullable(),
+		"filled_quantity":    openapi.Integer(),
+		"remaining_quantity": openapi.Integer(),
+		"average_fill_price": openapi.Number().WithNullable(),
+		"version":            openapi.Integer(),
+	}, "trade_uuid", "account_uuid", "symbol", "quantity", "side", "price", "status", "created_date",
+		"updated_date", "created_by", "updated_by", "order_type", "time_in_force", "stop_price",
+		"filled_quantity", "remaining_quantity", "average_fill_price", "version")
+	schemas["TradeRequest"] = openapi.Object(map[string]*openapi.Schema{
+		"symbol":        openapi.NonEmptyString(),
+		"quantity":      openapi.Integer().WithMinimum(1),
+		"side":          tradeSideSchema(),
+		"order_type":    orderTypeSchema().WithDescription("LIMIT by default"),
+		"time_in_force": timeInForceSchema().WithDescription("GTC by default, DAY for market orders"),
+		"price":         positiveDecimalSchema().WithNullable(),
+		"stop_price":    positiveDecimalSchema().WithNullable(),
+	}, "symbol", "quantity", "side")
+	schemas["ReplaceTradeRequest"] = openapi.Object(map[string]*openapi.Schema{
+		"quantity":   openapi.Integer().WithMinimum(1),
+		"price":      positiveDecimalSchema().WithNullable(),
+		"stop_price": positiveDecimalSchema().WithNullable(),
+	}, "quantity")
+	schemas["PatchTradeRequest"] = openapi.Object(map[string]*openapi.Schema{
+		"quantity":   openapi.Integer().WithMinimum(1).WithNullable(),
+		"price":      positiveDecimalSchema().WithNullable(),
+		"stop_price": positiveDecimalSchema().WithNullable(),
+	})
+	schemas["TradeExecution"] = openapi.Object(map[string]*openapi.Schema{
+		"execution_uuid": openapi.UUID(),
+		"trade_uuid":     openapi.UUID(),
+		"quantity":       openapi.Integer(),
+		"price":          openapi.Number(),
+		"executed_date":  openapi.DateTime(),
+		"fee":            openapi.Number().WithDescription("Fee charged on the fill"),
+	}, "execution_uuid", "trade_uuid", "quantity", "price", "executed_date", "fee")
+	schemas["TradeVersion"] = openapi.Object(map[string]*openapi.Schema{
+		"trade_uuid":   openapi.UUID(),
+		"version":      openapi.Integer(),
+		"quantity":     openapi.Integer(),
+		"price":        openapi.Number().WithNullable(),
+		"stop_price":   openapi.Number().WithNullable(),
+		"created_date": openapi.DateTime(),
+	}, "trade_uuid", "version", "quantity", "price", "stop_price", "created_date")
+
+	schemas["Instrument"] = openapi.Object(map[string]*openapi.Schema{
+		"symbol":       openapi.String(),
+		"name":         openapi.String(),
+		"exchange":     openapi.String(),
+		"tick_size":    openapi.Number(),
+		"lot_size":     openapi.Integer(),
+		"tradable":     openapi.Boolean(),
+		"currency":     openapi.String(),
+		"created_date": openapi.DateTime(),
+		"updated_date": openapi.DateTime(),
+	}, "symbol", "name", "exchange", "tick_size", "lot_size", "tradable", "currency", "created_date",
+		"updated_date")
+	schemas["InstrumentRequest"] = openapi.Object(instrumentRequestProperties(), "name", "exchange")
+	createInstrumentProperties := instrumentRequestProperties()
+	createInstrumentProperties["symbol"] = openapi.NonEmptyString()
+	schemas["CreateInstrumentRequest"] = openapi.Object(createInstrumentProperties, "symbol", "name", "exchange")
+	schemas["ImportInstrumentsResponse"] = openapi.Object(map[string]*openapi.Schema{
+		"imported": openapi.Integer(),
+	}, "imported")
+
+	schemas["Balance"] = openapi.Object(map[string]*openapi.Schema{
+		"type":    ledgerAccountTypeSchema(),
+		"asset":   openapi.String(),
+		"balance": openapi.Number(),
+	}, "type", "asset", "balance")
+	schemas["LedgerPosting"] = openapi.Object(map[string]*openapi.Schema{
+		"account_uuid": openapi.UUID().WithDescription("Account of the posting, missing for the house accounts"),
+		"type":         ledgerAccountTypeSchema(),
+		"asset":        openapi.String(),
+		"amount":       openapi.Number(),
+	}, "type", "asset", "amount")
+	schemas["LedgerEntry"] = openapi.Object(map[string]*openapi.Schema{
+		"journal_entry_uuid": openapi.UUID(),
+		"type": openapi.Enum(string(db.JournalEntryTypeDEPOSIT), string(db.JournalEntryTypeWITHDRAWAL),
+			string(db.JournalEntryTypeFILL), string(db.JournalEntryTypeFEE)),
+		"trade_uuid":   openapi.UUID().WithDescription("Trade of the fills and fees"),
+		"description":  openapi.String(),
+		"created_date": openapi.DateTime(),
+		"postings":     openapi.ArrayOf(openapi.Ref("LedgerPosting")),
+	}, "journal_entry_uuid", "type", "description", "created_date", "postings")
+	schemas["CashMovementRequest"] = openapi.Object(map[string]*openapi.Schema{
+		"amount": decimalSchema(),
+	}, "amount")
+	schemas["Position"] = openapi.Object(map[string]*openapi.Schema{
+		"account_uuid": openapi.UUID(),
+		"symbol":       openapi.String(),
+		"quantity":     openapi.Integer(),
+		"average_cost": openapi.Number(),
+		"created_date": openapi.DateTime(),
+		"updated_date": openapi.DateTime(),
+	}, "account_uuid", "symbol", "quantity", "average_cost", "created_date", "updated_date")
+	schemas["ClosedLot"] = openapi.Object(map[string]*openapi.Schema{
+		"quantity":     openapi.Integer(),
+		"open_price":   openapi.Number(),
+		"close_price":  openapi.Number(),
+		"opened_date":  openapi.DateTime(),
+		"closed_date":  openapi.DateTime(),
+		"fees":         openapi.Number().WithDescription("Fees of the opening and closing fills on the quantity"),
+		"realized_pnl": openapi.Number().WithDescription("Realized P&L net of the fees"),
+	}, "quantity", "open_price", "close_price", "opened_date", "closed_date", "fees", "realized_pnl")
+	schemas["SymbolPnL"] = openapi.Object(map[string]*openapi.Schema{
+		"symbol":         openapi.String(),
+		"quantity":       openapi.Integer(),
+		"cost_basis":     openapi.Number().WithDescription("Cost of the lots still open, first in first out"),
+		"mark_price":     openapi.Number().WithNullable(),
+		"fees":           openapi.Number().WithDescription("Fees of the lots closed in the period"),
+		"realized_pnl":   openapi.Number().WithDescription("Realized P&L net of the fees"),
+		"unrealized_pnl": openapi.Number().WithDescription("Unrealized P&L before fees"),
+		"closed_lots":    openapi.ArrayOf(openapi.Ref("ClosedLot")),
+	}, "symbol", "quantity", "cost_basis", "mark_price", "fees", "realized_pnl", "unrealized_pnl", "closed_lots")
+	schemas["PnL"] = openapi.Object(map[string]*openapi.Schema{
+		"from":           openapi.DateTime(),
+		"to":             openapi.DateTime(),
+		"symbols":        openapi.ArrayOf(openapi.Ref("SymbolPnL")),
+		"realized_pnl":   openapi.Number(),
+		"unrealized_pnl": openapi.Number(),
+		"total_pnl":      openapi.Number(),
+	}, "symbols", "realized_pnl", "unrealized_pnl", "total_pnl")
+
+	schemas["RiskLimits"] = openapi.Object(map[string]*openapi.Schema{
+		"max_order_notional":    openapi.Number().WithNullable(),
+		"max_daily_notional":    openapi.Number().WithNullable(),
+		"max_position_quantity": openapi.Integer().WithNullable(),
+	}, "max_order_notional", "max_daily_notional", "max_position_quantity")
+	schemas["AccountRiskLimits"] = openapi.Object(map[string]*openapi.Schema{
+		"account":   openapi.Ref("RiskLimits"),
+		"effective": openapi.Ref("RiskLimits"),
+	}, "account", "effective")
+	schemas["UpdateRiskLimitsRequest"] = openapi.Object(map[string]*openapi.Schema{
+		"max_order_notional":    positiveDecimalSchema().WithNullable(),
+		"max_daily_notional":    positiveDecimalSchema().WithNullable(),
+		"max_position_quantity": openapi.Integer().WithMinimum(1).WithNullable(),
+	})
+	schemas["RiskCheck"] = openapi.Object(map[string]*openapi.Schema{
+		"risk_check_uuid": openapi.UUID(),
+		"trade_uuid":      openapi.UUID().WithDescription("Trade placed, missing when the order was rejected"),
+		"symbol":          openapi.String(),
+		"side":            tradeSideSchema(),
+		"quantity":        openapi.Integer(),
+		"notional":        openapi.Number().WithNullable(),
+		"passed":          openapi.Boolean(),
+		"violations":      openapi.ArrayOf(openapi.Ref("RiskViolation")),
+		"created_date":    openapi.DateTime(),
+	}, "risk_check_uuid", "symbol", "side", "quantity", "notional", "passed", "violations", "created_date")
+
+	schemas["Webhook"] = openapi.Object(map[string]*openapi.Schema{
+		"webhook_uuid": openapi.UUID(),
+		"url":          openapi.String(),
+		"events":       openapi.ArrayOf(eventTypeSchema()),
+		"active":       openapi.Boolean(),
+		"secret":       openapi.String().WithDescription("Key of the signatures, only returned on creation"),
+		"created_date": openapi.DateTime(),
+		"updated_date": openapi.DateTime(),
+	}, "webhook_uuid", "url", "events", "active", "created_date", "updated_date")
+	schemas["CreateWebhookRequest"] = openapi.Object(map[string]*openapi.Schema{
+		"url":    &openapi.Schema{Type: "string", Format: "uri", MinLength: 1},
+		"events": &openapi.Schema{Type: "array", Items: eventTypeSchema(), MinItems: 1},
+	}, "url", "events")
+	schemas["UpdateWebhookRequest"] = openapi.Object(map[string]*openapi.Schema{
+		"url":    &openapi.Schema{Type: "string", Format: "uri", MinLength: 1},
+		"events": &openapi.Schema{Type: "array", Items: eventTypeSchema(), MinItems: 1},
+		"active": openapi.Boolean().WithNullable().WithDescription("true by default"),
+	}, "url", "events")
+	schemas["DeadLetter"] = openapi.Object(map[string]*openapi.Schema{
+		"dead_letter_uuid": openapi.UUID(),
+		"event_uuid":       openapi.UUID(),
+		"event":            eventTypeSchema(),
+		"payload":          openapi.Any("Payload of the event as delivered"),
+		"attempts":         openapi.Integer(),
+		"response_status":  openapi.Integer().WithDescription("Status of the last attempt, if any answered"),
+		"last_error":       openapi.String(),
+		"replayed_date":    openapi.DateTime(),
+		"created_date":     openapi.DateTime(),
+	}, "dead_letter_uuid", "event_uuid", "event", "payload", "attempts", "last_error", "created_date")
+	schemas["WebhookDelivery"] = openapi.Object(map[string]*openapi.Schema{
+		"delivery_uuid":     openapi.UUID(),
+		"event_uuid":        openapi.UUID(),
+		"event":             eventTypeSchema(),
+		"next_attempt_date": openapi.DateTime(),
+	}, "delivery_uuid", "event_uuid", "event", "next_attempt_date")
+
+	schemas["AuditLogEntry"] = openapi.Object(map[string]*openapi.Schema{
+		"audit_log_uuid": openapi.UUID(),
+		"actor":          openapi.String(),
+		"action": openapi.Enum(string(service.AuditCreate), string(service.AuditUpdate),
+			string(service.AuditDelete)),
+		"entity_type": openapi.Enum(service.EntityAccount, service.EntityAddress, service.EntityTrade,
+			service.EntityInstrument, service.EntityRiskLimit, service.EntityWebhook,
+			service.EntityWebhookDeadLetter, service.EntityJournalEntry),
+		"entity_id":    openapi.String(),
+		"before":       openapi.Any("Entity before the change, null for a creation"),
+		"after":        openapi.Any("Entity after the change, null for a deletion"),
+		"request_id":   openapi.String(),
+		"ip":           openapi.String(),
+		"created_date": openapi.DateTime(),
+	}, "audit_log_uuid", "actor", "action", "entity_type", "entity_id", "before", "after", "created_date")
+	schemas["MarketStatus"] = openapi.Object(map[string]*openapi.Schema{
+		"time":     openapi.DateTime(),
+		"timezone": openapi.String(),
+		"session": openapi.Enum(string(calendar.SessionClosed), string(calendar.SessionPreMarket),
+			string(calendar.SessionRegular), string(calendar.SessionAfterHours)),
+		"is_open":    openapi.Boolean(),
+		"holiday":    openapi.String(),
+		"next_open":  openapi.DateTime(),
+		"next_close": openapi.DateTime(),
+	}, "time", "timezone", "session", "is_open")
+	schemas["StreamMessage"] = openapi.Object(map[string]*openapi.Schema{
+		"type":     openapi.Enum(stream.MessageEvent, stream.MessageHeartbeat, stream.MessageReset),
+		"sequence": openapi.Integer(),
+		"event":    openapi.Any("Event, as delivered to the webhooks"),
+		"date":     openapi.DateTime(),
+	}, "type", "sequence", "date")
+}
+
+func instrumentRequestProperties() map[string]*openapi.Schema {
+	return map[string]*openapi.Schema{
+		"name":      openapi.NonEmptyString(),
+		"exchange":  openapi.NonEmptyString(),
+		"tick_size": positiveDecimalSchema().WithNullable(),
+		"lot_size":  openapi.Integer().WithMinimum(0).WithDescription("1 when missing or zero"),
+		"tradable":  openapi.Boolean().WithNullable().WithDescription("true by default"),
+		"currency":  &openapi.Schema{Type: "string", MaxLength: 3, Description: "USD by default"},
+	}
+}
+
+func tradeStatusSchema() *openapi.Schema {
+	return openapi.Enum(string(db.TradeStatusSUBMITTED), string(db.TradeStatusPARTIALLY_FILLED),
+		string(db.TradeStatusCANCELLED), string(db.TradeStatusCOMPLETED), string(db.TradeStatusFAILED))
+}
+
+func tradeSideSchema() *openapi.Schema {
+	return openapi.Enum(string(db.TradeSideBUY), string(db.TradeSideSELL))
+}
+
+func orderTypeSchema() *openapi.Schema {
+	return openapi.Enum(string(db.OrderTypeMARKET), string(db.OrderTypeLIMIT), string(db.OrderTypeSTOP),
+		string(db.OrderTypeSTOP_LIMIT))
+}
+
+func timeInForceSchema() *openapi.Schema {
+	return openapi.Enum(string(db.TimeInForceDAY), string(db.TimeInForceGTC), string(db.TimeInForceIOC),
+		string(db.TimeInForceFOK))
+}
+
+func ledgerAccountTypeSchema() *openapi.Schema {
+	return openapi.Enum(string(db.LedgerAccountTypeCASH), string(db.LedgerAccountTypeSECURITIES),
+		string(db.LedgerAccountTypeBANK), string(db.LedgerAccountTypeMARKET), string(db.LedgerAccountTypeFEES))
+}
+
+func eventTypeSchema() *openapi.Schema {
+	eventTypes := make([]string, len(service.EventTypes))
+	for i, eventType := range service.EventTypes {
+		eventTypes[i] = string(eventType)
+	}
+	return openapi.Enum(eventTypes...)
+}
+
+// decimalSchema matches the decimals of the requests, read from numbers or strings
+func decimalSchema() *openapi.Schema {
+	return openapi.OneOf(openapi.Number(), &openapi.Schema{Type: "string", Pattern: decimalPattern})
+}
+
+func positiveDecimalSchema() *openapi.Schema {
+	return openapi.OneOf(openapi.Number().WithExclusiveMinimum(0),
+		&openapi.Schema{Type: "string", Pattern: decimalPattern})
+}
+
+// addOperation documents a route, adding the parameters of its path and the responses of the middlewares:
+// 400 for invalid parameters or bodies, 401 and 403 for the authentication and the ownership of the account,
+// 504 and 499 for the timeouts and 500
+func addOperation(document *openapi.Document, method string, ginPath string, operation *openapi.Operation) {
+	var parameters []openapi.Parameter
+	for _, match := range ginPathParamPattern.FindAllStringSubmatch(ginPath, -1) {
+		parameters = append(parameters, pathParameters[match[1]])
+	}
+	operation.Parameters = append(parameters, operation.Parameters...)
+	if len(operation.Parameters) > 0 || operation.RequestBody != nil {
+		addErrorResponse(operation, http.StatusBadRequest)
+	}
+	if len(operation.Security) > 0 {
+		addErrorResponse(operation, http.StatusUnauthorized)
+		if hasAccountID(ginPath) {
+			addErrorResponse(operation, http.StatusForbidden)
+		}
+	}
+	addErrorResponse(operation, http.StatusInternalServerError)
+	addErrorResponse(operation, http.StatusGatewayTimeout)
+	addErrorResponse(operation, statusClientClosedRequest)
+	document.AddOperation(method, openAPIPathOf(ginPath), operation)
+}
+
+func hasAccountID(ginPath string) bool {
+	for _, match := range ginPathParamPattern.FindAllStringSubmatch(ginPath, -1) {
+		if match[1] == "id" {
+			return true
+		}
+	}
+	return false
+}
+
+// idempotent documents the Idempotency-Key header of the operation and the errors of the keys
+func idempotent(operation *openapi.Operation) *openapi.Operation {
+	operation.Parameters = append(operation.Parameters, openapi.Parameter{
+		Name:        idempotencyKeyHeader,
+		In:          "header",
+		Description: "Key replaying the response of the first request sent with it",
+		Schema:      &openapi.Schema{Type: "string", MaxLength: maxIdempotencyKeyLength},
+	})
+	addErrorResponse(operation, http.StatusConflict)
+	addErrorResponse(operation, http.StatusUnprocessableEntity)
+	for status, response := range operation.Responses {
+		if status[0] == '2' {
+			operation.Responses[status] = withHeaders(response, idempotentReplayedHeader)
+		}
+	}
+	return operation
+}
+
+// responses documents the successful response of an operation and its errors
+func responses(status int, response openapi.Response, errorStatuses ...int) map[string]openapi.Response {
+	operation := &openapi.Operation{
+		Responses: map[string]openapi.Response{strconv.Itoa(status): response},
+	}
+	for _, errorStatus := range errorStatuses {
+		addErrorResponse(operation, errorStatus)
+	}
+	return operation.Responses
+}
+
+func addErrorResponse(operation *openapi.Operation, status int) {
+	key := strconv.Itoa(status)
+	if _, ok := operation.Responses[key]; ok {
+		return
+	}
+	description := http.StatusText(status)
+	if status == statusClientClosedRequest {
+		description = statusClientClosedRequestText
+	}
+	operation.Responses[key] = problemResponse(description)
+}
+
+// problemResponse documents an error answered with the problem details of RFC 7807
+func problemResponse(description string) openapi.Response {
+	return openapi.Response{
+		Description: description,
+		Content:     map[string]openapi.MediaType{openapi.ContentTypeProblemJSON: {Schema: openapi.Ref("Problem")}},
+	}
+}
+
+func jsonResponse(description string, schema *openapi.Schema) openapi.Response {
+	return openapi.Response{
+		Description: description,
+		Content:     map[string]openapi.MediaType{openapi.ContentTypeJSON: {Schema: schema}},
+	}
+}
+
+func emptyResponse(description string) openapi.Response {
+	return openapi.Response{Description: description}
+}
+
+// responseHeaders describes the headers set by the API
+var responseHeaders = map[string]openapi.Header{
+	linkHeaderKey:            {Description: "Link to the next page, when there may be one", Schema: openapi.String()},
+	totalCountHeaderKey:      {Description: "Count of the matching items", Schema: openapi.Integer()},
+	nextCursorHeaderKey:      {Description: "Cursor of the next page, when there may be one", Schema: openapi.String()},
+	idempotentReplayedHeader: {Description: "true when the response is replayed", Schema: openapi.Boolean()},
+}
+
+func withHeaders(response openapi.Response, names ...string) openapi.Response {
+	headers := make(map[string]openapi.Header, len(response.Headers)+len(names))
+	for name, header := range response.Headers {
+		headers[name] = header
+	}
+	for _, name := range names {
+		headers[name] = responseHeaders[name]
+	}
+	response.Headers = headers
+	return response
+}
+
+func jsonBody(schema *openapi.Schema) *openapi.RequestBody {
+	return &openapi.RequestBody{
+		Required: true,
+		Content:  map[string]openapi.MediaType{openapi.ContentTypeJSON: {Schema: schema}},
+	}
+}
+
+func pathParameter(name string, description string, schema *openapi.Schema) openapi.Parameter {
+	return openapi.Parameter{Name: name, In: "path", Description: description, Required: true, Schema: schema}
+}
+
+func queryParameter(name string, description string, schema *openapi.Schema) openapi.Parameter {
+	return openapi.Parameter{Name: name, In: "query", Description: description, Schema: schema}
+}
+
+func pageParameter() openapi.Parameter {
+	return queryParameter(pageQueryParam, "Page number, from 1", openapi.Integer().WithMinimum(1))
+}
+
+func pageSizeParameter(defaultSize int) openapi.Parameter {
+	return queryParameter("page_size", "Size of the page, "+strconv.Itoa(defaultSize)+" by default",
+		openapi.Integer().WithMinimum(1).WithMaximum(100))
+}
+
+// openAPIPathOf writes a route like /accounts/:id as the path template /accounts/{id}
+func openAPIPathOf(ginPath string) string {
+	return ginPathParamPattern.ReplaceAllString(ginPath, "{$1}")
+}
+
+// DocsController serves the OpenAPI document of the API and a page browsing it
+type DocsController struct {
+	document *openapi.Document
+}
+
+// NewDocsController builds a new instance of docs controller
+func NewDocsController(document *openapi.Document) *DocsController {
+	return &DocsController{
+		document: document,
+	}
+}
+
+func (controller *DocsController) setupRoutes(router *gin.Engine) {
+	router.GET(openAPIPath, controller.getOpenAPIDocument)
+	router.GET(docsPath, controller.getDocs)
+}
+
+func (controller *DocsController) getOpenAPIDocument(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, controller.document)
+}
+
+func (controller *DocsController) getDocs(ctx *gin.Context) {
+	ctx.Header("Content-Type", "text/html; charset=utf-8")
+	ctx.Status(http.StatusOK)
+	if err := openapi.WriteDocs(ctx.Writer, apiTitle, openAPIPath); err != nil {
+		ctx.Error(err)
+	}
+}
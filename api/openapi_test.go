@@ -0,0 +1,230 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/valverdethiago/trading-api/openapi"
+)
+
+func TestOpenAPIDocumentCoversRoutes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	server := newTestServer(t, newMockStore(ctrl))
+	routes := server.router.Routes()
+	for _, route := range routes {
+		require.NotNil(t, server.document.Operation(route.Method, openAPIPathOf(route.Path)),
+			"%s %s is not documented", route.Method, route.Path)
+	}
+
+	operationIDs := make(map[string]bool)
+	for path, item := range server.document.Paths {
+		for method, operation := range item {
+			require.False(t, operationIDs[operation.OperationID], "duplicate operation %s", operation.OperationID)
+			operationIDs[operation.OperationID] = true
+			require.NotEmpty(t, operation.Responses, "%s %s has no response", method, path)
+			for _, parameter := range operation.Parameters {
+				requireResolvable(t, server.document, parameter.Schema)
+			}
+			if operation.RequestBody != nil {
+				for _, mediaType := range operation.RequestBody.Content {
+					requireResolvable(t, server.document, mediaType.Schema)
+				}
+			}
+			for _, response := range operation.Responses {
+				for _, mediaType := range response.Content {
+					requireResolvable(t, server.document, mediaType.Schema)
+				}
+			}
+		}
+	}
+	require.Len(t, operationIDs, len(routes), "documented operations without route")
+}
+
+// requireResolvable checks that every schema referenced from the schema exists
+func requireResolvable(t *testing.T, document *openapi.Document, schema *openapi.Schema) {
+	if schema == nil {
+		return
+	}
+	if schema.Ref != "" {
+		name := strings.TrimPrefix(schema.Ref, "#/components/schemas/")
+		_, ok := document.Components.Schemas[name]
+		require.True(t, ok, "unknown schema %s", schema.Ref)
+		return
+	}
+	requireResolvable(t, document, schema.Items)
+	for _, property := range schema.Properties {
+		requireResolvable(t, document, property)
+	}
+	for _, one := range schema.OneOf {
+		requireResolvable(t, document, one)
+	}
+}
+
+func TestGetOpenAPIDocument(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	server := newTestServer(t, newMockStore(ctrl))
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodGet, openAPIPath, nil)
+	require.NoError(t, err)
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	var document openapi.Document
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &document))
+	require.Equal(t, openapi.Version, document.OpenAPI)
+	getTrade := document.Operation(http.MethodGet, "/accounts/{id}/trades/{tradeID}")
+	require.NotNil(t, getTrade)
+	require.Contains(t, getTrade.Responses, "200")
+	require.NotContains(t, getTrade.Responses, "201")
+	require.Contains(t, document.Components.Schemas, "Trade")
+}
+
+func TestGetDocs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	server := newTestServer(t, newMockStore(ctrl))
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodGet, docsPath, nil)
+	require.NoError(t, err)
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Contains(t, recorder.Header().Get("Content-Type"), "text/html")
+	require.Contains(t, recorder.Body.String(), openAPIPath)
+}
+
+func TestOpenAPIValidationMiddleware(t *testing.T) {
+	document := openapi.NewDocument(openapi.Info{Title: "Test", Version: "1"})
+	addOpenAPISchemas(document.Components.Schemas)
+	document.AddOperation(http.MethodPost, "/items/{id}", &openapi.Operation{
+		OperationID: "createItem",
+		Parameters:  []openapi.Parameter{pathParameter("id", "ID of the item", openapi.UUID())},
+		RequestBody: jsonBody(openapi.Object(map[string]*openapi.Schema{
+			"name": openapi.NonEmptyString(),
+		}, "name")),
+		Responses: responses(http.StatusCreated, jsonResponse("The item", openapi.Object(map[string]*openapi.Schema{
+			"name": openapi.String(),
+		}, "name")), http.StatusBadRequest),
+	})
+	itemID := "0b6f5f4e-5f0e-4c1b-9a6a-7c1d2e3f4a5b"
+
+	testCases := []struct {
+		name          string
+		mode          string
+		path          string
+		body          string
+		handler       gin.HandlerFunc
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "Valid",
+			mode: openAPIValidationResponses,
+			path: "/items/" + itemID,
+			body: `{"name":"pen"}`,
+			handler: func(ctx *gin.Context) {
+				ctx.Header("X-Item", "pen")
+				ctx.JSON(http.StatusCreated, gin.H{"name": "pen"})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+				require.Equal(t, "pen", recorder.Header().Get("X-Item"))
+				require.JSONEq(t, `{"name":"pen"}`, recorder.Body.String())
+			},
+		}, {
+			name: "Invalid Path Parameter",
+			mode: openAPIValidationRequests,
+			path: "/items/invalid",
+			body: `{"name":"pen"}`,
+			handler: func(ctx *gin.Context) {
+				t.Fatal("handler called with an invalid request")
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Contains(t, recorder.Body.String(), "path.id")
+			},
+		}, {
+			name: "Invalid Body",
+			mode: openAPIValidationRequests,
+			path: "/items/" + itemID,
+			body: `{"name":"pen","color":"blue"}`,
+			handler: func(ctx *gin.Context) {
+				t.Fatal("handler called with an invalid request")
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				requireProblem(t, recorder, http.StatusBadRequest, codeInvalidRequest)
+				require.Contains(t, recorder.Body.String(), "body.color")
+			},
+		}, {
+			name: "Undocumented Status",
+			mode: openAPIValidationResponses,
+			path: "/items/" + itemID,
+			body: `{"name":"pen"}`,
+			handler: func(ctx *gin.Context) {
+				ctx.JSON(http.StatusOK, gin.H{"name": "pen"})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				requireProblem(t, recorder, http.StatusInternalServerError, codeInternalError)
+				require.Contains(t, recorder.Body.String(), "undocumented status 200")
+			},
+		}, {
+			name: "Undocumented Property",
+			mode: openAPIValidationResponses,
+			path: "/items/" + itemID,
+			body: `{"name":"pen"}`,
+			handler: func(ctx *gin.Context) {
+				ctx.JSON(http.StatusCreated, gin.H{"name": "pen", "message": "created"})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+				require.Contains(t, recorder.Body.String(), "body.message")
+			},
+		}, {
+			name: "Responses Not Validated",
+			mode: openAPIValidationRequests,
+			path: "/items/" + itemID,
+			body: `{"name":"pen"}`,
+			handler: func(ctx *gin.Context) {
+				ctx.JSON(http.StatusOK, gin.H{"message": "created"})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		testCase := testCases[i]
+
+		t.Run(testCase.name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(openAPIValidationMiddleware(document, testCase.mode))
+			router.POST("/items/:id", testCase.handler)
+
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodPost, testCase.path, strings.NewReader(testCase.body))
+			require.NoError(t, err)
+			request.Header.Set("Content-Type", openapi.ContentTypeJSON)
+
+			router.ServeHTTP(recorder, request)
+			testCase.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestNewServerInvalidOpenAPIValidation(t *testing.T) {
+	config := newTestConfig()
+	config.OpenAPIValidation = "always"
+	_, err := NewServer(config, nil, nil, nil, nil)
+	require.Error(t, err)
+}
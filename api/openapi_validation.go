@@ -0,0 +1,148 @@
+package api
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/valverdethiago/trading-api/openapi"
+)
+
+const (
+	// openAPIValidationOff serves the requests without checking them against the document
+	openAPIValidationOff = "off"
+	// openAPIValidationRequests rejects the requests that don't match the document with 400
+	openAPIValidationRequests = "requests"
+	// openAPIValidationResponses also replaces the responses that don't match the document with 500
+	openAPIValidationResponses = "responses"
+)
+
+// validOpenAPIValidation tells whether the mode is known, empty meaning off
+func validOpenAPIValidation(mode string) bool {
+	switch mode {
+	case "", openAPIValidationOff, openAPIValidationRequests, openAPIValidationResponses:
+		return true
+	}
+	return false
+}
+
+// responseBuffer holds the response written by the handlers until it is validated
+type responseBuffer struct {
+	gin.ResponseWriter
+	status   int
+	written  bool
+	hijacked bool
+	body     bytes.Buffer
+}
+
+func (buffer *responseBuffer) WriteHeader(status int) {
+	if status > 0 && !buffer.written {
+		buffer.status = status
+	}
+}
+
+func (buffer *responseBuffer) WriteHeaderNow() {
+	buffer.written = true
+}
+
+func (buffer *responseBuffer) Write(data []byte) (int, error) {
+	buffer.written = true
+	return buffer.body.Write(data)
+}
+
+func (buffer *responseBuffer) WriteString(data string) (int, error) {
+	buffer.written = true
+	return buffer.body.WriteString(data)
+}
+
+func (buffer *responseBuffer) Status() int {
+	return buffer.status
+}
+
+func (buffer *responseBuffer) Size() int {
+	if !buffer.written {
+		return -1
+	}
+	return buffer.body.Len()
+}
+
+func (buffer *responseBuffer) Written() bool {
+	return buffer.written
+}
+
+// Flush is deferred until the response is validated
+func (buffer *responseBuffer) Flush() {}
+
+// Hijack hands the connection over, the WebSockets aren't validated
+func (buffer *responseBuffer) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	buffer.hijacked = true
+	return buffer.ResponseWriter.Hijack()
+}
+
+// openAPIValidationMiddleware checks the requests of the documented routes against the document and,
+// in responses mode, their responses too. Meant for the tests, where drifts between the handlers and
+// the document fail the cases
+func openAPIValidationMiddleware(document *openapi.Document, mode string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		operation := document.Operation(ctx.Request.Method, openAPIPathOf(ctx.FullPath()))
+		if operation == nil {
+			ctx.Next()
+			return
+		}
+		var body []byte
+		if ctx.Request.Body != nil {
+			var err error
+			body, err = ioutil.ReadAll(ctx.Request.Body)
+			if err != nil {
+				invalidRequest(ctx, err)
+				ctx.Abort()
+				writeProblem(ctx)
+				return
+			}
+			ctx.Request.Body = ioutil.NopCloser(bytes.NewReader(body))
+		}
+		pathParams := make(map[string]string, len(ctx.Params))
+		for _, param := range ctx.Params {
+			pathParams[param.Key] = param.Value
+		}
+		if err := document.ValidateRequest(operation, ctx.Request, pathParams, body); err != nil {
+			invalidRequest(ctx, fmt.Errorf("request doesn't match the OpenAPI document: %w", err))
+			ctx.Abort()
+			writeProblem(ctx)
+			return
+		}
+		if mode != openAPIValidationResponses {
+			ctx.Next()
+			return
+		}
+
+		writer := ctx.Writer
+		buffer := &responseBuffer{ResponseWriter: writer, status: http.StatusOK}
+		ctx.Writer = buffer
+		ctx.Next()
+		ctx.Writer = writer
+		if buffer.hijacked {
+			return
+		}
+		err := document.ValidateResponse(operation, buffer.status, writer.Header(), buffer.body.Bytes())
+		if err != nil {
+			err = fmt.Errorf("response doesn't match the OpenAPI document: %w", err)
+			log.Printf("%s %s: %v", ctx.Request.Method, ctx.FullPath(), err)
+			writer.Header().Del("Content-Length")
+			writer.Header().Del("Content-Type")
+			// the detail names the mismatch, a fault of the handler that tells nothing of the database
+			result := newProblem(ctx.Error(err))
+			result.Detail = err.Error()
+			renderProblem(ctx, result)
+			return
+		}
+		writer.WriteHeader(buffer.status)
+		writer.WriteHeaderNow()
+		writer.Write(buffer.body.Bytes())
+	}
+}
@@ -0,0 +1,132 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/valverdethiago/trading-api/db/sqlc"
+	"github.com/valverdethiago/trading-api/service"
+)
+
+const (
+	cursorQueryParam     = "cursor"
+	pageQueryParam       = "page"
+	totalCountHeaderKey  = "X-Total-Count"
+	nextCursorHeaderKey  = "X-Next-Cursor"
+	linkHeaderKey        = "Link"
+	sortByCreatedDateRev = "-created_date"
+)
+
+var errInvalidCursor = errors.New("invalid cursor")
+
+// listTradesRequest query parameters to filter, sort and page the trades of an account
+type listTradesRequest struct {
+	Status      string    `form:"status" binding:"omitempty,oneof=SUBMITTED PARTIALLY_FILLED CANCELLED COMPLETED FAILED"`
+	Side        string    `form:"side" binding:"omitempty,oneof=BUY SELL"`
+	Symbol      string    `form:"symbol"`
+	CreatedFrom time.Time `form:"created_from" time_format:"2006-01-02T15:04:05Z07:00"`
+	CreatedTo   time.Time `form:"created_to" time_format:"2006-01-02T15:04:05Z07:00"`
+	Sort        string    `form:"sort" binding:"omitempty,oneof=created_date -created_date"`
+	Limit       int32     `form:"limit" binding:"omitempty,min=1,max=100"`
+	Cursor      string    `form:"cursor"`
+}
+
+func (req listTradesRequest) toFilter() (service.TradeFilter, error) {
+	filter := service.TradeFilter{
+		Status:      db.TradeStatus(req.Status),
+		Side:        db.TradeSide(req.Side),
+		Symbol:      req.Symbol,
+		CreatedFrom: toNullTime(req.CreatedFrom),
+		CreatedTo:   toNullTime(req.CreatedTo),
+		Descending:  req.Sort == sortByCreatedDateRev,
+		PageSize:    req.Limit,
+	}
+	if req.Cursor != "" {
+		cursor, err := decodeTradeCursor(req.Cursor)
+		if err != nil {
+			return filter, err
+		}
+		filter.Cursor = &cursor
+	}
+	return filter, nil
+}
+
+func toNullTime(value time.Time) sql.NullTime {
+	if value.IsZero() {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: value.UTC(), Valid: true}
+}
+
+// listAccountsRequest query parameters to search and page the accounts
+type listAccountsRequest struct {
+	Username    string    `form:"username"`
+	Email       string    `form:"email"`
+	State       string    `form:"state" binding:"omitempty,len=2"`
+	CreatedFrom time.Time `form:"created_from" time_format:"2006-01-02T15:04:05Z07:00"`
+	CreatedTo   time.Time `form:"created_to" time_format:"2006-01-02T15:04:05Z07:00"`
+	Query       string    `form:"q"`
+	Page        int32     `form:"page" binding:"omitempty,min=1"`
+	PageSize    int32     `form:"page_size" binding:"omitempty,min=1,max=100"`
+}
+
+func (req listAccountsRequest) toFilter() service.AccountFilter {
+	return service.AccountFilter{
+		UsernamePrefix: req.Username,
+		Email:          req.Email,
+		State:          db.State(strings.ToUpper(req.State)),
+		CreatedFrom:    toNullTime(req.CreatedFrom),
+		CreatedTo:      toNullTime(req.CreatedTo),
+		Query:          req.Query,
+		Page:           req.Page,
+		PageSize:       req.PageSize,
+	}
+}
+
+// encodeTradeCursor encodes the cursor as an opaque url safe token
+func encodeTradeCursor(cursor service.TradeCursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeTradeCursor(token string) (service.TradeCursor, error) {
+	var cursor service.TradeCursor
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return cursor, errInvalidCursor
+	}
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return cursor, errInvalidCursor
+	}
+	return cursor, nil
+}
+
+// setNextPageHeaders exposes the next cursor and a link to the next page
+func setNextPageHeaders(ctx *gin.Context, cursor string) {
+	ctx.Header(nextCursorHeaderKey, cursor)
+	setNextPageLink(ctx, cursorQueryParam, cursor)
+}
+
+// setNextPageLink links to the next page keeping the other query parameters
+func setNextPageLink(ctx *gin.Context, param string, value string) {
+	query := ctx.Request.URL.Query()
+	query.Set(param, value)
+	next := *ctx.Request.URL
+	next.RawQuery = query.Encode()
+	ctx.Header(linkHeaderKey, fmt.Sprintf("<%s>; rel=\"next\"", next.RequestURI()))
+}
+
+// setAccountPageHeaders exposes the total of matching accounts and a link to the next page if any
+func setAccountPageHeaders(ctx *gin.Context, page service.AccountPage) {
+	ctx.Header(totalCountHeaderKey, strconv.FormatInt(page.Total, 10))
+	if page.HasNextPage() {
+		setNextPageLink(ctx, pageQueryParam, strconv.Itoa(int(page.Page)+1))
+	}
+}
@@ -0,0 +1,124 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+	db "github.com/valverdethiago/trading-api/db/sqlc"
+	"github.com/valverdethiago/trading-api/service"
+)
+
+const (
+	pnlPath = "/accounts/:id/pnl"
+	// pnlDescription tells how the report differs from the positions, which are kept at average cost
+	pnlDescription = "The fills are matched first in first out, so the cost basis of the open lots can differ " +
+		"from the average cost of the positions. Realized P&L is net of the fees, unrealized P&L before them."
+)
+
+// pnlRequest query parameters to bound the P&L report of an account
+type pnlRequest struct {
+	From time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To   time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+}
+
+type closedLotResponse struct {
+	Quantity    int64           `json:"quantity"`
+	OpenPrice   decimal.Decimal `json:"open_price"`
+	ClosePrice  decimal.Decimal `json:"close_price"`
+	OpenedDate  time.Time       `json:"opened_date"`
+	ClosedDate  time.Time       `json:"closed_date"`
+	Fees        decimal.Decimal `json:"fees"`
+	RealizedPnL decimal.Decimal `json:"realized_pnl"`
+}
+
+type symbolPnLResponse struct {
+	Symbol        string              `json:"symbol"`
+	Quantity      int64               `json:"quantity"`
+	CostBasis     decimal.Decimal     `json:"cost_basis"`
+	MarkPrice     decimal.NullDecimal `json:"mark_price"`
+	Fees          decimal.Decimal     `json:"fees"`
+	RealizedPnL   decimal.Decimal     `json:"realized_pnl"`
+	UnrealizedPnL decimal.Decimal     `json:"unrealized_pnl"`
+	ClosedLots    []closedLotResponse `json:"closed_lots"`
+}
+
+type pnlResponse struct {
+	From          *time.Time          `json:"from,omitempty"`
+	To            *time.Time          `json:"to,omitempty"`
+	Symbols       []symbolPnLResponse `json:"symbols"`
+	RealizedPnL   decimal.Decimal     `json:"realized_pnl"`
+	UnrealizedPnL decimal.Decimal     `json:"unrealized_pnl"`
+	TotalPnL      decimal.Decimal     `json:"total_pnl"`
+}
+
+func newPnLResponse(report service.PnLReport) pnlResponse {
+	response := pnlResponse{
+		Symbols:       make([]symbolPnLResponse, 0, len(report.Symbols)),
+		RealizedPnL:   report.RealizedPnL,
+		UnrealizedPnL: report.UnrealizedPnL,
+		TotalPnL:      report.TotalPnL,
+	}
+	if report.Period.From.Valid {
+		response.From = &report.Period.From.Time
+	}
+	if report.Period.To.Valid {
+		response.To = &report.Period.To.Time
+	}
+	for _, symbol := range report.Symbols {
+		symbolResponse := symbolPnLResponse{
+			Symbol:        symbol.Symbol,
+			Quantity:      symbol.Quantity,
+			CostBasis:     symbol.CostBasis,
+			MarkPrice:     symbol.MarkPrice,
+			Fees:          symbol.Fees,
+			RealizedPnL:   symbol.RealizedPnL,
+			UnrealizedPnL: symbol.UnrealizedPnL,
+			ClosedLots:    make([]closedLotResponse, 0, len(symbol.ClosedLots)),
+		}
+		for _, lot := range symbol.ClosedLots {
+			symbolResponse.ClosedLots = append(symbolResponse.ClosedLots, closedLotResponse(lot))
+		}
+		response.Symbols = append(response.Symbols, symbolResponse)
+	}
+	return response
+}
+
+// PnLController controller for the P&L of the accounts
+type PnLController struct {
+	service *service.PnLService
+}
+
+// NewPnLController builds a new instance of P&L controller
+func NewPnLController(store db.Store, policy *service.Policy) *PnLController {
+	accountService := service.NewAccountService(store, policy)
+	return &PnLController{
+		service: service.NewPnLService(store, accountService),
+	}
+}
+
+func (controller *PnLController) setupRoutes(router *gin.Engine, authRoutes gin.IRoutes) {
+	authRoutes.GET(pnlPath, controller.getPnL)
+}
+
+func (controller *PnLController) getPnL(ctx *gin.Context) {
+	accountUUID, err := getAccountUUID(ctx)
+	if err != nil {
+		return
+	}
+	var req pnlRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		invalidRequest(ctx, err)
+		return
+	}
+	report, err := controller.service.GetAccountPnL(ctx.Request.Context(), accountUUID, service.Period{
+		From: toNullTime(req.From),
+		To:   toNullTime(req.To),
+	})
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, newPnLResponse(report))
+}
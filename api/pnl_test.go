@@ -0,0 +1,209 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+	mockdb "github.com/valverdethiago/trading-api/db/mock"
+	db "github.com/valverdethiago/trading-api/db/sqlc"
+)
+
+func TestGetPnL(t *testing.T) {
+	from := time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2021, 4, 1, 0, 0, 0, 0, time.UTC)
+	fills := []db.ListAccountFillsRow{
+		{Symbol: "AAPL", Side: db.TradeSideBUY, Quantity: 10, Price: decimal.NewFromInt(100),
+			Fee: decimal.RequireFromString("1"), ExecutedDate: from.AddDate(0, 0, -10)},
+		{Symbol: "AAPL", Side: db.TradeSideBUY, Quantity: 10, Price: decimal.NewFromInt(110),
+			Fee: decimal.RequireFromString("1.1"), ExecutedDate: from.AddDate(0, 0, 1)},
+		{Symbol: "AAPL", Side: db.TradeSideSELL, Quantity: 15, Price: decimal.NewFromInt(120),
+			Fee: decimal.RequireFromString("1.8"), ExecutedDate: from.AddDate(0, 0, 2)},
+		{Symbol: "MSFT", Side: db.TradeSideSELL, Quantity: 5, Price: decimal.NewFromInt(200),
+			Fee: decimal.RequireFromString("1"), ExecutedDate: from.AddDate(0, 0, 3)},
+	}
+
+	testCases := []struct {
+		name          string
+		query         url.Values
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			query: url.Values{
+				"from": []string{from.Format(time.RFC3339)},
+				"to":   []string{to.Format(time.RFC3339)},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccountById(gomock.Any(), gomock.Eq(account.AccountUuid)).
+					Times(1).
+					Return(account, nil)
+				store.EXPECT().
+					ListAccountFills(gomock.Any(), gomock.Eq(db.ListAccountFillsParams{
+						AccountUuid: account.AccountUuid,
+						ExecutedTo:  sql.NullTime{Time: to, Valid: true},
+					})).
+					Times(1).
+					Return(fills, nil)
+				store.EXPECT().
+					ListLatestPrices(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(ctx context.Context, arg db.ListLatestPricesParams) ([]db.ListLatestPricesRow, error) {
+						require.Equal(t, []string{"AAPL", "MSFT"}, arg.Symbols)
+						require.Equal(t, to, arg.ExecutedTo.Time)
+						return []db.ListLatestPricesRow{
+							{Symbol: "AAPL", Price: decimal.NewFromInt(130)},
+							{Symbol: "MSFT", Price: decimal.NewFromInt(190)},
+						}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				var response pnlResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.Len(t, response.Symbols, 2)
+
+				// 10 bought at 100 before the period and 5 of the 10 bought at 110 are sold at 120, the fees
+				// of 1.00, 1.10 and 1.80 being split in proportion to the quantity of each lot
+				aapl := response.Symbols[0]
+				require.Equal(t, "AAPL", aapl.Symbol)
+				require.Equal(t, int64(5), aapl.Quantity)
+				require.Len(t, aapl.ClosedLots, 2)
+				require.Equal(t, "2.2", aapl.ClosedLots[0].Fees.String())
+				require.Equal(t, "197.8", aapl.ClosedLots[0].RealizedPnL.String())
+				require.Equal(t, "1.15", aapl.ClosedLots[1].Fees.String())
+				require.Equal(t, "48.85", aapl.ClosedLots[1].RealizedPnL.String())
+				require.Equal(t, "3.35", aapl.Fees.String())
+				require.Equal(t, "246.65", aapl.RealizedPnL.String())
+				require.True(t, decimal.NewFromInt(100).Equal(aapl.UnrealizedPnL))
+				require.True(t, decimal.NewFromInt(550).Equal(aapl.CostBasis))
+
+				// the fee of the short sale is realized once it's covered
+				msft := response.Symbols[1]
+				require.Equal(t, "MSFT", msft.Symbol)
+				require.Equal(t, int64(-5), msft.Quantity)
+				require.True(t, msft.RealizedPnL.IsZero())
+				require.True(t, msft.Fees.IsZero())
+				require.True(t, decimal.NewFromInt(50).Equal(msft.UnrealizedPnL))
+
+				require.Equal(t, "246.65", response.RealizedPnL.String())
+				require.True(t, decimal.NewFromInt(150).Equal(response.UnrealizedPnL))
+				require.Equal(t, "396.65", response.TotalPnL.String())
+			},
+		}, {
+			name:  "No Fills",
+			query: url.Values{},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccountById(gomock.Any(), gomock.Eq(account.AccountUuid)).
+					Times(1).
+					Return(account, nil)
+				store.EXPECT().
+					ListAccountFills(gomock.Any(), gomock.Eq(db.ListAccountFillsParams{
+						AccountUuid: account.AccountUuid,
+					})).
+					Times(1).
+					Return(nil, nil)
+				store.EXPECT().
+					ListLatestPrices(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				var response pnlResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.Empty(t, response.Symbols)
+				require.True(t, response.TotalPnL.IsZero())
+			},
+		}, {
+			name: "Invalid Period",
+			query: url.Values{
+				"from": []string{to.Format(time.RFC3339)},
+				"to":   []string{from.Format(time.RFC3339)},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListAccountFills(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		}, {
+			name:  "Invalid Date",
+			query: url.Values{"from": []string{"yesterday"}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListAccountFills(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		}, {
+			name:  "Account Not Found",
+			query: url.Values{},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccountById(gomock.Any(), gomock.Eq(account.AccountUuid)).
+					Times(1).
+					Return(db.Account{}, sql.ErrNoRows)
+				store.EXPECT().
+					ListAccountFills(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		}, {
+			name:  "Internal Error",
+			query: url.Values{},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccountById(gomock.Any(), gomock.Eq(account.AccountUuid)).
+					Times(1).
+					Return(account, nil)
+				store.EXPECT().
+					ListAccountFills(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		testCase := testCases[i]
+
+		t.Run(testCase.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := newMockStore(ctrl)
+			testCase.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+			url := fmt.Sprintf("/accounts/%s/pnl?%s", account.AccountUuid.String(), testCase.query.Encode())
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, account)
+
+			server.router.ServeHTTP(recorder, request)
+			testCase.checkResponse(t, recorder)
+		})
+	}
+}
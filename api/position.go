@@ -0,0 +1,67 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/valverdethiago/trading-api/db/sqlc"
+	"github.com/valverdethiago/trading-api/service"
+)
+
+const (
+	positionsPath         = "/accounts/:id/positions"
+	positionsPathBySymbol = "/accounts/:id/positions/:symbol"
+)
+
+type positionSymbolRequest struct {
+	Symbol string `uri:"symbol" binding:"required"`
+}
+
+// PositionController controller for the positions of the accounts
+type PositionController struct {
+	service *service.PositionService
+}
+
+// NewPositionController builds a new instance of position controller
+func NewPositionController(store db.Store, policy *service.Policy) *PositionController {
+	accountService := service.NewAccountService(store, policy)
+	return &PositionController{
+		service: service.NewPositionService(store, accountService),
+	}
+}
+
+func (controller *PositionController) setupRoutes(router *gin.Engine, authRoutes gin.IRoutes) {
+	authRoutes.GET(positionsPath, controller.listPositions)
+	authRoutes.GET(positionsPathBySymbol, controller.getPosition)
+}
+
+func (controller *PositionController) listPositions(ctx *gin.Context) {
+	accountUUID, err := getAccountUUID(ctx)
+	if err != nil {
+		return
+	}
+	dbPositions, err := controller.service.ListPositions(ctx.Request.Context(), accountUUID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, dbPositions)
+}
+
+func (controller *PositionController) getPosition(ctx *gin.Context) {
+	accountUUID, err := getAccountUUID(ctx)
+	if err != nil {
+		return
+	}
+	var req positionSymbolRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		invalidRequest(ctx, err)
+		return
+	}
+	dbPosition, err := controller.service.GetPosition(ctx.Request.Context(), accountUUID, req.Symbol)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, dbPosition)
+}
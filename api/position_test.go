@@ -0,0 +1,186 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+	mockdb "github.com/valverdethiago/trading-api/db/mock"
+	db "github.com/valverdethiago/trading-api/db/sqlc"
+)
+
+var position = db.Position{
+	AccountUuid: account.AccountUuid,
+	Symbol:      "AAPL",
+	Quantity:    15,
+	AverageCost: decimal.RequireFromString("120.5"),
+}
+
+func TestListPositions(t *testing.T) {
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccountById(gomock.Any(), gomock.Eq(account.AccountUuid)).
+					Times(1).
+					Return(account, nil)
+				store.EXPECT().
+					ListPositionsByAccount(gomock.Any(), gomock.Eq(account.AccountUuid)).
+					Times(1).
+					Return([]db.Position{position}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				var positions []db.Position
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &positions))
+				require.Len(t, positions, 1)
+				require.Equal(t, position.Symbol, positions[0].Symbol)
+				require.Equal(t, position.Quantity, positions[0].Quantity)
+				require.True(t, position.AverageCost.Equal(positions[0].AverageCost))
+			},
+		}, {
+			name: "No Positions",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccountById(gomock.Any(), gomock.Eq(account.AccountUuid)).
+					Times(1).
+					Return(account, nil)
+				store.EXPECT().
+					ListPositionsByAccount(gomock.Any(), gomock.Eq(account.AccountUuid)).
+					Times(1).
+					Return(nil, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, "[]", recorder.Body.String())
+			},
+		}, {
+			name: "Account Not Found",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccountById(gomock.Any(), gomock.Eq(account.AccountUuid)).
+					Times(1).
+					Return(db.Account{}, sql.ErrNoRows)
+				store.EXPECT().
+					ListPositionsByAccount(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		testCase := testCases[i]
+
+		t.Run(testCase.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := newMockStore(ctrl)
+			testCase.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+			url := fmt.Sprintf("/accounts/%s/positions", account.AccountUuid.String())
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, account)
+
+			server.router.ServeHTTP(recorder, request)
+			testCase.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestGetPosition(t *testing.T) {
+	testCases := []struct {
+		name          string
+		symbol        string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "OK",
+			symbol: position.Symbol,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetPosition(gomock.Any(), gomock.Eq(db.GetPositionParams{
+						AccountUuid: account.AccountUuid,
+						Symbol:      position.Symbol,
+					})).
+					Times(1).
+					Return(position, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				var response db.Position
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.Equal(t, position.Quantity, response.Quantity)
+				require.True(t, position.AverageCost.Equal(response.AverageCost))
+			},
+		}, {
+			name:   "Never Traded",
+			symbol: "MSFT",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetPosition(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Position{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		}, {
+			name:   "Internal Error",
+			symbol: position.Symbol,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetPosition(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Position{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		testCase := testCases[i]
+
+		t.Run(testCase.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := newMockStore(ctrl)
+			store.EXPECT().
+				GetAccountById(gomock.Any(), gomock.Eq(account.AccountUuid)).
+				Times(1).
+				Return(account, nil)
+			testCase.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+			url := fmt.Sprintf("/accounts/%s/positions/%s", account.AccountUuid.String(), testCase.symbol)
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, account)
+
+			server.router.ServeHTTP(recorder, request)
+			testCase.checkResponse(t, recorder)
+		})
+	}
+}
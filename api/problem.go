@@ -0,0 +1,156 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/valverdethiago/trading-api/openapi"
+	"github.com/valverdethiago/trading-api/service"
+)
+
+const (
+	// problemTypeBlank is the type of the problems described by their status alone
+	problemTypeBlank = "about:blank"
+	// codeInvalidRequest is the code of the requests whose parameters or body can't be read
+	codeInvalidRequest = "INVALID_REQUEST"
+	// codeUnauthorized is the code of the requests without a valid bearer token
+	codeUnauthorized = "UNAUTHORIZED"
+	// codeNotFound is the code of the missing resources without a not found error of their own
+	codeNotFound      = "NOT_FOUND"
+	codeTimeout       = "TIMEOUT"
+	codeClientClosed  = "CLIENT_CLOSED_REQUEST"
+	codeInternalError = "INTERNAL_ERROR"
+	// detailTimeout and detailInternalError stand for the errors of the 5xx, which may carry the
+	// queries or the messages of the database driver and are only logged
+	detailTimeout       = "The request took too long to answer"
+	detailInternalError = "The server failed to answer the request, the error was logged"
+)
+
+// kindStatuses maps the kinds of the service errors to the status answered for them
+var kindStatuses = map[service.ErrorKind]int{
+	service.KindValidation:   http.StatusBadRequest,
+	service.KindUnauthorized: http.StatusUnauthorized,
+	service.KindForbidden:    http.StatusForbidden,
+	service.KindNotFound:     http.StatusNotFound,
+	service.KindConflict:     http.StatusConflict,
+	service.KindRejected:     http.StatusUnprocessableEntity,
+}
+
+// problem is the body of the error responses, following RFC 7807 with the stable code of the error,
+// the invalid fields and the failed risk rules as extensions
+type problem struct {
+	Type       string                  `json:"type"`
+	Title      string                  `json:"title"`
+	Status     int                     `json:"status"`
+	Detail     string                  `json:"detail"`
+	Instance   string                  `json:"instance"`
+	Code       string                  `json:"code"`
+	Errors     []service.FieldError    `json:"errors,omitempty"`
+	Violations []service.RiskViolation `json:"violations,omitempty"`
+}
+
+// newProblem describes an error recorded on the context with its status and code, the errors nobody
+// classified being internal ones. The server errors get a fixed detail, problemMiddleware logging them
+func newProblem(ginErr *gin.Error) problem {
+	err := ginErr.Err
+	result := problem{Type: problemTypeBlank, Detail: err.Error()}
+	var domainErr *service.Error
+	var rejection *service.RiskRejection
+	switch {
+	case ginErr.IsType(gin.ErrorTypeBind):
+		result.Status, result.Code = http.StatusBadRequest, codeInvalidRequest
+		result.Errors = bindingFieldErrors(err)
+	case errors.As(err, &domainErr) && kindStatuses[domainErr.Kind] != 0:
+		result.Status, result.Code = kindStatuses[domainErr.Kind], domainErr.Code
+		result.Errors = domainErr.Fields
+		if errors.As(err, &rejection) {
+			result.Violations = rejection.Violations
+		}
+	case errors.Is(err, context.DeadlineExceeded):
+		result.Status, result.Code, result.Detail = http.StatusGatewayTimeout, codeTimeout, detailTimeout
+	case errors.Is(err, context.Canceled):
+		result.Status, result.Code = statusClientClosedRequest, codeClientClosed
+	case errors.Is(err, sql.ErrNoRows):
+		result.Status, result.Code = http.StatusNotFound, codeNotFound
+	default:
+		result.Status, result.Code, result.Detail = http.StatusInternalServerError, codeInternalError, detailInternalError
+	}
+	result.Title = http.StatusText(result.Status)
+	if result.Status == statusClientClosedRequest {
+		result.Title = statusClientClosedRequestText
+	}
+	return result
+}
+
+// bindingFieldErrors lists the fields failing the binding tags, named after their json, form or uri tag
+func bindingFieldErrors(err error) []service.FieldError {
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return nil
+	}
+	fields := make([]service.FieldError, len(validationErrors))
+	for i, fieldErr := range validationErrors {
+		reason := "is required"
+		if fieldErr.Tag() != "required" {
+			reason = fmt.Sprintf("fails the %s rule", fieldErr.Tag())
+			if fieldErr.Param() != "" {
+				reason = fmt.Sprintf("fails the %s=%s rule", fieldErr.Tag(), fieldErr.Param())
+			}
+		}
+		fields[i] = service.FieldError{Field: fieldErr.Field(), Reason: reason}
+	}
+	return fields
+}
+
+// problemMiddleware renders the last error the handlers recorded with ctx.Error as a problem, unless
+// they answered already. The server errors are logged and reported to the hook of the server
+func (server *Server) problemMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.Next()
+		writeProblem(ctx)
+
+		if len(ctx.Errors) == 0 || ctx.Writer.Status() < http.StatusInternalServerError {
+			return
+		}
+		err := ctx.Errors.Last().Err
+		log.Printf("%s %s: %v", ctx.Request.Method, ctx.Request.URL.Path, err)
+		if server.onServerError != nil {
+			server.onServerError(err)
+		}
+	}
+}
+
+// writeProblem renders the last error recorded on the context, if the response isn't written yet
+func writeProblem(ctx *gin.Context) {
+	if len(ctx.Errors) == 0 || ctx.Writer.Written() {
+		return
+	}
+	renderProblem(ctx, newProblem(ctx.Errors.Last()))
+}
+
+// renderProblem answers the problem, as the instance of the path of the request
+func renderProblem(ctx *gin.Context, result problem) {
+	result.Instance = ctx.Request.URL.Path
+	ctx.Header("Content-Type", openapi.ContentTypeProblemJSON)
+	ctx.JSON(result.Status, result)
+}
+
+// invalidRequest records an error reading the parameters or the body of the request, answered with 400
+func invalidRequest(ctx *gin.Context, err error) {
+	ctx.Error(err).SetType(gin.ErrorTypeBind)
+}
+
+// recordContextError records the end of the request context as the error of the request, when it
+// is over and nothing was answered. The errors the handlers got from it are often wrapped beyond
+// recognition, like the ones of the database driver
+func recordContextError(ctx *gin.Context) {
+	if ctxErr := ctx.Request.Context().Err(); ctxErr != nil && !ctx.Writer.Written() {
+		ctx.Error(ctxErr)
+	}
+}
@@ -0,0 +1,214 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/stretchr/testify/require"
+	"github.com/valverdethiago/trading-api/openapi"
+	"github.com/valverdethiago/trading-api/service"
+)
+
+// requireProblem checks that the response is a problem of the status and code
+func requireProblem(t *testing.T, recorder *httptest.ResponseRecorder, status int, code string) problem {
+	require.Equal(t, status, recorder.Code)
+	require.Equal(t, openapi.ContentTypeProblemJSON, recorder.Header().Get("Content-Type"))
+	var response problem
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	require.Equal(t, status, response.Status)
+	require.Equal(t, code, response.Code)
+	require.Equal(t, problemTypeBlank, response.Type)
+	require.NotEmpty(t, response.Title)
+	require.NotEmpty(t, response.Instance)
+	return response
+}
+
+func TestNewProblem(t *testing.T) {
+	registerValidators()
+	bindErr := binding.Validator.ValidateStruct(tradeRequest{Quantity: -1})
+	require.Error(t, bindErr)
+	rejection := &service.RiskRejection{Violations: []service.RiskViolation{{Rule: service.RiskRuleBuyingPower}}}
+
+	testCases := []struct {
+		name        string
+		err         *gin.Error
+		checkResult func(t *testing.T, result problem)
+	}{
+		{
+			name: "Binding",
+			err:  &gin.Error{Err: bindErr, Type: gin.ErrorTypeBind},
+			checkResult: func(t *testing.T, result problem) {
+				require.Equal(t, http.StatusBadRequest, result.Status)
+				require.Equal(t, codeInvalidRequest, result.Code)
+				require.Contains(t, result.Errors, service.FieldError{Field: "symbol", Reason: "is required"})
+				require.Contains(t, result.Errors, service.FieldError{Field: "quantity", Reason: "fails the min=1 rule"})
+			},
+		}, {
+			name: "Wrapped Service Error",
+			err:  &gin.Error{Err: fmt.Errorf("%w: AAPL", service.ErrUnknownSymbol)},
+			checkResult: func(t *testing.T, result problem) {
+				require.Equal(t, http.StatusUnprocessableEntity, result.Status)
+				require.Equal(t, service.ErrUnknownSymbol.Code, result.Code)
+				require.Equal(t, "Unknown symbol: AAPL", result.Detail)
+			},
+		}, {
+			name: "Invalid Field",
+			err:  &gin.Error{Err: service.NewValidationError("INVALID", "Invalid", service.FieldError{Field: "name", Reason: "is required"})},
+			checkResult: func(t *testing.T, result problem) {
+				require.Equal(t, http.StatusBadRequest, result.Status)
+				require.Equal(t, []service.FieldError{{Field: "name", Reason: "is required"}}, result.Errors)
+			},
+		}, {
+			name: "Risk Rejection",
+			err:  &gin.Error{Err: rejection},
+			checkResult: func(t *testing.T, result problem) {
+				require.Equal(t, http.StatusUnprocessableEntity, result.Status)
+				require.Equal(t, service.ErrRiskRejected.Code, result.Code)
+				require.Equal(t, rejection.Violations, result.Violations)
+			},
+		}, {
+			name: "Not Found",
+			err:  &gin.Error{Err: sql.ErrNoRows},
+			checkResult: func(t *testing.T, result problem) {
+				require.Equal(t, http.StatusNotFound, result.Status)
+				require.Equal(t, codeNotFound, result.Code)
+			},
+		}, {
+			name: "Timeout",
+			err:  &gin.Error{Err: context.DeadlineExceeded},
+			checkResult: func(t *testing.T, result problem) {
+				require.Equal(t, http.StatusGatewayTimeout, result.Status)
+				require.Equal(t, codeTimeout, result.Code)
+				require.Equal(t, detailTimeout, result.Detail)
+			},
+		}, {
+			name: "Client Gone",
+			err:  &gin.Error{Err: fmt.Errorf("query: %w", context.Canceled)},
+			checkResult: func(t *testing.T, result problem) {
+				require.Equal(t, statusClientClosedRequest, result.Status)
+				require.Equal(t, statusClientClosedRequestText, result.Title)
+			},
+		}, {
+			name: "Internal",
+			err:  &gin.Error{Err: fmt.Errorf("SELECT * FROM account: %w", sql.ErrConnDone)},
+			checkResult: func(t *testing.T, result problem) {
+				require.Equal(t, http.StatusInternalServerError, result.Status)
+				require.Equal(t, codeInternalError, result.Code)
+				require.Equal(t, detailInternalError, result.Detail)
+			},
+		},
+	}
+
+	for i := range testCases {
+		testCase := testCases[i]
+
+		t.Run(testCase.name, func(t *testing.T) {
+			result := newProblem(testCase.err)
+			require.Equal(t, problemTypeBlank, result.Type)
+			require.NotEmpty(t, result.Title)
+			testCase.checkResult(t, result)
+		})
+	}
+}
+
+// TestServiceErrorsAreClientErrors keeps the errors of the services out of the 5xx, each with a code of its own
+func TestServiceErrorsAreClientErrors(t *testing.T) {
+	serviceErrors := []*service.Error{
+		service.ErrAccountNotFound, service.ErrAddressNotFound, service.ErrTradeNotFound,
+		service.ErrInstrumentNotFound, service.ErrPositionNotFound, service.ErrWebhookNotFound,
+		service.ErrDeadLetterNotFound, service.ErrInvalidCredentials, service.ErrUsernameTaken,
+		service.ErrAddressExists, service.ErrAddressRequired, service.ErrInvalidStatusTransition,
+		service.ErrForbidden, service.ErrInvalidTickSize, service.ErrInvalidOrder, service.ErrInvalidLotSize,
+		service.ErrInvalidInstrument, service.ErrInstrumentExists, service.ErrUnknownSymbol,
+		service.ErrSymbolNotTradable, service.ErrInvalidAmount, service.ErrDepositAccountNotApproved, service.ErrInsufficientFunds,
+		service.ErrInsufficientShares, service.ErrNoPriceEstimate, service.ErrInvalidPeriod, service.ErrRiskRejected,
+		service.ErrAccountNotApproved, service.ErrMarketClosed, service.ErrTradeNotInAccount,
+		service.ErrTradeNotCancellable, service.ErrTradeNotAmendable, service.ErrInvalidEventType,
+		service.ErrInvalidWebhookURL, service.ErrDeadLetterReplayed, service.ErrInvalidReport, service.ErrIdempotencyKeyReused, service.ErrIdempotencyKeyInProgress,
+	}
+	codes := make(map[string]bool, len(serviceErrors))
+	for _, serviceErr := range serviceErrors {
+		result := newProblem(&gin.Error{Err: serviceErr})
+		require.Less(t, result.Status, http.StatusInternalServerError, "%s is a server error", serviceErr.Code)
+		require.Equal(t, serviceErr.Code, result.Code)
+		require.False(t, codes[serviceErr.Code], "duplicate code %s", serviceErr.Code)
+		codes[serviceErr.Code] = true
+	}
+}
+
+func TestProblemMiddleware(t *testing.T) {
+	testCases := []struct {
+		name          string
+		handler       gin.HandlerFunc
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder, serverErrors []error)
+	}{
+		{
+			name: "Service Error",
+			handler: func(ctx *gin.Context) {
+				ctx.Error(service.ErrForbidden)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, serverErrors []error) {
+				result := requireProblem(t, recorder, http.StatusForbidden, service.ErrForbidden.Code)
+				require.Equal(t, "/items", result.Instance)
+				require.Empty(t, serverErrors)
+			},
+		}, {
+			name: "Server Error",
+			handler: func(ctx *gin.Context) {
+				ctx.Error(sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, serverErrors []error) {
+				requireProblem(t, recorder, http.StatusInternalServerError, codeInternalError)
+				require.Equal(t, []error{sql.ErrConnDone}, serverErrors)
+			},
+		}, {
+			name: "Already Answered",
+			handler: func(ctx *gin.Context) {
+				ctx.Error(sql.ErrConnDone)
+				ctx.JSON(http.StatusOK, gin.H{})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, serverErrors []error) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Empty(t, serverErrors)
+			},
+		}, {
+			name: "Last Error",
+			handler: func(ctx *gin.Context) {
+				ctx.Error(sql.ErrConnDone)
+				ctx.Error(errors.New("invalid page")).SetType(gin.ErrorTypeBind)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, serverErrors []error) {
+				requireProblem(t, recorder, http.StatusBadRequest, codeInvalidRequest)
+			},
+		},
+	}
+
+	for i := range testCases {
+		testCase := testCases[i]
+
+		t.Run(testCase.name, func(t *testing.T) {
+			var serverErrors []error
+			server := &Server{onServerError: func(err error) {
+				serverErrors = append(serverErrors, err)
+			}}
+			router := gin.New()
+			router.Use(server.problemMiddleware())
+			router.GET("/items", testCase.handler)
+
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodGet, "/items", nil)
+			require.NoError(t, err)
+
+			router.ServeHTTP(recorder, request)
+			testCase.checkResponse(t, recorder, serverErrors)
+		})
+	}
+}
@@ -0,0 +1,180 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	db "github.com/valverdethiago/trading-api/db/sqlc"
+	"github.com/valverdethiago/trading-api/service"
+)
+
+const (
+	riskLimitsPath = "/accounts/:id/risk-limits"
+	riskChecksPath = "/accounts/:id/risk-checks"
+)
+
+// updateRiskLimitsRequest replaces the limits of an account, an omitted limit falling back to the global one
+type updateRiskLimitsRequest struct {
+	MaxOrderNotional    *decimal.Decimal `json:"max_order_notional" binding:"omitempty,gt=0"`
+	MaxDailyNotional    *decimal.Decimal `json:"max_daily_notional" binding:"omitempty,gt=0"`
+	MaxPositionQuantity *int64           `json:"max_position_quantity" binding:"omitempty,min=1"`
+}
+
+func (req updateRiskLimitsRequest) toLimits() service.RiskLimits {
+	var limits service.RiskLimits
+	if req.MaxOrderNotional != nil {
+		limits.MaxOrderNotional = decimal.NullDecimal{Decimal: *req.MaxOrderNotional, Valid: true}
+	}
+	if req.MaxDailyNotional != nil {
+		limits.MaxDailyNotional = decimal.NullDecimal{Decimal: *req.MaxDailyNotional, Valid: true}
+	}
+	if req.MaxPositionQuantity != nil {
+		limits.MaxPositionQuantity = sql.NullInt64{Int64: *req.MaxPositionQuantity, Valid: true}
+	}
+	return limits
+}
+
+// listRiskChecksRequest query parameters to limit the risk checks listed
+type listRiskChecksRequest struct {
+	PageSize int32 `form:"page_size" binding:"omitempty,min=1,max=100"`
+}
+
+type riskLimitsResponse struct {
+	MaxOrderNotional    *decimal.Decimal `json:"max_order_notional"`
+	MaxDailyNotional    *decimal.Decimal `json:"max_daily_notional"`
+	MaxPositionQuantity *int64           `json:"max_position_quantity"`
+}
+
+func newRiskLimitsResponse(limits service.RiskLimits) riskLimitsResponse {
+	var response riskLimitsResponse
+	if limits.MaxOrderNotional.Valid {
+		response.MaxOrderNotional = &limits.MaxOrderNotional.Decimal
+	}
+	if limits.MaxDailyNotional.Valid {
+		response.MaxDailyNotional = &limits.MaxDailyNotional.Decimal
+	}
+	if limits.MaxPositionQuantity.Valid {
+		response.MaxPositionQuantity = &limits.MaxPositionQuantity.Int64
+	}
+	return response
+}
+
+type accountRiskLimitsResponse struct {
+	// Account holds the limits set on the account, Effective the ones applying once the global ones fill the gaps
+	Account   riskLimitsResponse `json:"account"`
+	Effective riskLimitsResponse `json:"effective"`
+}
+
+func newAccountRiskLimitsResponse(limits service.AccountRiskLimits) accountRiskLimitsResponse {
+	return accountRiskLimitsResponse{
+		Account:   newRiskLimitsResponse(limits.Account),
+		Effective: newRiskLimitsResponse(limits.Effective),
+	}
+}
+
+type riskCheckResponse struct {
+	RiskCheckUUID uuid.UUID `json:"risk_check_uuid"`
+	// TradeUUID is empty on the rejected orders
+	TradeUUID   *uuid.UUID       `json:"trade_uuid,omitempty"`
+	Symbol      string           `json:"symbol"`
+	Side        db.TradeSide     `json:"side"`
+	Quantity    int64            `json:"quantity"`
+	Notional    *decimal.Decimal `json:"notional"`
+	Passed      bool             `json:"passed"`
+	Violations  json.RawMessage  `json:"violations"`
+	CreatedDate time.Time        `json:"created_date"`
+}
+
+func newRiskCheckResponse(riskCheck db.RiskCheck) riskCheckResponse {
+	response := riskCheckResponse{
+		RiskCheckUUID: riskCheck.RiskCheckUuid,
+		TradeUUID:     optionalUUID(riskCheck.TradeUuid),
+		Symbol:        riskCheck.Symbol,
+		Side:          riskCheck.Side,
+		Quantity:      riskCheck.Quantity,
+		Passed:        riskCheck.Passed,
+		Violations:    riskCheck.Violations,
+		CreatedDate:   riskCheck.CreatedDate,
+	}
+	if riskCheck.Notional.Valid {
+		response.Notional = &riskCheck.Notional.Decimal
+	}
+	return response
+}
+
+// RiskController controller for the risk limits and the risk checks of the accounts
+type RiskController struct {
+	service *service.RiskService
+}
+
+// NewRiskController builds a new instance of risk controller
+func NewRiskController(store db.Store, policy *service.Policy, riskEngine *service.RiskEngine) *RiskController {
+	accountService := service.NewAccountService(store, policy)
+	return &RiskController{
+		service: service.NewRiskService(store, policy, accountService, riskEngine),
+	}
+}
+
+func (controller *RiskController) setupRoutes(router *gin.Engine, authRoutes gin.IRoutes) {
+	authRoutes.GET(riskLimitsPath, controller.getRiskLimits)
+	authRoutes.PUT(riskLimitsPath, controller.updateRiskLimits)
+	authRoutes.GET(riskChecksPath, controller.listRiskChecks)
+}
+
+func (controller *RiskController) getRiskLimits(ctx *gin.Context) {
+	accountUUID, err := getAccountUUID(ctx)
+	if err != nil {
+		return
+	}
+	limits, err := controller.service.GetRiskLimits(ctx.Request.Context(), accountUUID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, newAccountRiskLimitsResponse(limits))
+}
+
+func (controller *RiskController) updateRiskLimits(ctx *gin.Context) {
+	accountUUID, err := getAccountUUID(ctx)
+	if err != nil {
+		return
+	}
+	var req updateRiskLimitsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		invalidRequest(ctx, err)
+		return
+	}
+	limits, err := controller.service.UpdateRiskLimits(ctx.Request.Context(), getActor(ctx), accountUUID, req.toLimits())
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, newAccountRiskLimitsResponse(limits))
+}
+
+func (controller *RiskController) listRiskChecks(ctx *gin.Context) {
+	accountUUID, err := getAccountUUID(ctx)
+	if err != nil {
+		return
+	}
+	var req listRiskChecksRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		invalidRequest(ctx, err)
+		return
+	}
+	dbRiskChecks, err := controller.service.ListRiskChecks(ctx.Request.Context(), accountUUID, req.PageSize)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	response := make([]riskCheckResponse, 0, len(dbRiskChecks))
+	for _, riskCheck := range dbRiskChecks {
+		response = append(response, newRiskCheckResponse(riskCheck))
+	}
+	ctx.JSON(http.StatusOK, response)
+}
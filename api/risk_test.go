@@ -0,0 +1,422 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+	mockdb "github.com/valverdethiago/trading-api/db/mock"
+	db "github.com/valverdethiago/trading-api/db/sqlc"
+	mockexecution "github.com/valverdethiago/trading-api/execution/mock"
+	"github.com/valverdethiago/trading-api/service"
+)
+
+var riskLimit = db.RiskLimit{
+	AccountUuid:      account.AccountUuid,
+	MaxOrderNotional: decimal.NullDecimal{Decimal: decimal.NewFromInt(2000), Valid: true},
+}
+
+// newTestServerWithRiskLimits creates a server whose global limits allow orders of up to 500 and
+// positions of up to 5 shares
+func newTestServerWithRiskLimits(t *testing.T, store db.Store) *Server {
+	venue := mockexecution.NewMockVenue(gomock.NewController(t))
+	venue.EXPECT().
+		Submit(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		Return(nil)
+	config := newTestConfig()
+	config.RiskMaxOrderNotional = "500"
+	config.RiskMaxPositionQuantity = 5
+	server, err := NewServer(config, store, venue, nil, nil)
+	require.NoError(t, err)
+	return server
+}
+
+func TestCreateTradeRiskChecks(t *testing.T) {
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "Every Failed Rule",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccountById(gomock.Any(), gomock.Eq(account.AccountUuid)).
+					Times(1).
+					Return(account, nil)
+				store.EXPECT().
+					GetPosition(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Position{}, sql.ErrNoRows)
+				store.EXPECT().
+					GetOpenBuyQuantity(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(int64(0), nil)
+				store.EXPECT().
+					CreateRiskCheck(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(ctx context.Context, arg db.CreateRiskCheckParams) (db.RiskCheck, error) {
+						require.False(t, arg.Passed)
+						require.Equal(t, uuid.Nil, arg.TradeUuid)
+						require.True(t, decimal.NewFromInt(1000).Equal(arg.Notional.Decimal))
+						return db.RiskCheck{}, nil
+					})
+				// the order costs 1001.00 with the fee
+				expectBuyingPower(store, decimal.NewFromInt(1000), 0)
+				expectInstrument(store)
+				store.EXPECT().
+					CreateTrade(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+				rules := requireBodyMatchViolations(t, recorder)
+				require.Equal(t, []service.RiskRule{
+					service.RiskRuleMaxOrderNotional,
+					service.RiskRuleMaxPositionQuantity,
+					service.RiskRuleBuyingPower,
+				}, rules)
+			},
+		}, {
+			name: "Account Limits Override Global Ones",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccountById(gomock.Any(), gomock.Eq(account.AccountUuid)).
+					Times(1).
+					Return(account, nil)
+				store.EXPECT().
+					GetRiskLimit(gomock.Any(), gomock.Eq(account.AccountUuid)).
+					Times(1).
+					Return(riskLimit, nil)
+				store.EXPECT().
+					GetPosition(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Position{}, sql.ErrNoRows)
+				store.EXPECT().
+					GetOpenBuyQuantity(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(int64(0), nil)
+				store.EXPECT().
+					CreateRiskCheck(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(ctx context.Context, arg db.CreateRiskCheckParams) (db.RiskCheck, error) {
+						require.False(t, arg.Passed)
+						return db.RiskCheck{}, nil
+					})
+				expectBuyingPower(store, decimal.NewFromInt(1000000), 0)
+				expectInstrument(store)
+				store.EXPECT().
+					CreateTrade(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+				rules := requireBodyMatchViolations(t, recorder)
+				require.Equal(t, []service.RiskRule{service.RiskRuleMaxPositionQuantity}, rules)
+			},
+		},
+	}
+
+	for i := range testCases {
+		testCase := testCases[i]
+
+		t.Run(testCase.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := newMockStore(ctrl)
+			testCase.buildStubs(store)
+
+			server := newTestServerWithRiskLimits(t, store)
+			recorder := httptest.NewRecorder()
+			body, err := json.Marshal(tradeRequest{
+				Symbol:   trade.Symbol,
+				Quantity: 10,
+				Side:     db.TradeSideBUY,
+				Price:    decimalPointer("100"),
+			})
+			require.NoError(t, err)
+			url := fmt.Sprintf("/accounts/%s/trades", account.AccountUuid.String())
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, account)
+
+			server.router.ServeHTTP(recorder, request)
+			testCase.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestCreateMarketBuyBuyingPower(t *testing.T) {
+	testCases := []struct {
+		name          string
+		prices        []db.ListLatestPricesRow
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "Valued At The Last Price",
+			prices: []db.ListLatestPricesRow{{Symbol: trade.Symbol, Price: decimal.NewFromInt(10)}},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				response := requireProblem(t, recorder, http.StatusUnprocessableEntity, service.ErrRiskRejected.Code)
+				require.Len(t, response.Violations, 1)
+				violation := response.Violations[0]
+				require.Equal(t, service.RiskRuleBuyingPower, violation.Rule)
+				require.True(t, decimal.NewFromInt(1000).Equal(violation.Limit))
+				// the fee comes on top of the 10,000,000 notional
+				require.True(t, violation.Value.GreaterThan(decimal.NewFromInt(10000000)), violation.Value.String())
+			},
+		}, {
+			name: "Symbol Never Traded",
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+				rules := requireBodyMatchViolations(t, recorder)
+				require.Equal(t, []service.RiskRule{service.RiskRuleBuyingPower}, rules)
+			},
+		},
+	}
+
+	for i := range testCases {
+		testCase := testCases[i]
+
+		t.Run(testCase.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := newMockStore(ctrl)
+			store.EXPECT().
+				GetAccountById(gomock.Any(), gomock.Eq(account.AccountUuid)).
+				Times(1).
+				Return(account, nil)
+			store.EXPECT().
+				ListLatestPrices(gomock.Any(), gomock.Any()).
+				AnyTimes().
+				Return(testCase.prices, nil)
+			expectBuyingPower(store, decimal.NewFromInt(1000), 0)
+			expectInstrument(store)
+			store.EXPECT().
+				CreateTrade(gomock.Any(), gomock.Any()).
+				Times(0)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+			body, err := json.Marshal(tradeRequest{
+				Symbol:      trade.Symbol,
+				Quantity:    1000000,
+				Side:        db.TradeSideBUY,
+				OrderType:   db.OrderTypeMARKET,
+				TimeInForce: db.TimeInForceIOC,
+			})
+			require.NoError(t, err)
+			url := fmt.Sprintf("/accounts/%s/trades", account.AccountUuid.String())
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, account)
+
+			server.router.ServeHTTP(recorder, request)
+			testCase.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestGetRiskLimits(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := newMockStore(ctrl)
+	store.EXPECT().
+		GetAccountById(gomock.Any(), gomock.Eq(account.AccountUuid)).
+		Times(1).
+		Return(account, nil)
+	store.EXPECT().
+		GetRiskLimit(gomock.Any(), gomock.Eq(account.AccountUuid)).
+		Times(1).
+		Return(riskLimit, nil)
+
+	server := newTestServerWithRiskLimits(t, store)
+	recorder := httptest.NewRecorder()
+	url := fmt.Sprintf("/accounts/%s/risk-limits", account.AccountUuid.String())
+	request, err := http.NewRequest(http.MethodGet, url, nil)
+	require.NoError(t, err)
+	addAuthorization(t, request, server.tokenMaker, account)
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	var response accountRiskLimitsResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	require.True(t, decimal.NewFromInt(2000).Equal(*response.Account.MaxOrderNotional))
+	require.Nil(t, response.Account.MaxPositionQuantity)
+	require.True(t, decimal.NewFromInt(2000).Equal(*response.Effective.MaxOrderNotional))
+	require.Nil(t, response.Effective.MaxDailyNotional)
+	require.Equal(t, int64(5), *response.Effective.MaxPositionQuantity)
+}
+
+func TestUpdateRiskLimits(t *testing.T) {
+	testCases := []struct {
+		name          string
+		actor         db.Account
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "OK",
+			actor: staffAccount,
+			body:  gin.H{"max_order_notional": 2000},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccountById(gomock.Any(), gomock.Eq(account.AccountUuid)).
+					Times(1).
+					Return(account, nil)
+				store.EXPECT().
+					GetRiskLimit(gomock.Any(), gomock.Eq(account.AccountUuid)).
+					Times(1).
+					Return(db.RiskLimit{}, sql.ErrNoRows)
+				store.EXPECT().
+					UpsertRiskLimit(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(ctx context.Context, arg db.UpsertRiskLimitParams) (db.RiskLimit, error) {
+						require.Equal(t, account.AccountUuid, arg.AccountUuid)
+						require.True(t, decimal.NewFromInt(2000).Equal(arg.MaxOrderNotional.Decimal))
+						require.False(t, arg.MaxDailyNotional.Valid)
+						require.False(t, arg.MaxPositionQuantity.Valid)
+						return riskLimit, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		}, {
+			name:  "Not Staff",
+			actor: account,
+			body:  gin.H{"max_order_notional": 2000},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpsertRiskLimit(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		}, {
+			name:  "Negative Limit",
+			actor: staffAccount,
+			body:  gin.H{"max_daily_notional": -1},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpsertRiskLimit(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		}, {
+			name:  "Account Not Found",
+			actor: staffAccount,
+			body:  gin.H{"max_position_quantity": 100},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccountById(gomock.Any(), gomock.Eq(account.AccountUuid)).
+					Times(1).
+					Return(db.Account{}, sql.ErrNoRows)
+				store.EXPECT().
+					UpsertRiskLimit(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		testCase := testCases[i]
+
+		t.Run(testCase.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := newMockStore(ctrl)
+			testCase.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+			body, err := json.Marshal(testCase.body)
+			require.NoError(t, err)
+			url := fmt.Sprintf("/accounts/%s/risk-limits", account.AccountUuid.String())
+			request, err := http.NewRequest(http.MethodPut, url, bytes.NewReader(body))
+			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, testCase.actor)
+
+			server.router.ServeHTTP(recorder, request)
+			testCase.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestListRiskChecks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	riskCheck := db.RiskCheck{
+		RiskCheckUuid: uuid.New(),
+		AccountUuid:   account.AccountUuid,
+		Symbol:        "AAPL",
+		Side:          db.TradeSideBUY,
+		Quantity:      10,
+		Passed:        false,
+		Violations:    json.RawMessage(`[{"rule":"BUYING_POWER","message":"insufficient buying power","limit":1000,"value":1015.5}]`),
+		CreatedDate:   time.Now(),
+	}
+	store := newMockStore(ctrl)
+	store.EXPECT().
+		GetAccountById(gomock.Any(), gomock.Eq(account.AccountUuid)).
+		Times(1).
+		Return(account, nil)
+	store.EXPECT().
+		ListRiskChecksByAccount(gomock.Any(), gomock.Eq(db.ListRiskChecksByAccountParams{
+			AccountUuid: account.AccountUuid,
+			Limit:       10,
+		})).
+		Times(1).
+		Return([]db.RiskCheck{riskCheck}, nil)
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+	url := fmt.Sprintf("/accounts/%s/risk-checks?page_size=10", account.AccountUuid.String())
+	request, err := http.NewRequest(http.MethodGet, url, nil)
+	require.NoError(t, err)
+	addAuthorization(t, request, server.tokenMaker, account)
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	var response []riskCheckResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	require.Len(t, response, 1)
+	require.Nil(t, response[0].TradeUUID)
+	require.Nil(t, response[0].Notional)
+	require.JSONEq(t, `[{"rule":"BUYING_POWER","message":"insufficient buying power","limit":1000,"value":1015.5}]`, string(response[0].Violations))
+}
+
+func requireBodyMatchViolations(t *testing.T, recorder *httptest.ResponseRecorder) []service.RiskRule {
+	var response problem
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	require.Equal(t, service.ErrRiskRejected.Code, response.Code)
+	require.NotEmpty(t, response.Detail)
+	rules := make([]service.RiskRule, len(response.Violations))
+	for i, violation := range response.Violations {
+		rules[i] = violation.Rule
+	}
+	return rules
+}
@@ -1,36 +1,169 @@
 package api
 
 import (
+	"database/sql"
+	"fmt"
+	"net/http"
+
 	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+	"github.com/valverdethiago/trading-api/calendar"
 	db "github.com/valverdethiago/trading-api/db/sqlc"
+	"github.com/valverdethiago/trading-api/execution"
+	"github.com/valverdethiago/trading-api/openapi"
+	"github.com/valverdethiago/trading-api/service"
+	"github.com/valverdethiago/trading-api/stream"
+	"github.com/valverdethiago/trading-api/token"
+	"github.com/valverdethiago/trading-api/util"
 )
 
 // Server serves HTTP requests for the stock trading REST API
 type Server struct {
-	queries db.Querier
-	router  *gin.Engine
+	config     util.Config
+	store      db.Store
+	tokenMaker token.Maker
+	policy     *service.Policy
+	tickSize   decimal.Decimal
+	feeRate    decimal.Decimal
+	venue      execution.Venue
+	calendar   *calendar.Calendar
+	riskEngine *service.RiskEngine
+	hub        *stream.Hub
+	document   *openapi.Document
+	router     *gin.Engine
+	// onServerError is told about the errors answered with a 5xx, when set
+	onServerError func(err error)
 }
 
-// NewServer queries a new HTTP Server for the REST API
-func NewServer(queries db.Querier) *Server {
+// NewServer creates a new HTTP Server for the REST API, the market is always open without a calendar
+// and the events are streamed from a hub of its own without one
+func NewServer(config util.Config, store db.Store, venue execution.Venue, marketCalendar *calendar.Calendar,
+	hub *stream.Hub) (*Server, error) {
+	tokenMaker, err := token.NewJWTMaker(config.TokenSymmetricKey)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create token maker: %w", err)
+	}
+	timeouts, err := newRouteTimeouts(config.RequestTimeout, config.RouteTimeouts)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse route timeouts: %w", err)
+	}
+	timeouts.unbounded(http.MethodGet, streamPath)
+	tickSize, err := decimal.NewFromString(config.DefaultTickSize)
+	if err != nil || !tickSize.IsPositive() {
+		return nil, fmt.Errorf("invalid default tick size %q", config.DefaultTickSize)
+	}
+	feeRate, err := decimal.NewFromString(config.TradeFeeRate)
+	if err != nil || feeRate.IsNegative() {
+		return nil, fmt.Errorf("invalid trade fee rate %q", config.TradeFeeRate)
+	}
+	if config.IdempotencyKeyTTL <= 0 {
+		return nil, fmt.Errorf("invalid idempotency key TTL %s", config.IdempotencyKeyTTL)
+	}
+	if marketCalendar == nil {
+		marketCalendar = calendar.AlwaysOpen()
+	}
+	if hub == nil {
+		hub = stream.NewHub(config.StreamHistorySize)
+	}
+	riskLimits, err := newRiskLimits(config)
+	if err != nil {
+		return nil, err
+	}
+	if !validOpenAPIValidation(config.OpenAPIValidation) {
+		return nil, fmt.Errorf("invalid OpenAPI validation %q", config.OpenAPIValidation)
+	}
 	server := &Server{
-		queries: queries,
-		router:  gin.Default(),
-	}
-	accountController := NewAccountController(server.queries)
-	accountController.setupRoutes(server.router)
-	addressController := NewAddressController(server.queries)
-	addressController.setupRoutes(server.router)
-	tradeController := NewTradeController(server.queries)
-	tradeController.setupRoutes(server.router)
-	return server
+		config:     config,
+		store:      store,
+		tokenMaker: tokenMaker,
+		policy:     service.NewPolicy(),
+		tickSize:   tickSize,
+		feeRate:    feeRate,
+		venue:      venue,
+		calendar:   marketCalendar,
+		riskEngine: service.NewRiskEngine(riskLimits, marketCalendar.Location(), service.DefaultRiskChecks(feeRate)...),
+		hub:        hub,
+		document:   newOpenAPIDocument(),
+		router:     gin.Default(),
+	}
+	registerValidators()
+	server.router.Use(requestOriginMiddleware())
+	if config.OpenAPIValidation != "" && config.OpenAPIValidation != openAPIValidationOff {
+		server.router.Use(openAPIValidationMiddleware(server.document, config.OpenAPIValidation))
+	}
+	server.router.Use(server.problemMiddleware())
+	server.router.Use(timeoutMiddleware(timeouts))
+	server.setupRouter()
+	return server, nil
+}
+
+func (server *Server) setupRouter() {
+	authRoutes := server.router.Group("/").
+		Use(authMiddleware(server.tokenMaker, server.store)).
+		Use(accountOwnershipMiddleware(server.policy))
+
+	idempotency := idempotencyMiddleware(service.NewIdempotencyService(server.store, server.config.IdempotencyKeyTTL))
+
+	accountController := NewAccountController(server.store, server.policy, server.tokenMaker,
+		server.config.AccessTokenDuration, server.hub)
+	accountController.setupRoutes(server.router, authRoutes, idempotency)
+	addressController := NewAddressController(server.store, server.policy)
+	addressController.setupRoutes(server.router, authRoutes)
+	tradeController := NewTradeController(server.store, server.policy, server.feeRate, server.venue,
+		server.calendar, server.riskEngine, server.hub)
+	tradeController.setupRoutes(server.router, authRoutes, idempotency)
+	ledgerController := NewLedgerController(server.store, server.policy, server.feeRate)
+	ledgerController.setupRoutes(server.router, authRoutes, idempotency)
+	positionController := NewPositionController(server.store, server.policy)
+	positionController.setupRoutes(server.router, authRoutes)
+	pnlController := NewPnLController(server.store, server.policy)
+	pnlController.setupRoutes(server.router, authRoutes)
+	instrumentController := NewInstrumentController(server.store, server.policy, server.tickSize)
+	instrumentController.setupRoutes(server.router, authRoutes)
+	webhookController := NewWebhookController(server.store, server.policy)
+	webhookController.setupRoutes(server.router, authRoutes)
+	riskController := NewRiskController(server.store, server.policy, server.riskEngine)
+	riskController.setupRoutes(server.router, authRoutes)
+	auditController := NewAuditController(server.store, server.policy)
+	auditController.setupRoutes(server.router, authRoutes)
+	// the stream also takes the token from the query, browsers can't set headers on WebSockets
+	streamRoutes := server.router.Group("/").
+		Use(queryTokenMiddleware()).
+		Use(authMiddleware(server.tokenMaker, server.store)).
+		Use(accountOwnershipMiddleware(server.policy))
+	streamController := NewStreamController(server.store, server.policy, server.hub, server.config.StreamHeartbeatInterval)
+	streamController.setupRoutes(server.router, streamRoutes)
+	marketController := NewMarketController(server.calendar)
+	marketController.setupRoutes(server.router)
+	docsController := NewDocsController(server.document)
+	docsController.setupRoutes(server.router)
+}
+
+// newRiskLimits reads the global risk limits of the config, an empty or zero limit meaning no limit
+func newRiskLimits(config util.Config) (service.RiskLimits, error) {
+	var limits service.RiskLimits
+	if config.RiskMaxOrderNotional != "" {
+		limit, err := decimal.NewFromString(config.RiskMaxOrderNotional)
+		if err != nil || limit.IsNegative() {
+			return limits, fmt.Errorf("invalid max order notional %q", config.RiskMaxOrderNotional)
+		}
+		limits.MaxOrderNotional = decimal.NullDecimal{Decimal: limit, Valid: limit.IsPositive()}
+	}
+	if config.RiskMaxDailyNotional != "" {
+		limit, err := decimal.NewFromString(config.RiskMaxDailyNotional)
+		if err != nil || limit.IsNegative() {
+			return limits, fmt.Errorf("invalid max daily notional %q", config.RiskMaxDailyNotional)
+		}
+		limits.MaxDailyNotional = decimal.NullDecimal{Decimal: limit, Valid: limit.IsPositive()}
+	}
+	if config.RiskMaxPositionQuantity < 0 {
+		return limits, fmt.Errorf("invalid max position quantity %d", config.RiskMaxPositionQuantity)
+	}
+	limits.MaxPositionQuantity = sql.NullInt64{Int64: config.RiskMaxPositionQuantity, Valid: config.RiskMaxPositionQuantity > 0}
+	return limits, nil
 }
 
 // Start runs the HTTP Server on a specific address
 func (server *Server) Start(address string) error {
 	return server.router.Run(address)
 }
-
-func errorResponse(err error) gin.H {
-	return gin.H{"error": err.Error()}
-}
@@ -0,0 +1,138 @@
+package api
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	db "github.com/valverdethiago/trading-api/db/sqlc"
+	"github.com/valverdethiago/trading-api/service"
+	"github.com/valverdethiago/trading-api/stream"
+)
+
+const streamPath = "/accounts/:id/stream"
+
+const (
+	// defaultHeartbeatInterval is the time between two heartbeats when the config doesn't set it
+	defaultHeartbeatInterval = 15 * time.Second
+	// streamWriteTimeout bounds each message written, listeners slower than that are disconnected
+	streamWriteTimeout = 10 * time.Second
+)
+
+// streamRequest query parameters of the stream, since being the last sequence received before reconnecting
+type streamRequest struct {
+	Since *uint64 `form:"since"`
+}
+
+// StreamController controller for the live streams of the events of the accounts
+type StreamController struct {
+	accountService    *service.AccountService
+	hub               *stream.Hub
+	heartbeatInterval time.Duration
+	upgrader          websocket.Upgrader
+}
+
+// NewStreamController builds a new instance of stream controller
+func NewStreamController(store db.Store, policy *service.Policy, hub *stream.Hub,
+	heartbeatInterval time.Duration) *StreamController {
+	if heartbeatInterval <= 0 {
+		heartbeatInterval = defaultHeartbeatInterval
+	}
+	return &StreamController{
+		accountService:    service.NewAccountService(store, policy),
+		hub:               hub,
+		heartbeatInterval: heartbeatInterval,
+	}
+}
+
+func (controller *StreamController) setupRoutes(router *gin.Engine, streamRoutes gin.IRoutes) {
+	streamRoutes.GET(streamPath, controller.streamEvents)
+}
+
+// streamEvents upgrades the request to a WebSocket sending the trade events of the account as they're
+// committed, after those missed since the given sequence, and heartbeats while the account is idle
+func (controller *StreamController) streamEvents(ctx *gin.Context) {
+	accountUUID, err := getAccountUUID(ctx)
+	if err != nil {
+		return
+	}
+	var req streamRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		invalidRequest(ctx, err)
+		return
+	}
+	if _, err := controller.accountService.AssertAccountExists(ctx.Request.Context(), accountUUID); err != nil {
+		ctx.Error(err)
+		return
+	}
+	// subscribed before the handshake, so that nothing published once connected is missed
+	var subscription *stream.Subscription
+	var missed []stream.Message
+	complete := true
+	if req.Since != nil {
+		subscription, missed, complete = controller.hub.Resume(accountUUID, *req.Since)
+	} else {
+		subscription = controller.hub.Subscribe(accountUUID)
+	}
+	defer subscription.Close()
+	conn, err := controller.upgrader.Upgrade(ctx.Writer, ctx.Request, nil)
+	if err != nil {
+		// the upgrader already answered the handshake
+		return
+	}
+	defer conn.Close()
+	controller.serve(conn, subscription, missed, complete)
+}
+
+func (controller *StreamController) serve(conn *websocket.Conn, subscription *stream.Subscription,
+	missed []stream.Message, complete bool) {
+	// listeners send nothing, reading only handles the control frames and notices when they leave
+	left := make(chan struct{})
+	go func() {
+		defer close(left)
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	sequence := subscription.Sequence()
+	if !complete {
+		missed = []stream.Message{{Type: stream.MessageReset, Sequence: sequence, Date: time.Now().UTC()}}
+	}
+	for _, message := range missed {
+		if err := writeStreamMessage(conn, message); err != nil {
+			return
+		}
+	}
+	heartbeat := time.NewTicker(controller.heartbeatInterval)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-left:
+			return
+		case message, ok := <-subscription.Messages():
+			if !ok {
+				// dropped for falling behind, the listener resumes from its last sequence
+				closeMessage := websocket.FormatCloseMessage(websocket.CloseTryAgainLater, "listener too slow")
+				conn.WriteControl(websocket.CloseMessage, closeMessage, time.Now().Add(streamWriteTimeout))
+				return
+			}
+			if err := writeStreamMessage(conn, message); err != nil {
+				return
+			}
+			sequence = message.Sequence
+		case <-heartbeat.C:
+			message := stream.Message{Type: stream.MessageHeartbeat, Sequence: sequence, Date: time.Now().UTC()}
+			if err := writeStreamMessage(conn, message); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func writeStreamMessage(conn *websocket.Conn, message stream.Message) error {
+	conn.SetWriteDeadline(time.Now().Add(streamWriteTimeout))
+	return conn.WriteJSON(message)
+}
@@ -0,0 +1,198 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/websocket"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+	mockdb "github.com/valverdethiago/trading-api/db/mock"
+	db "github.com/valverdethiago/trading-api/db/sqlc"
+	"github.com/valverdethiago/trading-api/service"
+	"github.com/valverdethiago/trading-api/stream"
+)
+
+func TestStreamEvents(t *testing.T) {
+	testCases := []struct {
+		name     string
+		query    string
+		expected []stream.Message
+	}{
+		{
+			name:     "Live Events",
+			expected: []stream.Message{{Type: stream.MessageEvent, Sequence: 3}},
+		}, {
+			name:  "Resume",
+			query: "since=1",
+			expected: []stream.Message{
+				{Type: stream.MessageEvent, Sequence: 2},
+				{Type: stream.MessageEvent, Sequence: 3},
+			},
+		}, {
+			name:  "Lost Events",
+			query: "since=8",
+			expected: []stream.Message{
+				{Type: stream.MessageReset, Sequence: 2},
+				{Type: stream.MessageEvent, Sequence: 3},
+			},
+		},
+	}
+
+	for i := range testCases {
+		testCase := testCases[i]
+
+		t.Run(testCase.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := newMockStore(ctrl)
+			expectAccountExists(store)
+
+			server := newTestServer(t, store)
+			server.hub.Publish(account.AccountUuid, "first")
+			server.hub.Publish(account.AccountUuid, "second")
+			conn := dialStream(t, server, testCase.query)
+			defer conn.Close()
+			server.hub.Publish(account.AccountUuid, "third")
+
+			for _, expected := range testCase.expected {
+				message := readStreamMessage(t, conn)
+				require.Equal(t, expected.Type, message.Type)
+				require.Equal(t, expected.Sequence, message.Sequence)
+			}
+		})
+	}
+}
+
+func TestStreamHeartbeats(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := newMockStore(ctrl)
+	expectAccountExists(store)
+
+	config := newTestConfig()
+	config.StreamHeartbeatInterval = 10 * time.Millisecond
+	server, err := NewServer(config, store, nil, nil, nil)
+	require.NoError(t, err)
+	server.hub.Publish(account.AccountUuid, "first")
+	conn := dialStream(t, server, "")
+	defer conn.Close()
+
+	message := readStreamMessage(t, conn)
+	require.Equal(t, stream.MessageHeartbeat, message.Type)
+	require.Equal(t, uint64(1), message.Sequence)
+}
+
+func TestStreamAuthorization(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := newMockStore(ctrl)
+	expectAccountExists(store)
+	server := newTestServer(t, store)
+	httpServer := httptest.NewServer(server.router)
+	defer httpServer.Close()
+
+	_, response, err := websocket.DefaultDialer.Dial(streamURL(httpServer, ""), nil)
+	require.Error(t, err)
+	require.Equal(t, http.StatusUnauthorized, response.StatusCode)
+
+	token, err := server.tokenMaker.CreateToken(account.AccountUuid, account.Username, string(account.Role), time.Minute)
+	require.NoError(t, err)
+	query := fmt.Sprintf("%s=%s", accessTokenQueryKey, url.QueryEscape(token))
+	conn, _, err := websocket.DefaultDialer.Dial(streamURL(httpServer, query), nil)
+	require.NoError(t, err)
+	conn.Close()
+}
+
+func TestCreateTradeBroadcastsEvent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	accountTrade := trade
+	accountTrade.AccountUuid = account.AccountUuid
+	store := newMockStore(ctrl)
+	store.EXPECT().
+		GetAccountById(gomock.Any(), gomock.Eq(account.AccountUuid)).
+		Times(1).
+		Return(account, nil)
+	expectBuyingPower(store, decimal.NewFromInt(1000000), 0)
+	expectInstrument(store)
+	store.EXPECT().
+		CreateTrade(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(accountTrade, nil)
+	store.EXPECT().
+		CreateTradeVersion(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(db.TradeVersion{}, nil)
+	expectEvents(store)
+
+	server := newTestServer(t, store)
+	subscription := server.hub.Subscribe(account.AccountUuid)
+	defer subscription.Close()
+	recorder := httptest.NewRecorder()
+	body, err := json.Marshal(tradeRequest{
+		Symbol:   trade.Symbol,
+		Quantity: trade.Quantity,
+		Side:     trade.Side,
+		Price:    &trade.Price.Decimal,
+	})
+	require.NoError(t, err)
+	url := fmt.Sprintf("/accounts/%s/trades", account.AccountUuid.String())
+	request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	require.NoError(t, err)
+	addAuthorization(t, request, server.tokenMaker, account)
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusCreated, recorder.Code)
+	require.Len(t, subscription.Messages(), 1)
+	message := <-subscription.Messages()
+	var event service.Event
+	require.NoError(t, json.Unmarshal(message.Event, &event))
+	require.Equal(t, service.EventTradeCreated, event.Type)
+	require.Equal(t, account.AccountUuid, event.AccountUUID)
+}
+
+// expectAccountExists lets the stream find the account as many times as it looks for it
+func expectAccountExists(store *mockdb.MockStore) {
+	store.EXPECT().
+		GetAccountById(gomock.Any(), gomock.Eq(account.AccountUuid)).
+		AnyTimes().
+		Return(account, nil)
+}
+
+// dialStream opens the stream of the account on a live server, closed with the test
+func dialStream(t *testing.T, server *Server, query string) *websocket.Conn {
+	httpServer := httptest.NewServer(server.router)
+	t.Cleanup(httpServer.Close)
+	request, err := http.NewRequest(http.MethodGet, httpServer.URL, nil)
+	require.NoError(t, err)
+	addAuthorization(t, request, server.tokenMaker, account)
+
+	conn, _, err := websocket.DefaultDialer.Dial(streamURL(httpServer, query), request.Header)
+	require.NoError(t, err)
+	return conn
+}
+
+func streamURL(httpServer *httptest.Server, query string) string {
+	return fmt.Sprintf("ws%s/accounts/%s/stream?%s", strings.TrimPrefix(httpServer.URL, "http"),
+		account.AccountUuid.String(), query)
+}
+
+func readStreamMessage(t *testing.T, conn *websocket.Conn) stream.Message {
+	var message stream.Message
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second)))
+	require.NoError(t, conn.ReadJSON(&message))
+	return message
+}
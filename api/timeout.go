@@ -0,0 +1,82 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// statusClientClosedRequest is the non standard status used when the client goes away before the response
+const statusClientClosedRequest = 499
+
+// routeTimeouts holds the deadline applied to each route, keyed by "METHOD /path"
+type routeTimeouts struct {
+	defaultTimeout time.Duration
+	routes         map[string]time.Duration
+}
+
+// newRouteTimeouts parses overrides in the form "GET /accounts/:id/trades=10s,POST /login=2s"
+func newRouteTimeouts(defaultTimeout time.Duration, overrides string) (routeTimeouts, error) {
+	timeouts := routeTimeouts{
+		defaultTimeout: defaultTimeout,
+		routes:         make(map[string]time.Duration),
+	}
+	for _, override := range strings.Split(overrides, ",") {
+		override = strings.TrimSpace(override)
+		if override == "" {
+			continue
+		}
+		parts := strings.SplitN(override, "=", 2)
+		if len(parts) != 2 {
+			return timeouts, fmt.Errorf("invalid route timeout %q", override)
+		}
+		route := strings.Fields(parts[0])
+		if len(route) != 2 {
+			return timeouts, fmt.Errorf("invalid route timeout %q, expected METHOD /path=duration", override)
+		}
+		timeout, err := time.ParseDuration(strings.TrimSpace(parts[1]))
+		if err != nil {
+			return timeouts, fmt.Errorf("invalid route timeout %q: %w", override, err)
+		}
+		timeouts.routes[routeKey(route[0], route[1])] = timeout
+	}
+	return timeouts, nil
+}
+
+func (timeouts routeTimeouts) forRoute(method string, path string) time.Duration {
+	if timeout, ok := timeouts.routes[routeKey(method, path)]; ok {
+		return timeout
+	}
+	return timeouts.defaultTimeout
+}
+
+// unbounded lifts the deadline of a long-lived route, whatever the overrides
+func (timeouts routeTimeouts) unbounded(method string, path string) {
+	timeouts.routes[routeKey(method, path)] = 0
+}
+
+func routeKey(method string, path string) string {
+	return strings.ToUpper(method) + " " + path
+}
+
+// timeoutMiddleware bounds the request context with the deadline configured for the matched route
+func timeoutMiddleware(timeouts routeTimeouts) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		timeout := timeouts.forRoute(ctx.Request.Method, ctx.FullPath())
+		if timeout <= 0 {
+			ctx.Next()
+			return
+		}
+		requestCtx, cancel := context.WithTimeout(ctx.Request.Context(), timeout)
+		defer cancel()
+		ctx.Request = ctx.Request.WithContext(requestCtx)
+
+		ctx.Next()
+
+		// once cancelled on return, the context can't tell a timeout from a client gone anymore
+		recordContextError(ctx)
+	}
+}
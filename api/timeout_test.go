@@ -0,0 +1,137 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	mockdb "github.com/valverdethiago/trading-api/db/mock"
+	db "github.com/valverdethiago/trading-api/db/sqlc"
+	"github.com/valverdethiago/trading-api/util"
+)
+
+func TestNewRouteTimeouts(t *testing.T) {
+	timeouts, err := newRouteTimeouts(time.Second, "GET /accounts/:id/trades=10s, post /login=2s")
+	require.NoError(t, err)
+	require.Equal(t, 10*time.Second, timeouts.forRoute(http.MethodGet, "/accounts/:id/trades"))
+	require.Equal(t, 2*time.Second, timeouts.forRoute(http.MethodPost, "/login"))
+	require.Equal(t, time.Second, timeouts.forRoute(http.MethodGet, "/accounts/:id"))
+
+	timeouts, err = newRouteTimeouts(0, "")
+	require.NoError(t, err)
+	require.Zero(t, timeouts.forRoute(http.MethodGet, "/accounts"))
+
+	for _, invalid := range []string{"GET /login", "/login=2s", "GET /login=abc"} {
+		_, err = newRouteTimeouts(time.Second, invalid)
+		require.Error(t, err, invalid)
+	}
+}
+
+func TestRequestTimeout(t *testing.T) {
+	account := createRandomAccount()
+
+	waitForCancellation := func(ctx context.Context, ID interface{}) (db.Account, error) {
+		<-ctx.Done()
+		return db.Account{}, ctx.Err()
+	}
+
+	testCases := []struct {
+		name          string
+		config        func(config *util.Config)
+		buildContext  func() (context.Context, context.CancelFunc)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "Deadline Propagated",
+			config: func(config *util.Config) {
+				config.RequestTimeout = time.Second
+				config.RouteTimeouts = "GET /accounts/:id=1m"
+			},
+			buildContext: func() (context.Context, context.CancelFunc) {
+				return context.WithCancel(context.Background())
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccountById(gomock.Any(), gomock.Eq(account.AccountUuid)).
+					Times(1).
+					DoAndReturn(func(ctx context.Context, ID interface{}) (db.Account, error) {
+						deadline, ok := ctx.Deadline()
+						require.True(t, ok)
+						require.WithinDuration(t, time.Now().Add(time.Minute), deadline, time.Second)
+						return account, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		}, {
+			name: "Deadline Exceeded",
+			config: func(config *util.Config) {
+				config.RequestTimeout = 10 * time.Millisecond
+			},
+			buildContext: func() (context.Context, context.CancelFunc) {
+				return context.WithCancel(context.Background())
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccountById(gomock.Any(), gomock.Eq(account.AccountUuid)).
+					Times(1).
+					DoAndReturn(waitForCancellation)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusGatewayTimeout, recorder.Code)
+			},
+		}, {
+			name: "Client Cancelled",
+			config: func(config *util.Config) {
+				config.RequestTimeout = time.Minute
+			},
+			buildContext: func() (context.Context, context.CancelFunc) {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+				return ctx, cancel
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccountById(gomock.Any(), gomock.Eq(account.AccountUuid)).
+					Times(1).
+					DoAndReturn(waitForCancellation)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, statusClientClosedRequest, recorder.Code)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := newMockStore(ctrl)
+			testCase.buildStubs(store)
+
+			config := newTestConfig()
+			testCase.config(&config)
+			server, err := NewServer(config, store, nil, nil, nil)
+			require.NoError(t, err)
+
+			ctx, cancel := testCase.buildContext()
+			defer cancel()
+			recorder := httptest.NewRecorder()
+			urlToTest := fmt.Sprintf("/accounts/%s", account.AccountUuid)
+			request, err := http.NewRequestWithContext(ctx, http.MethodGet, urlToTest, nil)
+			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, account)
+
+			server.router.ServeHTTP(recorder, request)
+			testCase.checkResponse(t, recorder)
+		})
+	}
+}
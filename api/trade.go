@@ -1,24 +1,73 @@
 package api
 
 import (
-	"database/sql"
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/valverdethiago/trading-api/calendar"
 	db "github.com/valverdethiago/trading-api/db/sqlc"
+	"github.com/valverdethiago/trading-api/execution"
 	"github.com/valverdethiago/trading-api/service"
 )
 
 const (
 	tradesPath     = "/accounts/:id/trades"
 	tradesPathByID = "/accounts/:id/trades/:tradeID"
+	executionsPath = "/accounts/:id/trades/:tradeID/executions"
+	versionsPath   = "/accounts/:id/trades/:tradeID/versions"
 )
 
 type tradeRequest struct {
-	Symbol   string       `json:"symbol" binding:"required"`
-	Quantity int64        `json:"quantity" binding:"required,min=1"`
-	Side     db.TradeSide `json:"side" binding:"required"`
-	Price    float64      `json:"price" binding:"required,gt=0"`
+	Symbol      string           `json:"symbol" binding:"required"`
+	Quantity    int64            `json:"quantity" binding:"required,min=1"`
+	Side        db.TradeSide     `json:"side" binding:"required"`
+	OrderType   db.OrderType     `json:"order_type,omitempty" binding:"omitempty,oneof=MARKET LIMIT STOP STOP_LIMIT"`
+	TimeInForce db.TimeInForce   `json:"time_in_force,omitempty" binding:"omitempty,oneof=DAY GTC IOC FOK"`
+	Price       *decimal.Decimal `json:"price" binding:"omitempty,gt=0"`
+	StopPrice   *decimal.Decimal `json:"stop_price" binding:"omitempty,gt=0"`
+}
+
+// replaceTradeRequest holds every amendable term, missing prices are removed from the trade
+type replaceTradeRequest struct {
+	Quantity  int64            `json:"quantity" binding:"required,min=1"`
+	Price     *decimal.Decimal `json:"price" binding:"omitempty,gt=0"`
+	StopPrice *decimal.Decimal `json:"stop_price" binding:"omitempty,gt=0"`
+}
+
+func (req replaceTradeRequest) toAmendment() service.TradeAmendment {
+	price, stopPrice := toNullDecimal(req.Price), toNullDecimal(req.StopPrice)
+	return service.TradeAmendment{
+		Quantity:  &req.Quantity,
+		Price:     &price,
+		StopPrice: &stopPrice,
+	}
+}
+
+// patchTradeRequest holds the terms to amend, missing ones are left unchanged
+type patchTradeRequest struct {
+	Quantity  *int64           `json:"quantity" binding:"omitempty,min=1"`
+	Price     *decimal.Decimal `json:"price" binding:"omitempty,gt=0"`
+	StopPrice *decimal.Decimal `json:"stop_price" binding:"omitempty,gt=0"`
+}
+
+func (req patchTradeRequest) toAmendment() (service.TradeAmendment, error) {
+	var amendment service.TradeAmendment
+	if req.Quantity == nil && req.Price == nil && req.StopPrice == nil {
+		return amendment, errors.New("Nothing to amend")
+	}
+	amendment.Quantity = req.Quantity
+	if req.Price != nil {
+		price := toNullDecimal(req.Price)
+		amendment.Price = &price
+	}
+	if req.StopPrice != nil {
+		stopPrice := toNullDecimal(req.StopPrice)
+		amendment.StopPrice = &stopPrice
+	}
+	return amendment, nil
 }
 
 type tradeIDRequest struct {
@@ -31,49 +80,55 @@ type TradeController struct {
 }
 
 // NewTradeController builds a new intance of trade controller
-func NewTradeController(queries db.Querier) *TradeController {
-	accountService := service.NewAccountService(queries)
+func NewTradeController(store db.Store, policy *service.Policy,
+	feeRate decimal.Decimal, venue execution.Venue, marketCalendar *calendar.Calendar,
+	riskEngine *service.RiskEngine, broadcaster service.EventBroadcaster) *TradeController {
+	accountService := service.NewAccountService(store, policy)
 	return &TradeController{
-		service: service.NewTradeService(queries, accountService),
+		service: service.NewTradeService(store, accountService, policy, feeRate, venue, marketCalendar, riskEngine,
+			broadcaster),
 	}
 }
 
-func (controller *TradeController) setupRoutes(router *gin.Engine) {
-	router.POST(tradesPath, controller.createTrade)
-	router.GET(tradesPath, controller.listTradesByAccount)
-	router.GET(tradesPathByID, controller.getTradeByIDAndAccountID)
-	router.DELETE(tradesPathByID, controller.cancelTradeByIDAndAccountID)
+func (controller *TradeController) setupRoutes(router *gin.Engine, authRoutes gin.IRoutes, idempotency gin.HandlerFunc) {
+	authRoutes.POST(tradesPath, idempotency, controller.createTrade)
+	authRoutes.GET(tradesPath, controller.listTradesByAccount)
+	authRoutes.GET(tradesPathByID, controller.getTradeByIDAndAccountID)
+	authRoutes.DELETE(tradesPathByID, controller.cancelTradeByIDAndAccountID)
+	authRoutes.PUT(tradesPathByID, controller.replaceTrade)
+	authRoutes.PATCH(tradesPathByID, controller.patchTrade)
+	authRoutes.GET(executionsPath, controller.listTradeExecutions)
+	authRoutes.GET(versionsPath, controller.listTradeVersions)
 }
 
 func (controller *TradeController) createTrade(ctx *gin.Context) {
 	idReq, err := getAccountIDRequest(ctx)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		invalidRequest(ctx, err)
 		return
 	}
 	req, err := getTradeRequest(ctx)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		invalidRequest(ctx, err)
 		return
 	}
 	accountUUID, err := parseUUID(idReq.ID)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		invalidRequest(ctx, err)
 		return
 	}
 	trade := db.Trade{
-		Symbol:   req.Symbol,
-		Side:     req.Side,
-		Price:    req.Price,
-		Quantity: req.Quantity,
+		Symbol:      req.Symbol,
+		Side:        req.Side,
+		Price:       toNullDecimal(req.Price),
+		Quantity:    req.Quantity,
+		OrderType:   req.OrderType,
+		TimeInForce: req.TimeInForce,
+		StopPrice:   toNullDecimal(req.StopPrice),
 	}
-	dbTrade, err := controller.service.CreateTrade(trade, accountUUID)
+	dbTrade, err := controller.service.CreateTrade(ctx.Request.Context(), getActor(ctx), trade, accountUUID)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
-		} else {
-			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
-		}
+		ctx.Error(err)
 		return
 	}
 	ctx.JSON(http.StatusCreated, dbTrade)
@@ -82,83 +137,171 @@ func (controller *TradeController) createTrade(ctx *gin.Context) {
 func (controller *TradeController) listTradesByAccount(ctx *gin.Context) {
 	idReq, err := getAccountIDRequest(ctx)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		invalidRequest(ctx, err)
 		return
 	}
 	accountUUID, err := parseUUID(idReq.ID)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, err)
+		invalidRequest(ctx, err)
+		return
+	}
+	var req listTradesRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		invalidRequest(ctx, err)
+		return
+	}
+	filter, err := req.toFilter()
+	if err != nil {
+		invalidRequest(ctx, err)
 		return
 	}
-	dbTrades, err := controller.service.ListTradesByAccount(accountUUID)
+	page, err := controller.service.ListTradesByAccount(ctx.Request.Context(), accountUUID, filter)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
-		} else {
-			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
-		}
+		ctx.Error(err)
 		return
 	}
-	ctx.JSON(http.StatusOK, dbTrades)
+	if page.NextCursor != nil {
+		setNextPageHeaders(ctx, encodeTradeCursor(*page.NextCursor))
+	}
+	ctx.JSON(http.StatusOK, page.Trades)
 }
 
 func (controller *TradeController) getTradeByIDAndAccountID(ctx *gin.Context) {
 	accountIDReq, err := getAccountIDRequest(ctx)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		invalidRequest(ctx, err)
 		return
 	}
 	tradeIDReq, err := getTradeIDRequest(ctx)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		invalidRequest(ctx, err)
 		return
 	}
 	accountUUID, err := parseUUID(accountIDReq.ID)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		invalidRequest(ctx, err)
 		return
 	}
 	tradeUUID, err := parseUUID(tradeIDReq.ID)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		invalidRequest(ctx, err)
 		return
 	}
-	dbTrade, err := controller.service.FindByIDAndAccountID(tradeUUID, accountUUID)
+	dbTrade, err := controller.service.FindByIDAndAccountID(ctx.Request.Context(), tradeUUID, accountUUID)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		ctx.Error(err)
 		return
 	}
-	ctx.JSON(http.StatusCreated, dbTrade)
+	ctx.JSON(http.StatusOK, dbTrade)
+}
+
+func (controller *TradeController) listTradeExecutions(ctx *gin.Context) {
+	accountIDReq, err := getAccountIDRequest(ctx)
+	if err != nil {
+		invalidRequest(ctx, err)
+		return
+	}
+	tradeIDReq, err := getTradeIDRequest(ctx)
+	if err != nil {
+		invalidRequest(ctx, err)
+		return
+	}
+	accountUUID, err := parseUUID(accountIDReq.ID)
+	if err != nil {
+		invalidRequest(ctx, err)
+		return
+	}
+	tradeUUID, err := parseUUID(tradeIDReq.ID)
+	if err != nil {
+		invalidRequest(ctx, err)
+		return
+	}
+	dbExecutions, err := controller.service.ListTradeExecutions(ctx.Request.Context(), tradeUUID, accountUUID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, dbExecutions)
+}
+
+func (controller *TradeController) replaceTrade(ctx *gin.Context) {
+	accountUUID, tradeUUID, err := getTradeURI(ctx)
+	if err != nil {
+		return
+	}
+	var req replaceTradeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		invalidRequest(ctx, err)
+		return
+	}
+	controller.amendTrade(ctx, accountUUID, tradeUUID, req.toAmendment())
+}
+
+func (controller *TradeController) patchTrade(ctx *gin.Context) {
+	accountUUID, tradeUUID, err := getTradeURI(ctx)
+	if err != nil {
+		return
+	}
+	var req patchTradeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		invalidRequest(ctx, err)
+		return
+	}
+	amendment, err := req.toAmendment()
+	if err != nil {
+		invalidRequest(ctx, err)
+		return
+	}
+	controller.amendTrade(ctx, accountUUID, tradeUUID, amendment)
+}
+
+func (controller *TradeController) amendTrade(ctx *gin.Context, accountUUID uuid.UUID, tradeUUID uuid.UUID,
+	amendment service.TradeAmendment) {
+	dbTrade, err := controller.service.AmendTradeByIDAndAccountID(ctx.Request.Context(), tradeUUID, accountUUID, amendment)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, dbTrade)
+}
+
+func (controller *TradeController) listTradeVersions(ctx *gin.Context) {
+	accountUUID, tradeUUID, err := getTradeURI(ctx)
+	if err != nil {
+		return
+	}
+	dbVersions, err := controller.service.ListTradeVersions(ctx.Request.Context(), tradeUUID, accountUUID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, dbVersions)
 }
 
 func (controller *TradeController) cancelTradeByIDAndAccountID(ctx *gin.Context) {
 	accountIDReq, err := getAccountIDRequest(ctx)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		invalidRequest(ctx, err)
 		return
 	}
 	tradeIDReq, err := getTradeIDRequest(ctx)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		invalidRequest(ctx, err)
 		return
 	}
 	accountUUID, err := parseUUID(accountIDReq.ID)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		invalidRequest(ctx, err)
 		return
 	}
 	tradeUUID, err := parseUUID(tradeIDReq.ID)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		invalidRequest(ctx, err)
 		return
 	}
-	dbTrade, err := controller.service.CancelTradeByIDAndAccountID(tradeUUID, accountUUID)
+	dbTrade, err := controller.service.CancelTradeByIDAndAccountID(ctx.Request.Context(), tradeUUID, accountUUID)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
-		} else {
-			ctx.JSON(http.StatusConflict, errorResponse(err))
-		}
+		ctx.Error(err)
 		return
 	}
 	ctx.JSON(http.StatusAccepted, dbTrade)
@@ -168,16 +311,39 @@ func getTradeRequest(ctx *gin.Context) (tradeRequest, error) {
 	var req tradeRequest
 	var err error
 	if err = ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		invalidRequest(ctx, err)
 	}
 	return req, err
 }
 
+// getTradeURI parses the account and trade IDs of the path, answering 400 when they're invalid
+func getTradeURI(ctx *gin.Context) (uuid.UUID, uuid.UUID, error) {
+	var accountUUID, tradeUUID uuid.UUID
+	accountIDReq, err := getAccountIDRequest(ctx)
+	if err != nil {
+		return accountUUID, tradeUUID, err
+	}
+	tradeIDReq, err := getTradeIDRequest(ctx)
+	if err != nil {
+		return accountUUID, tradeUUID, err
+	}
+	accountUUID, err = parseUUID(accountIDReq.ID)
+	if err != nil {
+		invalidRequest(ctx, err)
+		return accountUUID, tradeUUID, err
+	}
+	tradeUUID, err = parseUUID(tradeIDReq.ID)
+	if err != nil {
+		invalidRequest(ctx, err)
+	}
+	return accountUUID, tradeUUID, err
+}
+
 func getTradeIDRequest(ctx *gin.Context) (tradeIDRequest, error) {
 	var req tradeIDRequest
 	var err error
 	if err = ctx.ShouldBindUri(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		invalidRequest(ctx, err)
 	}
 	return req, err
 }
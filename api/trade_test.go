@@ -11,12 +11,17 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/require"
 	mockdb "github.com/valverdethiago/trading-api/db/mock"
 	db "github.com/valverdethiago/trading-api/db/sqlc"
+	"github.com/valverdethiago/trading-api/execution"
+	mockexecution "github.com/valverdethiago/trading-api/execution/mock"
+	"github.com/valverdethiago/trading-api/service"
 	"github.com/valverdethiago/trading-api/util"
 )
 
@@ -25,7 +30,7 @@ func TestCreateTrade(t *testing.T) {
 		name          string
 		accountID     string
 		buildRequest  func() tradeRequest
-		buildStubs    func(querier *mockdb.MockQuerier)
+		buildStubs    func(store *mockdb.MockStore)
 		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
 	}{
 		{
@@ -36,22 +41,207 @@ func TestCreateTrade(t *testing.T) {
 					Symbol:   trade.Symbol,
 					Quantity: trade.Quantity,
 					Side:     trade.Side,
-					Price:    trade.Price,
+					Price:    &trade.Price.Decimal,
 				}
 			},
-			buildStubs: func(querier *mockdb.MockQuerier) {
-				querier.EXPECT().
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
 					GetAccountById(gomock.Any(), gomock.Eq(account.AccountUuid)).
 					Times(1).
 					Return(account, nil)
-				querier.EXPECT().
+				expectBuyingPower(store, decimal.NewFromInt(1000000), 0)
+				expectInstrument(store)
+				store.EXPECT().
 					CreateTrade(gomock.Any(), gomock.Any()).
-					Return(trade, nil)
+					Times(1).
+					DoAndReturn(func(ctx context.Context, arg db.CreateTradeParams) (db.Trade, error) {
+						require.True(t, trade.Price.Decimal.Equal(arg.Price.Decimal))
+						return trade, nil
+					})
+				store.EXPECT().
+					CreateTradeVersion(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.TradeVersion{}, nil)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusCreated, recorder.Code)
 				requireBodyMatchTrade(t, recorder.Body, trade)
 			},
+		}, {
+			name:      "Insufficient Funds",
+			accountID: account.AccountUuid.String(),
+			buildRequest: func() tradeRequest {
+				return tradeRequest{
+					Symbol:   trade.Symbol,
+					Quantity: 10,
+					Side:     db.TradeSideBUY,
+					Price:    decimalPointer("100"),
+				}
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccountById(gomock.Any(), gomock.Eq(account.AccountUuid)).
+					Times(1).
+					Return(account, nil)
+				// the fee of 1.00 isn't covered
+				expectBuyingPower(store, decimal.NewFromInt(1000), 0)
+				expectInstrument(store)
+				store.EXPECT().
+					CreateTrade(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+			},
+		}, {
+			name:      "Insufficient Shares",
+			accountID: account.AccountUuid.String(),
+			buildRequest: func() tradeRequest {
+				return tradeRequest{
+					Symbol:   trade.Symbol,
+					Quantity: 10,
+					Side:     db.TradeSideSELL,
+					Price:    decimalPointer("100"),
+				}
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccountById(gomock.Any(), gomock.Eq(account.AccountUuid)).
+					Times(1).
+					Return(account, nil)
+				expectBuyingPower(store, decimal.NewFromInt(1000000), 9)
+				expectInstrument(store)
+				store.EXPECT().
+					CreateTrade(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+			},
+		}, {
+			name:      "Price Finer Than Tick Size",
+			accountID: account.AccountUuid.String(),
+			buildRequest: func() tradeRequest {
+				return tradeRequest{
+					Symbol:   trade.Symbol,
+					Quantity: trade.Quantity,
+					Side:     trade.Side,
+					Price:    decimalPointer("10.005"),
+				}
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccountById(gomock.Any(), gomock.Eq(account.AccountUuid)).
+					Times(1).
+					Return(account, nil)
+				expectInstrument(store)
+				store.EXPECT().
+					CreateTrade(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		}, {
+			name:      "Quantity Not Multiple Of Lot Size",
+			accountID: account.AccountUuid.String(),
+			buildRequest: func() tradeRequest {
+				return tradeRequest{
+					Symbol:   trade.Symbol,
+					Quantity: 150,
+					Side:     trade.Side,
+					Price:    &trade.Price.Decimal,
+				}
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccountById(gomock.Any(), gomock.Eq(account.AccountUuid)).
+					Times(1).
+					Return(account, nil)
+				store.EXPECT().
+					GetInstrument(gomock.Any(), gomock.Eq(trade.Symbol)).
+					Times(1).
+					Return(db.Instrument{Symbol: trade.Symbol, TickSize: decimal.RequireFromString("0.01"), LotSize: 100, Tradable: true}, nil)
+				store.EXPECT().
+					CreateTrade(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		}, {
+			name:      "Unknown Symbol",
+			accountID: account.AccountUuid.String(),
+			buildRequest: func() tradeRequest {
+				return tradeRequest{
+					Symbol:   trade.Symbol,
+					Quantity: trade.Quantity,
+					Side:     trade.Side,
+					Price:    &trade.Price.Decimal,
+				}
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccountById(gomock.Any(), gomock.Eq(account.AccountUuid)).
+					Times(1).
+					Return(account, nil)
+				store.EXPECT().
+					GetInstrument(gomock.Any(), gomock.Eq(trade.Symbol)).
+					Times(1).
+					Return(db.Instrument{}, sql.ErrNoRows)
+				store.EXPECT().
+					CreateTrade(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+			},
+		}, {
+			name:      "Symbol Not Tradable",
+			accountID: account.AccountUuid.String(),
+			buildRequest: func() tradeRequest {
+				return tradeRequest{
+					Symbol:   trade.Symbol,
+					Quantity: trade.Quantity,
+					Side:     trade.Side,
+					Price:    &trade.Price.Decimal,
+				}
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccountById(gomock.Any(), gomock.Eq(account.AccountUuid)).
+					Times(1).
+					Return(account, nil)
+				store.EXPECT().
+					GetInstrument(gomock.Any(), gomock.Eq(trade.Symbol)).
+					Times(1).
+					Return(db.Instrument{Symbol: trade.Symbol, TickSize: decimal.RequireFromString("0.01"), LotSize: 1}, nil)
+				store.EXPECT().
+					CreateTrade(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+			},
+		}, {
+			name:      "Zero Price",
+			accountID: account.AccountUuid.String(),
+			buildRequest: func() tradeRequest {
+				return tradeRequest{
+					Symbol:   trade.Symbol,
+					Quantity: trade.Quantity,
+					Side:     trade.Side,
+					Price:    decimalPointer("0"),
+				}
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateTrade(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
 		}, {
 			name:      "Missing account ID",
 			accountID: "",
@@ -60,10 +250,10 @@ func TestCreateTrade(t *testing.T) {
 					Symbol:   trade.Symbol,
 					Quantity: trade.Quantity,
 					Side:     trade.Side,
-					Price:    trade.Price,
+					Price:    &trade.Price.Decimal,
 				}
 			},
-			buildStubs: func(querier *mockdb.MockQuerier) {},
+			buildStubs: func(store *mockdb.MockStore) {},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusBadRequest, recorder.Code)
 			},
@@ -73,7 +263,7 @@ func TestCreateTrade(t *testing.T) {
 			buildRequest: func() tradeRequest {
 				return tradeRequest{}
 			},
-			buildStubs: func(querier *mockdb.MockQuerier) {},
+			buildStubs: func(store *mockdb.MockStore) {},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusBadRequest, recorder.Code)
 			},
@@ -85,9 +275,9 @@ func TestCreateTrade(t *testing.T) {
 					Symbol:   trade.Symbol,
 					Quantity: trade.Quantity,
 					Side:     trade.Side,
-					Price:    trade.Price}
+					Price:    &trade.Price.Decimal}
 			},
-			buildStubs: func(querier *mockdb.MockQuerier) {},
+			buildStubs: func(store *mockdb.MockStore) {},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusBadRequest, recorder.Code)
 			},
@@ -99,10 +289,10 @@ func TestCreateTrade(t *testing.T) {
 					Symbol:   trade.Symbol,
 					Quantity: trade.Quantity,
 					Side:     trade.Side,
-					Price:    trade.Price}
+					Price:    &trade.Price.Decimal}
 			},
-			buildStubs: func(querier *mockdb.MockQuerier) {
-				querier.EXPECT().
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
 					GetAccountById(gomock.Any(), gomock.Eq(account.AccountUuid)).
 					Times(1).
 					Return(db.Account{}, sql.ErrNoRows)
@@ -110,6 +300,30 @@ func TestCreateTrade(t *testing.T) {
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusNotFound, recorder.Code)
 			},
+		}, {
+			name:      "Account Not Approved",
+			accountID: account.AccountUuid.String(),
+			buildRequest: func() tradeRequest {
+				return tradeRequest{
+					Symbol:   trade.Symbol,
+					Quantity: trade.Quantity,
+					Side:     trade.Side,
+					Price:    &trade.Price.Decimal}
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				pendingAccount := account
+				pendingAccount.Status = db.AccountStatusPENDING
+				store.EXPECT().
+					GetAccountById(gomock.Any(), gomock.Eq(account.AccountUuid)).
+					Times(1).
+					Return(pendingAccount, nil)
+				store.EXPECT().
+					CreateTrade(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
 		}, {
 			name:      "Internal Server Error",
 			accountID: account.AccountUuid.String(),
@@ -118,10 +332,10 @@ func TestCreateTrade(t *testing.T) {
 					Symbol:   trade.Symbol,
 					Quantity: trade.Quantity,
 					Side:     trade.Side,
-					Price:    trade.Price}
+					Price:    &trade.Price.Decimal}
 			},
-			buildStubs: func(querier *mockdb.MockQuerier) {
-				querier.EXPECT().
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
 					GetAccountById(gomock.Any(), gomock.Eq(account.AccountUuid)).
 					Times(1).
 					Return(db.Account{}, sql.ErrConnDone)
@@ -135,16 +349,18 @@ func TestCreateTrade(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
-		querier := mockdb.NewMockQuerier(ctrl)
+		store := newMockStore(ctrl)
 		//build stubs
-		testCase.buildStubs(querier)
+		testCase.buildStubs(store)
+		expectEvents(store)
 
 		// start server and send the request
-		server := NewServer(querier)
+		server := newTestServer(t, store)
 		recorder := httptest.NewRecorder()
 		url := fmt.Sprintf("/accounts/%s/trades", testCase.accountID)
 		request, err := http.NewRequest(http.MethodPost, url, sendObjectAsRequestBody(t, testCase.buildRequest()))
 		require.NoError(t, err)
+		addAuthorization(t, request, server.tokenMaker, account)
 
 		server.router.ServeHTTP(recorder, request)
 		//check response
@@ -152,48 +368,187 @@ func TestCreateTrade(t *testing.T) {
 	}
 }
 
+func TestCreateTradeAsStaff(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := newMockStore(ctrl)
+	store.EXPECT().
+		CreateTrade(gomock.Any(), gomock.Any()).
+		Times(0)
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+	url := fmt.Sprintf("/accounts/%s/trades", account.AccountUuid.String())
+	request, err := http.NewRequest(http.MethodPost, url, sendObjectAsRequestBody(t, tradeRequest{
+		Symbol:   trade.Symbol,
+		Quantity: trade.Quantity,
+		Side:     trade.Side,
+		Price:    &trade.Price.Decimal,
+	}))
+	require.NoError(t, err)
+	addAuthorization(t, request, server.tokenMaker, staffAccount)
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusForbidden, recorder.Code)
+}
+
 func TestListTrade(t *testing.T) {
 	testCases := []struct {
 		name          string
 		accountID     string
-		buildStubs    func(querier *mockdb.MockQuerier)
+		query         string
+		buildStubs    func(store *mockdb.MockStore)
 		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
 	}{
 		{
 			name:      "OK",
 			accountID: account.AccountUuid.String(),
-			buildStubs: func(querier *mockdb.MockQuerier) {
-				querier.EXPECT().
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
 					GetAccountById(gomock.Any(), gomock.Eq(account.AccountUuid)).
 					Times(1).
 					Return(account, nil)
-				querier.EXPECT().
-					ListTradesByAccount(gomock.Any(), account.AccountUuid).
+				store.EXPECT().
+					ListTradesByAccountAsc(gomock.Any(), gomock.Eq(db.ListTradesByAccountAscParams{
+						AccountUuid: account.AccountUuid,
+						PageSize:    service.DefaultTradePageSize + 1,
+					})).
+					Times(1).
 					Return(trades, nil)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Empty(t, recorder.Header().Get(nextCursorHeaderKey))
 				requireBodyMatchTradeList(t, recorder.Body, trades)
 			},
+		}, {
+			name:      "Filtered Descending Page",
+			accountID: account.AccountUuid.String(),
+			query:     "status=SUBMITTED&side=BUY&symbol=ABC&created_from=2021-01-01T00:00:00Z&sort=-created_date&limit=5",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccountById(gomock.Any(), gomock.Eq(account.AccountUuid)).
+					Times(1).
+					Return(account, nil)
+				store.EXPECT().
+					ListTradesByAccountDesc(gomock.Any(), gomock.Eq(db.ListTradesByAccountDescParams{
+						AccountUuid: account.AccountUuid,
+						Status:      string(db.TradeStatusSUBMITTED),
+						Side:        string(db.TradeSideBUY),
+						Symbol:      "ABC",
+						CreatedFrom: sql.NullTime{Time: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC), Valid: true},
+						PageSize:    6,
+					})).
+					Times(1).
+					Return(trades[:6], nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				nextCursor := recorder.Header().Get(nextCursorHeaderKey)
+				cursor, err := decodeTradeCursor(nextCursor)
+				require.NoError(t, err)
+				require.Equal(t, trades[4].TradeUuid, cursor.TradeUUID)
+				require.Contains(t, recorder.Header().Get(linkHeaderKey), "cursor="+nextCursor)
+				require.Contains(t, recorder.Header().Get(linkHeaderKey), `rel="next"`)
+				var bodyTrades []db.Trade
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &bodyTrades))
+				require.Len(t, bodyTrades, 5)
+				for i, bodyTrade := range bodyTrades {
+					requireTradeEqual(t, trades[i], bodyTrade)
+				}
+			},
+		}, {
+			name:      "Partially Filled",
+			accountID: account.AccountUuid.String(),
+			query:     "status=PARTIALLY_FILLED",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccountById(gomock.Any(), gomock.Eq(account.AccountUuid)).
+					Times(1).
+					Return(account, nil)
+				store.EXPECT().
+					ListTradesByAccountAsc(gomock.Any(), gomock.Eq(db.ListTradesByAccountAscParams{
+						AccountUuid: account.AccountUuid,
+						Status:      string(db.TradeStatusPARTIALLY_FILLED),
+						PageSize:    service.DefaultTradePageSize + 1,
+					})).
+					Times(1).
+					Return(trades[:2], nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchTradeList(t, recorder.Body, trades[:2])
+			},
+		}, {
+			name:      "Next Page",
+			accountID: account.AccountUuid.String(),
+			query: "cursor=" + encodeTradeCursor(service.TradeCursor{
+				CreatedDate: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+				TradeUUID:   trade.TradeUuid,
+			}),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccountById(gomock.Any(), gomock.Eq(account.AccountUuid)).
+					Times(1).
+					Return(account, nil)
+				store.EXPECT().
+					ListTradesByAccountAsc(gomock.Any(), gomock.Eq(db.ListTradesByAccountAscParams{
+						AccountUuid:      account.AccountUuid,
+						AfterCreatedDate: sql.NullTime{Time: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC), Valid: true},
+						AfterTradeUuid:   trade.TradeUuid,
+						PageSize:         service.DefaultTradePageSize + 1,
+					})).
+					Times(1).
+					Return(nil, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.JSONEq(t, "[]", recorder.Body.String())
+			},
+		}, {
+			name:       "Invalid Cursor",
+			accountID:  account.AccountUuid.String(),
+			query:      "cursor=" + util.RandomString(10),
+			buildStubs: func(store *mockdb.MockStore) {},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		}, {
+			name:       "Invalid Status",
+			accountID:  account.AccountUuid.String(),
+			query:      "status=UNKNOWN",
+			buildStubs: func(store *mockdb.MockStore) {},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		}, {
+			name:       "Limit Too Large",
+			accountID:  account.AccountUuid.String(),
+			query:      "limit=1000",
+			buildStubs: func(store *mockdb.MockStore) {},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
 		}, {
 			name:       "Missing account ID",
 			accountID:  "",
-			buildStubs: func(querier *mockdb.MockQuerier) {},
+			buildStubs: func(store *mockdb.MockStore) {},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusBadRequest, recorder.Code)
 			},
 		}, {
 			name:       "Invalid AccountID",
 			accountID:  util.RandomAlphaNumericString(4),
-			buildStubs: func(querier *mockdb.MockQuerier) {},
+			buildStubs: func(store *mockdb.MockStore) {},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusBadRequest, recorder.Code)
 			},
 		}, {
 			name:      "Unexistent Account",
 			accountID: account.AccountUuid.String(),
-			buildStubs: func(querier *mockdb.MockQuerier) {
-				querier.EXPECT().
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
 					GetAccountById(gomock.Any(), gomock.Eq(account.AccountUuid)).
 					Times(1).
 					Return(db.Account{}, sql.ErrNoRows)
@@ -204,8 +559,8 @@ func TestListTrade(t *testing.T) {
 		}, {
 			name:      "Internal Server Error",
 			accountID: account.AccountUuid.String(),
-			buildStubs: func(querier *mockdb.MockQuerier) {
-				querier.EXPECT().
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
 					GetAccountById(gomock.Any(), gomock.Eq(account.AccountUuid)).
 					Times(1).
 					Return(db.Account{}, sql.ErrConnDone)
@@ -219,16 +574,18 @@ func TestListTrade(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
-		querier := mockdb.NewMockQuerier(ctrl)
+		store := newMockStore(ctrl)
 		//build stubs
-		testCase.buildStubs(querier)
+		testCase.buildStubs(store)
+		expectEvents(store)
 
 		// start server and send the request
-		server := NewServer(querier)
+		server := newTestServer(t, store)
 		recorder := httptest.NewRecorder()
-		url := fmt.Sprintf("/accounts/%s/trades", testCase.accountID)
+		url := fmt.Sprintf("/accounts/%s/trades?%s", testCase.accountID, testCase.query)
 		request, err := http.NewRequest(http.MethodGet, url, nil)
 		require.NoError(t, err)
+		addAuthorization(t, request, server.tokenMaker, account)
 
 		server.router.ServeHTTP(recorder, request)
 		//check response
@@ -243,7 +600,26 @@ func requireBodyMatchTrade(t *testing.T, body *bytes.Buffer, trade db.Trade) {
 	var bodyTrade db.Trade
 	err = json.Unmarshal(data, &bodyTrade)
 	require.NoError(t, err)
-	require.Equal(t, trade, bodyTrade)
+	requireTradeEqual(t, trade, bodyTrade)
+}
+
+// requireTradeEqual compares prices by value, 10.5 and 10.50 being the same price
+func requireTradeEqual(t *testing.T, expected db.Trade, actual db.Trade) {
+	requireNullDecimalEqual(t, expected.Price, actual.Price)
+	requireNullDecimalEqual(t, expected.StopPrice, actual.StopPrice)
+	expected.Price, actual.Price = decimal.NullDecimal{}, decimal.NullDecimal{}
+	expected.StopPrice, actual.StopPrice = decimal.NullDecimal{}, decimal.NullDecimal{}
+	require.Equal(t, expected, actual)
+}
+
+func requireNullDecimalEqual(t *testing.T, expected decimal.NullDecimal, actual decimal.NullDecimal) {
+	require.Equal(t, expected.Valid, actual.Valid)
+	require.True(t, expected.Decimal.Equal(actual.Decimal), "expected %s, got %s", expected.Decimal, actual.Decimal)
+}
+
+func decimalPointer(value string) *decimal.Decimal {
+	result := decimal.RequireFromString(value)
+	return &result
 }
 
 func requireBodyMatchTradeList(t *testing.T, body *bytes.Buffer, trades []db.Trade) {
@@ -255,7 +631,7 @@ func requireBodyMatchTradeList(t *testing.T, body *bytes.Buffer, trades []db.Tra
 	require.NoError(t, err)
 	for _, trade := range trades {
 		dbTrade := findTradeInList(bodyTrades, trade)
-		require.Equal(t, dbTrade, trade)
+		requireTradeEqual(t, trade, dbTrade)
 	}
 }
 
@@ -274,26 +650,27 @@ func TestCancelTrade(t *testing.T) {
 		name          string
 		accountID     string
 		tradeID       string
-		buildStubs    func(querier *mockdb.MockQuerier)
+		buildStubs    func(store *mockdb.MockStore)
 		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
 	}{
 		{
 			name:      "OK",
 			accountID: account.AccountUuid.String(),
 			tradeID:   trade.TradeUuid.String(),
-			buildStubs: func(querier *mockdb.MockQuerier) {
-				querier.EXPECT().
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
 					GetAccountById(gomock.Any(), gomock.Eq(account.AccountUuid)).
 					Times(1).
 					Return(account, nil)
-				querier.EXPECT().
-					GetTradeById(gomock.Any(), gomock.Eq(trade.TradeUuid)).
+				store.EXPECT().
+					GetTradeByIdForUpdate(gomock.Any(), gomock.Eq(trade.TradeUuid)).
 					Times(1).
 					Return(expectedSubmittedTrade, nil)
-				querier.EXPECT().
+				store.EXPECT().
 					UpdateTradeStatus(gomock.Any(), gomock.Eq(db.UpdateTradeStatusParams{
-						TradeUuid: trade.TradeUuid,
 						Status:    db.TradeStatusCANCELLED,
+						UpdatedBy: actedBy(account),
+						TradeUuid: trade.TradeUuid,
 					})).
 					Return(expectedCanceledTrade, nil)
 			},
@@ -305,13 +682,13 @@ func TestCancelTrade(t *testing.T) {
 			name:      "Trade doesn't belong to the account",
 			accountID: account.AccountUuid.String(),
 			tradeID:   trade.TradeUuid.String(),
-			buildStubs: func(querier *mockdb.MockQuerier) {
-				querier.EXPECT().
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
 					GetAccountById(gomock.Any(), gomock.Eq(account.AccountUuid)).
 					Times(1).
 					Return(account, nil)
-				querier.EXPECT().
-					GetTradeById(gomock.Any(), gomock.Eq(trade.TradeUuid)).
+				store.EXPECT().
+					GetTradeByIdForUpdate(gomock.Any(), gomock.Eq(trade.TradeUuid)).
 					Times(1).
 					DoAndReturn(func(ctx context.Context, tradeUuid uuid.UUID) (db.Trade, error) {
 						return db.Trade{
@@ -326,26 +703,27 @@ func TestCancelTrade(t *testing.T) {
 					})
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
-				require.Equal(t, http.StatusConflict, recorder.Code)
+				require.Equal(t, http.StatusNotFound, recorder.Code)
 			},
 		},
 		{
 			name:      "OK",
 			accountID: account.AccountUuid.String(),
 			tradeID:   trade.TradeUuid.String(),
-			buildStubs: func(querier *mockdb.MockQuerier) {
-				querier.EXPECT().
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
 					GetAccountById(gomock.Any(), gomock.Eq(account.AccountUuid)).
 					Times(1).
 					Return(account, nil)
-				querier.EXPECT().
-					GetTradeById(gomock.Any(), gomock.Eq(trade.TradeUuid)).
+				store.EXPECT().
+					GetTradeByIdForUpdate(gomock.Any(), gomock.Eq(trade.TradeUuid)).
 					Times(1).
 					Return(expectedSubmittedTrade, nil)
-				querier.EXPECT().
+				store.EXPECT().
 					UpdateTradeStatus(gomock.Any(), gomock.Eq(db.UpdateTradeStatusParams{
-						TradeUuid: trade.TradeUuid,
 						Status:    db.TradeStatusCANCELLED,
+						UpdatedBy: actedBy(account),
+						TradeUuid: trade.TradeUuid,
 					})).
 					Return(expectedCanceledTrade, nil)
 			},
@@ -353,17 +731,43 @@ func TestCancelTrade(t *testing.T) {
 				require.Equal(t, http.StatusAccepted, recorder.Code)
 				requireBodyMatchTrade(t, recorder.Body, expectedCanceledTrade)
 			},
+		}, {
+			name:      "Partially Filled",
+			accountID: account.AccountUuid.String(),
+			tradeID:   trade.TradeUuid.String(),
+			buildStubs: func(store *mockdb.MockStore) {
+				partiallyFilledTrade := expectedSubmittedTrade
+				partiallyFilledTrade.Status = db.TradeStatusPARTIALLY_FILLED
+				store.EXPECT().
+					GetAccountById(gomock.Any(), gomock.Eq(account.AccountUuid)).
+					Times(1).
+					Return(account, nil)
+				store.EXPECT().
+					GetTradeByIdForUpdate(gomock.Any(), gomock.Eq(trade.TradeUuid)).
+					Times(1).
+					Return(partiallyFilledTrade, nil)
+				store.EXPECT().
+					UpdateTradeStatus(gomock.Any(), gomock.Eq(db.UpdateTradeStatusParams{
+						Status:    db.TradeStatusCANCELLED,
+						UpdatedBy: actedBy(account),
+						TradeUuid: trade.TradeUuid,
+					})).
+					Return(expectedCanceledTrade, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusAccepted, recorder.Code)
+			},
 		}, {
 			name:      "Already Cancelled",
 			accountID: account.AccountUuid.String(),
 			tradeID:   trade.TradeUuid.String(),
-			buildStubs: func(querier *mockdb.MockQuerier) {
-				querier.EXPECT().
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
 					GetAccountById(gomock.Any(), gomock.Eq(account.AccountUuid)).
 					Times(1).
 					Return(account, nil)
-				querier.EXPECT().
-					GetTradeById(gomock.Any(), gomock.Eq(trade.TradeUuid)).
+				store.EXPECT().
+					GetTradeByIdForUpdate(gomock.Any(), gomock.Eq(trade.TradeUuid)).
 					Times(1).
 					Return(expectedCanceledTrade, nil)
 			},
@@ -374,7 +778,7 @@ func TestCancelTrade(t *testing.T) {
 			name:       "Missing account ID",
 			accountID:  "",
 			tradeID:    trade.TradeUuid.String(),
-			buildStubs: func(querier *mockdb.MockQuerier) {},
+			buildStubs: func(store *mockdb.MockStore) {},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusBadRequest, recorder.Code)
 			},
@@ -384,20 +788,1079 @@ func TestCancelTrade(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
-		querier := mockdb.NewMockQuerier(ctrl)
+		store := newMockStore(ctrl)
 		//build stubs
-		testCase.buildStubs(querier)
+		testCase.buildStubs(store)
+		expectEvents(store)
 
 		// start server and send the request
-		server := NewServer(querier)
+		server := newTestServer(t, store)
 		recorder := httptest.NewRecorder()
 		url := fmt.Sprintf("/accounts/%s/trades/%s", testCase.accountID, testCase.tradeID)
 		log.Print(url)
 		request, err := http.NewRequest(http.MethodDelete, url, nil)
 		require.NoError(t, err)
+		addAuthorization(t, request, server.tokenMaker, account)
 
 		server.router.ServeHTTP(recorder, request)
 		//check response
 		testCase.checkResponse(t, recorder)
 	}
 }
+
+func TestCreateTradeWithStringPrice(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := newMockStore(ctrl)
+	store.EXPECT().
+		GetAccountById(gomock.Any(), gomock.Eq(account.AccountUuid)).
+		Times(1).
+		Return(account, nil)
+	expectBuyingPower(store, decimal.NewFromInt(1000000), 0)
+	expectEvents(store)
+	expectInstrument(store)
+	store.EXPECT().
+		CreateTrade(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(ctx context.Context, arg db.CreateTradeParams) (db.Trade, error) {
+			require.Equal(t, "10.15", arg.Price.Decimal.String())
+			return trade, nil
+		})
+	store.EXPECT().
+		CreateTradeVersion(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(db.TradeVersion{}, nil)
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+	url := fmt.Sprintf("/accounts/%s/trades", account.AccountUuid.String())
+	body := fmt.Sprintf(`{"symbol":"%s","quantity":%d,"side":"BUY","price":"10.15"}`, trade.Symbol, trade.Quantity)
+	request, err := http.NewRequest(http.MethodPost, url, bytes.NewBufferString(body))
+	require.NoError(t, err)
+	addAuthorization(t, request, server.tokenMaker, account)
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusCreated, recorder.Code)
+	require.Contains(t, recorder.Body.String(), fmt.Sprintf(`"price":%s`, trade.Price.Decimal.String()))
+}
+
+func TestCreateTradeSubmitsToVenue(t *testing.T) {
+	failedTrade := trade
+	failedTrade.Status = db.TradeStatusFAILED
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore, venue *mockexecution.MockVenue)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "Submitted",
+			buildStubs: func(store *mockdb.MockStore, venue *mockexecution.MockVenue) {
+				store.EXPECT().
+					GetAccountById(gomock.Any(), gomock.Eq(account.AccountUuid)).
+					Times(1).
+					Return(account, nil)
+				expectBuyingPower(store, decimal.NewFromInt(1000000), 0)
+				expectInstrument(store)
+				store.EXPECT().
+					CreateTrade(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(trade, nil)
+				store.EXPECT().
+					CreateTradeVersion(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.TradeVersion{}, nil)
+				venue.EXPECT().
+					Submit(gomock.Any(), gomock.Eq(trade)).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+				requireBodyMatchTrade(t, recorder.Body, trade)
+			},
+		}, {
+			name: "Venue Unavailable",
+			buildStubs: func(store *mockdb.MockStore, venue *mockexecution.MockVenue) {
+				store.EXPECT().
+					GetAccountById(gomock.Any(), gomock.Eq(account.AccountUuid)).
+					Times(1).
+					Return(account, nil)
+				expectBuyingPower(store, decimal.NewFromInt(1000000), 0)
+				expectInstrument(store)
+				store.EXPECT().
+					CreateTrade(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(trade, nil)
+				store.EXPECT().
+					CreateTradeVersion(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.TradeVersion{}, nil)
+				venue.EXPECT().
+					Submit(gomock.Any(), gomock.Eq(trade)).
+					Times(1).
+					Return(execution.ErrVenueUnavailable)
+				store.EXPECT().
+					UpdateTradeStatus(gomock.Any(), gomock.Eq(db.UpdateTradeStatusParams{
+						Status:    db.TradeStatusFAILED,
+						UpdatedBy: actedBy(account),
+						TradeUuid: trade.TradeUuid,
+					})).
+					Times(1).
+					Return(failedTrade, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+				requireBodyMatchTrade(t, recorder.Body, failedTrade)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := newMockStore(ctrl)
+			venue := mockexecution.NewMockVenue(ctrl)
+			testCase.buildStubs(store, venue)
+			expectEvents(store)
+
+			server := newTestServerWithVenue(t, store, venue)
+			recorder := httptest.NewRecorder()
+			url := fmt.Sprintf("/accounts/%s/trades", account.AccountUuid.String())
+			request, err := http.NewRequest(http.MethodPost, url, sendObjectAsRequestBody(t, tradeRequest{
+				Symbol:   trade.Symbol,
+				Quantity: trade.Quantity,
+				Side:     trade.Side,
+				Price:    &trade.Price.Decimal,
+			}))
+			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, account)
+
+			server.router.ServeHTTP(recorder, request)
+			testCase.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestCreateTradeOutsideSession(t *testing.T) {
+	testCases := []struct {
+		name          string
+		orderType     db.OrderType
+		timeInForce   db.TimeInForce
+		buildStubs    func(store *mockdb.MockStore, venue *mockexecution.MockVenue)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:        "Market Order Rejected",
+			orderType:   db.OrderTypeMARKET,
+			timeInForce: db.TimeInForceDAY,
+			buildStubs: func(store *mockdb.MockStore, venue *mockexecution.MockVenue) {
+				store.EXPECT().
+					CreateTrade(gomock.Any(), gomock.Any()).
+					Times(0)
+				venue.EXPECT().
+					Submit(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+			},
+		}, {
+			name:        "IOC Limit Order Rejected",
+			orderType:   db.OrderTypeLIMIT,
+			timeInForce: db.TimeInForceIOC,
+			buildStubs: func(store *mockdb.MockStore, venue *mockexecution.MockVenue) {
+				store.EXPECT().
+					CreateTrade(gomock.Any(), gomock.Any()).
+					Times(0)
+				venue.EXPECT().
+					Submit(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+			},
+		}, {
+			name:        "GTC Limit Order Queued",
+			orderType:   db.OrderTypeLIMIT,
+			timeInForce: db.TimeInForceGTC,
+			buildStubs: func(store *mockdb.MockStore, venue *mockexecution.MockVenue) {
+				store.EXPECT().
+					GetAccountById(gomock.Any(), gomock.Eq(account.AccountUuid)).
+					Times(1).
+					Return(account, nil)
+				expectBuyingPower(store, decimal.NewFromInt(1000000), 0)
+				expectInstrument(store)
+				store.EXPECT().
+					CreateTrade(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(trade, nil)
+				store.EXPECT().
+					CreateTradeVersion(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.TradeVersion{}, nil)
+				venue.EXPECT().
+					Submit(gomock.Any(), gomock.Eq(trade)).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+				requireBodyMatchTrade(t, recorder.Body, trade)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := newMockStore(ctrl)
+			venue := mockexecution.NewMockVenue(ctrl)
+			testCase.buildStubs(store, venue)
+			expectEvents(store)
+
+			server := newTestServerWithCalendar(t, store, venue, newClosedCalendar(t))
+			recorder := httptest.NewRecorder()
+			url := fmt.Sprintf("/accounts/%s/trades", account.AccountUuid.String())
+			req := tradeRequest{
+				Symbol:      trade.Symbol,
+				Quantity:    trade.Quantity,
+				Side:        trade.Side,
+				OrderType:   testCase.orderType,
+				TimeInForce: testCase.timeInForce,
+			}
+			if testCase.orderType == db.OrderTypeLIMIT {
+				req.Price = &trade.Price.Decimal
+			}
+			request, err := http.NewRequest(http.MethodPost, url, sendObjectAsRequestBody(t, req))
+			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, account)
+
+			server.router.ServeHTTP(recorder, request)
+			testCase.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestCreateTradeOrderTypes(t *testing.T) {
+	testCases := []struct {
+		name           string
+		body           string
+		expectedStatus int
+		checkParams    func(t *testing.T, arg db.CreateTradeParams)
+	}{
+		{
+			name:           "Default Limit GTC",
+			body:           `{"symbol":"AAPL","quantity":10,"side":"BUY","price":10.15}`,
+			expectedStatus: http.StatusCreated,
+			checkParams: func(t *testing.T, arg db.CreateTradeParams) {
+				require.Equal(t, db.OrderTypeLIMIT, arg.OrderType)
+				require.Equal(t, db.TimeInForceGTC, arg.TimeInForce)
+				require.False(t, arg.StopPrice.Valid)
+			},
+		}, {
+			name:           "Symbol Normalized",
+			body:           `{"symbol":" aapl ","quantity":10,"side":"BUY","price":10.15}`,
+			expectedStatus: http.StatusCreated,
+			checkParams: func(t *testing.T, arg db.CreateTradeParams) {
+				require.Equal(t, "AAPL", arg.Symbol)
+			},
+		}, {
+			name:           "Blank Symbol",
+			body:           `{"symbol":"  ","quantity":10,"side":"BUY","price":10.15}`,
+			expectedStatus: http.StatusBadRequest,
+		}, {
+			name:           "Market IOC",
+			body:           `{"symbol":"AAPL","quantity":10,"side":"BUY","order_type":"MARKET","time_in_force":"IOC"}`,
+			expectedStatus: http.StatusCreated,
+			checkParams: func(t *testing.T, arg db.CreateTradeParams) {
+				require.Equal(t, db.OrderTypeMARKET, arg.OrderType)
+				require.Equal(t, db.TimeInForceIOC, arg.TimeInForce)
+				require.False(t, arg.Price.Valid)
+			},
+		}, {
+			name:           "Default Market Day",
+			body:           `{"symbol":"AAPL","quantity":10,"side":"BUY","order_type":"MARKET"}`,
+			expectedStatus: http.StatusCreated,
+			checkParams: func(t *testing.T, arg db.CreateTradeParams) {
+				require.Equal(t, db.OrderTypeMARKET, arg.OrderType)
+				require.Equal(t, db.TimeInForceDAY, arg.TimeInForce)
+			},
+		}, {
+			name:           "Stop Limit Day",
+			body:           `{"symbol":"AAPL","quantity":10,"side":"SELL","order_type":"STOP_LIMIT","time_in_force":"DAY","price":9.5,"stop_price":9.75}`,
+			expectedStatus: http.StatusCreated,
+			checkParams: func(t *testing.T, arg db.CreateTradeParams) {
+				require.Equal(t, db.OrderTypeSTOP_LIMIT, arg.OrderType)
+				require.Equal(t, "9.5", arg.Price.Decimal.String())
+				require.Equal(t, "9.75", arg.StopPrice.Decimal.String())
+			},
+		}, {
+			name:           "Market With Price",
+			body:           `{"symbol":"AAPL","quantity":10,"side":"BUY","order_type":"MARKET","time_in_force":"DAY","price":10}`,
+			expectedStatus: http.StatusBadRequest,
+		}, {
+			name:           "Market GTC",
+			body:           `{"symbol":"AAPL","quantity":10,"side":"BUY","order_type":"MARKET","time_in_force":"GTC"}`,
+			expectedStatus: http.StatusBadRequest,
+		}, {
+			name:           "Limit Without Price",
+			body:           `{"symbol":"AAPL","quantity":10,"side":"BUY","order_type":"LIMIT"}`,
+			expectedStatus: http.StatusBadRequest,
+		}, {
+			name:           "Limit With Stop Price",
+			body:           `{"symbol":"AAPL","quantity":10,"side":"BUY","order_type":"LIMIT","price":10,"stop_price":9}`,
+			expectedStatus: http.StatusBadRequest,
+		}, {
+			name:           "Stop Without Stop Price",
+			body:           `{"symbol":"AAPL","quantity":10,"side":"BUY","order_type":"STOP"}`,
+			expectedStatus: http.StatusBadRequest,
+		}, {
+			name:           "Stop Limit Without Price",
+			body:           `{"symbol":"AAPL","quantity":10,"side":"BUY","order_type":"STOP_LIMIT","stop_price":10}`,
+			expectedStatus: http.StatusBadRequest,
+		}, {
+			name:           "Stop Price Finer Than Tick Size",
+			body:           `{"symbol":"AAPL","quantity":10,"side":"BUY","order_type":"STOP","stop_price":10.001}`,
+			expectedStatus: http.StatusBadRequest,
+		}, {
+			name:           "Unknown Order Type",
+			body:           `{"symbol":"AAPL","quantity":10,"side":"BUY","order_type":"ICEBERG","price":10}`,
+			expectedStatus: http.StatusBadRequest,
+		}, {
+			name:           "Unknown Time In Force",
+			body:           `{"symbol":"AAPL","quantity":10,"side":"BUY","time_in_force":"GTD","price":10}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for i := range testCases {
+		testCase := testCases[i]
+
+		t.Run(testCase.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := newMockStore(ctrl)
+			if testCase.expectedStatus == http.StatusCreated {
+				store.EXPECT().
+					GetAccountById(gomock.Any(), gomock.Eq(account.AccountUuid)).
+					Times(1).
+					Return(account, nil)
+				// the market buys are valued at the last price
+				store.EXPECT().
+					ListLatestPrices(gomock.Any(), gomock.Any()).
+					AnyTimes().
+					Return([]db.ListLatestPricesRow{{Symbol: "AAPL", Price: decimal.NewFromInt(10)}}, nil)
+				expectBuyingPower(store, decimal.NewFromInt(1000000), 100)
+				expectEvents(store)
+				expectInstrument(store)
+				store.EXPECT().
+					CreateTrade(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(ctx context.Context, arg db.CreateTradeParams) (db.Trade, error) {
+						testCase.checkParams(t, arg)
+						return trade, nil
+					})
+				store.EXPECT().
+					CreateTradeVersion(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.TradeVersion{}, nil)
+			} else {
+				store.EXPECT().
+					GetAccountById(gomock.Any(), gomock.Eq(account.AccountUuid)).
+					AnyTimes().
+					Return(account, nil)
+				expectInstrument(store)
+				store.EXPECT().
+					CreateTrade(gomock.Any(), gomock.Any()).
+					Times(0)
+			}
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+			url := fmt.Sprintf("/accounts/%s/trades", account.AccountUuid.String())
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewBufferString(testCase.body))
+			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, account)
+
+			server.router.ServeHTTP(recorder, request)
+			require.Equal(t, testCase.expectedStatus, recorder.Code)
+		})
+	}
+}
+
+func TestGetTrade(t *testing.T) {
+	otherAccountTrade := expectedSubmittedTrade
+	otherAccountTrade.AccountUuid = uuid.New()
+
+	testCases := []struct {
+		name          string
+		tradeID       string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:    "OK",
+			tradeID: trade.TradeUuid.String(),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccountById(gomock.Any(), gomock.Eq(account.AccountUuid)).
+					Times(1).
+					Return(account, nil)
+				store.EXPECT().
+					GetTradeById(gomock.Any(), gomock.Eq(trade.TradeUuid)).
+					Times(1).
+					Return(expectedSubmittedTrade, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				var bodyTrade db.Trade
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &bodyTrade))
+				require.Equal(t, expectedSubmittedTrade.TradeUuid, bodyTrade.TradeUuid)
+				require.Equal(t, expectedSubmittedTrade.Status, bodyTrade.Status)
+			},
+		}, {
+			name:    "Not Found",
+			tradeID: trade.TradeUuid.String(),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccountById(gomock.Any(), gomock.Eq(account.AccountUuid)).
+					Times(1).
+					Return(account, nil)
+				store.EXPECT().
+					GetTradeById(gomock.Any(), gomock.Eq(trade.TradeUuid)).
+					Times(1).
+					Return(db.Trade{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		}, {
+			name:    "Trade Of Another Account",
+			tradeID: trade.TradeUuid.String(),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccountById(gomock.Any(), gomock.Eq(account.AccountUuid)).
+					Times(1).
+					Return(account, nil)
+				store.EXPECT().
+					GetTradeById(gomock.Any(), gomock.Eq(trade.TradeUuid)).
+					Times(1).
+					Return(otherAccountTrade, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		}, {
+			name:    "Internal Error",
+			tradeID: trade.TradeUuid.String(),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccountById(gomock.Any(), gomock.Eq(account.AccountUuid)).
+					Times(1).
+					Return(account, nil)
+				store.EXPECT().
+					GetTradeById(gomock.Any(), gomock.Eq(trade.TradeUuid)).
+					Times(1).
+					Return(db.Trade{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		}, {
+			name:       "Invalid Trade ID",
+			tradeID:    "invalid",
+			buildStubs: func(store *mockdb.MockStore) {},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		testCase := testCases[i]
+
+		t.Run(testCase.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := newMockStore(ctrl)
+			testCase.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+			url := fmt.Sprintf("/accounts/%s/trades/%s", account.AccountUuid, testCase.tradeID)
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, account)
+
+			server.router.ServeHTTP(recorder, request)
+			testCase.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestListTradeExecutions(t *testing.T) {
+	executions := []db.TradeExecution{
+		{
+			ExecutionUuid: uuid.New(),
+			TradeUuid:     trade.TradeUuid,
+			Quantity:      3,
+			Price:         decimal.RequireFromString("10.15"),
+			ExecutedDate:  time.Now().UTC().Truncate(time.Second),
+			Fee:           decimal.RequireFromString("0.03"),
+		}, {
+			ExecutionUuid: uuid.New(),
+			TradeUuid:     trade.TradeUuid,
+			Quantity:      2,
+			Price:         decimal.RequireFromString("10.2"),
+			ExecutedDate:  time.Now().UTC().Truncate(time.Second),
+			Fee:           decimal.RequireFromString("0.02"),
+		},
+	}
+
+	testCases := []struct {
+		name          string
+		accountID     string
+		tradeID       string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "OK",
+			accountID: account.AccountUuid.String(),
+			tradeID:   trade.TradeUuid.String(),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccountById(gomock.Any(), gomock.Eq(account.AccountUuid)).
+					Times(1).
+					Return(account, nil)
+				store.EXPECT().
+					GetTradeById(gomock.Any(), gomock.Eq(trade.TradeUuid)).
+					Times(1).
+					Return(expectedSubmittedTrade, nil)
+				store.EXPECT().
+					ListTradeExecutions(gomock.Any(), gomock.Eq(trade.TradeUuid)).
+					Times(1).
+					Return(executions, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				var bodyExecutions []db.TradeExecution
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &bodyExecutions))
+				require.Len(t, bodyExecutions, len(executions))
+				for i := range executions {
+					require.Equal(t, executions[i].ExecutionUuid, bodyExecutions[i].ExecutionUuid)
+					require.Equal(t, executions[i].Quantity, bodyExecutions[i].Quantity)
+					require.True(t, executions[i].Price.Equal(bodyExecutions[i].Price))
+					require.True(t, executions[i].ExecutedDate.Equal(bodyExecutions[i].ExecutedDate))
+					require.True(t, executions[i].Fee.Equal(bodyExecutions[i].Fee))
+				}
+			},
+		}, {
+			name:      "No Executions",
+			accountID: account.AccountUuid.String(),
+			tradeID:   trade.TradeUuid.String(),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccountById(gomock.Any(), gomock.Eq(account.AccountUuid)).
+					Times(1).
+					Return(account, nil)
+				store.EXPECT().
+					GetTradeById(gomock.Any(), gomock.Eq(trade.TradeUuid)).
+					Times(1).
+					Return(expectedSubmittedTrade, nil)
+				store.EXPECT().
+					ListTradeExecutions(gomock.Any(), gomock.Eq(trade.TradeUuid)).
+					Times(1).
+					Return(nil, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, "[]", recorder.Body.String())
+			},
+		}, {
+			name:      "Trade Not Found",
+			accountID: account.AccountUuid.String(),
+			tradeID:   trade.TradeUuid.String(),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccountById(gomock.Any(), gomock.Eq(account.AccountUuid)).
+					Times(1).
+					Return(account, nil)
+				store.EXPECT().
+					GetTradeById(gomock.Any(), gomock.Eq(trade.TradeUuid)).
+					Times(1).
+					Return(db.Trade{}, sql.ErrNoRows)
+				store.EXPECT().
+					ListTradeExecutions(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		}, {
+			name:      "Trade doesn't belong to the account",
+			accountID: account.AccountUuid.String(),
+			tradeID:   trade.TradeUuid.String(),
+			buildStubs: func(store *mockdb.MockStore) {
+				otherTrade := trade
+				otherTrade.AccountUuid = uuid.New()
+				store.EXPECT().
+					GetAccountById(gomock.Any(), gomock.Eq(account.AccountUuid)).
+					Times(1).
+					Return(account, nil)
+				store.EXPECT().
+					GetTradeById(gomock.Any(), gomock.Eq(trade.TradeUuid)).
+					Times(1).
+					Return(otherTrade, nil)
+				store.EXPECT().
+					ListTradeExecutions(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		}, {
+			name:       "Invalid Trade ID",
+			accountID:  account.AccountUuid.String(),
+			tradeID:    "invalid",
+			buildStubs: func(store *mockdb.MockStore) {},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		testCase := testCases[i]
+
+		t.Run(testCase.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := newMockStore(ctrl)
+			testCase.buildStubs(store)
+			expectEvents(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+			url := fmt.Sprintf("/accounts/%s/trades/%s/executions", testCase.accountID, testCase.tradeID)
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, account)
+
+			server.router.ServeHTTP(recorder, request)
+			testCase.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestAmendTrade(t *testing.T) {
+	openTrade := trade
+	openTrade.AccountUuid = account.AccountUuid
+	openTrade.Status = db.TradeStatusSUBMITTED
+	openTrade.Quantity, openTrade.FilledQuantity, openTrade.RemainingQuantity = 10, 4, 6
+	openTrade.Version = 1
+
+	amendedTrade := openTrade
+	amendedTrade.Version = 2
+
+	testCases := []struct {
+		name          string
+		method        string
+		body          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "Replace",
+			method: http.MethodPut,
+			body:   `{"quantity":12,"price":10.5}`,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccountById(gomock.Any(), gomock.Eq(account.AccountUuid)).
+					Times(1).
+					Return(account, nil)
+				store.EXPECT().
+					GetTradeByIdForUpdate(gomock.Any(), gomock.Eq(trade.TradeUuid)).
+					Times(1).
+					Return(openTrade, nil)
+				expectBuyingPower(store, decimal.NewFromInt(1000000), 0)
+				expectInstrument(store)
+				store.EXPECT().
+					UpdateTrade(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(ctx context.Context, arg db.UpdateTradeParams) (db.Trade, error) {
+						require.Equal(t, int64(12), arg.Quantity)
+						require.Equal(t, "10.5", arg.Price.Decimal.String())
+						require.False(t, arg.StopPrice.Valid)
+						require.Equal(t, openTrade.Symbol, arg.Symbol)
+						require.Equal(t, openTrade.Side, arg.Side)
+						require.Equal(t, db.TradeStatusSUBMITTED, arg.Status)
+						return amendedTrade, nil
+					})
+				store.EXPECT().
+					CreateTradeVersion(gomock.Any(), gomock.Eq(db.CreateTradeVersionParams{
+						TradeUuid: amendedTrade.TradeUuid,
+						Version:   amendedTrade.Version,
+						Quantity:  amendedTrade.Quantity,
+						Price:     amendedTrade.Price,
+						StopPrice: amendedTrade.StopPrice,
+					})).
+					Times(1).
+					Return(db.TradeVersion{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchTrade(t, recorder.Body, amendedTrade)
+			},
+		}, {
+			name:   "Patch Price",
+			method: http.MethodPatch,
+			body:   `{"price":"11.25"}`,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccountById(gomock.Any(), gomock.Eq(account.AccountUuid)).
+					Times(1).
+					Return(account, nil)
+				store.EXPECT().
+					GetTradeByIdForUpdate(gomock.Any(), gomock.Eq(trade.TradeUuid)).
+					Times(1).
+					Return(openTrade, nil)
+				expectBuyingPower(store, decimal.NewFromInt(1000000), 0)
+				expectInstrument(store)
+				store.EXPECT().
+					UpdateTrade(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(ctx context.Context, arg db.UpdateTradeParams) (db.Trade, error) {
+						require.Equal(t, openTrade.Quantity, arg.Quantity)
+						require.Equal(t, "11.25", arg.Price.Decimal.String())
+						return amendedTrade, nil
+					})
+				store.EXPECT().
+					CreateTradeVersion(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.TradeVersion{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		}, {
+			name:   "Patch Quantity Down To Filled",
+			method: http.MethodPatch,
+			body:   `{"quantity":4}`,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccountById(gomock.Any(), gomock.Eq(account.AccountUuid)).
+					Times(1).
+					Return(account, nil)
+				store.EXPECT().
+					GetTradeByIdForUpdate(gomock.Any(), gomock.Eq(trade.TradeUuid)).
+					Times(1).
+					Return(openTrade, nil)
+				expectBuyingPower(store, decimal.NewFromInt(1000000), 0)
+				expectInstrument(store)
+				store.EXPECT().
+					UpdateTrade(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(ctx context.Context, arg db.UpdateTradeParams) (db.Trade, error) {
+						require.Equal(t, int64(4), arg.Quantity)
+						require.Equal(t, db.TradeStatusCOMPLETED, arg.Status)
+						return amendedTrade, nil
+					})
+				store.EXPECT().
+					CreateTradeVersion(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.TradeVersion{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		}, {
+			name:   "Market Buy Without Price",
+			method: http.MethodPatch,
+			body:   `{"quantity":1000000}`,
+			buildStubs: func(store *mockdb.MockStore) {
+				marketTrade := openTrade
+				marketTrade.OrderType, marketTrade.TimeInForce = db.OrderTypeMARKET, db.TimeInForceDAY
+				marketTrade.Price = decimal.NullDecimal{}
+				store.EXPECT().
+					GetAccountById(gomock.Any(), gomock.Eq(account.AccountUuid)).
+					Times(1).
+					Return(account, nil)
+				store.EXPECT().
+					GetTradeByIdForUpdate(gomock.Any(), gomock.Eq(trade.TradeUuid)).
+					Times(1).
+					Return(marketTrade, nil)
+				// the symbol never traded
+				expectBuyingPower(store, decimal.NewFromInt(1000), 0)
+				expectInstrument(store)
+				store.EXPECT().
+					UpdateTrade(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				requireProblem(t, recorder, http.StatusUnprocessableEntity, service.ErrNoPriceEstimate.Code)
+			},
+		}, {
+			name:   "Quantity Below Filled",
+			method: http.MethodPatch,
+			body:   `{"quantity":3}`,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccountById(gomock.Any(), gomock.Eq(account.AccountUuid)).
+					Times(1).
+					Return(account, nil)
+				store.EXPECT().
+					GetTradeByIdForUpdate(gomock.Any(), gomock.Eq(trade.TradeUuid)).
+					Times(1).
+					Return(openTrade, nil)
+				store.EXPECT().
+					UpdateTrade(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		}, {
+			name:   "Price Finer Than Tick Size",
+			method: http.MethodPatch,
+			body:   `{"price":10.001}`,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccountById(gomock.Any(), gomock.Eq(account.AccountUuid)).
+					Times(1).
+					Return(account, nil)
+				store.EXPECT().
+					GetTradeByIdForUpdate(gomock.Any(), gomock.Eq(trade.TradeUuid)).
+					Times(1).
+					Return(openTrade, nil)
+				expectInstrument(store)
+				store.EXPECT().
+					UpdateTrade(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		}, {
+			name:   "Stop Price On Limit Order",
+			method: http.MethodPatch,
+			body:   `{"stop_price":9}`,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccountById(gomock.Any(), gomock.Eq(account.AccountUuid)).
+					Times(1).
+					Return(account, nil)
+				store.EXPECT().
+					GetTradeByIdForUpdate(gomock.Any(), gomock.Eq(trade.TradeUuid)).
+					Times(1).
+					Return(openTrade, nil)
+				store.EXPECT().
+					UpdateTrade(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		}, {
+			name:   "Not Open",
+			method: http.MethodPut,
+			body:   `{"quantity":12,"price":10.5}`,
+			buildStubs: func(store *mockdb.MockStore) {
+				completedTrade := openTrade
+				completedTrade.Status = db.TradeStatusCOMPLETED
+				store.EXPECT().
+					GetAccountById(gomock.Any(), gomock.Eq(account.AccountUuid)).
+					Times(1).
+					Return(account, nil)
+				store.EXPECT().
+					GetTradeByIdForUpdate(gomock.Any(), gomock.Eq(trade.TradeUuid)).
+					Times(1).
+					Return(completedTrade, nil)
+				store.EXPECT().
+					UpdateTrade(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		}, {
+			name:   "Trade doesn't belong to the account",
+			method: http.MethodPatch,
+			body:   `{"quantity":12}`,
+			buildStubs: func(store *mockdb.MockStore) {
+				otherTrade := openTrade
+				otherTrade.AccountUuid = uuid.New()
+				store.EXPECT().
+					GetAccountById(gomock.Any(), gomock.Eq(account.AccountUuid)).
+					Times(1).
+					Return(account, nil)
+				store.EXPECT().
+					GetTradeByIdForUpdate(gomock.Any(), gomock.Eq(trade.TradeUuid)).
+					Times(1).
+					Return(otherTrade, nil)
+				store.EXPECT().
+					UpdateTrade(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		}, {
+			name:   "Trade Not Found",
+			method: http.MethodPatch,
+			body:   `{"quantity":12}`,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccountById(gomock.Any(), gomock.Eq(account.AccountUuid)).
+					Times(1).
+					Return(account, nil)
+				store.EXPECT().
+					GetTradeByIdForUpdate(gomock.Any(), gomock.Eq(trade.TradeUuid)).
+					Times(1).
+					Return(db.Trade{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		}, {
+			name:       "Replace Without Quantity",
+			method:     http.MethodPut,
+			body:       `{"price":10.5}`,
+			buildStubs: func(store *mockdb.MockStore) {},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		}, {
+			name:       "Empty Patch",
+			method:     http.MethodPatch,
+			body:       `{}`,
+			buildStubs: func(store *mockdb.MockStore) {},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		testCase := testCases[i]
+
+		t.Run(testCase.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := newMockStore(ctrl)
+			testCase.buildStubs(store)
+			expectEvents(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+			url := fmt.Sprintf("/accounts/%s/trades/%s", account.AccountUuid.String(), trade.TradeUuid.String())
+			request, err := http.NewRequest(testCase.method, url, bytes.NewBufferString(testCase.body))
+			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, account)
+
+			server.router.ServeHTTP(recorder, request)
+			testCase.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestListTradeVersions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	versions := []db.TradeVersion{
+		{TradeUuid: trade.TradeUuid, Version: 1, Quantity: 10, Price: trade.Price},
+		{TradeUuid: trade.TradeUuid, Version: 2, Quantity: 12, Price: trade.Price},
+	}
+	store := newMockStore(ctrl)
+	store.EXPECT().
+		GetAccountById(gomock.Any(), gomock.Eq(account.AccountUuid)).
+		Times(1).
+		Return(account, nil)
+	store.EXPECT().
+		GetTradeById(gomock.Any(), gomock.Eq(trade.TradeUuid)).
+		Times(1).
+		Return(expectedSubmittedTrade, nil)
+	store.EXPECT().
+		ListTradeVersions(gomock.Any(), gomock.Eq(trade.TradeUuid)).
+		Times(1).
+		Return(versions, nil)
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+	url := fmt.Sprintf("/accounts/%s/trades/%s/versions", account.AccountUuid.String(), trade.TradeUuid.String())
+	request, err := http.NewRequest(http.MethodGet, url, nil)
+	require.NoError(t, err)
+	addAuthorization(t, request, server.tokenMaker, account)
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	var bodyVersions []db.TradeVersion
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &bodyVersions))
+	require.Len(t, bodyVersions, 2)
+	require.Equal(t, int32(1), bodyVersions[0].Version)
+	require.Equal(t, int64(12), bodyVersions[1].Quantity)
+}
+
+// TestCreateTradeSubmitsAfterRequestCancelled keeps handing the committed trade over to the venue when the
+// client goes away meanwhile, with the origin of the request
+func TestCreateTradeSubmitsAfterRequestCancelled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	requestCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	store := newMockStore(ctrl)
+	store.EXPECT().
+		GetAccountById(gomock.Any(), gomock.Eq(account.AccountUuid)).
+		Times(1).
+		Return(account, nil)
+	expectBuyingPower(store, decimal.NewFromInt(1000000), 0)
+	expectInstrument(store)
+	store.EXPECT().
+		CreateTrade(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(trade, nil)
+	store.EXPECT().
+		CreateTradeVersion(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ context.Context, _ db.CreateTradeVersionParams) (db.TradeVersion, error) {
+			cancel()
+			return db.TradeVersion{}, nil
+		})
+	expectEvents(store)
+	venue := mockexecution.NewMockVenue(ctrl)
+	venue.EXPECT().
+		Submit(gomock.Any(), gomock.Eq(trade)).
+		Times(1).
+		DoAndReturn(func(ctx context.Context, _ db.Trade) error {
+			require.NoError(t, ctx.Err())
+			require.Equal(t, account.AccountUuid.String(), service.OriginFromContext(ctx).Actor)
+			return nil
+		})
+
+	server := newTestServerWithVenue(t, store, venue)
+	recorder := httptest.NewRecorder()
+	url := fmt.Sprintf("/accounts/%s/trades", account.AccountUuid.String())
+	request, err := http.NewRequestWithContext(requestCtx, http.MethodPost, url, sendObjectAsRequestBody(t, tradeRequest{
+		Symbol:   trade.Symbol,
+		Quantity: trade.Quantity,
+		Side:     trade.Side,
+		Price:    &trade.Price.Decimal,
+	}))
+	require.NoError(t, err)
+	addAuthorization(t, request, server.tokenMaker, account)
+
+	server.router.ServeHTTP(recorder, request)
+}
@@ -5,6 +5,7 @@ import (
 	"log"
 
 	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
 )
 
 func parseUUID(ID string) (uuid.UUID, error) {
@@ -16,3 +17,10 @@ func parseUUID(ID string) (uuid.UUID, error) {
 	}
 	return result, nil
 }
+
+func toNullDecimal(value *decimal.Decimal) decimal.NullDecimal {
+	if value == nil {
+		return decimal.NullDecimal{}
+	}
+	return decimal.NullDecimal{Decimal: *value, Valid: true}
+}
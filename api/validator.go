@@ -0,0 +1,43 @@
+package api
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/shopspring/decimal"
+)
+
+func init() {
+	// prices are written as JSON numbers, they're still read from numbers or strings
+	decimal.MarshalJSONWithoutQuotes = true
+}
+
+// registerValidators lets the binding tags (required, gt, min...) work on decimal fields and names the
+// invalid fields as the clients send them
+func registerValidators() {
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterCustomTypeFunc(decimalValue, decimal.Decimal{})
+		v.RegisterTagNameFunc(fieldName)
+	}
+}
+
+// fieldName is the name of the field in the body, the query or the path of the request
+func fieldName(field reflect.StructField) string {
+	for _, tag := range []string{"json", "form", "uri"} {
+		name := strings.SplitN(field.Tag.Get(tag), ",", 2)[0]
+		if name != "" && name != "-" {
+			return name
+		}
+	}
+	return field.Name
+}
+
+func decimalValue(field reflect.Value) interface{} {
+	if value, ok := field.Interface().(decimal.Decimal); ok {
+		result, _ := value.Float64()
+		return result
+	}
+	return nil
+}
@@ -0,0 +1,289 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	db "github.com/valverdethiago/trading-api/db/sqlc"
+	"github.com/valverdethiago/trading-api/service"
+)
+
+const (
+	webhooksPath         = "/accounts/:id/webhooks"
+	webhooksPathByID     = "/accounts/:id/webhooks/:webhookID"
+	deadLettersPath      = "/accounts/:id/webhooks/:webhookID/dead-letters"
+	deadLetterReplayPath = "/accounts/:id/webhooks/:webhookID/dead-letters/:deadLetterID/replay"
+)
+
+type webhookIDRequest struct {
+	ID string `uri:"webhookID" binding:"required"`
+}
+
+type deadLetterIDRequest struct {
+	ID string `uri:"deadLetterID" binding:"required"`
+}
+
+type createWebhookRequest struct {
+	URL    string   `json:"url" binding:"required,url"`
+	Events []string `json:"events" binding:"required,min=1"`
+}
+
+type updateWebhookRequest struct {
+	URL    string   `json:"url" binding:"required,url"`
+	Events []string `json:"events" binding:"required,min=1"`
+	Active *bool    `json:"active"`
+}
+
+// listDeadLettersRequest query parameters to limit the dead letters listed
+type listDeadLettersRequest struct {
+	PageSize int32 `form:"page_size" binding:"omitempty,min=1,max=100"`
+}
+
+type webhookResponse struct {
+	WebhookUUID uuid.UUID `json:"webhook_uuid"`
+	URL         string    `json:"url"`
+	Events      []string  `json:"events"`
+	Active      bool      `json:"active"`
+	// Secret signs the payloads, it's only returned when the webhook is created
+	Secret      string    `json:"secret,omitempty"`
+	CreatedDate time.Time `json:"created_date"`
+	UpdatedDate time.Time `json:"updated_date"`
+}
+
+func newWebhookResponse(subscription db.WebhookSubscription) webhookResponse {
+	return webhookResponse{
+		WebhookUUID: subscription.WebhookSubscriptionUuid,
+		URL:         subscription.Url,
+		Events:      subscription.EventTypes,
+		Active:      subscription.Active,
+		CreatedDate: subscription.CreatedDate,
+		UpdatedDate: subscription.UpdatedDate,
+	}
+}
+
+type deadLetterResponse struct {
+	DeadLetterUUID uuid.UUID       `json:"dead_letter_uuid"`
+	EventUUID      uuid.UUID       `json:"event_uuid"`
+	Event          string          `json:"event"`
+	Payload        json.RawMessage `json:"payload"`
+	Attempts       int32           `json:"attempts"`
+	// ResponseStatus is empty when the webhook didn't answer the last attempt
+	ResponseStatus *int32     `json:"response_status,omitempty"`
+	LastError      string     `json:"last_error"`
+	ReplayedDate   *time.Time `json:"replayed_date,omitempty"`
+	CreatedDate    time.Time  `json:"created_date"`
+}
+
+func newDeadLetterResponse(deadLetter db.WebhookDeadLetter) deadLetterResponse {
+	response := deadLetterResponse{
+		DeadLetterUUID: deadLetter.WebhookDeadLetterUuid,
+		EventUUID:      deadLetter.EventUuid,
+		Event:          deadLetter.EventType,
+		Payload:        deadLetter.Payload,
+		Attempts:       deadLetter.Attempts,
+		LastError:      deadLetter.LastError,
+		CreatedDate:    deadLetter.CreatedDate,
+	}
+	if deadLetter.ResponseStatus.Valid {
+		response.ResponseStatus = &deadLetter.ResponseStatus.Int32
+	}
+	if deadLetter.ReplayedDate.Valid {
+		response.ReplayedDate = &deadLetter.ReplayedDate.Time
+	}
+	return response
+}
+
+type webhookDeliveryResponse struct {
+	DeliveryUUID    uuid.UUID `json:"delivery_uuid"`
+	EventUUID       uuid.UUID `json:"event_uuid"`
+	Event           string    `json:"event"`
+	NextAttemptDate time.Time `json:"next_attempt_date"`
+}
+
+// WebhookController controller for the webhooks of the accounts
+type WebhookController struct {
+	service *service.WebhookService
+}
+
+// NewWebhookController builds a new instance of webhook controller
+func NewWebhookController(store db.Store, policy *service.Policy) *WebhookController {
+	accountService := service.NewAccountService(store, policy)
+	return &WebhookController{
+		service: service.NewWebhookService(store, accountService),
+	}
+}
+
+func (controller *WebhookController) setupRoutes(router *gin.Engine, authRoutes gin.IRoutes) {
+	authRoutes.POST(webhooksPath, controller.createWebhook)
+	authRoutes.GET(webhooksPath, controller.listWebhooks)
+	authRoutes.GET(webhooksPathByID, controller.getWebhook)
+	authRoutes.PUT(webhooksPathByID, controller.updateWebhook)
+	authRoutes.DELETE(webhooksPathByID, controller.deleteWebhook)
+	authRoutes.GET(deadLettersPath, controller.listDeadLetters)
+	authRoutes.POST(deadLetterReplayPath, controller.replayDeadLetter)
+}
+
+func (controller *WebhookController) createWebhook(ctx *gin.Context) {
+	accountUUID, err := getAccountUUID(ctx)
+	if err != nil {
+		return
+	}
+	var req createWebhookRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		invalidRequest(ctx, err)
+		return
+	}
+	subscription, err := controller.service.CreateSubscription(ctx.Request.Context(), accountUUID, service.WebhookSubscription{
+		URL:        req.URL,
+		EventTypes: req.Events,
+		Active:     true,
+	})
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	response := newWebhookResponse(subscription)
+	response.Secret = subscription.Secret
+	ctx.JSON(http.StatusCreated, response)
+}
+
+func (controller *WebhookController) listWebhooks(ctx *gin.Context) {
+	accountUUID, err := getAccountUUID(ctx)
+	if err != nil {
+		return
+	}
+	subscriptions, err := controller.service.ListSubscriptions(ctx.Request.Context(), accountUUID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	response := make([]webhookResponse, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		response = append(response, newWebhookResponse(subscription))
+	}
+	ctx.JSON(http.StatusOK, response)
+}
+
+func (controller *WebhookController) getWebhook(ctx *gin.Context) {
+	accountUUID, webhookUUID, err := getWebhookURI(ctx)
+	if err != nil {
+		return
+	}
+	subscription, err := controller.service.GetSubscription(ctx.Request.Context(), accountUUID, webhookUUID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, newWebhookResponse(subscription))
+}
+
+func (controller *WebhookController) updateWebhook(ctx *gin.Context) {
+	accountUUID, webhookUUID, err := getWebhookURI(ctx)
+	if err != nil {
+		return
+	}
+	var req updateWebhookRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		invalidRequest(ctx, err)
+		return
+	}
+	// webhooks stay active unless told otherwise
+	active := true
+	if req.Active != nil {
+		active = *req.Active
+	}
+	subscription, err := controller.service.UpdateSubscription(ctx.Request.Context(), accountUUID, webhookUUID,
+		service.WebhookSubscription{
+			URL:        req.URL,
+			EventTypes: req.Events,
+			Active:     active,
+		})
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, newWebhookResponse(subscription))
+}
+
+func (controller *WebhookController) deleteWebhook(ctx *gin.Context) {
+	accountUUID, webhookUUID, err := getWebhookURI(ctx)
+	if err != nil {
+		return
+	}
+	if err := controller.service.DeleteSubscription(ctx.Request.Context(), accountUUID, webhookUUID); err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+func (controller *WebhookController) listDeadLetters(ctx *gin.Context) {
+	accountUUID, webhookUUID, err := getWebhookURI(ctx)
+	if err != nil {
+		return
+	}
+	var req listDeadLettersRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		invalidRequest(ctx, err)
+		return
+	}
+	deadLetters, err := controller.service.ListDeadLetters(ctx.Request.Context(), accountUUID, webhookUUID, req.PageSize)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	response := make([]deadLetterResponse, 0, len(deadLetters))
+	for _, deadLetter := range deadLetters {
+		response = append(response, newDeadLetterResponse(deadLetter))
+	}
+	ctx.JSON(http.StatusOK, response)
+}
+
+func (controller *WebhookController) replayDeadLetter(ctx *gin.Context) {
+	accountUUID, webhookUUID, err := getWebhookURI(ctx)
+	if err != nil {
+		return
+	}
+	var req deadLetterIDRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		invalidRequest(ctx, err)
+		return
+	}
+	deadLetterUUID, err := parseUUID(req.ID)
+	if err != nil {
+		invalidRequest(ctx, err)
+		return
+	}
+	delivery, err := controller.service.ReplayDeadLetter(ctx.Request.Context(), accountUUID, webhookUUID, deadLetterUUID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusAccepted, webhookDeliveryResponse{
+		DeliveryUUID:    delivery.WebhookDeliveryUuid,
+		EventUUID:       delivery.EventUuid,
+		Event:           delivery.EventType,
+		NextAttemptDate: delivery.NextAttemptDate,
+	})
+}
+
+// getWebhookURI parses the account and webhook IDs of the path, answering 400 when they're invalid
+func getWebhookURI(ctx *gin.Context) (uuid.UUID, uuid.UUID, error) {
+	accountUUID, err := getAccountUUID(ctx)
+	if err != nil {
+		return accountUUID, uuid.Nil, err
+	}
+	var req webhookIDRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		invalidRequest(ctx, err)
+		return accountUUID, uuid.Nil, err
+	}
+	webhookUUID, err := parseUUID(req.ID)
+	if err != nil {
+		invalidRequest(ctx, err)
+	}
+	return accountUUID, webhookUUID, err
+}
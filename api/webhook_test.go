@@ -0,0 +1,607 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+	mockdb "github.com/valverdethiago/trading-api/db/mock"
+	db "github.com/valverdethiago/trading-api/db/sqlc"
+	"github.com/valverdethiago/trading-api/service"
+)
+
+var webhookSubscription = db.WebhookSubscription{
+	WebhookSubscriptionUuid: uuid.New(),
+	AccountUuid:             account.AccountUuid,
+	Url:                     "https://example.com/hooks",
+	Secret:                  "secret",
+	EventTypes:              []string{string(service.EventTradeCreated)},
+	Active:                  true,
+	CreatedDate:             time.Now().UTC(),
+	UpdatedDate:             time.Now().UTC(),
+}
+
+var webhookDeadLetter = db.WebhookDeadLetter{
+	WebhookDeadLetterUuid:   uuid.New(),
+	WebhookSubscriptionUuid: webhookSubscription.WebhookSubscriptionUuid,
+	EventUuid:               uuid.New(),
+	EventType:               string(service.EventTradeCreated),
+	Payload:                 json.RawMessage(`{"type": "trade.created"}`),
+	Attempts:                8,
+	ResponseStatus:          sql.NullInt32{Int32: 500, Valid: true},
+	LastError:               "unexpected status 500",
+	CreatedDate:             time.Now().UTC(),
+}
+
+func TestCreateWebhook(t *testing.T) {
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{
+				"url":    webhookSubscription.Url,
+				"events": webhookSubscription.EventTypes,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccountById(gomock.Any(), gomock.Eq(account.AccountUuid)).
+					Times(1).
+					Return(account, nil)
+				store.EXPECT().
+					CreateWebhookSubscription(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(ctx context.Context, arg db.CreateWebhookSubscriptionParams) (db.WebhookSubscription, error) {
+						require.Equal(t, account.AccountUuid, arg.AccountUuid)
+						require.Equal(t, webhookSubscription.Url, arg.Url)
+						require.Equal(t, webhookSubscription.EventTypes, arg.EventTypes)
+						require.Len(t, arg.Secret, 64)
+						return webhookSubscription, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+				response := requireBodyMatchWebhook(t, recorder, webhookSubscription)
+				require.Equal(t, webhookSubscription.Secret, response.Secret)
+			},
+		}, {
+			name: "Invalid Event",
+			body: gin.H{
+				"url":    webhookSubscription.Url,
+				"events": []string{"trade.unknown"},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateWebhookSubscription(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		}, {
+			name: "Invalid URL",
+			body: gin.H{
+				"url":    "not a url",
+				"events": webhookSubscription.EventTypes,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateWebhookSubscription(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		}, {
+			name: "Metadata Address",
+			body: gin.H{
+				"url":    "http://169.254.169.254/latest/meta-data",
+				"events": webhookSubscription.EventTypes,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateWebhookSubscription(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				requireProblem(t, recorder, http.StatusBadRequest, service.ErrInvalidWebhookURL.Code)
+			},
+		}, {
+			name: "Unsupported Scheme",
+			body: gin.H{
+				"url":    "gopher://example.com/hooks",
+				"events": webhookSubscription.EventTypes,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateWebhookSubscription(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				requireProblem(t, recorder, http.StatusBadRequest, service.ErrInvalidWebhookURL.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		testCase := testCases[i]
+
+		t.Run(testCase.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := newMockStore(ctrl)
+			testCase.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+			body, err := json.Marshal(testCase.body)
+			require.NoError(t, err)
+			url := fmt.Sprintf("/accounts/%s/webhooks", account.AccountUuid.String())
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, account)
+
+			server.router.ServeHTTP(recorder, request)
+			testCase.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestListWebhooks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := newMockStore(ctrl)
+	store.EXPECT().
+		GetAccountById(gomock.Any(), gomock.Eq(account.AccountUuid)).
+		Times(1).
+		Return(account, nil)
+	store.EXPECT().
+		ListWebhookSubscriptionsByAccount(gomock.Any(), gomock.Eq(account.AccountUuid)).
+		Times(1).
+		Return([]db.WebhookSubscription{webhookSubscription}, nil)
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+	url := fmt.Sprintf("/accounts/%s/webhooks", account.AccountUuid.String())
+	request, err := http.NewRequest(http.MethodGet, url, nil)
+	require.NoError(t, err)
+	addAuthorization(t, request, server.tokenMaker, account)
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	var response []webhookResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	require.Len(t, response, 1)
+	require.Equal(t, webhookSubscription.WebhookSubscriptionUuid, response[0].WebhookUUID)
+	// the secret is only disclosed when the webhook is created
+	require.Empty(t, response[0].Secret)
+}
+
+func TestGetWebhook(t *testing.T) {
+	otherSubscription := webhookSubscription
+	otherSubscription.AccountUuid = uuid.New()
+
+	testCases := []struct {
+		name          string
+		webhookID     string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "OK",
+			webhookID: webhookSubscription.WebhookSubscriptionUuid.String(),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetWebhookSubscription(gomock.Any(), gomock.Eq(webhookSubscription.WebhookSubscriptionUuid)).
+					Times(1).
+					Return(webhookSubscription, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				response := requireBodyMatchWebhook(t, recorder, webhookSubscription)
+				require.Empty(t, response.Secret)
+			},
+		}, {
+			name:      "Webhook Of Another Account",
+			webhookID: webhookSubscription.WebhookSubscriptionUuid.String(),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetWebhookSubscription(gomock.Any(), gomock.Eq(webhookSubscription.WebhookSubscriptionUuid)).
+					Times(1).
+					Return(otherSubscription, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		}, {
+			name:      "Invalid ID",
+			webhookID: "invalid",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetWebhookSubscription(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		testCase := testCases[i]
+
+		t.Run(testCase.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := newMockStore(ctrl)
+			testCase.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+			url := fmt.Sprintf("/accounts/%s/webhooks/%s", account.AccountUuid.String(), testCase.webhookID)
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, account)
+
+			server.router.ServeHTTP(recorder, request)
+			testCase.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestUpdateWebhook(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	updatedSubscription := webhookSubscription
+	updatedSubscription.EventTypes = []string{string(service.EventTradeFilled), string(service.EventTradeCompleted)}
+	updatedSubscription.Active = false
+
+	store := newMockStore(ctrl)
+	store.EXPECT().
+		GetWebhookSubscription(gomock.Any(), gomock.Eq(webhookSubscription.WebhookSubscriptionUuid)).
+		Times(1).
+		Return(webhookSubscription, nil)
+	store.EXPECT().
+		UpdateWebhookSubscription(gomock.Any(), gomock.Eq(db.UpdateWebhookSubscriptionParams{
+			Url:                     webhookSubscription.Url,
+			EventTypes:              updatedSubscription.EventTypes,
+			Active:                  false,
+			WebhookSubscriptionUuid: webhookSubscription.WebhookSubscriptionUuid,
+		})).
+		Times(1).
+		Return(updatedSubscription, nil)
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+	body, err := json.Marshal(gin.H{
+		"url":    webhookSubscription.Url,
+		"events": updatedSubscription.EventTypes,
+		"active": false,
+	})
+	require.NoError(t, err)
+	url := fmt.Sprintf("/accounts/%s/webhooks/%s", account.AccountUuid.String(),
+		webhookSubscription.WebhookSubscriptionUuid.String())
+	request, err := http.NewRequest(http.MethodPut, url, bytes.NewReader(body))
+	require.NoError(t, err)
+	addAuthorization(t, request, server.tokenMaker, account)
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	requireBodyMatchWebhook(t, recorder, updatedSubscription)
+}
+
+func TestDeleteWebhook(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := newMockStore(ctrl)
+	store.EXPECT().
+		GetWebhookSubscription(gomock.Any(), gomock.Eq(webhookSubscription.WebhookSubscriptionUuid)).
+		Times(1).
+		Return(webhookSubscription, nil)
+	store.EXPECT().
+		DeleteWebhookSubscription(gomock.Any(), gomock.Eq(webhookSubscription.WebhookSubscriptionUuid)).
+		Times(1).
+		Return(nil)
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+	url := fmt.Sprintf("/accounts/%s/webhooks/%s", account.AccountUuid.String(),
+		webhookSubscription.WebhookSubscriptionUuid.String())
+	request, err := http.NewRequest(http.MethodDelete, url, nil)
+	require.NoError(t, err)
+	addAuthorization(t, request, server.tokenMaker, account)
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusNoContent, recorder.Code)
+}
+
+func TestListWebhookDeadLetters(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := newMockStore(ctrl)
+	store.EXPECT().
+		GetWebhookSubscription(gomock.Any(), gomock.Eq(webhookSubscription.WebhookSubscriptionUuid)).
+		Times(1).
+		Return(webhookSubscription, nil)
+	store.EXPECT().
+		ListWebhookDeadLetters(gomock.Any(), gomock.Eq(db.ListWebhookDeadLettersParams{
+			WebhookSubscriptionUuid: webhookSubscription.WebhookSubscriptionUuid,
+			Limit:                   service.DefaultDeadLetterPageSize,
+		})).
+		Times(1).
+		Return([]db.WebhookDeadLetter{webhookDeadLetter}, nil)
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+	url := fmt.Sprintf("/accounts/%s/webhooks/%s/dead-letters", account.AccountUuid.String(),
+		webhookSubscription.WebhookSubscriptionUuid.String())
+	request, err := http.NewRequest(http.MethodGet, url, nil)
+	require.NoError(t, err)
+	addAuthorization(t, request, server.tokenMaker, account)
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	var response []deadLetterResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	require.Len(t, response, 1)
+	require.Equal(t, webhookDeadLetter.WebhookDeadLetterUuid, response[0].DeadLetterUUID)
+	require.Equal(t, int32(500), *response[0].ResponseStatus)
+	require.Nil(t, response[0].ReplayedDate)
+	require.JSONEq(t, string(webhookDeadLetter.Payload), string(response[0].Payload))
+}
+
+func TestReplayWebhookDeadLetter(t *testing.T) {
+	otherDeadLetter := webhookDeadLetter
+	otherDeadLetter.WebhookSubscriptionUuid = uuid.New()
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetWebhookSubscription(gomock.Any(), gomock.Eq(webhookSubscription.WebhookSubscriptionUuid)).
+					Times(1).
+					Return(webhookSubscription, nil)
+				store.EXPECT().
+					GetWebhookDeadLetter(gomock.Any(), gomock.Eq(webhookDeadLetter.WebhookDeadLetterUuid)).
+					Times(1).
+					Return(webhookDeadLetter, nil)
+				store.EXPECT().
+					MarkWebhookDeadLetterReplayed(gomock.Any(), gomock.Eq(webhookDeadLetter.WebhookDeadLetterUuid)).
+					Times(1).
+					Return(webhookDeadLetter, nil)
+				store.EXPECT().
+					CreateWebhookDelivery(gomock.Any(), gomock.Eq(db.CreateWebhookDeliveryParams{
+						WebhookSubscriptionUuid: webhookSubscription.WebhookSubscriptionUuid,
+						EventUuid:               webhookDeadLetter.EventUuid,
+						EventType:               webhookDeadLetter.EventType,
+						Payload:                 webhookDeadLetter.Payload,
+					})).
+					Times(1).
+					Return(db.WebhookDelivery{
+						WebhookDeliveryUuid: uuid.New(),
+						EventUuid:           webhookDeadLetter.EventUuid,
+						EventType:           webhookDeadLetter.EventType,
+					}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusAccepted, recorder.Code)
+				var response webhookDeliveryResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.Equal(t, webhookDeadLetter.EventUuid, response.EventUUID)
+				require.NotEqual(t, uuid.Nil, response.DeliveryUUID)
+			},
+		}, {
+			name: "Dead Letter Of Another Webhook",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetWebhookSubscription(gomock.Any(), gomock.Eq(webhookSubscription.WebhookSubscriptionUuid)).
+					Times(1).
+					Return(webhookSubscription, nil)
+				store.EXPECT().
+					GetWebhookDeadLetter(gomock.Any(), gomock.Eq(webhookDeadLetter.WebhookDeadLetterUuid)).
+					Times(1).
+					Return(otherDeadLetter, nil)
+				store.EXPECT().
+					CreateWebhookDelivery(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		}, {
+			name: "Already Replayed",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetWebhookSubscription(gomock.Any(), gomock.Eq(webhookSubscription.WebhookSubscriptionUuid)).
+					Times(1).
+					Return(webhookSubscription, nil)
+				store.EXPECT().
+					GetWebhookDeadLetter(gomock.Any(), gomock.Eq(webhookDeadLetter.WebhookDeadLetterUuid)).
+					Times(1).
+					Return(webhookDeadLetter, nil)
+				store.EXPECT().
+					MarkWebhookDeadLetterReplayed(gomock.Any(), gomock.Eq(webhookDeadLetter.WebhookDeadLetterUuid)).
+					Times(1).
+					Return(db.WebhookDeadLetter{}, sql.ErrNoRows)
+				store.EXPECT().
+					CreateWebhookDelivery(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				requireProblem(t, recorder, http.StatusConflict, service.ErrDeadLetterReplayed.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		testCase := testCases[i]
+
+		t.Run(testCase.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := newMockStore(ctrl)
+			testCase.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+			url := fmt.Sprintf("/accounts/%s/webhooks/%s/dead-letters/%s/replay", account.AccountUuid.String(),
+				webhookSubscription.WebhookSubscriptionUuid.String(), webhookDeadLetter.WebhookDeadLetterUuid.String())
+			request, err := http.NewRequest(http.MethodPost, url, nil)
+			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, account)
+
+			server.router.ServeHTTP(recorder, request)
+			testCase.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestReplayWebhookDeadLetterRecordsAudit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	replayedDeadLetter := webhookDeadLetter
+	replayedDeadLetter.ReplayedDate = sql.NullTime{Time: time.Now(), Valid: true}
+	var recorded db.CreateAuditLogParams
+	store := newMockStoreWithoutAudit(ctrl)
+	store.EXPECT().
+		GetWebhookSubscription(gomock.Any(), gomock.Eq(webhookSubscription.WebhookSubscriptionUuid)).
+		Times(1).
+		Return(webhookSubscription, nil)
+	store.EXPECT().
+		GetWebhookDeadLetter(gomock.Any(), gomock.Eq(webhookDeadLetter.WebhookDeadLetterUuid)).
+		Times(1).
+		Return(webhookDeadLetter, nil)
+	store.EXPECT().
+		MarkWebhookDeadLetterReplayed(gomock.Any(), gomock.Eq(webhookDeadLetter.WebhookDeadLetterUuid)).
+		Times(1).
+		Return(replayedDeadLetter, nil)
+	store.EXPECT().
+		CreateWebhookDelivery(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(db.WebhookDelivery{
+			WebhookDeliveryUuid: uuid.New(),
+			EventUuid:           webhookDeadLetter.EventUuid,
+			EventType:           webhookDeadLetter.EventType,
+		}, nil)
+	store.EXPECT().
+		CreateAuditLog(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(ctx context.Context, arg db.CreateAuditLogParams) (db.AuditLog, error) {
+			recorded = arg
+			return db.AuditLog{}, nil
+		})
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+	url := fmt.Sprintf("/accounts/%s/webhooks/%s/dead-letters/%s/replay", account.AccountUuid.String(),
+		webhookSubscription.WebhookSubscriptionUuid.String(), webhookDeadLetter.WebhookDeadLetterUuid.String())
+	request, err := http.NewRequest(http.MethodPost, url, nil)
+	require.NoError(t, err)
+	addAuthorization(t, request, server.tokenMaker, account)
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusAccepted, recorder.Code)
+	require.Equal(t, account.AccountUuid.String(), recorded.Actor)
+	require.Equal(t, string(service.AuditUpdate), recorded.Action)
+	require.Equal(t, service.EntityWebhookDeadLetter, recorded.EntityType)
+	require.Equal(t, webhookDeadLetter.WebhookDeadLetterUuid.String(), recorded.EntityID)
+	require.NotEqual(t, string(recorded.Before), string(recorded.After))
+}
+
+func TestCreateTradeEnqueuesWebhookDelivery(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	accountTrade := trade
+	accountTrade.AccountUuid = account.AccountUuid
+
+	store := newMockStore(ctrl)
+	store.EXPECT().
+		GetAccountById(gomock.Any(), gomock.Eq(account.AccountUuid)).
+		Times(1).
+		Return(account, nil)
+	expectBuyingPower(store, decimal.NewFromInt(1000000), 0)
+	expectInstrument(store)
+	store.EXPECT().
+		CreateTrade(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(accountTrade, nil)
+	store.EXPECT().
+		CreateTradeVersion(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(db.TradeVersion{}, nil)
+	store.EXPECT().
+		ListWebhookSubscriptionsForEvent(gomock.Any(), gomock.Eq(db.ListWebhookSubscriptionsForEventParams{
+			AccountUuid: account.AccountUuid,
+			EventType:   string(service.EventTradeCreated),
+		})).
+		Times(1).
+		Return([]db.WebhookSubscription{webhookSubscription}, nil)
+	store.EXPECT().
+		CreateWebhookDelivery(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(ctx context.Context, arg db.CreateWebhookDeliveryParams) (db.WebhookDelivery, error) {
+			require.Equal(t, webhookSubscription.WebhookSubscriptionUuid, arg.WebhookSubscriptionUuid)
+			require.Equal(t, string(service.EventTradeCreated), arg.EventType)
+			var event service.Event
+			require.NoError(t, json.Unmarshal(arg.Payload, &event))
+			require.Equal(t, arg.EventUuid, event.EventUUID)
+			require.Equal(t, account.AccountUuid, event.AccountUUID)
+			return db.WebhookDelivery{}, nil
+		})
+	expectEvents(store)
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+	body, err := json.Marshal(tradeRequest{
+		Symbol:   trade.Symbol,
+		Quantity: trade.Quantity,
+		Side:     trade.Side,
+		Price:    &trade.Price.Decimal,
+	})
+	require.NoError(t, err)
+	url := fmt.Sprintf("/accounts/%s/trades", account.AccountUuid.String())
+	request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	require.NoError(t, err)
+	addAuthorization(t, request, server.tokenMaker, account)
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusCreated, recorder.Code)
+}
+
+func requireBodyMatchWebhook(t *testing.T, recorder *httptest.ResponseRecorder, subscription db.WebhookSubscription) webhookResponse {
+	var response webhookResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	require.Equal(t, subscription.WebhookSubscriptionUuid, response.WebhookUUID)
+	require.Equal(t, subscription.Url, response.URL)
+	require.Equal(t, subscription.EventTypes, response.Events)
+	require.Equal(t, subscription.Active, response.Active)
+	return response
+}
@@ -0,0 +1,370 @@
+package calendar
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// Session is the trading session the market is in at a given time
+type Session string
+
+const (
+	SessionClosed     Session = "CLOSED"
+	SessionPreMarket  Session = "PRE_MARKET"
+	SessionRegular    Session = "REGULAR"
+	SessionAfterHours Session = "AFTER_HOURS"
+)
+
+const (
+	dateLayout = "2006-01-02"
+	timeLayout = "15:04"
+	// searchDays bounds the search of the next open or close, a calendar without any within a year
+	// is considered never to open
+	searchDays = 366
+)
+
+// Config is the calendar as written in its JSON file, dates are "2006-01-02" and times of the day
+// "15:04" in the time zone of the exchange
+type Config struct {
+	Timezone string `json:"timezone"`
+	// TradingDays are the English names of the weekdays the market opens, Monday to Friday when omitted
+	TradingDays []string `json:"trading_days"`
+	// PreMarket and AfterHours are the extended hours, the market has none when they're omitted
+	PreMarket  *HoursConfig    `json:"pre_market"`
+	Regular    HoursConfig     `json:"regular"`
+	AfterHours *HoursConfig    `json:"after_hours"`
+	Holidays   []HolidayConfig `json:"holidays"`
+	HalfDays   []HalfDayConfig `json:"half_days"`
+}
+
+// HoursConfig bounds a session, from open inclusive to close exclusive, "24:00" closing at midnight
+type HoursConfig struct {
+	Open  string `json:"open"`
+	Close string `json:"close"`
+}
+
+// HolidayConfig is a day the market stays closed
+type HolidayConfig struct {
+	Date string `json:"date"`
+	Name string `json:"name"`
+}
+
+// HalfDayConfig is a day the regular session closes early, followed by after hours only when they
+// have a close of their own
+type HalfDayConfig struct {
+	Date            string `json:"date"`
+	Close           string `json:"close"`
+	AfterHoursClose string `json:"after_hours_close"`
+}
+
+// hours are the offsets of a session from midnight, the zero value being no session
+type hours struct {
+	open  time.Duration
+	close time.Duration
+}
+
+func (h hours) isEmpty() bool {
+	return h.open == h.close
+}
+
+func (h hours) contains(offset time.Duration) bool {
+	return offset >= h.open && offset < h.close
+}
+
+// schedule holds the sessions of one day
+type schedule struct {
+	preMarket  hours
+	regular    hours
+	afterHours hours
+	holiday    string
+}
+
+// Calendar tells the sessions of an exchange on any day, in the time zone of the exchange
+type Calendar struct {
+	location    *time.Location
+	tradingDays map[time.Weekday]bool
+	preMarket   hours
+	regular     hours
+	afterHours  hours
+	holidays    map[string]string
+	halfDays    map[string]schedule
+}
+
+// Status is the session of the market at a given time and the next regular open and close after it,
+// zero when there is none within a year
+type Status struct {
+	Time      time.Time
+	Session   Session
+	Holiday   string
+	NextOpen  time.Time
+	NextClose time.Time
+}
+
+// Load reads the calendar of a JSON file
+func Load(path string) (*Calendar, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	var config Config
+	decoder := json.NewDecoder(file)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&config); err != nil {
+		return nil, fmt.Errorf("cannot read calendar %s: %w", path, err)
+	}
+	return New(config)
+}
+
+// New creates the calendar of the config, checking that the sessions of a day follow each other
+func New(config Config) (*Calendar, error) {
+	location, err := time.LoadLocation(config.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone %q: %w", config.Timezone, err)
+	}
+	calendar := &Calendar{
+		location:    location,
+		tradingDays: make(map[time.Weekday]bool),
+		holidays:    make(map[string]string),
+		halfDays:    make(map[string]schedule),
+	}
+	if err := calendar.setTradingDays(config.TradingDays); err != nil {
+		return nil, err
+	}
+	if calendar.regular, err = parseHours("regular", &config.Regular); err != nil {
+		return nil, err
+	}
+	if calendar.regular.isEmpty() {
+		return nil, fmt.Errorf("the regular session is required")
+	}
+	if calendar.preMarket, err = parseHours("pre market", config.PreMarket); err != nil {
+		return nil, err
+	}
+	if calendar.afterHours, err = parseHours("after hours", config.AfterHours); err != nil {
+		return nil, err
+	}
+	if err := validateSchedule(schedule{
+		preMarket:  calendar.preMarket,
+		regular:    calendar.regular,
+		afterHours: calendar.afterHours,
+	}); err != nil {
+		return nil, err
+	}
+	for _, holiday := range config.Holidays {
+		if _, err := time.Parse(dateLayout, holiday.Date); err != nil {
+			return nil, fmt.Errorf("invalid holiday date %q", holiday.Date)
+		}
+		calendar.holidays[holiday.Date] = holiday.Name
+	}
+	for _, halfDay := range config.HalfDays {
+		day, err := calendar.parseHalfDay(halfDay)
+		if err != nil {
+			return nil, err
+		}
+		calendar.halfDays[halfDay.Date] = day
+	}
+	return calendar, nil
+}
+
+// AlwaysOpen creates a calendar whose regular session runs every day from midnight to midnight UTC
+func AlwaysOpen() *Calendar {
+	calendar := &Calendar{
+		location:    time.UTC,
+		tradingDays: make(map[time.Weekday]bool),
+		regular:     hours{open: 0, close: 24 * time.Hour},
+		holidays:    make(map[string]string),
+		halfDays:    make(map[string]schedule),
+	}
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		calendar.tradingDays[day] = true
+	}
+	return calendar
+}
+
+// Location returns the time zone of the exchange
+func (calendar *Calendar) Location() *time.Location {
+	return calendar.location
+}
+
+// Session returns the session the market is in at the given time
+func (calendar *Calendar) Session(at time.Time) Session {
+	local := at.In(calendar.location)
+	day := calendar.schedule(local)
+	offset := local.Sub(calendar.midnight(local))
+	switch {
+	case day.regular.contains(offset):
+		return SessionRegular
+	case day.preMarket.contains(offset):
+		return SessionPreMarket
+	case day.afterHours.contains(offset):
+		return SessionAfterHours
+	}
+	return SessionClosed
+}
+
+// Status returns the session of the market at the given time and its next regular open and close
+func (calendar *Calendar) Status(at time.Time) Status {
+	local := at.In(calendar.location)
+	return Status{
+		Time:      local,
+		Session:   calendar.Session(at),
+		Holiday:   calendar.schedule(local).holiday,
+		NextOpen:  calendar.NextOpen(at),
+		NextClose: calendar.NextClose(at),
+	}
+}
+
+// NextOpen returns the first regular open after the given time, zero when there is none within a year
+func (calendar *Calendar) NextOpen(from time.Time) time.Time {
+	return calendar.next(from, func(day schedule) time.Duration { return day.regular.open })
+}
+
+// NextClose returns the first regular close after the given time, zero when there is none within a year
+func (calendar *Calendar) NextClose(from time.Time) time.Time {
+	return calendar.next(from, func(day schedule) time.Duration { return day.regular.close })
+}
+
+func (calendar *Calendar) next(from time.Time, offset func(schedule) time.Duration) time.Time {
+	local := from.In(calendar.location)
+	for i := 0; i < searchDays; i++ {
+		date := local.AddDate(0, 0, i)
+		day := calendar.schedule(date)
+		if day.regular.isEmpty() {
+			continue
+		}
+		if at := calendar.wallClock(date, offset(day)); at.After(from) {
+			return at
+		}
+	}
+	return time.Time{}
+}
+
+// schedule returns the sessions of the day of the given local time
+func (calendar *Calendar) schedule(local time.Time) schedule {
+	date := local.Format(dateLayout)
+	if name, ok := calendar.holidays[date]; ok {
+		return schedule{holiday: name}
+	}
+	if !calendar.tradingDays[local.Weekday()] {
+		return schedule{}
+	}
+	if day, ok := calendar.halfDays[date]; ok {
+		return day
+	}
+	return schedule{
+		preMarket:  calendar.preMarket,
+		regular:    calendar.regular,
+		afterHours: calendar.afterHours,
+	}
+}
+
+func (calendar *Calendar) midnight(local time.Time) time.Time {
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, calendar.location)
+}
+
+// wallClock returns the time of the day at the given offset from midnight, read on the wall clock so
+// that sessions keep their hours across daylight saving changes
+func (calendar *Calendar) wallClock(local time.Time, offset time.Duration) time.Time {
+	hour, minute := int(offset/time.Hour), int(offset%time.Hour/time.Minute)
+	return time.Date(local.Year(), local.Month(), local.Day(), hour, minute, 0, 0, calendar.location)
+}
+
+func (calendar *Calendar) setTradingDays(names []string) error {
+	if names == nil {
+		names = []string{"Monday", "Tuesday", "Wednesday", "Thursday", "Friday"}
+	}
+	for _, name := range names {
+		day, ok := parseWeekday(name)
+		if !ok {
+			return fmt.Errorf("invalid trading day %q", name)
+		}
+		calendar.tradingDays[day] = true
+	}
+	return nil
+}
+
+func (calendar *Calendar) parseHalfDay(halfDay HalfDayConfig) (schedule, error) {
+	if _, err := time.Parse(dateLayout, halfDay.Date); err != nil {
+		return schedule{}, fmt.Errorf("invalid half day date %q", halfDay.Date)
+	}
+	closing, err := parseTimeOfDay(halfDay.Close)
+	if err != nil {
+		return schedule{}, fmt.Errorf("invalid close of half day %s: %w", halfDay.Date, err)
+	}
+	day := schedule{
+		preMarket: calendar.preMarket,
+		regular:   hours{open: calendar.regular.open, close: closing},
+	}
+	if halfDay.AfterHoursClose != "" {
+		afterHoursClose, err := parseTimeOfDay(halfDay.AfterHoursClose)
+		if err != nil {
+			return schedule{}, fmt.Errorf("invalid after hours close of half day %s: %w", halfDay.Date, err)
+		}
+		day.afterHours = hours{open: closing, close: afterHoursClose}
+	}
+	if err := validateSchedule(day); err != nil {
+		return schedule{}, fmt.Errorf("half day %s: %w", halfDay.Date, err)
+	}
+	return day, nil
+}
+
+// validateSchedule checks that each session opens before it closes and after the previous one closed
+func validateSchedule(day schedule) error {
+	sessions := []struct {
+		name  string
+		hours hours
+	}{{"pre market", day.preMarket}, {"regular", day.regular}, {"after hours", day.afterHours}}
+	var previous time.Duration
+	for _, session := range sessions {
+		if session.hours.isEmpty() {
+			continue
+		}
+		if session.hours.open > session.hours.close {
+			return fmt.Errorf("the %s session closes before it opens", session.name)
+		}
+		if session.hours.open < previous {
+			return fmt.Errorf("the %s session opens before the previous one closes", session.name)
+		}
+		previous = session.hours.close
+	}
+	return nil
+}
+
+func parseHours(name string, config *HoursConfig) (hours, error) {
+	if config == nil {
+		return hours{}, nil
+	}
+	open, err := parseTimeOfDay(config.Open)
+	if err != nil {
+		return hours{}, fmt.Errorf("invalid open of the %s session: %w", name, err)
+	}
+	closing, err := parseTimeOfDay(config.Close)
+	if err != nil {
+		return hours{}, fmt.Errorf("invalid close of the %s session: %w", name, err)
+	}
+	return hours{open: open, close: closing}, nil
+}
+
+// parseTimeOfDay parses "15:04" as the offset from midnight, "24:00" being the end of the day
+func parseTimeOfDay(value string) (time.Duration, error) {
+	if value == "24:00" {
+		return 24 * time.Hour, nil
+	}
+	parsed, err := time.Parse(timeLayout, value)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(parsed.Hour())*time.Hour + time.Duration(parsed.Minute())*time.Minute, nil
+}
+
+func parseWeekday(name string) (time.Weekday, bool) {
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if strings.EqualFold(day.String(), name) {
+			return day, true
+		}
+	}
+	return time.Sunday, false
+}
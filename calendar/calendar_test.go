@@ -0,0 +1,148 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestCalendar(t *testing.T) *Calendar {
+	calendar, err := New(Config{
+		Timezone:   "America/New_York",
+		PreMarket:  &HoursConfig{Open: "04:00", Close: "09:30"},
+		Regular:    HoursConfig{Open: "09:30", Close: "16:00"},
+		AfterHours: &HoursConfig{Open: "16:00", Close: "20:00"},
+		Holidays:   []HolidayConfig{{Date: "2021-07-05", Name: "Independence Day"}},
+		HalfDays:   []HalfDayConfig{{Date: "2021-11-26", Close: "13:00", AfterHoursClose: "17:00"}},
+	})
+	if err != nil {
+		t.Skip("timezone database not available")
+	}
+	return calendar
+}
+
+func TestSession(t *testing.T) {
+	calendar := newTestCalendar(t)
+	location := calendar.Location()
+
+	testCases := []struct {
+		name     string
+		at       time.Time
+		expected Session
+	}{
+		{"Overnight", time.Date(2021, time.March, 1, 3, 0, 0, 0, location), SessionClosed},
+		{"Pre Market", time.Date(2021, time.March, 1, 8, 0, 0, 0, location), SessionPreMarket},
+		{"Regular Open", time.Date(2021, time.March, 1, 9, 30, 0, 0, location), SessionRegular},
+		{"Regular", time.Date(2021, time.March, 1, 12, 0, 0, 0, location), SessionRegular},
+		{"After Hours", time.Date(2021, time.March, 1, 16, 0, 0, 0, location), SessionAfterHours},
+		{"Evening", time.Date(2021, time.March, 1, 20, 0, 0, 0, location), SessionClosed},
+		{"Weekend", time.Date(2021, time.March, 6, 12, 0, 0, 0, location), SessionClosed},
+		{"Holiday", time.Date(2021, time.July, 5, 12, 0, 0, 0, location), SessionClosed},
+		{"Half Day Regular", time.Date(2021, time.November, 26, 12, 0, 0, 0, location), SessionRegular},
+		{"Half Day After Hours", time.Date(2021, time.November, 26, 14, 0, 0, 0, location), SessionAfterHours},
+		{"Half Day Evening", time.Date(2021, time.November, 26, 17, 30, 0, 0, location), SessionClosed},
+		{"Other Time Zone", time.Date(2021, time.March, 1, 15, 0, 0, 0, time.UTC), SessionRegular},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			require.Equal(t, testCase.expected, calendar.Session(testCase.at))
+		})
+	}
+}
+
+func TestNextOpenAndClose(t *testing.T) {
+	calendar := newTestCalendar(t)
+	location := calendar.Location()
+
+	morning := time.Date(2021, time.March, 1, 8, 0, 0, 0, location)
+	require.Equal(t, time.Date(2021, time.March, 1, 9, 30, 0, 0, location), calendar.NextOpen(morning))
+	require.Equal(t, time.Date(2021, time.March, 1, 16, 0, 0, 0, location), calendar.NextClose(morning))
+
+	evening := time.Date(2021, time.March, 1, 17, 0, 0, 0, location)
+	require.Equal(t, time.Date(2021, time.March, 2, 9, 30, 0, 0, location), calendar.NextOpen(evening))
+	require.Equal(t, time.Date(2021, time.March, 2, 16, 0, 0, 0, location), calendar.NextClose(evening))
+
+	// the clocks move forward on Sunday, the market still opens at 9:30
+	friday := time.Date(2021, time.March, 12, 17, 0, 0, 0, location)
+	nextOpen := calendar.NextOpen(friday)
+	require.Equal(t, time.Date(2021, time.March, 15, 9, 30, 0, 0, location), nextOpen)
+	require.Equal(t, time.Date(2021, time.March, 15, 13, 30, 0, 0, time.UTC), nextOpen.UTC())
+
+	beforeHoliday := time.Date(2021, time.July, 2, 17, 0, 0, 0, location)
+	require.Equal(t, time.Date(2021, time.July, 6, 9, 30, 0, 0, location), calendar.NextOpen(beforeHoliday))
+
+	halfDay := time.Date(2021, time.November, 26, 10, 0, 0, 0, location)
+	require.Equal(t, time.Date(2021, time.November, 26, 13, 0, 0, 0, location), calendar.NextClose(halfDay))
+}
+
+func TestStatus(t *testing.T) {
+	calendar := newTestCalendar(t)
+	location := calendar.Location()
+
+	status := calendar.Status(time.Date(2021, time.July, 5, 16, 0, 0, 0, time.UTC))
+	require.Equal(t, SessionClosed, status.Session)
+	require.Equal(t, "Independence Day", status.Holiday)
+	require.Equal(t, location, status.Time.Location())
+	require.Equal(t, time.Date(2021, time.July, 6, 9, 30, 0, 0, location), status.NextOpen)
+	require.Equal(t, time.Date(2021, time.July, 6, 16, 0, 0, 0, location), status.NextClose)
+}
+
+func TestNeverOpen(t *testing.T) {
+	calendar, err := New(Config{
+		Timezone:    "UTC",
+		TradingDays: []string{},
+		Regular:     HoursConfig{Open: "09:30", Close: "16:00"},
+	})
+	require.NoError(t, err)
+
+	now := time.Now()
+	require.Equal(t, SessionClosed, calendar.Session(now))
+	require.True(t, calendar.NextOpen(now).IsZero())
+	require.True(t, calendar.NextClose(now).IsZero())
+}
+
+func TestAlwaysOpen(t *testing.T) {
+	calendar := AlwaysOpen()
+	at := time.Date(2021, time.March, 6, 23, 59, 0, 0, time.UTC)
+
+	require.Equal(t, SessionRegular, calendar.Session(at))
+	require.Equal(t, time.Date(2021, time.March, 7, 0, 0, 0, 0, time.UTC), calendar.NextClose(at))
+}
+
+func TestInvalidConfig(t *testing.T) {
+	regular := HoursConfig{Open: "09:30", Close: "16:00"}
+	testCases := []struct {
+		name   string
+		config Config
+	}{
+		{"Unknown Timezone", Config{Timezone: "Mars/Olympus_Mons", Regular: regular}},
+		{"No Regular Session", Config{Timezone: "UTC"}},
+		{"Invalid Time", Config{Timezone: "UTC", Regular: HoursConfig{Open: "9h30", Close: "16:00"}}},
+		{"Closes Before Open", Config{Timezone: "UTC", Regular: HoursConfig{Open: "16:00", Close: "09:30"}}},
+		{"Overlapping Sessions", Config{Timezone: "UTC", Regular: regular,
+			PreMarket: &HoursConfig{Open: "04:00", Close: "10:00"}}},
+		{"Invalid Trading Day", Config{Timezone: "UTC", Regular: regular, TradingDays: []string{"Funday"}}},
+		{"Invalid Holiday", Config{Timezone: "UTC", Regular: regular,
+			Holidays: []HolidayConfig{{Date: "2021-13-01"}}}},
+		{"Half Day Closing Before Open", Config{Timezone: "UTC", Regular: regular,
+			HalfDays: []HalfDayConfig{{Date: "2021-11-26", Close: "08:00"}}}},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, err := New(testCase.config)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	calendar, err := Load("../env/calendar.json")
+	require.NoError(t, err)
+	require.Equal(t, "America/New_York", calendar.Location().String())
+
+	_, err = Load("../env/missing.json")
+	require.Error(t, err)
+}
@@ -0,0 +1 @@
+ALTER TABLE account ADD COLUMN hashed_password TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,2 @@
+ALTER TABLE account DROP COLUMN IF EXISTS role;
+DROP TYPE account_role;
@@ -0,0 +1,2 @@
+CREATE TYPE account_role as ENUM ('STAFF', 'USER');
+ALTER TABLE account ADD COLUMN role account_role NOT NULL DEFAULT 'USER'::account_role;
@@ -0,0 +1,2 @@
+CREATE TYPE account_status as ENUM ('PENDING', 'APPROVED', 'INACTIVE');
+ALTER TABLE account ADD COLUMN status account_status NOT NULL DEFAULT 'PENDING'::account_status;
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS trade_account_created_idx;
@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS trade_account_created_idx ON trade (account_uuid, created_date, trade_uuid);
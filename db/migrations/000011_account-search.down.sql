@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS address_state_idx;
+DROP INDEX IF EXISTS account_search_idx;
+DROP INDEX IF EXISTS account_created_date_idx;
+DROP INDEX IF EXISTS account_email_lower_idx;
+DROP INDEX IF EXISTS account_username_pattern_idx;
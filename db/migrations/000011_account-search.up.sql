@@ -0,0 +1,5 @@
+CREATE INDEX IF NOT EXISTS account_username_pattern_idx ON account (username text_pattern_ops);
+CREATE INDEX IF NOT EXISTS account_email_lower_idx ON account (lower(email));
+CREATE INDEX IF NOT EXISTS account_created_date_idx ON account (created_date, account_uuid);
+CREATE INDEX IF NOT EXISTS account_search_idx ON account USING GIN (to_tsvector('simple', username || ' ' || email));
+CREATE INDEX IF NOT EXISTS address_state_idx ON address (state);
@@ -0,0 +1 @@
+ALTER TABLE trade ALTER COLUMN price TYPE NUMERIC(11,2);
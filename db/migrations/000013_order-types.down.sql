@@ -0,0 +1,7 @@
+DELETE FROM trade WHERE price IS NULL;
+ALTER TABLE trade ALTER COLUMN price SET NOT NULL;
+ALTER TABLE trade DROP COLUMN IF EXISTS stop_price;
+ALTER TABLE trade DROP COLUMN IF EXISTS time_in_force;
+ALTER TABLE trade DROP COLUMN IF EXISTS order_type;
+DROP TYPE time_in_force;
+DROP TYPE order_type;
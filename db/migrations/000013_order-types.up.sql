@@ -0,0 +1,6 @@
+CREATE TYPE order_type as ENUM ('MARKET', 'LIMIT', 'STOP', 'STOP_LIMIT');
+CREATE TYPE time_in_force as ENUM ('DAY', 'GTC', 'IOC', 'FOK');
+ALTER TABLE trade ADD COLUMN order_type order_type NOT NULL DEFAULT 'LIMIT'::order_type;
+ALTER TABLE trade ADD COLUMN time_in_force time_in_force NOT NULL DEFAULT 'GTC'::time_in_force;
+ALTER TABLE trade ADD COLUMN stop_price NUMERIC(18,8);
+ALTER TABLE trade ALTER COLUMN price DROP NOT NULL;
@@ -0,0 +1,7 @@
+UPDATE trade SET status = 'SUBMITTED' WHERE status = 'PARTIALLY_FILLED';
+ALTER TYPE trade_status RENAME TO trade_status_old;
+CREATE TYPE trade_status as ENUM ('SUBMITTED', 'CANCELLED', 'COMPLETED', 'FAILED');
+ALTER TABLE trade ALTER COLUMN status DROP DEFAULT;
+ALTER TABLE trade ALTER COLUMN status TYPE trade_status USING status::text::trade_status;
+ALTER TABLE trade ALTER COLUMN status SET DEFAULT 'SUBMITTED'::trade_status;
+DROP TYPE trade_status_old;
@@ -0,0 +1 @@
+ALTER TYPE trade_status ADD VALUE IF NOT EXISTS 'PARTIALLY_FILLED' AFTER 'SUBMITTED';
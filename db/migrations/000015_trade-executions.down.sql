@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS trade_execution;
+ALTER TABLE trade DROP COLUMN IF EXISTS average_fill_price;
+ALTER TABLE trade DROP COLUMN IF EXISTS remaining_quantity;
+ALTER TABLE trade DROP COLUMN IF EXISTS filled_quantity;
@@ -0,0 +1,19 @@
+ALTER TABLE trade ADD COLUMN filled_quantity NUMERIC(9) NOT NULL DEFAULT 0;
+ALTER TABLE trade ADD COLUMN remaining_quantity NUMERIC(9) NOT NULL DEFAULT 0;
+ALTER TABLE trade ADD COLUMN average_fill_price NUMERIC(18,8);
+UPDATE trade 
+   SET filled_quantity = CASE WHEN status = 'COMPLETED' THEN quantity ELSE 0 END,
+       remaining_quantity = CASE WHEN status = 'COMPLETED' THEN 0 ELSE quantity END,
+       average_fill_price = CASE WHEN status = 'COMPLETED' THEN price END;
+
+CREATE TABLE IF NOT EXISTS trade_execution
+(
+  execution_uuid UUID NOT NULL DEFAULT uuid_generate_v4(),
+  trade_uuid UUID NOT NULL,
+  quantity NUMERIC(9) NOT NULL CHECK (quantity > 0),
+  price NUMERIC(18,8) NOT NULL,
+  executed_date TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT now(),
+  PRIMARY KEY(execution_uuid),
+  FOREIGN KEY (trade_uuid) REFERENCES trade (trade_uuid)
+);
+CREATE INDEX IF NOT EXISTS trade_execution_trade_idx ON trade_execution (trade_uuid, executed_date);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS trade_version;
+ALTER TABLE trade DROP COLUMN IF EXISTS version;
@@ -0,0 +1,17 @@
+ALTER TABLE trade ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+
+CREATE TABLE IF NOT EXISTS trade_version
+(
+  trade_uuid UUID NOT NULL,
+  version INTEGER NOT NULL,
+  quantity NUMERIC(9) NOT NULL,
+  price NUMERIC(18,8),
+  stop_price NUMERIC(18,8),
+  created_date TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT now(),
+  PRIMARY KEY(trade_uuid, version),
+  FOREIGN KEY (trade_uuid) REFERENCES trade (trade_uuid)
+);
+
+INSERT INTO trade_version (trade_uuid, version, quantity, price, stop_price, created_date)
+     SELECT trade_uuid, version, quantity, price, stop_price, COALESCE(created_date, now())
+       FROM trade;
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS idempotency_key
+(
+  scope TEXT NOT NULL,
+  idempotency_key TEXT NOT NULL,
+  request_hash TEXT NOT NULL,
+  response_status INTEGER,
+  response_body BYTEA,
+  created_date TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT now(),
+  expires_date TIMESTAMP WITHOUT TIME ZONE NOT NULL,
+  PRIMARY KEY(scope, idempotency_key)
+);
+CREATE INDEX IF NOT EXISTS idempotency_key_expires_idx ON idempotency_key (expires_date);
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS ledger_posting;
+DROP TABLE IF EXISTS journal_entry;
+DROP TYPE IF EXISTS journal_entry_type;
+DROP TABLE IF EXISTS ledger_account;
+DROP TYPE IF EXISTS ledger_account_type;
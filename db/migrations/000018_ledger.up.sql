@@ -0,0 +1,43 @@
+CREATE TYPE ledger_account_type as ENUM ('CASH', 'SECURITIES', 'BANK', 'MARKET', 'FEES');
+
+-- ledger accounts of the broker itself belong to the nil account uuid
+CREATE TABLE IF NOT EXISTS ledger_account
+(
+  ledger_account_uuid UUID NOT NULL DEFAULT uuid_generate_v4(),
+  account_uuid UUID NOT NULL,
+  type ledger_account_type NOT NULL,
+  asset TEXT NOT NULL,
+  created_date TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT now(),
+  PRIMARY KEY(ledger_account_uuid),
+  UNIQUE (account_uuid, type, asset)
+);
+
+CREATE TYPE journal_entry_type as ENUM ('DEPOSIT', 'WITHDRAWAL', 'FILL', 'FEE');
+
+CREATE TABLE IF NOT EXISTS journal_entry
+(
+  journal_entry_uuid UUID NOT NULL DEFAULT uuid_generate_v4(),
+  account_uuid UUID NOT NULL,
+  type journal_entry_type NOT NULL,
+  trade_uuid UUID,
+  description TEXT NOT NULL,
+  created_date TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT now(),
+  PRIMARY KEY(journal_entry_uuid),
+  FOREIGN KEY (account_uuid) REFERENCES account (account_uuid),
+  FOREIGN KEY (trade_uuid) REFERENCES trade (trade_uuid)
+);
+CREATE INDEX IF NOT EXISTS journal_entry_account_idx ON journal_entry (account_uuid, created_date, journal_entry_uuid);
+
+-- debits are positive and credits negative, the postings of an entry sum to zero per asset
+CREATE TABLE IF NOT EXISTS ledger_posting
+(
+  posting_uuid UUID NOT NULL DEFAULT uuid_generate_v4(),
+  journal_entry_uuid UUID NOT NULL,
+  ledger_account_uuid UUID NOT NULL,
+  amount NUMERIC(20,8) NOT NULL CHECK (amount <> 0),
+  PRIMARY KEY(posting_uuid),
+  FOREIGN KEY (journal_entry_uuid) REFERENCES journal_entry (journal_entry_uuid),
+  FOREIGN KEY (ledger_account_uuid) REFERENCES ledger_account (ledger_account_uuid)
+);
+CREATE INDEX IF NOT EXISTS ledger_posting_entry_idx ON ledger_posting (journal_entry_uuid);
+CREATE INDEX IF NOT EXISTS ledger_posting_account_idx ON ledger_posting (ledger_account_uuid);
@@ -0,0 +1,41 @@
+CREATE TABLE IF NOT EXISTS position
+(
+  account_uuid UUID NOT NULL,
+  symbol TEXT NOT NULL,
+  quantity NUMERIC(9) NOT NULL DEFAULT 0,
+  average_cost NUMERIC(18,8) NOT NULL DEFAULT 0,
+  created_date TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT now(),
+  updated_date TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT now(),
+  PRIMARY KEY(account_uuid, symbol),
+  FOREIGN KEY (account_uuid) REFERENCES account (account_uuid)
+);
+
+-- replays the fills made so far, the same way service.applyFillToPosition does
+DO $$
+DECLARE
+  fill RECORD;
+  held position%ROWTYPE;
+  signed NUMERIC;
+BEGIN
+  FOR fill IN
+        SELECT trade.account_uuid, trade.symbol, trade.side, trade_execution.quantity, trade_execution.price
+          FROM trade_execution
+    INNER JOIN trade ON trade.trade_uuid = trade_execution.trade_uuid
+      ORDER BY trade_execution.executed_date, trade_execution.execution_uuid
+  LOOP
+    INSERT INTO position (account_uuid, symbol) VALUES (fill.account_uuid, fill.symbol) ON CONFLICT DO NOTHING;
+    SELECT * INTO held FROM position WHERE account_uuid = fill.account_uuid AND symbol = fill.symbol;
+    signed := CASE WHEN fill.side = 'BUY' THEN fill.quantity ELSE -fill.quantity END;
+    UPDATE position
+       SET average_cost = CASE
+             WHEN held.quantity + signed = 0 THEN 0
+             WHEN held.quantity = 0 OR sign(held.quantity) = sign(signed)
+               THEN round((abs(held.quantity) * held.average_cost + fill.quantity * fill.price) / (abs(held.quantity) + fill.quantity), 8)
+             WHEN sign(held.quantity + signed) <> sign(held.quantity) THEN fill.price
+             ELSE held.average_cost
+           END,
+           quantity = held.quantity + signed
+     WHERE account_uuid = fill.account_uuid
+       AND symbol = fill.symbol;
+  END LOOP;
+END $$;
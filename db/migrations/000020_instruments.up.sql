@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS instrument
+(
+  symbol TEXT NOT NULL CHECK (symbol = upper(symbol)),
+  name TEXT NOT NULL,
+  exchange TEXT NOT NULL,
+  tick_size NUMERIC(18,8) NOT NULL CHECK (tick_size > 0),
+  lot_size NUMERIC(9) NOT NULL DEFAULT 1 CHECK (lot_size > 0),
+  tradable BOOLEAN NOT NULL DEFAULT TRUE,
+  currency CHAR(3) NOT NULL DEFAULT 'USD',
+  created_date TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT now(),
+  updated_date TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT now(),
+  PRIMARY KEY(symbol)
+);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS risk_check;
+DROP TABLE IF EXISTS risk_limit;
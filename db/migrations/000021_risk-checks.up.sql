@@ -0,0 +1,29 @@
+CREATE TABLE IF NOT EXISTS risk_limit
+(
+  account_uuid UUID NOT NULL,
+  max_order_notional NUMERIC(18,2) CHECK (max_order_notional > 0),
+  max_daily_notional NUMERIC(18,2) CHECK (max_daily_notional > 0),
+  max_position_quantity NUMERIC(9) CHECK (max_position_quantity > 0),
+  created_date TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT now(),
+  updated_date TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT now(),
+  PRIMARY KEY(account_uuid),
+  FOREIGN KEY (account_uuid) REFERENCES account (account_uuid)
+);
+
+CREATE TABLE IF NOT EXISTS risk_check
+(
+  risk_check_uuid UUID NOT NULL DEFAULT uuid_generate_v4(),
+  account_uuid UUID NOT NULL,
+  trade_uuid UUID,
+  symbol TEXT NOT NULL,
+  side trade_side NOT NULL,
+  quantity NUMERIC(9) NOT NULL,
+  notional NUMERIC(18,2),
+  passed BOOLEAN NOT NULL,
+  violations JSONB NOT NULL DEFAULT '[]',
+  created_date TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT now(),
+  PRIMARY KEY(risk_check_uuid),
+  FOREIGN KEY (account_uuid) REFERENCES account (account_uuid),
+  FOREIGN KEY (trade_uuid) REFERENCES trade (trade_uuid)
+);
+CREATE INDEX IF NOT EXISTS risk_check_account_idx ON risk_check (account_uuid, created_date);
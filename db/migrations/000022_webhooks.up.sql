@@ -0,0 +1,49 @@
+CREATE TABLE IF NOT EXISTS webhook_subscription
+(
+  webhook_subscription_uuid UUID NOT NULL DEFAULT uuid_generate_v4(),
+  account_uuid UUID NOT NULL,
+  url TEXT NOT NULL,
+  secret TEXT NOT NULL,
+  event_types TEXT[] NOT NULL,
+  active BOOLEAN NOT NULL DEFAULT TRUE,
+  created_date TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT now(),
+  updated_date TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT now(),
+  PRIMARY KEY(webhook_subscription_uuid),
+  FOREIGN KEY (account_uuid) REFERENCES account (account_uuid)
+);
+CREATE INDEX IF NOT EXISTS webhook_subscription_account_idx ON webhook_subscription (account_uuid);
+
+CREATE TABLE IF NOT EXISTS webhook_delivery
+(
+  webhook_delivery_uuid UUID NOT NULL DEFAULT uuid_generate_v4(),
+  webhook_subscription_uuid UUID NOT NULL,
+  event_uuid UUID NOT NULL,
+  event_type TEXT NOT NULL,
+  payload JSONB NOT NULL,
+  attempts INTEGER NOT NULL DEFAULT 0,
+  next_attempt_date TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT now(),
+  response_status INTEGER,
+  last_error TEXT,
+  delivered_date TIMESTAMP WITHOUT TIME ZONE,
+  created_date TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT now(),
+  PRIMARY KEY(webhook_delivery_uuid),
+  FOREIGN KEY (webhook_subscription_uuid) REFERENCES webhook_subscription (webhook_subscription_uuid) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS webhook_delivery_due_idx ON webhook_delivery (next_attempt_date) WHERE delivered_date IS NULL;
+
+CREATE TABLE IF NOT EXISTS webhook_dead_letter
+(
+  webhook_dead_letter_uuid UUID NOT NULL DEFAULT uuid_generate_v4(),
+  webhook_subscription_uuid UUID NOT NULL,
+  event_uuid UUID NOT NULL,
+  event_type TEXT NOT NULL,
+  payload JSONB NOT NULL,
+  attempts INTEGER NOT NULL,
+  response_status INTEGER,
+  last_error TEXT NOT NULL,
+  replayed_date TIMESTAMP WITHOUT TIME ZONE,
+  created_date TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT now(),
+  PRIMARY KEY(webhook_dead_letter_uuid),
+  FOREIGN KEY (webhook_subscription_uuid) REFERENCES webhook_subscription (webhook_subscription_uuid) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS webhook_dead_letter_subscription_idx ON webhook_dead_letter (webhook_subscription_uuid, created_date);
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS outbox
+(
+  event_uuid UUID NOT NULL,
+  sequence BIGSERIAL NOT NULL,
+  event_type TEXT NOT NULL,
+  aggregate_type TEXT NOT NULL,
+  aggregate_uuid UUID NOT NULL,
+  payload JSONB NOT NULL,
+  attempts INTEGER NOT NULL DEFAULT 0,
+  last_error TEXT,
+  locked_until TIMESTAMP WITHOUT TIME ZONE,
+  published_date TIMESTAMP WITHOUT TIME ZONE,
+  created_date TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT now(),
+  PRIMARY KEY(event_uuid),
+  UNIQUE(sequence)
+);
+CREATE INDEX IF NOT EXISTS outbox_pending_idx ON outbox (aggregate_type, aggregate_uuid, sequence) WHERE published_date IS NULL;
@@ -0,0 +1,29 @@
+CREATE TABLE IF NOT EXISTS audit_log
+(
+  audit_log_uuid UUID NOT NULL DEFAULT uuid_generate_v4(),
+  actor TEXT NOT NULL,
+  action TEXT NOT NULL,
+  entity_type TEXT NOT NULL,
+  entity_id TEXT NOT NULL,
+  before JSONB NOT NULL,
+  after JSONB NOT NULL,
+  request_id TEXT,
+  ip TEXT,
+  created_date TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT clock_timestamp(),
+  PRIMARY KEY(audit_log_uuid)
+);
+CREATE INDEX IF NOT EXISTS audit_log_entity_idx ON audit_log (entity_type, entity_id, created_date);
+CREATE INDEX IF NOT EXISTS audit_log_actor_idx ON audit_log (actor, created_date);
+
+CREATE OR REPLACE FUNCTION audit_log_append_only() RETURNS trigger AS $$
+BEGIN
+  RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_log_no_change
+  BEFORE UPDATE OR DELETE ON audit_log
+  FOR EACH ROW EXECUTE PROCEDURE audit_log_append_only();
+CREATE TRIGGER audit_log_no_truncate
+  BEFORE TRUNCATE ON audit_log
+  FOR EACH STATEMENT EXECUTE PROCEDURE audit_log_append_only();
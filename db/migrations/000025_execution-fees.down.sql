@@ -0,0 +1 @@
+ALTER TABLE trade_execution DROP COLUMN IF EXISTS fee;
@@ -0,0 +1,15 @@
+ALTER TABLE trade_execution ADD COLUMN fee NUMERIC(20,8) NOT NULL DEFAULT 0;
+
+-- the fee of a fill is posted in the transaction of its execution and now() is the start of the
+-- transaction, so the fee entry is created at the execution date
+UPDATE trade_execution
+   SET fee = fees.fee
+  FROM (    SELECT journal_entry.trade_uuid, journal_entry.created_date, -SUM(ledger_posting.amount) AS fee
+              FROM journal_entry
+        INNER JOIN ledger_posting ON ledger_posting.journal_entry_uuid = journal_entry.journal_entry_uuid
+        INNER JOIN ledger_account ON ledger_account.ledger_account_uuid = ledger_posting.ledger_account_uuid
+             WHERE journal_entry.type = 'FEE'
+               AND ledger_account.type = 'CASH'
+          GROUP BY journal_entry.trade_uuid, journal_entry.created_date) AS fees
+ WHERE fees.trade_uuid = trade_execution.trade_uuid
+   AND fees.created_date = trade_execution.executed_date;
@@ -0,0 +1,1318 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/valverdethiago/trading-api/db/sqlc (interfaces: Store)
+
+// Package mockdb is a generated GoMock package.
+package mockdb
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+	decimal "github.com/shopspring/decimal"
+	db "github.com/valverdethiago/trading-api/db/sqlc"
+)
+
+// MockStore is a mock of Store interface.
+type MockStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockStoreMockRecorder
+}
+
+// MockStoreMockRecorder is the mock recorder for MockStore.
+type MockStoreMockRecorder struct {
+	mock *MockStore
+}
+
+// NewMockStore creates a new mock instance.
+func NewMockStore(ctrl *gomock.Controller) *MockStore {
+	mock := &MockStore{ctrl: ctrl}
+	mock.recorder = &MockStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStore) EXPECT() *MockStoreMockRecorder {
+	return m.recorder
+}
+
+// ClaimOutboxEvents mocks base method.
+func (m *MockStore) ClaimOutboxEvents(arg0 context.Context, arg1 db.ClaimOutboxEventsParams) ([]db.Outbox, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimOutboxEvents", arg0, arg1)
+	ret0, _ := ret[0].([]db.Outbox)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimOutboxEvents indicates an expected call of ClaimOutboxEvents.
+func (mr *MockStoreMockRecorder) ClaimOutboxEvents(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimOutboxEvents", reflect.TypeOf((*MockStore)(nil).ClaimOutboxEvents), arg0, arg1)
+}
+
+// ClaimWebhookDeliveries mocks base method.
+func (m *MockStore) ClaimWebhookDeliveries(arg0 context.Context, arg1 db.ClaimWebhookDeliveriesParams) ([]db.ClaimWebhookDeliveriesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimWebhookDeliveries", arg0, arg1)
+	ret0, _ := ret[0].([]db.ClaimWebhookDeliveriesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimWebhookDeliveries indicates an expected call of ClaimWebhookDeliveries.
+func (mr *MockStoreMockRecorder) ClaimWebhookDeliveries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimWebhookDeliveries", reflect.TypeOf((*MockStore)(nil).ClaimWebhookDeliveries), arg0, arg1)
+}
+
+// CountAccounts mocks base method.
+func (m *MockStore) CountAccounts(arg0 context.Context, arg1 db.CountAccountsParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountAccounts", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountAccounts indicates an expected call of CountAccounts.
+func (mr *MockStoreMockRecorder) CountAccounts(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountAccounts", reflect.TypeOf((*MockStore)(nil).CountAccounts), arg0, arg1)
+}
+
+// CreateAccount mocks base method.
+func (m *MockStore) CreateAccount(arg0 context.Context, arg1 db.CreateAccountParams) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAccount", arg0, arg1)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAccount indicates an expected call of CreateAccount.
+func (mr *MockStoreMockRecorder) CreateAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccount", reflect.TypeOf((*MockStore)(nil).CreateAccount), arg0, arg1)
+}
+
+// CreateAddress mocks base method.
+func (m *MockStore) CreateAddress(arg0 context.Context, arg1 db.CreateAddressParams) (db.Address, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAddress", arg0, arg1)
+	ret0, _ := ret[0].(db.Address)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAddress indicates an expected call of CreateAddress.
+func (mr *MockStoreMockRecorder) CreateAddress(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAddress", reflect.TypeOf((*MockStore)(nil).CreateAddress), arg0, arg1)
+}
+
+// CreateAuditLog mocks base method.
+func (m *MockStore) CreateAuditLog(arg0 context.Context, arg1 db.CreateAuditLogParams) (db.AuditLog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAuditLog", arg0, arg1)
+	ret0, _ := ret[0].(db.AuditLog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAuditLog indicates an expected call of CreateAuditLog.
+func (mr *MockStoreMockRecorder) CreateAuditLog(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAuditLog", reflect.TypeOf((*MockStore)(nil).CreateAuditLog), arg0, arg1)
+}
+
+// CreateIdempotencyKey mocks base method.
+func (m *MockStore) CreateIdempotencyKey(arg0 context.Context, arg1 db.CreateIdempotencyKeyParams) (db.IdempotencyKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateIdempotencyKey", arg0, arg1)
+	ret0, _ := ret[0].(db.IdempotencyKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateIdempotencyKey indicates an expected call of CreateIdempotencyKey.
+func (mr *MockStoreMockRecorder) CreateIdempotencyKey(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateIdempotencyKey", reflect.TypeOf((*MockStore)(nil).CreateIdempotencyKey), arg0, arg1)
+}
+
+// CreateInstrument mocks base method.
+func (m *MockStore) CreateInstrument(arg0 context.Context, arg1 db.CreateInstrumentParams) (db.Instrument, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateInstrument", arg0, arg1)
+	ret0, _ := ret[0].(db.Instrument)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateInstrument indicates an expected call of CreateInstrument.
+func (mr *MockStoreMockRecorder) CreateInstrument(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateInstrument", reflect.TypeOf((*MockStore)(nil).CreateInstrument), arg0, arg1)
+}
+
+// CreateJournalEntry mocks base method.
+func (m *MockStore) CreateJournalEntry(arg0 context.Context, arg1 db.CreateJournalEntryParams) (db.JournalEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateJournalEntry", arg0, arg1)
+	ret0, _ := ret[0].(db.JournalEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateJournalEntry indicates an expected call of CreateJournalEntry.
+func (mr *MockStoreMockRecorder) CreateJournalEntry(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateJournalEntry", reflect.TypeOf((*MockStore)(nil).CreateJournalEntry), arg0, arg1)
+}
+
+// CreateLedgerPosting mocks base method.
+func (m *MockStore) CreateLedgerPosting(arg0 context.Context, arg1 db.CreateLedgerPostingParams) (db.LedgerPosting, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateLedgerPosting", arg0, arg1)
+	ret0, _ := ret[0].(db.LedgerPosting)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateLedgerPosting indicates an expected call of CreateLedgerPosting.
+func (mr *MockStoreMockRecorder) CreateLedgerPosting(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLedgerPosting", reflect.TypeOf((*MockStore)(nil).CreateLedgerPosting), arg0, arg1)
+}
+
+// CreateOutboxEvent mocks base method.
+func (m *MockStore) CreateOutboxEvent(arg0 context.Context, arg1 db.CreateOutboxEventParams) (db.Outbox, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOutboxEvent", arg0, arg1)
+	ret0, _ := ret[0].(db.Outbox)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateOutboxEvent indicates an expected call of CreateOutboxEvent.
+func (mr *MockStoreMockRecorder) CreateOutboxEvent(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOutboxEvent", reflect.TypeOf((*MockStore)(nil).CreateOutboxEvent), arg0, arg1)
+}
+
+// CreateRiskCheck mocks base method.
+func (m *MockStore) CreateRiskCheck(arg0 context.Context, arg1 db.CreateRiskCheckParams) (db.RiskCheck, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRiskCheck", arg0, arg1)
+	ret0, _ := ret[0].(db.RiskCheck)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateRiskCheck indicates an expected call of CreateRiskCheck.
+func (mr *MockStoreMockRecorder) CreateRiskCheck(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRiskCheck", reflect.TypeOf((*MockStore)(nil).CreateRiskCheck), arg0, arg1)
+}
+
+// CreateTrade mocks base method.
+func (m *MockStore) CreateTrade(arg0 context.Context, arg1 db.CreateTradeParams) (db.Trade, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTrade", arg0, arg1)
+	ret0, _ := ret[0].(db.Trade)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTrade indicates an expected call of CreateTrade.
+func (mr *MockStoreMockRecorder) CreateTrade(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTrade", reflect.TypeOf((*MockStore)(nil).CreateTrade), arg0, arg1)
+}
+
+// CreateTradeExecution mocks base method.
+func (m *MockStore) CreateTradeExecution(arg0 context.Context, arg1 db.CreateTradeExecutionParams) (db.TradeExecution, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTradeExecution", arg0, arg1)
+	ret0, _ := ret[0].(db.TradeExecution)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTradeExecution indicates an expected call of CreateTradeExecution.
+func (mr *MockStoreMockRecorder) CreateTradeExecution(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTradeExecution", reflect.TypeOf((*MockStore)(nil).CreateTradeExecution), arg0, arg1)
+}
+
+// CreateTradeVersion mocks base method.
+func (m *MockStore) CreateTradeVersion(arg0 context.Context, arg1 db.CreateTradeVersionParams) (db.TradeVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTradeVersion", arg0, arg1)
+	ret0, _ := ret[0].(db.TradeVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTradeVersion indicates an expected call of CreateTradeVersion.
+func (mr *MockStoreMockRecorder) CreateTradeVersion(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTradeVersion", reflect.TypeOf((*MockStore)(nil).CreateTradeVersion), arg0, arg1)
+}
+
+// CreateWebhookDeadLetter mocks base method.
+func (m *MockStore) CreateWebhookDeadLetter(arg0 context.Context, arg1 db.CreateWebhookDeadLetterParams) (db.WebhookDeadLetter, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhookDeadLetter", arg0, arg1)
+	ret0, _ := ret[0].(db.WebhookDeadLetter)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWebhookDeadLetter indicates an expected call of CreateWebhookDeadLetter.
+func (mr *MockStoreMockRecorder) CreateWebhookDeadLetter(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhookDeadLetter", reflect.TypeOf((*MockStore)(nil).CreateWebhookDeadLetter), arg0, arg1)
+}
+
+// CreateWebhookDelivery mocks base method.
+func (m *MockStore) CreateWebhookDelivery(arg0 context.Context, arg1 db.CreateWebhookDeliveryParams) (db.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhookDelivery", arg0, arg1)
+	ret0, _ := ret[0].(db.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWebhookDelivery indicates an expected call of CreateWebhookDelivery.
+func (mr *MockStoreMockRecorder) CreateWebhookDelivery(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhookDelivery", reflect.TypeOf((*MockStore)(nil).CreateWebhookDelivery), arg0, arg1)
+}
+
+// CreateWebhookSubscription mocks base method.
+func (m *MockStore) CreateWebhookSubscription(arg0 context.Context, arg1 db.CreateWebhookSubscriptionParams) (db.WebhookSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhookSubscription", arg0, arg1)
+	ret0, _ := ret[0].(db.WebhookSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWebhookSubscription indicates an expected call of CreateWebhookSubscription.
+func (mr *MockStoreMockRecorder) CreateWebhookSubscription(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhookSubscription", reflect.TypeOf((*MockStore)(nil).CreateWebhookSubscription), arg0, arg1)
+}
+
+// DeleteAddressFromAccount mocks base method.
+func (m *MockStore) DeleteAddressFromAccount(arg0 context.Context, arg1 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAddressFromAccount", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAddressFromAccount indicates an expected call of DeleteAddressFromAccount.
+func (mr *MockStoreMockRecorder) DeleteAddressFromAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAddressFromAccount", reflect.TypeOf((*MockStore)(nil).DeleteAddressFromAccount), arg0, arg1)
+}
+
+// DeleteExpiredIdempotencyKeys mocks base method.
+func (m *MockStore) DeleteExpiredIdempotencyKeys(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredIdempotencyKeys", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteExpiredIdempotencyKeys indicates an expected call of DeleteExpiredIdempotencyKeys.
+func (mr *MockStoreMockRecorder) DeleteExpiredIdempotencyKeys(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredIdempotencyKeys", reflect.TypeOf((*MockStore)(nil).DeleteExpiredIdempotencyKeys), arg0)
+}
+
+// DeleteIdempotencyKey mocks base method.
+func (m *MockStore) DeleteIdempotencyKey(arg0 context.Context, arg1 db.DeleteIdempotencyKeyParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteIdempotencyKey", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteIdempotencyKey indicates an expected call of DeleteIdempotencyKey.
+func (mr *MockStoreMockRecorder) DeleteIdempotencyKey(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteIdempotencyKey", reflect.TypeOf((*MockStore)(nil).DeleteIdempotencyKey), arg0, arg1)
+}
+
+// DeleteInstrument mocks base method.
+func (m *MockStore) DeleteInstrument(arg0 context.Context, arg1 string) (db.Instrument, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteInstrument", arg0, arg1)
+	ret0, _ := ret[0].(db.Instrument)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteInstrument indicates an expected call of DeleteInstrument.
+func (mr *MockStoreMockRecorder) DeleteInstrument(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteInstrument", reflect.TypeOf((*MockStore)(nil).DeleteInstrument), arg0, arg1)
+}
+
+// DeleteWebhookDelivery mocks base method.
+func (m *MockStore) DeleteWebhookDelivery(arg0 context.Context, arg1 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhookDelivery", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebhookDelivery indicates an expected call of DeleteWebhookDelivery.
+func (mr *MockStoreMockRecorder) DeleteWebhookDelivery(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhookDelivery", reflect.TypeOf((*MockStore)(nil).DeleteWebhookDelivery), arg0, arg1)
+}
+
+// DeleteWebhookSubscription mocks base method.
+func (m *MockStore) DeleteWebhookSubscription(arg0 context.Context, arg1 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhookSubscription", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebhookSubscription indicates an expected call of DeleteWebhookSubscription.
+func (mr *MockStoreMockRecorder) DeleteWebhookSubscription(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhookSubscription", reflect.TypeOf((*MockStore)(nil).DeleteWebhookSubscription), arg0, arg1)
+}
+
+// ExecTx mocks base method.
+func (m *MockStore) ExecTx(arg0 context.Context, arg1 func(db.Querier) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExecTx", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExecTx indicates an expected call of ExecTx.
+func (mr *MockStoreMockRecorder) ExecTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecTx", reflect.TypeOf((*MockStore)(nil).ExecTx), arg0, arg1)
+}
+
+// GetAccountById mocks base method.
+func (m *MockStore) GetAccountById(arg0 context.Context, arg1 uuid.UUID) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccountById", arg0, arg1)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccountById indicates an expected call of GetAccountById.
+func (mr *MockStoreMockRecorder) GetAccountById(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountById", reflect.TypeOf((*MockStore)(nil).GetAccountById), arg0, arg1)
+}
+
+// GetAccountByUsername mocks base method.
+func (m *MockStore) GetAccountByUsername(arg0 context.Context, arg1 string) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccountByUsername", arg0, arg1)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccountByUsername indicates an expected call of GetAccountByUsername.
+func (mr *MockStoreMockRecorder) GetAccountByUsername(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountByUsername", reflect.TypeOf((*MockStore)(nil).GetAccountByUsername), arg0, arg1)
+}
+
+// GetAccountRole mocks base method.
+func (m *MockStore) GetAccountRole(arg0 context.Context, arg1 uuid.UUID) (db.AccountRole, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccountRole", arg0, arg1)
+	ret0, _ := ret[0].(db.AccountRole)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccountRole indicates an expected call of GetAccountRole.
+func (mr *MockStoreMockRecorder) GetAccountRole(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountRole", reflect.TypeOf((*MockStore)(nil).GetAccountRole), arg0, arg1)
+}
+
+// GetAddressByAccount mocks base method.
+func (m *MockStore) GetAddressByAccount(arg0 context.Context, arg1 uuid.UUID) (db.Address, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAddressByAccount", arg0, arg1)
+	ret0, _ := ret[0].(db.Address)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAddressByAccount indicates an expected call of GetAddressByAccount.
+func (mr *MockStoreMockRecorder) GetAddressByAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAddressByAccount", reflect.TypeOf((*MockStore)(nil).GetAddressByAccount), arg0, arg1)
+}
+
+// GetAddressById mocks base method.
+func (m *MockStore) GetAddressById(arg0 context.Context, arg1 uuid.UUID) (db.Address, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAddressById", arg0, arg1)
+	ret0, _ := ret[0].(db.Address)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAddressById indicates an expected call of GetAddressById.
+func (mr *MockStoreMockRecorder) GetAddressById(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAddressById", reflect.TypeOf((*MockStore)(nil).GetAddressById), arg0, arg1)
+}
+
+// GetIdempotencyKey mocks base method.
+func (m *MockStore) GetIdempotencyKey(arg0 context.Context, arg1 db.GetIdempotencyKeyParams) (db.IdempotencyKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIdempotencyKey", arg0, arg1)
+	ret0, _ := ret[0].(db.IdempotencyKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIdempotencyKey indicates an expected call of GetIdempotencyKey.
+func (mr *MockStoreMockRecorder) GetIdempotencyKey(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIdempotencyKey", reflect.TypeOf((*MockStore)(nil).GetIdempotencyKey), arg0, arg1)
+}
+
+// GetInstrument mocks base method.
+func (m *MockStore) GetInstrument(arg0 context.Context, arg1 string) (db.Instrument, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInstrument", arg0, arg1)
+	ret0, _ := ret[0].(db.Instrument)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInstrument indicates an expected call of GetInstrument.
+func (mr *MockStoreMockRecorder) GetInstrument(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInstrument", reflect.TypeOf((*MockStore)(nil).GetInstrument), arg0, arg1)
+}
+
+// GetLedgerAccountBalance mocks base method.
+func (m *MockStore) GetLedgerAccountBalance(arg0 context.Context, arg1 uuid.UUID) (decimal.Decimal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLedgerAccountBalance", arg0, arg1)
+	ret0, _ := ret[0].(decimal.Decimal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLedgerAccountBalance indicates an expected call of GetLedgerAccountBalance.
+func (mr *MockStoreMockRecorder) GetLedgerAccountBalance(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLedgerAccountBalance", reflect.TypeOf((*MockStore)(nil).GetLedgerAccountBalance), arg0, arg1)
+}
+
+// GetOpenBuyNotional mocks base method.
+func (m *MockStore) GetOpenBuyNotional(arg0 context.Context, arg1 uuid.UUID) (decimal.Decimal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOpenBuyNotional", arg0, arg1)
+	ret0, _ := ret[0].(decimal.Decimal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOpenBuyNotional indicates an expected call of GetOpenBuyNotional.
+func (mr *MockStoreMockRecorder) GetOpenBuyNotional(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOpenBuyNotional", reflect.TypeOf((*MockStore)(nil).GetOpenBuyNotional), arg0, arg1)
+}
+
+// GetOpenBuyQuantity mocks base method.
+func (m *MockStore) GetOpenBuyQuantity(arg0 context.Context, arg1 db.GetOpenBuyQuantityParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOpenBuyQuantity", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOpenBuyQuantity indicates an expected call of GetOpenBuyQuantity.
+func (mr *MockStoreMockRecorder) GetOpenBuyQuantity(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOpenBuyQuantity", reflect.TypeOf((*MockStore)(nil).GetOpenBuyQuantity), arg0, arg1)
+}
+
+// GetOpenNotional mocks base method.
+func (m *MockStore) GetOpenNotional(arg0 context.Context, arg1 uuid.UUID) (decimal.Decimal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOpenNotional", arg0, arg1)
+	ret0, _ := ret[0].(decimal.Decimal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOpenNotional indicates an expected call of GetOpenNotional.
+func (mr *MockStoreMockRecorder) GetOpenNotional(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOpenNotional", reflect.TypeOf((*MockStore)(nil).GetOpenNotional), arg0, arg1)
+}
+
+// GetOpenSellQuantity mocks base method.
+func (m *MockStore) GetOpenSellQuantity(arg0 context.Context, arg1 db.GetOpenSellQuantityParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOpenSellQuantity", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOpenSellQuantity indicates an expected call of GetOpenSellQuantity.
+func (mr *MockStoreMockRecorder) GetOpenSellQuantity(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOpenSellQuantity", reflect.TypeOf((*MockStore)(nil).GetOpenSellQuantity), arg0, arg1)
+}
+
+// GetOutboxEvent mocks base method.
+func (m *MockStore) GetOutboxEvent(arg0 context.Context, arg1 uuid.UUID) (db.Outbox, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOutboxEvent", arg0, arg1)
+	ret0, _ := ret[0].(db.Outbox)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOutboxEvent indicates an expected call of GetOutboxEvent.
+func (mr *MockStoreMockRecorder) GetOutboxEvent(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOutboxEvent", reflect.TypeOf((*MockStore)(nil).GetOutboxEvent), arg0, arg1)
+}
+
+// GetPosition mocks base method.
+func (m *MockStore) GetPosition(arg0 context.Context, arg1 db.GetPositionParams) (db.Position, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPosition", arg0, arg1)
+	ret0, _ := ret[0].(db.Position)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPosition indicates an expected call of GetPosition.
+func (mr *MockStoreMockRecorder) GetPosition(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPosition", reflect.TypeOf((*MockStore)(nil).GetPosition), arg0, arg1)
+}
+
+// GetPositionForUpdate mocks base method.
+func (m *MockStore) GetPositionForUpdate(arg0 context.Context, arg1 db.GetPositionForUpdateParams) (db.Position, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPositionForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.Position)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPositionForUpdate indicates an expected call of GetPositionForUpdate.
+func (mr *MockStoreMockRecorder) GetPositionForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPositionForUpdate", reflect.TypeOf((*MockStore)(nil).GetPositionForUpdate), arg0, arg1)
+}
+
+// GetRiskLimit mocks base method.
+func (m *MockStore) GetRiskLimit(arg0 context.Context, arg1 uuid.UUID) (db.RiskLimit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRiskLimit", arg0, arg1)
+	ret0, _ := ret[0].(db.RiskLimit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRiskLimit indicates an expected call of GetRiskLimit.
+func (mr *MockStoreMockRecorder) GetRiskLimit(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRiskLimit", reflect.TypeOf((*MockStore)(nil).GetRiskLimit), arg0, arg1)
+}
+
+// GetTradeById mocks base method.
+func (m *MockStore) GetTradeById(arg0 context.Context, arg1 uuid.UUID) (db.Trade, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTradeById", arg0, arg1)
+	ret0, _ := ret[0].(db.Trade)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTradeById indicates an expected call of GetTradeById.
+func (mr *MockStoreMockRecorder) GetTradeById(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTradeById", reflect.TypeOf((*MockStore)(nil).GetTradeById), arg0, arg1)
+}
+
+// GetTradeByIdForUpdate mocks base method.
+func (m *MockStore) GetTradeByIdForUpdate(arg0 context.Context, arg1 uuid.UUID) (db.Trade, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTradeByIdForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.Trade)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTradeByIdForUpdate indicates an expected call of GetTradeByIdForUpdate.
+func (mr *MockStoreMockRecorder) GetTradeByIdForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTradeByIdForUpdate", reflect.TypeOf((*MockStore)(nil).GetTradeByIdForUpdate), arg0, arg1)
+}
+
+// GetTradedNotional mocks base method.
+func (m *MockStore) GetTradedNotional(arg0 context.Context, arg1 db.GetTradedNotionalParams) (decimal.Decimal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTradedNotional", arg0, arg1)
+	ret0, _ := ret[0].(decimal.Decimal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTradedNotional indicates an expected call of GetTradedNotional.
+func (mr *MockStoreMockRecorder) GetTradedNotional(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTradedNotional", reflect.TypeOf((*MockStore)(nil).GetTradedNotional), arg0, arg1)
+}
+
+// GetWebhookDeadLetter mocks base method.
+func (m *MockStore) GetWebhookDeadLetter(arg0 context.Context, arg1 uuid.UUID) (db.WebhookDeadLetter, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookDeadLetter", arg0, arg1)
+	ret0, _ := ret[0].(db.WebhookDeadLetter)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookDeadLetter indicates an expected call of GetWebhookDeadLetter.
+func (mr *MockStoreMockRecorder) GetWebhookDeadLetter(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookDeadLetter", reflect.TypeOf((*MockStore)(nil).GetWebhookDeadLetter), arg0, arg1)
+}
+
+// GetWebhookSubscription mocks base method.
+func (m *MockStore) GetWebhookSubscription(arg0 context.Context, arg1 uuid.UUID) (db.WebhookSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookSubscription", arg0, arg1)
+	ret0, _ := ret[0].(db.WebhookSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookSubscription indicates an expected call of GetWebhookSubscription.
+func (mr *MockStoreMockRecorder) GetWebhookSubscription(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookSubscription", reflect.TypeOf((*MockStore)(nil).GetWebhookSubscription), arg0, arg1)
+}
+
+// ListAccountFills mocks base method.
+func (m *MockStore) ListAccountFills(arg0 context.Context, arg1 db.ListAccountFillsParams) ([]db.ListAccountFillsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccountFills", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListAccountFillsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccountFills indicates an expected call of ListAccountFills.
+func (mr *MockStoreMockRecorder) ListAccountFills(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccountFills", reflect.TypeOf((*MockStore)(nil).ListAccountFills), arg0, arg1)
+}
+
+// ListAccounts mocks base method.
+func (m *MockStore) ListAccounts(arg0 context.Context) ([]db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccounts", arg0)
+	ret0, _ := ret[0].([]db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccounts indicates an expected call of ListAccounts.
+func (mr *MockStoreMockRecorder) ListAccounts(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccounts", reflect.TypeOf((*MockStore)(nil).ListAccounts), arg0)
+}
+
+// ListAuditLog mocks base method.
+func (m *MockStore) ListAuditLog(arg0 context.Context, arg1 db.ListAuditLogParams) ([]db.AuditLog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAuditLog", arg0, arg1)
+	ret0, _ := ret[0].([]db.AuditLog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAuditLog indicates an expected call of ListAuditLog.
+func (mr *MockStoreMockRecorder) ListAuditLog(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAuditLog", reflect.TypeOf((*MockStore)(nil).ListAuditLog), arg0, arg1)
+}
+
+// ListInstruments mocks base method.
+func (m *MockStore) ListInstruments(arg0 context.Context) ([]db.Instrument, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListInstruments", arg0)
+	ret0, _ := ret[0].([]db.Instrument)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListInstruments indicates an expected call of ListInstruments.
+func (mr *MockStoreMockRecorder) ListInstruments(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListInstruments", reflect.TypeOf((*MockStore)(nil).ListInstruments), arg0)
+}
+
+// ListJournalEntries mocks base method.
+func (m *MockStore) ListJournalEntries(arg0 context.Context, arg1 db.ListJournalEntriesParams) ([]db.JournalEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListJournalEntries", arg0, arg1)
+	ret0, _ := ret[0].([]db.JournalEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListJournalEntries indicates an expected call of ListJournalEntries.
+func (mr *MockStoreMockRecorder) ListJournalEntries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListJournalEntries", reflect.TypeOf((*MockStore)(nil).ListJournalEntries), arg0, arg1)
+}
+
+// ListLatestPrices mocks base method.
+func (m *MockStore) ListLatestPrices(arg0 context.Context, arg1 db.ListLatestPricesParams) ([]db.ListLatestPricesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLatestPrices", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListLatestPricesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListLatestPrices indicates an expected call of ListLatestPrices.
+func (mr *MockStoreMockRecorder) ListLatestPrices(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLatestPrices", reflect.TypeOf((*MockStore)(nil).ListLatestPrices), arg0, arg1)
+}
+
+// ListLedgerBalances mocks base method.
+func (m *MockStore) ListLedgerBalances(arg0 context.Context, arg1 uuid.UUID) ([]db.ListLedgerBalancesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLedgerBalances", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListLedgerBalancesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListLedgerBalances indicates an expected call of ListLedgerBalances.
+func (mr *MockStoreMockRecorder) ListLedgerBalances(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLedgerBalances", reflect.TypeOf((*MockStore)(nil).ListLedgerBalances), arg0, arg1)
+}
+
+// ListLedgerPostings mocks base method.
+func (m *MockStore) ListLedgerPostings(arg0 context.Context, arg1 []uuid.UUID) ([]db.ListLedgerPostingsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLedgerPostings", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListLedgerPostingsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListLedgerPostings indicates an expected call of ListLedgerPostings.
+func (mr *MockStoreMockRecorder) ListLedgerPostings(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLedgerPostings", reflect.TypeOf((*MockStore)(nil).ListLedgerPostings), arg0, arg1)
+}
+
+// ListOpenTradesByAccount mocks base method.
+func (m *MockStore) ListOpenTradesByAccount(arg0 context.Context, arg1 uuid.UUID) ([]db.Trade, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOpenTradesByAccount", arg0, arg1)
+	ret0, _ := ret[0].([]db.Trade)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOpenTradesByAccount indicates an expected call of ListOpenTradesByAccount.
+func (mr *MockStoreMockRecorder) ListOpenTradesByAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOpenTradesByAccount", reflect.TypeOf((*MockStore)(nil).ListOpenTradesByAccount), arg0, arg1)
+}
+
+// ListPositionsByAccount mocks base method.
+func (m *MockStore) ListPositionsByAccount(arg0 context.Context, arg1 uuid.UUID) ([]db.Position, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPositionsByAccount", arg0, arg1)
+	ret0, _ := ret[0].([]db.Position)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPositionsByAccount indicates an expected call of ListPositionsByAccount.
+func (mr *MockStoreMockRecorder) ListPositionsByAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPositionsByAccount", reflect.TypeOf((*MockStore)(nil).ListPositionsByAccount), arg0, arg1)
+}
+
+// ListRiskChecksByAccount mocks base method.
+func (m *MockStore) ListRiskChecksByAccount(arg0 context.Context, arg1 db.ListRiskChecksByAccountParams) ([]db.RiskCheck, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRiskChecksByAccount", arg0, arg1)
+	ret0, _ := ret[0].([]db.RiskCheck)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRiskChecksByAccount indicates an expected call of ListRiskChecksByAccount.
+func (mr *MockStoreMockRecorder) ListRiskChecksByAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRiskChecksByAccount", reflect.TypeOf((*MockStore)(nil).ListRiskChecksByAccount), arg0, arg1)
+}
+
+// ListTradeExecutions mocks base method.
+func (m *MockStore) ListTradeExecutions(arg0 context.Context, arg1 uuid.UUID) ([]db.TradeExecution, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTradeExecutions", arg0, arg1)
+	ret0, _ := ret[0].([]db.TradeExecution)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTradeExecutions indicates an expected call of ListTradeExecutions.
+func (mr *MockStoreMockRecorder) ListTradeExecutions(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTradeExecutions", reflect.TypeOf((*MockStore)(nil).ListTradeExecutions), arg0, arg1)
+}
+
+// ListTradeVersions mocks base method.
+func (m *MockStore) ListTradeVersions(arg0 context.Context, arg1 uuid.UUID) ([]db.TradeVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTradeVersions", arg0, arg1)
+	ret0, _ := ret[0].([]db.TradeVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTradeVersions indicates an expected call of ListTradeVersions.
+func (mr *MockStoreMockRecorder) ListTradeVersions(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTradeVersions", reflect.TypeOf((*MockStore)(nil).ListTradeVersions), arg0, arg1)
+}
+
+// ListTradesByAccount mocks base method.
+func (m *MockStore) ListTradesByAccount(arg0 context.Context, arg1 uuid.UUID) ([]db.Trade, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTradesByAccount", arg0, arg1)
+	ret0, _ := ret[0].([]db.Trade)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTradesByAccount indicates an expected call of ListTradesByAccount.
+func (mr *MockStoreMockRecorder) ListTradesByAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTradesByAccount", reflect.TypeOf((*MockStore)(nil).ListTradesByAccount), arg0, arg1)
+}
+
+// ListTradesByAccountAsc mocks base method.
+func (m *MockStore) ListTradesByAccountAsc(arg0 context.Context, arg1 db.ListTradesByAccountAscParams) ([]db.Trade, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTradesByAccountAsc", arg0, arg1)
+	ret0, _ := ret[0].([]db.Trade)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTradesByAccountAsc indicates an expected call of ListTradesByAccountAsc.
+func (mr *MockStoreMockRecorder) ListTradesByAccountAsc(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTradesByAccountAsc", reflect.TypeOf((*MockStore)(nil).ListTradesByAccountAsc), arg0, arg1)
+}
+
+// ListTradesByAccountDesc mocks base method.
+func (m *MockStore) ListTradesByAccountDesc(arg0 context.Context, arg1 db.ListTradesByAccountDescParams) ([]db.Trade, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTradesByAccountDesc", arg0, arg1)
+	ret0, _ := ret[0].([]db.Trade)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTradesByAccountDesc indicates an expected call of ListTradesByAccountDesc.
+func (mr *MockStoreMockRecorder) ListTradesByAccountDesc(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTradesByAccountDesc", reflect.TypeOf((*MockStore)(nil).ListTradesByAccountDesc), arg0, arg1)
+}
+
+// ListTradesByStatus mocks base method.
+func (m *MockStore) ListTradesByStatus(arg0 context.Context, arg1 db.TradeStatus) ([]db.Trade, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTradesByStatus", arg0, arg1)
+	ret0, _ := ret[0].([]db.Trade)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTradesByStatus indicates an expected call of ListTradesByStatus.
+func (mr *MockStoreMockRecorder) ListTradesByStatus(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTradesByStatus", reflect.TypeOf((*MockStore)(nil).ListTradesByStatus), arg0, arg1)
+}
+
+// ListWebhookDeadLetters mocks base method.
+func (m *MockStore) ListWebhookDeadLetters(arg0 context.Context, arg1 db.ListWebhookDeadLettersParams) ([]db.WebhookDeadLetter, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWebhookDeadLetters", arg0, arg1)
+	ret0, _ := ret[0].([]db.WebhookDeadLetter)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWebhookDeadLetters indicates an expected call of ListWebhookDeadLetters.
+func (mr *MockStoreMockRecorder) ListWebhookDeadLetters(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhookDeadLetters", reflect.TypeOf((*MockStore)(nil).ListWebhookDeadLetters), arg0, arg1)
+}
+
+// ListWebhookSubscriptionsByAccount mocks base method.
+func (m *MockStore) ListWebhookSubscriptionsByAccount(arg0 context.Context, arg1 uuid.UUID) ([]db.WebhookSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWebhookSubscriptionsByAccount", arg0, arg1)
+	ret0, _ := ret[0].([]db.WebhookSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWebhookSubscriptionsByAccount indicates an expected call of ListWebhookSubscriptionsByAccount.
+func (mr *MockStoreMockRecorder) ListWebhookSubscriptionsByAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhookSubscriptionsByAccount", reflect.TypeOf((*MockStore)(nil).ListWebhookSubscriptionsByAccount), arg0, arg1)
+}
+
+// ListWebhookSubscriptionsForEvent mocks base method.
+func (m *MockStore) ListWebhookSubscriptionsForEvent(arg0 context.Context, arg1 db.ListWebhookSubscriptionsForEventParams) ([]db.WebhookSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWebhookSubscriptionsForEvent", arg0, arg1)
+	ret0, _ := ret[0].([]db.WebhookSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWebhookSubscriptionsForEvent indicates an expected call of ListWebhookSubscriptionsForEvent.
+func (mr *MockStoreMockRecorder) ListWebhookSubscriptionsForEvent(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhookSubscriptionsForEvent", reflect.TypeOf((*MockStore)(nil).ListWebhookSubscriptionsForEvent), arg0, arg1)
+}
+
+// MarkOutboxEventPublished mocks base method.
+func (m *MockStore) MarkOutboxEventPublished(arg0 context.Context, arg1 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkOutboxEventPublished", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkOutboxEventPublished indicates an expected call of MarkOutboxEventPublished.
+func (mr *MockStoreMockRecorder) MarkOutboxEventPublished(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkOutboxEventPublished", reflect.TypeOf((*MockStore)(nil).MarkOutboxEventPublished), arg0, arg1)
+}
+
+// MarkWebhookDeadLetterReplayed mocks base method.
+func (m *MockStore) MarkWebhookDeadLetterReplayed(arg0 context.Context, arg1 uuid.UUID) (db.WebhookDeadLetter, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkWebhookDeadLetterReplayed", arg0, arg1)
+	ret0, _ := ret[0].(db.WebhookDeadLetter)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkWebhookDeadLetterReplayed indicates an expected call of MarkWebhookDeadLetterReplayed.
+func (mr *MockStoreMockRecorder) MarkWebhookDeadLetterReplayed(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkWebhookDeadLetterReplayed", reflect.TypeOf((*MockStore)(nil).MarkWebhookDeadLetterReplayed), arg0, arg1)
+}
+
+// MarkWebhookDelivered mocks base method.
+func (m *MockStore) MarkWebhookDelivered(arg0 context.Context, arg1 db.MarkWebhookDeliveredParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkWebhookDelivered", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkWebhookDelivered indicates an expected call of MarkWebhookDelivered.
+func (mr *MockStoreMockRecorder) MarkWebhookDelivered(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkWebhookDelivered", reflect.TypeOf((*MockStore)(nil).MarkWebhookDelivered), arg0, arg1)
+}
+
+// RetryOutboxEvent mocks base method.
+func (m *MockStore) RetryOutboxEvent(arg0 context.Context, arg1 db.RetryOutboxEventParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetryOutboxEvent", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RetryOutboxEvent indicates an expected call of RetryOutboxEvent.
+func (mr *MockStoreMockRecorder) RetryOutboxEvent(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetryOutboxEvent", reflect.TypeOf((*MockStore)(nil).RetryOutboxEvent), arg0, arg1)
+}
+
+// RetryWebhookDelivery mocks base method.
+func (m *MockStore) RetryWebhookDelivery(arg0 context.Context, arg1 db.RetryWebhookDeliveryParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetryWebhookDelivery", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RetryWebhookDelivery indicates an expected call of RetryWebhookDelivery.
+func (mr *MockStoreMockRecorder) RetryWebhookDelivery(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetryWebhookDelivery", reflect.TypeOf((*MockStore)(nil).RetryWebhookDelivery), arg0, arg1)
+}
+
+// SearchAccounts mocks base method.
+func (m *MockStore) SearchAccounts(arg0 context.Context, arg1 db.SearchAccountsParams) ([]db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchAccounts", arg0, arg1)
+	ret0, _ := ret[0].([]db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchAccounts indicates an expected call of SearchAccounts.
+func (mr *MockStoreMockRecorder) SearchAccounts(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchAccounts", reflect.TypeOf((*MockStore)(nil).SearchAccounts), arg0, arg1)
+}
+
+// UpdateAccount mocks base method.
+func (m *MockStore) UpdateAccount(arg0 context.Context, arg1 db.UpdateAccountParams) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAccount", arg0, arg1)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateAccount indicates an expected call of UpdateAccount.
+func (mr *MockStoreMockRecorder) UpdateAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAccount", reflect.TypeOf((*MockStore)(nil).UpdateAccount), arg0, arg1)
+}
+
+// UpdateAccountRole mocks base method.
+func (m *MockStore) UpdateAccountRole(arg0 context.Context, arg1 db.UpdateAccountRoleParams) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAccountRole", arg0, arg1)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateAccountRole indicates an expected call of UpdateAccountRole.
+func (mr *MockStoreMockRecorder) UpdateAccountRole(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAccountRole", reflect.TypeOf((*MockStore)(nil).UpdateAccountRole), arg0, arg1)
+}
+
+// UpdateAccountStatus mocks base method.
+func (m *MockStore) UpdateAccountStatus(arg0 context.Context, arg1 db.UpdateAccountStatusParams) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAccountStatus", arg0, arg1)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateAccountStatus indicates an expected call of UpdateAccountStatus.
+func (mr *MockStoreMockRecorder) UpdateAccountStatus(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAccountStatus", reflect.TypeOf((*MockStore)(nil).UpdateAccountStatus), arg0, arg1)
+}
+
+// UpdateAddress mocks base method.
+func (m *MockStore) UpdateAddress(arg0 context.Context, arg1 db.UpdateAddressParams) (db.Address, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAddress", arg0, arg1)
+	ret0, _ := ret[0].(db.Address)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateAddress indicates an expected call of UpdateAddress.
+func (mr *MockStoreMockRecorder) UpdateAddress(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAddress", reflect.TypeOf((*MockStore)(nil).UpdateAddress), arg0, arg1)
+}
+
+// UpdateIdempotencyKeyResponse mocks base method.
+func (m *MockStore) UpdateIdempotencyKeyResponse(arg0 context.Context, arg1 db.UpdateIdempotencyKeyResponseParams) (db.IdempotencyKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateIdempotencyKeyResponse", arg0, arg1)
+	ret0, _ := ret[0].(db.IdempotencyKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateIdempotencyKeyResponse indicates an expected call of UpdateIdempotencyKeyResponse.
+func (mr *MockStoreMockRecorder) UpdateIdempotencyKeyResponse(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateIdempotencyKeyResponse", reflect.TypeOf((*MockStore)(nil).UpdateIdempotencyKeyResponse), arg0, arg1)
+}
+
+// UpdateInstrument mocks base method.
+func (m *MockStore) UpdateInstrument(arg0 context.Context, arg1 db.UpdateInstrumentParams) (db.Instrument, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateInstrument", arg0, arg1)
+	ret0, _ := ret[0].(db.Instrument)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateInstrument indicates an expected call of UpdateInstrument.
+func (mr *MockStoreMockRecorder) UpdateInstrument(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateInstrument", reflect.TypeOf((*MockStore)(nil).UpdateInstrument), arg0, arg1)
+}
+
+// UpdateTrade mocks base method.
+func (m *MockStore) UpdateTrade(arg0 context.Context, arg1 db.UpdateTradeParams) (db.Trade, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTrade", arg0, arg1)
+	ret0, _ := ret[0].(db.Trade)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTrade indicates an expected call of UpdateTrade.
+func (mr *MockStoreMockRecorder) UpdateTrade(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTrade", reflect.TypeOf((*MockStore)(nil).UpdateTrade), arg0, arg1)
+}
+
+// UpdateTradeFill mocks base method.
+func (m *MockStore) UpdateTradeFill(arg0 context.Context, arg1 db.UpdateTradeFillParams) (db.Trade, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTradeFill", arg0, arg1)
+	ret0, _ := ret[0].(db.Trade)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTradeFill indicates an expected call of UpdateTradeFill.
+func (mr *MockStoreMockRecorder) UpdateTradeFill(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTradeFill", reflect.TypeOf((*MockStore)(nil).UpdateTradeFill), arg0, arg1)
+}
+
+// UpdateTradeStatus mocks base method.
+func (m *MockStore) UpdateTradeStatus(arg0 context.Context, arg1 db.UpdateTradeStatusParams) (db.Trade, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTradeStatus", arg0, arg1)
+	ret0, _ := ret[0].(db.Trade)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTradeStatus indicates an expected call of UpdateTradeStatus.
+func (mr *MockStoreMockRecorder) UpdateTradeStatus(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTradeStatus", reflect.TypeOf((*MockStore)(nil).UpdateTradeStatus), arg0, arg1)
+}
+
+// UpdateWebhookSubscription mocks base method.
+func (m *MockStore) UpdateWebhookSubscription(arg0 context.Context, arg1 db.UpdateWebhookSubscriptionParams) (db.WebhookSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWebhookSubscription", arg0, arg1)
+	ret0, _ := ret[0].(db.WebhookSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateWebhookSubscription indicates an expected call of UpdateWebhookSubscription.
+func (mr *MockStoreMockRecorder) UpdateWebhookSubscription(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWebhookSubscription", reflect.TypeOf((*MockStore)(nil).UpdateWebhookSubscription), arg0, arg1)
+}
+
+// UpsertInstrument mocks base method.
+func (m *MockStore) UpsertInstrument(arg0 context.Context, arg1 db.UpsertInstrumentParams) (db.Instrument, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertInstrument", arg0, arg1)
+	ret0, _ := ret[0].(db.Instrument)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertInstrument indicates an expected call of UpsertInstrument.
+func (mr *MockStoreMockRecorder) UpsertInstrument(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertInstrument", reflect.TypeOf((*MockStore)(nil).UpsertInstrument), arg0, arg1)
+}
+
+// UpsertLedgerAccount mocks base method.
+func (m *MockStore) UpsertLedgerAccount(arg0 context.Context, arg1 db.UpsertLedgerAccountParams) (db.LedgerAccount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertLedgerAccount", arg0, arg1)
+	ret0, _ := ret[0].(db.LedgerAccount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertLedgerAccount indicates an expected call of UpsertLedgerAccount.
+func (mr *MockStoreMockRecorder) UpsertLedgerAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertLedgerAccount", reflect.TypeOf((*MockStore)(nil).UpsertLedgerAccount), arg0, arg1)
+}
+
+// UpsertPosition mocks base method.
+func (m *MockStore) UpsertPosition(arg0 context.Context, arg1 db.UpsertPositionParams) (db.Position, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertPosition", arg0, arg1)
+	ret0, _ := ret[0].(db.Position)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertPosition indicates an expected call of UpsertPosition.
+func (mr *MockStoreMockRecorder) UpsertPosition(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertPosition", reflect.TypeOf((*MockStore)(nil).UpsertPosition), arg0, arg1)
+}
+
+// UpsertRiskLimit mocks base method.
+func (m *MockStore) UpsertRiskLimit(arg0 context.Context, arg1 db.UpsertRiskLimitParams) (db.RiskLimit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertRiskLimit", arg0, arg1)
+	ret0, _ := ret[0].(db.RiskLimit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertRiskLimit indicates an expected call of UpsertRiskLimit.
+func (mr *MockStoreMockRecorder) UpsertRiskLimit(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertRiskLimit", reflect.TypeOf((*MockStore)(nil).UpsertRiskLimit), arg0, arg1)
+}
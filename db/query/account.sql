@@ -8,20 +8,71 @@ SELECT *
   FROM account
  WHERE account_uuid = $1;
 
+-- name: GetAccountRole :one
+SELECT role
+  FROM account
+ WHERE account_uuid = $1;
+
 -- name: ListAccounts :many
   SELECT * 
     FROM account
 ORDER BY created_date;
 
+-- name: SearchAccounts :many
+   SELECT account.*
+     FROM account
+LEFT JOIN address ON address.account_uuid = account.account_uuid
+   WHERE (sqlc.arg(username_prefix)::text = '' OR account.username LIKE sqlc.arg(username_prefix)::text || '%')
+     AND (sqlc.arg(email)::text = '' OR lower(account.email) = lower(sqlc.arg(email)::text))
+     AND (sqlc.arg(created_from)::timestamp IS NULL OR account.created_date >= sqlc.arg(created_from)::timestamp)
+     AND (sqlc.arg(created_to)::timestamp IS NULL OR account.created_date < sqlc.arg(created_to)::timestamp)
+     AND (sqlc.arg(state)::text = '' OR address.state::text = sqlc.arg(state)::text)
+     AND (sqlc.arg(query)::text = '' 
+          OR to_tsvector('simple', account.username || ' ' || account.email) @@ plainto_tsquery('simple', sqlc.arg(query)::text))
+ ORDER BY ts_rank(to_tsvector('simple', account.username || ' ' || account.email), 
+                  plainto_tsquery('simple', sqlc.arg(query)::text)) DESC,
+          account.created_date, account.account_uuid
+    LIMIT sqlc.arg(page_size)
+   OFFSET sqlc.arg(page_offset);
+
+-- name: CountAccounts :one
+   SELECT count(*)
+     FROM account
+LEFT JOIN address ON address.account_uuid = account.account_uuid
+   WHERE (sqlc.arg(username_prefix)::text = '' OR account.username LIKE sqlc.arg(username_prefix)::text || '%')
+     AND (sqlc.arg(email)::text = '' OR lower(account.email) = lower(sqlc.arg(email)::text))
+     AND (sqlc.arg(created_from)::timestamp IS NULL OR account.created_date >= sqlc.arg(created_from)::timestamp)
+     AND (sqlc.arg(created_to)::timestamp IS NULL OR account.created_date < sqlc.arg(created_to)::timestamp)
+     AND (sqlc.arg(state)::text = '' OR address.state::text = sqlc.arg(state)::text)
+     AND (sqlc.arg(query)::text = '' 
+          OR to_tsvector('simple', account.username || ' ' || account.email) @@ plainto_tsquery('simple', sqlc.arg(query)::text));
+
 -- name: CreateAccount :one
-INSERT INTO account (username, email) 
-VALUES ($1, $2)
+INSERT INTO account (username, email, hashed_password, created_by, updated_by) 
+VALUES ($1, $2, $3, $4, $4)
 RETURNING *; 
 
 -- name: UpdateAccount :one
 UPDATE account 
    SET username = $1, 
        email = $2,
+       updated_by = $3,
+       updated_date = now()
+ WHERE account_uuid = $4
+ RETURNING *;
+
+-- name: UpdateAccountRole :one
+UPDATE account 
+   SET role = $1::account_role,
+       updated_by = $2,
+       updated_date = now()
+ WHERE account_uuid = $3
+ RETURNING *;
+
+-- name: UpdateAccountStatus :one
+UPDATE account 
+   SET status = $1::account_status,
+       updated_by = $2,
        updated_date = now()
  WHERE account_uuid = $3
- RETURNING *;
\ No newline at end of file
+ RETURNING *;
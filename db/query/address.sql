@@ -9,8 +9,8 @@ SELECT *
  WHERE account_uuid = $1;
 
 -- name: CreateAddress :one
-INSERT INTO address (name, street, city, state,     zipcode, account_uuid)
-             VALUES ($1,   $2,     $3,   $4::state, $5,      $6)
+INSERT INTO address (name, street, city, state,     zipcode, account_uuid, created_by, updated_by)
+             VALUES ($1,   $2,     $3,   $4::state, $5,      $6,           $7,         $7)
 RETURNING *; 
 
 -- name: UpdateAddress :one
@@ -20,8 +20,9 @@ UPDATE address
        city = $3, 
        state = $4::state,     
        zipcode = $5,
+       updated_by = $6,
        updated_date = now()
- WHERE address_uuid = $6
+ WHERE address_uuid = $7
 RETURNING *; 
 
 
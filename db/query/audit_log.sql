@@ -0,0 +1,14 @@
+-- name: CreateAuditLog :one
+INSERT INTO audit_log (actor, action, entity_type, entity_id, before, after, request_id, ip) 
+     VALUES           ($1   , $2    , $3         , $4       , $5    , $6   , $7        , $8)
+RETURNING *;
+
+-- name: ListAuditLog :many
+  SELECT * 
+    FROM audit_log
+   WHERE (sqlc.arg(entity_type)::text = '' OR entity_type = sqlc.arg(entity_type)::text)
+     AND (sqlc.arg(entity_id)::text = '' OR entity_id = sqlc.arg(entity_id)::text)
+     AND (sqlc.arg(actor)::text = '' OR actor = sqlc.arg(actor)::text)
+ORDER BY created_date DESC, audit_log_uuid
+   LIMIT sqlc.arg(page_size)
+  OFFSET sqlc.arg(page_offset);
@@ -0,0 +1,35 @@
+-- name: CreateIdempotencyKey :one
+INSERT INTO idempotency_key (scope, idempotency_key, request_hash, expires_date) 
+     VALUES                 ($1   , $2             , $3          , now() + make_interval(secs => sqlc.arg(ttl_seconds)::float8))
+ON CONFLICT (scope, idempotency_key) DO UPDATE 
+        SET request_hash = EXCLUDED.request_hash,
+            response_status = NULL,
+            response_body = NULL,
+            created_date = now(),
+            expires_date = EXCLUDED.expires_date
+      WHERE idempotency_key.expires_date <= now()
+RETURNING *;
+
+-- name: GetIdempotencyKey :one
+SELECT * 
+  FROM idempotency_key
+ WHERE scope = $1
+   AND idempotency_key = $2
+   AND expires_date > now();
+
+-- name: UpdateIdempotencyKeyResponse :one
+UPDATE idempotency_key 
+   SET response_status = $3,
+       response_body = $4
+ WHERE scope = $1
+   AND idempotency_key = $2
+ RETURNING *;
+
+-- name: DeleteIdempotencyKey :exec
+DELETE FROM idempotency_key 
+ WHERE scope = $1
+   AND idempotency_key = $2;
+
+-- name: DeleteExpiredIdempotencyKeys :exec
+DELETE FROM idempotency_key 
+ WHERE expires_date <= now();
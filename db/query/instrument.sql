@@ -0,0 +1,45 @@
+-- name: CreateInstrument :one
+INSERT INTO instrument (symbol, name, exchange, tick_size, lot_size, tradable, currency) 
+     VALUES           ($1    , $2  , $3      , $4       , $5      , $6      , $7      )
+ON CONFLICT (symbol) DO NOTHING
+RETURNING *;
+
+-- name: DeleteInstrument :one
+DELETE FROM instrument
+ WHERE symbol = $1
+RETURNING *;
+
+-- name: GetInstrument :one
+SELECT * 
+  FROM instrument
+ WHERE symbol = $1;
+
+-- name: ListInstruments :many
+  SELECT * 
+    FROM instrument
+ORDER BY symbol;
+
+-- name: UpdateInstrument :one
+UPDATE instrument 
+   SET name = $2,
+       exchange = $3,
+       tick_size = $4,
+       lot_size = $5,
+       tradable = $6,
+       currency = $7,
+       updated_date = now()
+ WHERE symbol = $1
+RETURNING *;
+
+-- name: UpsertInstrument :one
+INSERT INTO instrument (symbol, name, exchange, tick_size, lot_size, tradable, currency) 
+     VALUES           ($1    , $2  , $3      , $4       , $5      , $6      , $7      )
+ON CONFLICT (symbol) DO UPDATE 
+        SET name = EXCLUDED.name,
+            exchange = EXCLUDED.exchange,
+            tick_size = EXCLUDED.tick_size,
+            lot_size = EXCLUDED.lot_size,
+            tradable = EXCLUDED.tradable,
+            currency = EXCLUDED.currency,
+            updated_date = now()
+RETURNING *;
@@ -0,0 +1,44 @@
+-- name: UpsertLedgerAccount :one
+INSERT INTO ledger_account (account_uuid, type                  , asset) 
+     VALUES                ($1          , $2::ledger_account_type, $3   )
+ON CONFLICT (account_uuid, type, asset) DO UPDATE 
+        SET asset = EXCLUDED.asset
+RETURNING *;
+
+-- name: GetLedgerAccountBalance :one
+SELECT COALESCE(SUM(amount), 0)::numeric AS balance
+  FROM ledger_posting
+ WHERE ledger_account_uuid = $1;
+
+-- name: ListLedgerBalances :many
+   SELECT la.type, la.asset, COALESCE(SUM(lp.amount), 0)::numeric AS balance
+     FROM ledger_account la
+LEFT JOIN ledger_posting lp ON lp.ledger_account_uuid = la.ledger_account_uuid
+    WHERE la.account_uuid = $1
+ GROUP BY la.type, la.asset
+ ORDER BY la.type, la.asset;
+
+-- name: CreateJournalEntry :one
+INSERT INTO journal_entry (account_uuid, type                  , trade_uuid                                                     , description) 
+     VALUES               ($1          , $2::journal_entry_type, NULLIF($3::uuid, '00000000-0000-0000-0000-000000000000'::uuid), $4         )
+RETURNING *; 
+
+-- name: CreateLedgerPosting :one
+INSERT INTO ledger_posting (journal_entry_uuid, ledger_account_uuid, amount) 
+     VALUES                ($1                , $2                 , $3    )
+RETURNING *; 
+
+-- name: ListJournalEntries :many
+  SELECT * 
+    FROM journal_entry
+   WHERE account_uuid = sqlc.arg(account_uuid)
+ORDER BY created_date, journal_entry_uuid
+   LIMIT sqlc.arg(page_size)
+  OFFSET sqlc.arg(page_offset);
+
+-- name: ListLedgerPostings :many
+    SELECT lp.posting_uuid, lp.journal_entry_uuid, la.account_uuid, la.type, la.asset, lp.amount
+      FROM ledger_posting lp
+INNER JOIN ledger_account la ON la.ledger_account_uuid = lp.ledger_account_uuid
+     WHERE lp.journal_entry_uuid = ANY(sqlc.arg(journal_entry_uuids)::uuid[])
+  ORDER BY lp.journal_entry_uuid, la.type, la.asset;
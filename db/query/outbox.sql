@@ -0,0 +1,46 @@
+-- name: CreateOutboxEvent :one
+INSERT INTO outbox (event_uuid, event_type, aggregate_type, aggregate_uuid, payload) 
+     VALUES        ($1        , $2        , $3            , $4            , $5     )
+RETURNING *;
+
+-- name: GetOutboxEvent :one
+SELECT * 
+  FROM outbox
+ WHERE event_uuid = $1;
+
+-- name: ClaimOutboxEvents :many
+UPDATE outbox
+   SET locked_until = now() + make_interval(secs => sqlc.arg(lease_seconds)::float8)
+ WHERE event_uuid IN (
+         SELECT o.event_uuid
+           FROM outbox AS o
+          WHERE o.published_date IS NULL
+            AND (o.locked_until IS NULL OR o.locked_until <= now())
+            AND NOT EXISTS (
+                  SELECT 1
+                    FROM outbox AS e
+                   WHERE e.aggregate_type = o.aggregate_type
+                     AND e.aggregate_uuid = o.aggregate_uuid
+                     AND e.published_date IS NULL
+                     AND e.sequence < o.sequence
+                )
+       ORDER BY o.sequence
+          LIMIT sqlc.arg(batch_size)
+            FOR UPDATE SKIP LOCKED
+       )
+RETURNING *;
+
+-- name: MarkOutboxEventPublished :exec
+UPDATE outbox 
+   SET attempts = attempts + 1,
+       last_error = NULL,
+       locked_until = NULL,
+       published_date = now()
+ WHERE event_uuid = $1;
+
+-- name: RetryOutboxEvent :exec
+UPDATE outbox 
+   SET attempts = attempts + 1,
+       last_error = sqlc.arg(last_error),
+       locked_until = now() + make_interval(secs => sqlc.arg(delay_seconds)::float8)
+ WHERE event_uuid = sqlc.arg(event_uuid);
@@ -0,0 +1,28 @@
+-- name: GetPosition :one
+SELECT * 
+  FROM position
+ WHERE account_uuid = $1
+   AND symbol = $2;
+
+-- name: GetPositionForUpdate :one
+SELECT * 
+  FROM position
+ WHERE account_uuid = $1
+   AND symbol = $2
+   FOR UPDATE;
+
+-- name: ListPositionsByAccount :many
+  SELECT * 
+    FROM position
+   WHERE account_uuid = $1
+     AND quantity <> 0
+ORDER BY symbol;
+
+-- name: UpsertPosition :one
+INSERT INTO position (account_uuid, symbol, quantity, average_cost) 
+     VALUES          ($1          , $2    , $3      , $4          )
+ON CONFLICT (account_uuid, symbol) DO UPDATE 
+        SET quantity = EXCLUDED.quantity,
+            average_cost = EXCLUDED.average_cost,
+            updated_date = now()
+RETURNING *;
@@ -0,0 +1,26 @@
+-- name: GetRiskLimit :one
+SELECT * 
+  FROM risk_limit
+ WHERE account_uuid = $1;
+
+-- name: UpsertRiskLimit :one
+INSERT INTO risk_limit (account_uuid, max_order_notional, max_daily_notional, max_position_quantity) 
+     VALUES            ($1          , $2                , $3                , $4                   )
+ON CONFLICT (account_uuid) DO UPDATE 
+        SET max_order_notional = EXCLUDED.max_order_notional,
+            max_daily_notional = EXCLUDED.max_daily_notional,
+            max_position_quantity = EXCLUDED.max_position_quantity,
+            updated_date = now()
+RETURNING *;
+
+-- name: CreateRiskCheck :one
+INSERT INTO risk_check (account_uuid, trade_uuid                                                     , symbol, side, quantity, notional, passed, violations) 
+     VALUES            ($1          , NULLIF($2::uuid, '00000000-0000-0000-0000-000000000000'::uuid), $3    , $4  , $5      , $6      , $7    , $8        )
+RETURNING *;
+
+-- name: ListRiskChecksByAccount :many
+  SELECT * 
+    FROM risk_check
+   WHERE account_uuid = $1
+ORDER BY created_date DESC, risk_check_uuid
+   LIMIT $2;
@@ -3,31 +3,130 @@ SELECT *
   FROM trade
  WHERE trade_uuid = $1;
 
+-- name: GetTradeByIdForUpdate :one
+SELECT * 
+  FROM trade
+ WHERE trade_uuid = $1
+   FOR UPDATE;
+
 -- name: ListTradesByAccount :many
   SELECT * 
     FROM trade
    WHERE account_uuid = $1
 ORDER BY created_date;
 
+-- name: ListTradesByAccountAsc :many
+  SELECT * 
+    FROM trade
+   WHERE account_uuid = sqlc.arg(account_uuid)
+     AND (sqlc.arg(status)::text = '' OR status::text = sqlc.arg(status)::text)
+     AND (sqlc.arg(side)::text = '' OR side::text = sqlc.arg(side)::text)
+     AND (sqlc.arg(symbol)::text = '' OR symbol = sqlc.arg(symbol)::text)
+     AND (sqlc.arg(created_from)::timestamp IS NULL OR created_date >= sqlc.arg(created_from)::timestamp)
+     AND (sqlc.arg(created_to)::timestamp IS NULL OR created_date < sqlc.arg(created_to)::timestamp)
+     AND (sqlc.arg(after_created_date)::timestamp IS NULL 
+          OR (created_date, trade_uuid) > (sqlc.arg(after_created_date)::timestamp, sqlc.arg(after_trade_uuid)::uuid))
+ORDER BY created_date ASC, trade_uuid ASC
+   LIMIT sqlc.arg(page_size);
+
+-- name: ListTradesByAccountDesc :many
+  SELECT * 
+    FROM trade
+   WHERE account_uuid = sqlc.arg(account_uuid)
+     AND (sqlc.arg(status)::text = '' OR status::text = sqlc.arg(status)::text)
+     AND (sqlc.arg(side)::text = '' OR side::text = sqlc.arg(side)::text)
+     AND (sqlc.arg(symbol)::text = '' OR symbol = sqlc.arg(symbol)::text)
+     AND (sqlc.arg(created_from)::timestamp IS NULL OR created_date >= sqlc.arg(created_from)::timestamp)
+     AND (sqlc.arg(created_to)::timestamp IS NULL OR created_date < sqlc.arg(created_to)::timestamp)
+     AND (sqlc.arg(after_created_date)::timestamp IS NULL 
+          OR (created_date, trade_uuid) < (sqlc.arg(after_created_date)::timestamp, sqlc.arg(after_trade_uuid)::uuid))
+ORDER BY created_date DESC, trade_uuid DESC
+   LIMIT sqlc.arg(page_size);
+
+-- name: ListTradesByStatus :many
+  SELECT * 
+    FROM trade
+   WHERE status = $1::trade_status
+ORDER BY created_date;
+
+-- name: ListOpenTradesByAccount :many
+  SELECT * 
+    FROM trade
+   WHERE account_uuid = $1
+     AND status IN ('SUBMITTED'::trade_status, 'PARTIALLY_FILLED'::trade_status)
+ORDER BY created_date;
+
 -- name: CreateTrade :one
-INSERT INTO trade (account_uuid, symbol, quantity, side          , price) 
-     VALUES       ($1          , $2    , $3      , $4::trade_side, $5   )
+INSERT INTO trade (account_uuid, symbol, quantity, remaining_quantity, side          , price, order_type     , time_in_force     , stop_price, created_by, updated_by) 
+     VALUES       ($1          , $2    , $3      , $3                , $4::trade_side, $5   , $6::order_type, $7::time_in_force, $8        , $9        , $9        )
 RETURNING *; 
 
 -- name: UpdateTrade :one
 UPDATE trade 
    SET symbol = $1, 
        quantity = $2,
+       remaining_quantity = $2 - filled_quantity,
        side = $3::trade_side,
        price = $4, 
-       status = $5::trade_status,
+       stop_price = $5,
+       status = $6::trade_status,
+       version = version + 1,
+       updated_by = $7,
        updated_date = now()
- WHERE trade_uuid = $6
+ WHERE trade_uuid = $8
  RETURNING *;
 
 -- name: UpdateTradeStatus :one
 UPDATE trade 
    SET status = $1::trade_status,
+       updated_by = $2,
+       updated_date = now()
+ WHERE trade_uuid = $3
+ RETURNING *;
+
+-- name: UpdateTradeFill :one
+UPDATE trade 
+   SET filled_quantity = $1,
+       remaining_quantity = $2,
+       average_fill_price = $3,
+       status = $4::trade_status,
+       updated_by = $5,
        updated_date = now()
- WHERE trade_uuid = $2
- RETURNING *;
\ No newline at end of file
+ WHERE trade_uuid = $6
+ RETURNING *;
+
+-- name: GetOpenBuyNotional :one
+SELECT COALESCE(SUM(trade.remaining_quantity * COALESCE(trade.price, trade.stop_price, (
+           SELECT trade_execution.price
+             FROM trade_execution
+       INNER JOIN trade AS traded ON traded.trade_uuid = trade_execution.trade_uuid
+            WHERE traded.symbol = trade.symbol
+         ORDER BY trade_execution.executed_date DESC, trade_execution.execution_uuid DESC
+            LIMIT 1
+       ), 0)), 0)::numeric AS notional
+  FROM trade
+ WHERE trade.account_uuid = $1
+   AND trade.side = 'BUY'
+   AND trade.status IN ('SUBMITTED', 'PARTIALLY_FILLED');
+
+-- name: GetOpenBuyQuantity :one
+SELECT COALESCE(SUM(remaining_quantity), 0)::bigint AS quantity
+  FROM trade
+ WHERE account_uuid = $1
+   AND symbol = $2
+   AND side = 'BUY'
+   AND status IN ('SUBMITTED', 'PARTIALLY_FILLED');
+
+-- name: GetOpenNotional :one
+SELECT COALESCE(SUM(remaining_quantity * COALESCE(price, stop_price, 0)), 0)::numeric AS notional
+  FROM trade
+ WHERE account_uuid = $1
+   AND status IN ('SUBMITTED', 'PARTIALLY_FILLED');
+
+-- name: GetOpenSellQuantity :one
+SELECT COALESCE(SUM(remaining_quantity), 0)::bigint AS quantity
+  FROM trade
+ WHERE account_uuid = $1
+   AND symbol = $2
+   AND side = 'SELL'
+   AND status IN ('SUBMITTED', 'PARTIALLY_FILLED');
@@ -0,0 +1,34 @@
+-- name: CreateTradeExecution :one
+INSERT INTO trade_execution (trade_uuid, quantity, price, fee) 
+     VALUES                 ($1        , $2      , $3   , $4 )
+RETURNING *; 
+
+-- name: ListTradeExecutions :many
+  SELECT * 
+    FROM trade_execution
+   WHERE trade_uuid = $1
+ORDER BY executed_date, execution_uuid;
+
+-- name: GetTradedNotional :one
+    SELECT COALESCE(SUM(trade_execution.quantity * trade_execution.price), 0)::numeric AS notional
+      FROM trade_execution
+INNER JOIN trade ON trade.trade_uuid = trade_execution.trade_uuid
+     WHERE trade.account_uuid = sqlc.arg(account_uuid)
+       AND trade_execution.executed_date >= sqlc.arg(executed_from)::timestamp;
+
+-- name: ListAccountFills :many
+    SELECT trade_execution.execution_uuid, trade_execution.trade_uuid, trade.symbol, trade.side,
+           trade_execution.quantity, trade_execution.price, trade_execution.fee, trade_execution.executed_date
+      FROM trade_execution
+INNER JOIN trade ON trade.trade_uuid = trade_execution.trade_uuid
+     WHERE trade.account_uuid = sqlc.arg(account_uuid)
+       AND (sqlc.arg(executed_to)::timestamp IS NULL OR trade_execution.executed_date < sqlc.arg(executed_to)::timestamp)
+  ORDER BY trade_execution.executed_date, trade_execution.execution_uuid;
+
+-- name: ListLatestPrices :many
+    SELECT DISTINCT ON (trade.symbol) trade.symbol, trade_execution.price, trade_execution.executed_date
+      FROM trade_execution
+INNER JOIN trade ON trade.trade_uuid = trade_execution.trade_uuid
+     WHERE trade.symbol = ANY(sqlc.arg(symbols)::text[])
+       AND (sqlc.arg(executed_to)::timestamp IS NULL OR trade_execution.executed_date < sqlc.arg(executed_to)::timestamp)
+  ORDER BY trade.symbol, trade_execution.executed_date DESC, trade_execution.execution_uuid DESC;
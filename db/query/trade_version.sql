@@ -0,0 +1,10 @@
+-- name: CreateTradeVersion :one
+INSERT INTO trade_version (trade_uuid, version, quantity, price, stop_price) 
+     VALUES               ($1        , $2     , $3      , $4   , $5        )
+RETURNING *; 
+
+-- name: ListTradeVersions :many
+  SELECT * 
+    FROM trade_version
+   WHERE trade_uuid = $1
+ORDER BY version;
@@ -0,0 +1,102 @@
+-- name: CreateWebhookSubscription :one
+INSERT INTO webhook_subscription (account_uuid, url, secret, event_types) 
+     VALUES                      ($1          , $2 , $3    , $4         )
+RETURNING *;
+
+-- name: GetWebhookSubscription :one
+SELECT * 
+  FROM webhook_subscription
+ WHERE webhook_subscription_uuid = $1;
+
+-- name: ListWebhookSubscriptionsByAccount :many
+  SELECT * 
+    FROM webhook_subscription
+   WHERE account_uuid = $1
+ORDER BY created_date, webhook_subscription_uuid;
+
+-- name: ListWebhookSubscriptionsForEvent :many
+  SELECT * 
+    FROM webhook_subscription
+   WHERE account_uuid = sqlc.arg(account_uuid)
+     AND active
+     AND sqlc.arg(event_type)::text = ANY(event_types)
+ORDER BY created_date, webhook_subscription_uuid;
+
+-- name: UpdateWebhookSubscription :one
+UPDATE webhook_subscription 
+   SET url = $1,
+       event_types = $2,
+       active = $3,
+       updated_date = now()
+ WHERE webhook_subscription_uuid = $4
+RETURNING *;
+
+-- name: DeleteWebhookSubscription :exec
+DELETE FROM webhook_subscription 
+ WHERE webhook_subscription_uuid = $1;
+
+-- name: CreateWebhookDelivery :one
+INSERT INTO webhook_delivery (webhook_subscription_uuid, event_uuid, event_type, payload) 
+     VALUES                  ($1                       , $2        , $3        , $4     )
+RETURNING *;
+
+-- name: ClaimWebhookDeliveries :many
+UPDATE webhook_delivery AS d
+   SET next_attempt_date = now() + make_interval(secs => sqlc.arg(lease_seconds)::float8)
+  FROM webhook_subscription AS s
+ WHERE s.webhook_subscription_uuid = d.webhook_subscription_uuid
+   AND s.active
+   AND d.webhook_delivery_uuid IN (
+         SELECT webhook_delivery_uuid
+           FROM webhook_delivery
+          WHERE delivered_date IS NULL
+            AND next_attempt_date <= now()
+       ORDER BY next_attempt_date
+          LIMIT sqlc.arg(batch_size)
+            FOR UPDATE SKIP LOCKED
+       )
+RETURNING d.webhook_delivery_uuid, d.webhook_subscription_uuid, d.event_uuid, d.event_type, d.payload, d.attempts, s.url, s.secret;
+
+-- name: MarkWebhookDelivered :exec
+UPDATE webhook_delivery 
+   SET attempts = attempts + 1,
+       response_status = $1,
+       last_error = NULL,
+       delivered_date = now()
+ WHERE webhook_delivery_uuid = $2;
+
+-- name: RetryWebhookDelivery :exec
+UPDATE webhook_delivery 
+   SET attempts = attempts + 1,
+       response_status = sqlc.arg(response_status),
+       last_error = sqlc.arg(last_error),
+       next_attempt_date = now() + make_interval(secs => sqlc.arg(delay_seconds)::float8)
+ WHERE webhook_delivery_uuid = sqlc.arg(webhook_delivery_uuid);
+
+-- name: DeleteWebhookDelivery :exec
+DELETE FROM webhook_delivery 
+ WHERE webhook_delivery_uuid = $1;
+
+-- name: CreateWebhookDeadLetter :one
+INSERT INTO webhook_dead_letter (webhook_subscription_uuid, event_uuid, event_type, payload, attempts, response_status, last_error) 
+     VALUES                     ($1                       , $2        , $3        , $4     , $5      , $6             , $7        )
+RETURNING *;
+
+-- name: GetWebhookDeadLetter :one
+SELECT * 
+  FROM webhook_dead_letter
+ WHERE webhook_dead_letter_uuid = $1;
+
+-- name: ListWebhookDeadLetters :many
+  SELECT * 
+    FROM webhook_dead_letter
+   WHERE webhook_subscription_uuid = $1
+ORDER BY created_date DESC, webhook_dead_letter_uuid
+   LIMIT $2;
+
+-- name: MarkWebhookDeadLetterReplayed :one
+UPDATE webhook_dead_letter 
+   SET replayed_date = now()
+ WHERE webhook_dead_letter_uuid = $1
+   AND replayed_date IS NULL
+RETURNING *;
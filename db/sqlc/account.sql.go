@@ -5,23 +5,67 @@ package db
 
 import (
 	"context"
+	"database/sql"
 
 	"github.com/google/uuid"
 )
 
+const countAccounts = `-- name: CountAccounts :one
+   SELECT count(*)
+     FROM account
+LEFT JOIN address ON address.account_uuid = account.account_uuid
+   WHERE ($1::text = '' OR account.username LIKE $1::text || '%')
+     AND ($2::text = '' OR lower(account.email) = lower($2::text))
+     AND ($3::timestamp IS NULL OR account.created_date >= $3::timestamp)
+     AND ($4::timestamp IS NULL OR account.created_date < $4::timestamp)
+     AND ($5::text = '' OR address.state::text = $5::text)
+     AND ($6::text = '' 
+          OR to_tsvector('simple', account.username || ' ' || account.email) @@ plainto_tsquery('simple', $6::text))
+`
+
+type CountAccountsParams struct {
+	UsernamePrefix string       `json:"username_prefix"`
+	Email          string       `json:"email"`
+	CreatedFrom    sql.NullTime `json:"created_from"`
+	CreatedTo      sql.NullTime `json:"created_to"`
+	State          string       `json:"state"`
+	Query          string       `json:"query"`
+}
+
+func (q *Queries) CountAccounts(ctx context.Context, arg CountAccountsParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countAccounts,
+		arg.UsernamePrefix,
+		arg.Email,
+		arg.CreatedFrom,
+		arg.CreatedTo,
+		arg.State,
+		arg.Query,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createAccount = `-- name: CreateAccount :one
-INSERT INTO account (username, email) 
-VALUES ($1, $2)
-RETURNING account_uuid, username, email, created_date, updated_date, created_by, updated_by
+INSERT INTO account (username, email, hashed_password, created_by, updated_by) 
+VALUES ($1, $2, $3, $4, $4)
+RETURNING account_uuid, username, email, created_date, updated_date, created_by, updated_by, hashed_password, role, status
 `
 
 type CreateAccountParams struct {
-	Username string `json:"username"`
-	Email    string `json:"email"`
+	Username       string         `json:"username"`
+	Email          string         `json:"email"`
+	HashedPassword string         `json:"hashed_password"`
+	CreatedBy      sql.NullString `json:"created_by"`
 }
 
 func (q *Queries) CreateAccount(ctx context.Context, arg CreateAccountParams) (Account, error) {
-	row := q.db.QueryRowContext(ctx, createAccount, arg.Username, arg.Email)
+	row := q.db.QueryRowContext(ctx, createAccount,
+		arg.Username,
+		arg.Email,
+		arg.HashedPassword,
+		arg.CreatedBy,
+	)
 	var i Account
 	err := row.Scan(
 		&i.AccountUuid,
@@ -31,12 +75,15 @@ func (q *Queries) CreateAccount(ctx context.Context, arg CreateAccountParams) (A
 		&i.UpdatedDate,
 		&i.CreatedBy,
 		&i.UpdatedBy,
+		&i.HashedPassword,
+		&i.Role,
+		&i.Status,
 	)
 	return i, err
 }
 
 const getAccountById = `-- name: GetAccountById :one
-SELECT account_uuid, username, email, created_date, updated_date, created_by, updated_by 
+SELECT account_uuid, username, email, created_date, updated_date, created_by, updated_by, hashed_password, role, status 
   FROM account
  WHERE account_uuid = $1
 `
@@ -52,12 +99,15 @@ func (q *Queries) GetAccountById(ctx context.Context, accountUuid uuid.UUID) (Ac
 		&i.UpdatedDate,
 		&i.CreatedBy,
 		&i.UpdatedBy,
+		&i.HashedPassword,
+		&i.Role,
+		&i.Status,
 	)
 	return i, err
 }
 
 const getAccountByUsername = `-- name: GetAccountByUsername :one
-SELECT account_uuid, username, email, created_date, updated_date, created_by, updated_by 
+SELECT account_uuid, username, email, created_date, updated_date, created_by, updated_by, hashed_password, role, status 
   FROM account
  WHERE username = $1
 `
@@ -73,12 +123,28 @@ func (q *Queries) GetAccountByUsername(ctx context.Context, username string) (Ac
 		&i.UpdatedDate,
 		&i.CreatedBy,
 		&i.UpdatedBy,
+		&i.HashedPassword,
+		&i.Role,
+		&i.Status,
 	)
 	return i, err
 }
 
+const getAccountRole = `-- name: GetAccountRole :one
+SELECT role
+  FROM account
+ WHERE account_uuid = $1
+`
+
+func (q *Queries) GetAccountRole(ctx context.Context, accountUuid uuid.UUID) (AccountRole, error) {
+	row := q.db.QueryRowContext(ctx, getAccountRole, accountUuid)
+	var role AccountRole
+	err := row.Scan(&role)
+	return role, err
+}
+
 const listAccounts = `-- name: ListAccounts :many
-  SELECT account_uuid, username, email, created_date, updated_date, created_by, updated_by 
+  SELECT account_uuid, username, email, created_date, updated_date, created_by, updated_by, hashed_password, role, status 
     FROM account
 ORDER BY created_date
 `
@@ -100,6 +166,81 @@ func (q *Queries) ListAccounts(ctx context.Context) ([]Account, error) {
 			&i.UpdatedDate,
 			&i.CreatedBy,
 			&i.UpdatedBy,
+			&i.HashedPassword,
+			&i.Role,
+			&i.Status,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchAccounts = `-- name: SearchAccounts :many
+   SELECT account.account_uuid, account.username, account.email, account.created_date, account.updated_date, account.created_by, account.updated_by, account.hashed_password, account.role, account.status
+     FROM account
+LEFT JOIN address ON address.account_uuid = account.account_uuid
+   WHERE ($1::text = '' OR account.username LIKE $1::text || '%')
+     AND ($2::text = '' OR lower(account.email) = lower($2::text))
+     AND ($3::timestamp IS NULL OR account.created_date >= $3::timestamp)
+     AND ($4::timestamp IS NULL OR account.created_date < $4::timestamp)
+     AND ($5::text = '' OR address.state::text = $5::text)
+     AND ($6::text = '' 
+          OR to_tsvector('simple', account.username || ' ' || account.email) @@ plainto_tsquery('simple', $6::text))
+ ORDER BY ts_rank(to_tsvector('simple', account.username || ' ' || account.email), 
+                  plainto_tsquery('simple', $6::text)) DESC,
+          account.created_date, account.account_uuid
+    LIMIT $7
+   OFFSET $8
+`
+
+type SearchAccountsParams struct {
+	UsernamePrefix string       `json:"username_prefix"`
+	Email          string       `json:"email"`
+	CreatedFrom    sql.NullTime `json:"created_from"`
+	CreatedTo      sql.NullTime `json:"created_to"`
+	State          string       `json:"state"`
+	Query          string       `json:"query"`
+	PageSize       int32        `json:"page_size"`
+	PageOffset     int32        `json:"page_offset"`
+}
+
+func (q *Queries) SearchAccounts(ctx context.Context, arg SearchAccountsParams) ([]Account, error) {
+	rows, err := q.db.QueryContext(ctx, searchAccounts,
+		arg.UsernamePrefix,
+		arg.Email,
+		arg.CreatedFrom,
+		arg.CreatedTo,
+		arg.State,
+		arg.Query,
+		arg.PageSize,
+		arg.PageOffset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Account
+	for rows.Next() {
+		var i Account
+		if err := rows.Scan(
+			&i.AccountUuid,
+			&i.Username,
+			&i.Email,
+			&i.CreatedDate,
+			&i.UpdatedDate,
+			&i.CreatedBy,
+			&i.UpdatedBy,
+			&i.HashedPassword,
+			&i.Role,
+			&i.Status,
 		); err != nil {
 			return nil, err
 		}
@@ -118,19 +259,92 @@ const updateAccount = `-- name: UpdateAccount :one
 UPDATE account 
    SET username = $1, 
        email = $2,
+       updated_by = $3,
        updated_date = now()
- WHERE account_uuid = $3
- RETURNING account_uuid, username, email, created_date, updated_date, created_by, updated_by
+ WHERE account_uuid = $4
+ RETURNING account_uuid, username, email, created_date, updated_date, created_by, updated_by, hashed_password, role, status
 `
 
 type UpdateAccountParams struct {
-	Username    string    `json:"username"`
-	Email       string    `json:"email"`
-	AccountUuid uuid.UUID `json:"account_uuid"`
+	Username    string         `json:"username"`
+	Email       string         `json:"email"`
+	UpdatedBy   sql.NullString `json:"updated_by"`
+	AccountUuid uuid.UUID      `json:"account_uuid"`
 }
 
 func (q *Queries) UpdateAccount(ctx context.Context, arg UpdateAccountParams) (Account, error) {
-	row := q.db.QueryRowContext(ctx, updateAccount, arg.Username, arg.Email, arg.AccountUuid)
+	row := q.db.QueryRowContext(ctx, updateAccount,
+		arg.Username,
+		arg.Email,
+		arg.UpdatedBy,
+		arg.AccountUuid,
+	)
+	var i Account
+	err := row.Scan(
+		&i.AccountUuid,
+		&i.Username,
+		&i.Email,
+		&i.CreatedDate,
+		&i.UpdatedDate,
+		&i.CreatedBy,
+		&i.UpdatedBy,
+		&i.HashedPassword,
+		&i.Role,
+		&i.Status,
+	)
+	return i, err
+}
+
+const updateAccountRole = `-- name: UpdateAccountRole :one
+UPDATE account 
+   SET role = $1::account_role,
+       updated_by = $2,
+       updated_date = now()
+ WHERE account_uuid = $3
+ RETURNING account_uuid, username, email, created_date, updated_date, created_by, updated_by, hashed_password, role, status
+`
+
+type UpdateAccountRoleParams struct {
+	Role        AccountRole    `json:"role"`
+	UpdatedBy   sql.NullString `json:"updated_by"`
+	AccountUuid uuid.UUID      `json:"account_uuid"`
+}
+
+func (q *Queries) UpdateAccountRole(ctx context.Context, arg UpdateAccountRoleParams) (Account, error) {
+	row := q.db.QueryRowContext(ctx, updateAccountRole, arg.Role, arg.UpdatedBy, arg.AccountUuid)
+	var i Account
+	err := row.Scan(
+		&i.AccountUuid,
+		&i.Username,
+		&i.Email,
+		&i.CreatedDate,
+		&i.UpdatedDate,
+		&i.CreatedBy,
+		&i.UpdatedBy,
+		&i.HashedPassword,
+		&i.Role,
+		&i.Status,
+	)
+	return i, err
+}
+
+const updateAccountStatus = `-- name: UpdateAccountStatus :one
+UPDATE account 
+   SET status = $1::account_status,
+       updated_by = $2,
+       updated_date = now()
+ WHERE account_uuid = $3
+ RETURNING account_uuid, username, email, created_date, updated_date, created_by, updated_by, hashed_password, role, status
+`
+
+type UpdateAccountStatusParams struct {
+	Status      AccountStatus  `json:"status"`
+	UpdatedBy   sql.NullString `json:"updated_by"`
+	AccountUuid uuid.UUID      `json:"account_uuid"`
+}
+
+func (q *Queries) UpdateAccountStatus(ctx context.Context, arg UpdateAccountStatusParams) (Account, error) {
+	row := q.db.QueryRowContext(ctx, updateAccountStatus, arg.Status, arg.UpdatedBy, arg.AccountUuid)
 	var i Account
 	err := row.Scan(
 		&i.AccountUuid,
@@ -140,6 +354,9 @@ func (q *Queries) UpdateAccount(ctx context.Context, arg UpdateAccountParams) (A
 		&i.UpdatedDate,
 		&i.CreatedBy,
 		&i.UpdatedBy,
+		&i.HashedPassword,
+		&i.Role,
+		&i.Status,
 	)
 	return i, err
 }
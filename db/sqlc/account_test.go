@@ -2,17 +2,24 @@ package db
 
 import (
 	"context"
+	"database/sql"
 	"testing"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/valverdethiago/trading-api/util"
 )
 
 func createRandomAccount(t *testing.T) Account {
+	hashedPassword, err := util.HashPassword(util.RandomString(8))
+	require.NoError(t, err)
 	arg := CreateAccountParams{
-		Username: util.RandomUsername(),
-		Email:    util.RandomEmail(),
+		Username:       util.RandomUsername(),
+		Email:          util.RandomEmail(),
+		HashedPassword: hashedPassword,
+		CreatedBy:      sql.NullString{String: "anonymous", Valid: true},
 	}
 
 	account, err := testQueries.CreateAccount(context.Background(), arg)
@@ -23,6 +30,11 @@ func createRandomAccount(t *testing.T) Account {
 	require.NotNil(t, account.UpdatedDate)
 	require.Equal(t, arg.Email, account.Email)
 	require.Equal(t, arg.Username, account.Username)
+	require.Equal(t, arg.HashedPassword, account.HashedPassword)
+	require.Equal(t, arg.CreatedBy, account.CreatedBy)
+	require.Equal(t, arg.CreatedBy, account.UpdatedBy)
+	require.Equal(t, AccountRoleUSER, account.Role)
+	require.Equal(t, AccountStatusPENDING, account.Status)
 
 	return account
 }
@@ -53,6 +65,16 @@ func TestGetAccount(t *testing.T) {
 	require.Equal(t, account.UpdatedDate, dbAccount.UpdatedDate)
 }
 
+func TestGetAccountRole(t *testing.T) {
+	account := createRandomAccount(t)
+	role, err := testQueries.GetAccountRole(context.Background(), account.AccountUuid)
+	require.NoError(t, err)
+	require.Equal(t, account.Role, role)
+
+	_, err = testQueries.GetAccountRole(context.Background(), uuid.New())
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
 func TestUpdateAccount(t *testing.T) {
 	account := createRandomAccount(t)
 	arg := UpdateAccountParams{
@@ -69,6 +91,36 @@ func TestUpdateAccount(t *testing.T) {
 	require.NotEqual(t, dbAccount.CreatedDate, dbAccount.UpdatedDate)
 }
 
+func TestUpdateAccountRole(t *testing.T) {
+	account := createRandomAccount(t)
+	arg := UpdateAccountRoleParams{
+		Role:        AccountRoleSTAFF,
+		AccountUuid: account.AccountUuid,
+	}
+
+	dbAccount, err := testQueries.UpdateAccountRole(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotEmpty(t, dbAccount)
+	require.Equal(t, account.AccountUuid, dbAccount.AccountUuid)
+	require.Equal(t, AccountRoleSTAFF, dbAccount.Role)
+	require.NotEqual(t, account.UpdatedDate, dbAccount.UpdatedDate)
+}
+
+func TestUpdateAccountStatus(t *testing.T) {
+	account := createRandomAccount(t)
+	arg := UpdateAccountStatusParams{
+		Status:      AccountStatusAPPROVED,
+		AccountUuid: account.AccountUuid,
+	}
+
+	dbAccount, err := testQueries.UpdateAccountStatus(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotEmpty(t, dbAccount)
+	require.Equal(t, account.AccountUuid, dbAccount.AccountUuid)
+	require.Equal(t, AccountStatusAPPROVED, dbAccount.Status)
+	require.NotEqual(t, account.UpdatedDate, dbAccount.UpdatedDate)
+}
+
 func TestListAccounts(t *testing.T) {
 	var accounts [10]Account
 	for i := 0; i >= len(accounts); i++ {
@@ -81,3 +133,62 @@ func TestListAccounts(t *testing.T) {
 		assert.Equal(t, dbAccount, account)
 	}
 }
+
+func TestSearchAccounts(t *testing.T) {
+	address := createRandomAddress(t)
+	account, err := testQueries.GetAccountById(context.Background(), address.AccountUuid)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name string
+		arg  SearchAccountsParams
+	}{
+		{
+			name: "Username Prefix",
+			arg:  SearchAccountsParams{UsernamePrefix: account.Username[:len(account.Username)-1]},
+		}, {
+			name: "Email",
+			arg:  SearchAccountsParams{Email: account.Email},
+		}, {
+			name: "State And Created Range",
+			arg: SearchAccountsParams{
+				UsernamePrefix: account.Username,
+				State:          string(address.State),
+				CreatedFrom:    sql.NullTime{Time: account.CreatedDate.Time.Add(-time.Minute), Valid: true},
+				CreatedTo:      sql.NullTime{Time: account.CreatedDate.Time.Add(time.Minute), Valid: true},
+			},
+		}, {
+			name: "Full Text",
+			arg:  SearchAccountsParams{Query: account.Username},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			arg := testCase.arg
+			arg.PageSize = 100
+			dbAccounts, err := testQueries.SearchAccounts(context.Background(), arg)
+			require.NoError(t, err)
+			require.Equal(t, account, findAccountInList(dbAccounts, account))
+
+			count, err := testQueries.CountAccounts(context.Background(), CountAccountsParams{
+				UsernamePrefix: arg.UsernamePrefix,
+				Email:          arg.Email,
+				CreatedFrom:    arg.CreatedFrom,
+				CreatedTo:      arg.CreatedTo,
+				State:          arg.State,
+				Query:          arg.Query,
+			})
+			require.NoError(t, err)
+			require.GreaterOrEqual(t, count, int64(1))
+		})
+	}
+
+	dbAccounts, err := testQueries.SearchAccounts(context.Background(), SearchAccountsParams{
+		UsernamePrefix: account.Username,
+		State:          string(StateCA),
+		PageSize:       100,
+	})
+	require.NoError(t, err)
+	require.Empty(t, dbAccounts)
+}
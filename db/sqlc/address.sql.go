@@ -5,23 +5,25 @@ package db
 
 import (
 	"context"
+	"database/sql"
 
 	"github.com/google/uuid"
 )
 
 const createAddress = `-- name: CreateAddress :one
-INSERT INTO address (name, street, city, state,     zipcode, account_uuid)
-             VALUES ($1,   $2,     $3,   $4::state, $5,      $6)
+INSERT INTO address (name, street, city, state,     zipcode, account_uuid, created_by, updated_by)
+             VALUES ($1,   $2,     $3,   $4::state, $5,      $6,           $7,         $7)
 RETURNING address_uuid, name, street, city, state, zipcode, account_uuid, created_date, updated_date, created_by, updated_by
 `
 
 type CreateAddressParams struct {
-	Name        string    `json:"name"`
-	Street      string    `json:"street"`
-	City        string    `json:"city"`
-	State       State     `json:"state"`
-	Zipcode     string    `json:"zipcode"`
-	AccountUuid uuid.UUID `json:"account_uuid"`
+	Name        string         `json:"name"`
+	Street      string         `json:"street"`
+	City        string         `json:"city"`
+	State       State          `json:"state"`
+	Zipcode     string         `json:"zipcode"`
+	AccountUuid uuid.UUID      `json:"account_uuid"`
+	CreatedBy   sql.NullString `json:"created_by"`
 }
 
 func (q *Queries) CreateAddress(ctx context.Context, arg CreateAddressParams) (Address, error) {
@@ -32,6 +34,7 @@ func (q *Queries) CreateAddress(ctx context.Context, arg CreateAddressParams) (A
 		arg.State,
 		arg.Zipcode,
 		arg.AccountUuid,
+		arg.CreatedBy,
 	)
 	var i Address
 	err := row.Scan(
@@ -117,18 +120,20 @@ UPDATE address
        city = $3, 
        state = $4::state,     
        zipcode = $5,
+       updated_by = $6,
        updated_date = now()
- WHERE address_uuid = $6
+ WHERE address_uuid = $7
 RETURNING address_uuid, name, street, city, state, zipcode, account_uuid, created_date, updated_date, created_by, updated_by
 `
 
 type UpdateAddressParams struct {
-	Name        string    `json:"name"`
-	Street      string    `json:"street"`
-	City        string    `json:"city"`
-	State       State     `json:"state"`
-	Zipcode     string    `json:"zipcode"`
-	AddressUuid uuid.UUID `json:"address_uuid"`
+	Name        string         `json:"name"`
+	Street      string         `json:"street"`
+	City        string         `json:"city"`
+	State       State          `json:"state"`
+	Zipcode     string         `json:"zipcode"`
+	UpdatedBy   sql.NullString `json:"updated_by"`
+	AddressUuid uuid.UUID      `json:"address_uuid"`
 }
 
 func (q *Queries) UpdateAddress(ctx context.Context, arg UpdateAddressParams) (Address, error) {
@@ -138,6 +143,7 @@ func (q *Queries) UpdateAddress(ctx context.Context, arg UpdateAddressParams) (A
 		arg.City,
 		arg.State,
 		arg.Zipcode,
+		arg.UpdatedBy,
 		arg.AddressUuid,
 	)
 	var i Address
@@ -0,0 +1,72 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomAuditLog(t *testing.T, actor string, entityID string) AuditLog {
+	arg := CreateAuditLogParams{
+		Actor:      actor,
+		Action:     "update",
+		EntityType: "trade",
+		EntityID:   entityID,
+		Before:     json.RawMessage(`{"status":"SUBMITTED"}`),
+		After:      json.RawMessage(`{"status":"CANCELLED"}`),
+		RequestID:  sql.NullString{String: uuid.New().String(), Valid: true},
+		Ip:         sql.NullString{String: "192.0.2.1", Valid: true},
+	}
+	entry, err := testQueries.CreateAuditLog(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotEmpty(t, entry.AuditLogUuid)
+	require.Equal(t, arg.Actor, entry.Actor)
+	require.Equal(t, arg.EntityID, entry.EntityID)
+	require.JSONEq(t, string(arg.Before), string(entry.Before))
+	require.JSONEq(t, string(arg.After), string(entry.After))
+	require.Equal(t, arg.RequestID, entry.RequestID)
+	require.Equal(t, arg.Ip, entry.Ip)
+	require.NotZero(t, entry.CreatedDate)
+	return entry
+}
+
+func TestCreateAuditLog(t *testing.T) {
+	createRandomAuditLog(t, uuid.New().String(), uuid.New().String())
+}
+
+func TestListAuditLogByEntity(t *testing.T) {
+	entityID := uuid.New().String()
+	first := createRandomAuditLog(t, uuid.New().String(), entityID)
+	second := createRandomAuditLog(t, uuid.New().String(), entityID)
+	createRandomAuditLog(t, uuid.New().String(), uuid.New().String())
+
+	entries, err := testQueries.ListAuditLog(context.Background(), ListAuditLogParams{
+		EntityType: "trade",
+		EntityID:   entityID,
+		PageSize:   10,
+		PageOffset: 0,
+	})
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	require.Equal(t, second.AuditLogUuid, entries[0].AuditLogUuid)
+	require.Equal(t, first.AuditLogUuid, entries[1].AuditLogUuid)
+}
+
+func TestListAuditLogByActor(t *testing.T) {
+	actor := uuid.New().String()
+	entry := createRandomAuditLog(t, actor, uuid.New().String())
+	createRandomAuditLog(t, uuid.New().String(), entry.EntityID)
+
+	entries, err := testQueries.ListAuditLog(context.Background(), ListAuditLogParams{
+		Actor:      actor,
+		PageSize:   10,
+		PageOffset: 0,
+	})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, entry.AuditLogUuid, entries[0].AuditLogUuid)
+}
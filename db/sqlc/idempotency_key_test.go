@@ -0,0 +1,100 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/valverdethiago/trading-api/util"
+)
+
+func createRandomIdempotencyKey(t *testing.T, ttlSeconds float64) IdempotencyKey {
+	arg := CreateIdempotencyKeyParams{
+		Scope:          util.RandomString(10),
+		IdempotencyKey: util.RandomAlphaNumericString(20),
+		RequestHash:    util.RandomAlphaNumericString(64),
+		TtlSeconds:     ttlSeconds,
+	}
+
+	key, err := testQueries.CreateIdempotencyKey(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.Scope, key.Scope)
+	require.Equal(t, arg.IdempotencyKey, key.IdempotencyKey)
+	require.Equal(t, arg.RequestHash, key.RequestHash)
+	require.False(t, key.ResponseStatus.Valid)
+	require.True(t, key.ExpiresDate.After(key.CreatedDate) || ttlSeconds <= 0)
+	return key
+}
+
+func TestCreateIdempotencyKey(t *testing.T) {
+	createRandomIdempotencyKey(t, 60)
+}
+
+func TestCreateIdempotencyKeyTaken(t *testing.T) {
+	key := createRandomIdempotencyKey(t, 60)
+
+	_, err := testQueries.CreateIdempotencyKey(context.Background(), CreateIdempotencyKeyParams{
+		Scope:          key.Scope,
+		IdempotencyKey: key.IdempotencyKey,
+		RequestHash:    util.RandomAlphaNumericString(64),
+		TtlSeconds:     60,
+	})
+	require.Equal(t, sql.ErrNoRows, err)
+}
+
+func TestCreateIdempotencyKeyExpired(t *testing.T) {
+	key := createRandomIdempotencyKey(t, 0)
+
+	_, err := testQueries.GetIdempotencyKey(context.Background(), GetIdempotencyKeyParams{
+		Scope:          key.Scope,
+		IdempotencyKey: key.IdempotencyKey,
+	})
+	require.Equal(t, sql.ErrNoRows, err)
+
+	renewed, err := testQueries.CreateIdempotencyKey(context.Background(), CreateIdempotencyKeyParams{
+		Scope:          key.Scope,
+		IdempotencyKey: key.IdempotencyKey,
+		RequestHash:    util.RandomAlphaNumericString(64),
+		TtlSeconds:     60,
+	})
+	require.NoError(t, err)
+	require.NotEqual(t, key.RequestHash, renewed.RequestHash)
+}
+
+func TestUpdateIdempotencyKeyResponse(t *testing.T) {
+	key := createRandomIdempotencyKey(t, 60)
+
+	arg := UpdateIdempotencyKeyResponseParams{
+		Scope:          key.Scope,
+		IdempotencyKey: key.IdempotencyKey,
+		ResponseStatus: sql.NullInt32{Int32: 201, Valid: true},
+		ResponseBody:   []byte(`{"ok":true}`),
+	}
+	_, err := testQueries.UpdateIdempotencyKeyResponse(context.Background(), arg)
+	require.NoError(t, err)
+
+	dbKey, err := testQueries.GetIdempotencyKey(context.Background(), GetIdempotencyKeyParams{
+		Scope:          key.Scope,
+		IdempotencyKey: key.IdempotencyKey,
+	})
+	require.NoError(t, err)
+	require.Equal(t, arg.ResponseStatus, dbKey.ResponseStatus)
+	require.Equal(t, arg.ResponseBody, dbKey.ResponseBody)
+}
+
+func TestDeleteIdempotencyKey(t *testing.T) {
+	key := createRandomIdempotencyKey(t, 60)
+
+	err := testQueries.DeleteIdempotencyKey(context.Background(), DeleteIdempotencyKeyParams{
+		Scope:          key.Scope,
+		IdempotencyKey: key.IdempotencyKey,
+	})
+	require.NoError(t, err)
+
+	_, err = testQueries.GetIdempotencyKey(context.Background(), GetIdempotencyKeyParams{
+		Scope:          key.Scope,
+		IdempotencyKey: key.IdempotencyKey,
+	})
+	require.Equal(t, sql.ErrNoRows, err)
+}
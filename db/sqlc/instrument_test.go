@@ -0,0 +1,122 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+	"github.com/valverdethiago/trading-api/util"
+)
+
+func createRandomInstrument(t *testing.T) Instrument {
+	arg := CreateInstrumentParams{
+		// long enough not to clash with the symbols of other tests
+		Symbol:   util.RandomFromSource(10, "ABCDEFGHIJKLMNOPQRSTUVWXYZ"),
+		Name:     util.RandomString(20),
+		Exchange: "NASDAQ",
+		TickSize: decimal.RequireFromString("0.01"),
+		LotSize:  util.RandomInt(1, 100),
+		Tradable: true,
+		Currency: "USD",
+	}
+
+	instrument, err := testQueries.CreateInstrument(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.Symbol, instrument.Symbol)
+	require.Equal(t, arg.Name, instrument.Name)
+	require.Equal(t, arg.Exchange, instrument.Exchange)
+	require.True(t, arg.TickSize.Equal(instrument.TickSize))
+	require.Equal(t, arg.LotSize, instrument.LotSize)
+	require.Equal(t, arg.Tradable, instrument.Tradable)
+	require.Equal(t, arg.Currency, instrument.Currency)
+	require.NotZero(t, instrument.CreatedDate)
+	return instrument
+}
+
+func TestCreateInstrument(t *testing.T) {
+	instrument := createRandomInstrument(t)
+
+	_, err := testQueries.CreateInstrument(context.Background(), CreateInstrumentParams{
+		Symbol:   instrument.Symbol,
+		Name:     instrument.Name,
+		Exchange: instrument.Exchange,
+		TickSize: instrument.TickSize,
+		LotSize:  instrument.LotSize,
+		Currency: instrument.Currency,
+	})
+	require.Equal(t, sql.ErrNoRows, err)
+}
+
+func TestGetInstrument(t *testing.T) {
+	instrument := createRandomInstrument(t)
+
+	found, err := testQueries.GetInstrument(context.Background(), instrument.Symbol)
+	require.NoError(t, err)
+	require.Equal(t, instrument, found)
+}
+
+func TestListInstruments(t *testing.T) {
+	for i := 0; i < 3; i++ {
+		createRandomInstrument(t)
+	}
+
+	instruments, err := testQueries.ListInstruments(context.Background())
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, len(instruments), 3)
+	for i := 1; i < len(instruments); i++ {
+		require.Less(t, instruments[i-1].Symbol, instruments[i].Symbol)
+	}
+}
+
+func TestUpdateInstrument(t *testing.T) {
+	instrument := createRandomInstrument(t)
+
+	arg := UpdateInstrumentParams{
+		Symbol:   instrument.Symbol,
+		Name:     util.RandomString(20),
+		Exchange: "NYSE",
+		TickSize: decimal.RequireFromString("0.05"),
+		LotSize:  100,
+		Tradable: false,
+		Currency: "USD",
+	}
+	updated, err := testQueries.UpdateInstrument(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.Name, updated.Name)
+	require.Equal(t, arg.Exchange, updated.Exchange)
+	require.True(t, arg.TickSize.Equal(updated.TickSize))
+	require.Equal(t, arg.LotSize, updated.LotSize)
+	require.False(t, updated.Tradable)
+	require.Equal(t, instrument.CreatedDate, updated.CreatedDate)
+}
+
+func TestUpsertInstrument(t *testing.T) {
+	instrument := createRandomInstrument(t)
+
+	arg := UpsertInstrumentParams{
+		Symbol:   instrument.Symbol,
+		Name:     instrument.Name,
+		Exchange: instrument.Exchange,
+		TickSize: instrument.TickSize,
+		LotSize:  instrument.LotSize,
+		Tradable: false,
+		Currency: instrument.Currency,
+	}
+	upserted, err := testQueries.UpsertInstrument(context.Background(), arg)
+	require.NoError(t, err)
+	require.False(t, upserted.Tradable)
+	require.Equal(t, instrument.CreatedDate, upserted.CreatedDate)
+}
+
+func TestDeleteInstrument(t *testing.T) {
+	instrument := createRandomInstrument(t)
+
+	deleted, err := testQueries.DeleteInstrument(context.Background(), instrument.Symbol)
+	require.NoError(t, err)
+	require.Equal(t, instrument.Symbol, deleted.Symbol)
+
+	_, err = testQueries.GetInstrument(context.Background(), instrument.Symbol)
+	require.Equal(t, sql.ErrNoRows, err)
+}
@@ -0,0 +1,143 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+	"github.com/valverdethiago/trading-api/util"
+)
+
+func createRandomLedgerAccount(t *testing.T, accountUUID uuid.UUID, accountType LedgerAccountType) LedgerAccount {
+	arg := UpsertLedgerAccountParams{
+		AccountUuid: accountUUID,
+		Type:        accountType,
+		Asset:       util.RandomString(4),
+	}
+
+	ledgerAccount, err := testQueries.UpsertLedgerAccount(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotEmpty(t, ledgerAccount.LedgerAccountUuid)
+	require.Equal(t, arg.AccountUuid, ledgerAccount.AccountUuid)
+	require.Equal(t, arg.Type, ledgerAccount.Type)
+	require.Equal(t, arg.Asset, ledgerAccount.Asset)
+	require.NotZero(t, ledgerAccount.CreatedDate)
+	return ledgerAccount
+}
+
+func createRandomJournalEntry(t *testing.T, account Account, tradeUUID uuid.UUID) JournalEntry {
+	arg := CreateJournalEntryParams{
+		AccountUuid: account.AccountUuid,
+		Type:        JournalEntryTypeDEPOSIT,
+		TradeUuid:   tradeUUID,
+		Description: util.RandomString(20),
+	}
+
+	entry, err := testQueries.CreateJournalEntry(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotEmpty(t, entry.JournalEntryUuid)
+	require.Equal(t, arg.AccountUuid, entry.AccountUuid)
+	require.Equal(t, arg.Type, entry.Type)
+	require.Equal(t, arg.TradeUuid, entry.TradeUuid)
+	require.Equal(t, arg.Description, entry.Description)
+	require.NotZero(t, entry.CreatedDate)
+	return entry
+}
+
+func createTestLedgerPosting(t *testing.T, entry JournalEntry, ledgerAccount LedgerAccount, amount decimal.Decimal) LedgerPosting {
+	arg := CreateLedgerPostingParams{
+		JournalEntryUuid:  entry.JournalEntryUuid,
+		LedgerAccountUuid: ledgerAccount.LedgerAccountUuid,
+		Amount:            amount,
+	}
+
+	posting, err := testQueries.CreateLedgerPosting(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotEmpty(t, posting.PostingUuid)
+	require.Equal(t, arg.JournalEntryUuid, posting.JournalEntryUuid)
+	require.Equal(t, arg.LedgerAccountUuid, posting.LedgerAccountUuid)
+	require.True(t, arg.Amount.Equal(posting.Amount))
+	return posting
+}
+
+func TestUpsertLedgerAccount(t *testing.T) {
+	account := createRandomAccount(t)
+	ledgerAccount := createRandomLedgerAccount(t, account.AccountUuid, LedgerAccountTypeCASH)
+
+	again, err := testQueries.UpsertLedgerAccount(context.Background(), UpsertLedgerAccountParams{
+		AccountUuid: account.AccountUuid,
+		Type:        LedgerAccountTypeCASH,
+		Asset:       ledgerAccount.Asset,
+	})
+	require.NoError(t, err)
+	require.Equal(t, ledgerAccount.LedgerAccountUuid, again.LedgerAccountUuid)
+}
+
+func TestCreateJournalEntry(t *testing.T) {
+	account := createRandomAccount(t)
+	createRandomJournalEntry(t, account, uuid.Nil)
+	trade := createRandomTrade(t, account)
+	createRandomJournalEntry(t, account, trade.TradeUuid)
+}
+
+func TestLedgerBalances(t *testing.T) {
+	account := createRandomAccount(t)
+	cash := createRandomLedgerAccount(t, account.AccountUuid, LedgerAccountTypeCASH)
+	bank := createRandomLedgerAccount(t, uuid.Nil, LedgerAccountTypeBANK)
+	securities := createRandomLedgerAccount(t, account.AccountUuid, LedgerAccountTypeSECURITIES)
+	for _, amount := range []string{"100.50", "-20.25"} {
+		entry := createRandomJournalEntry(t, account, uuid.Nil)
+		createTestLedgerPosting(t, entry, cash, decimal.RequireFromString(amount))
+		createTestLedgerPosting(t, entry, bank, decimal.RequireFromString(amount).Neg())
+	}
+
+	balance, err := testQueries.GetLedgerAccountBalance(context.Background(), cash.LedgerAccountUuid)
+	require.NoError(t, err)
+	require.Equal(t, "80.25", balance.String())
+
+	balances, err := testQueries.ListLedgerBalances(context.Background(), account.AccountUuid)
+	require.NoError(t, err)
+	require.Len(t, balances, 2)
+	require.Equal(t, LedgerAccountTypeCASH, balances[0].Type)
+	require.Equal(t, "80.25", balances[0].Balance.String())
+	require.Equal(t, LedgerAccountTypeSECURITIES, balances[1].Type)
+	require.Equal(t, securities.Asset, balances[1].Asset)
+	require.True(t, balances[1].Balance.IsZero())
+}
+
+func TestListJournalEntriesAndPostings(t *testing.T) {
+	account := createRandomAccount(t)
+	cash := createRandomLedgerAccount(t, account.AccountUuid, LedgerAccountTypeCASH)
+	bank := createRandomLedgerAccount(t, uuid.Nil, LedgerAccountTypeBANK)
+	entries := make([]JournalEntry, 3)
+	for i := range entries {
+		entries[i] = createRandomJournalEntry(t, account, uuid.Nil)
+		createTestLedgerPosting(t, entries[i], cash, decimal.NewFromInt(10))
+		createTestLedgerPosting(t, entries[i], bank, decimal.NewFromInt(-10))
+	}
+
+	page, err := testQueries.ListJournalEntries(context.Background(), ListJournalEntriesParams{
+		AccountUuid: account.AccountUuid,
+		PageSize:    2,
+		PageOffset:  1,
+	})
+	require.NoError(t, err)
+	require.Len(t, page, 2)
+	for _, entry := range page {
+		require.Equal(t, account.AccountUuid, entry.AccountUuid)
+	}
+
+	postings, err := testQueries.ListLedgerPostings(context.Background(), []uuid.UUID{page[0].JournalEntryUuid, page[1].JournalEntryUuid})
+	require.NoError(t, err)
+	require.Len(t, postings, 4)
+	for _, posting := range postings {
+		require.Contains(t, []uuid.UUID{page[0].JournalEntryUuid, page[1].JournalEntryUuid}, posting.JournalEntryUuid)
+		if posting.Type == LedgerAccountTypeCASH {
+			require.Equal(t, account.AccountUuid, posting.AccountUuid)
+		} else {
+			require.Equal(t, uuid.Nil, posting.AccountUuid)
+		}
+	}
+}
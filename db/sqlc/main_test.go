@@ -11,11 +11,13 @@ import (
 )
 
 var testQueries Querier
+var testStore Store
 
 func TestMain(m *testing.M) {
 	config := loadConfig()
 	conn := openDatabaseConnection(config)
 	testQueries = New(conn)
+	testStore = NewStore(conn, TxOptions{Isolation: sql.LevelSerializable, MaxRetries: 3})
 	os.Exit(m.Run())
 }
 
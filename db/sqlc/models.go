@@ -4,11 +4,117 @@ package db
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
 )
 
+type AccountRole string
+
+const (
+	AccountRoleSTAFF AccountRole = "STAFF"
+	AccountRoleUSER  AccountRole = "USER"
+)
+
+func (e *AccountRole) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = AccountRole(s)
+	case string:
+		*e = AccountRole(s)
+	default:
+		return fmt.Errorf("unsupported scan type for AccountRole: %T", src)
+	}
+	return nil
+}
+
+type AccountStatus string
+
+const (
+	AccountStatusPENDING  AccountStatus = "PENDING"
+	AccountStatusAPPROVED AccountStatus = "APPROVED"
+	AccountStatusINACTIVE AccountStatus = "INACTIVE"
+)
+
+func (e *AccountStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = AccountStatus(s)
+	case string:
+		*e = AccountStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for AccountStatus: %T", src)
+	}
+	return nil
+}
+
+type JournalEntryType string
+
+const (
+	JournalEntryTypeDEPOSIT    JournalEntryType = "DEPOSIT"
+	JournalEntryTypeWITHDRAWAL JournalEntryType = "WITHDRAWAL"
+	JournalEntryTypeFILL       JournalEntryType = "FILL"
+	JournalEntryTypeFEE        JournalEntryType = "FEE"
+)
+
+func (e *JournalEntryType) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = JournalEntryType(s)
+	case string:
+		*e = JournalEntryType(s)
+	default:
+		return fmt.Errorf("unsupported scan type for JournalEntryType: %T", src)
+	}
+	return nil
+}
+
+type LedgerAccountType string
+
+const (
+	LedgerAccountTypeCASH       LedgerAccountType = "CASH"
+	LedgerAccountTypeSECURITIES LedgerAccountType = "SECURITIES"
+	LedgerAccountTypeBANK       LedgerAccountType = "BANK"
+	LedgerAccountTypeMARKET     LedgerAccountType = "MARKET"
+	LedgerAccountTypeFEES       LedgerAccountType = "FEES"
+)
+
+func (e *LedgerAccountType) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = LedgerAccountType(s)
+	case string:
+		*e = LedgerAccountType(s)
+	default:
+		return fmt.Errorf("unsupported scan type for LedgerAccountType: %T", src)
+	}
+	return nil
+}
+
+type OrderType string
+
+const (
+	OrderTypeMARKET     OrderType = "MARKET"
+	OrderTypeLIMIT      OrderType = "LIMIT"
+	OrderTypeSTOP       OrderType = "STOP"
+	OrderTypeSTOP_LIMIT OrderType = "STOP_LIMIT"
+)
+
+func (e *OrderType) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = OrderType(s)
+	case string:
+		*e = OrderType(s)
+	default:
+		return fmt.Errorf("unsupported scan type for OrderType: %T", src)
+	}
+	return nil
+}
+
 type State string
 
 const (
@@ -76,6 +182,27 @@ func (e *State) Scan(src interface{}) error {
 	return nil
 }
 
+type TimeInForce string
+
+const (
+	TimeInForceDAY TimeInForce = "DAY"
+	TimeInForceGTC TimeInForce = "GTC"
+	TimeInForceIOC TimeInForce = "IOC"
+	TimeInForceFOK TimeInForce = "FOK"
+)
+
+func (e *TimeInForce) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = TimeInForce(s)
+	case string:
+		*e = TimeInForce(s)
+	default:
+		return fmt.Errorf("unsupported scan type for TimeInForce: %T", src)
+	}
+	return nil
+}
+
 type TradeSide string
 
 const (
@@ -98,10 +225,11 @@ func (e *TradeSide) Scan(src interface{}) error {
 type TradeStatus string
 
 const (
-	TradeStatusSUBMITTED TradeStatus = "SUBMITTED"
-	TradeStatusCANCELLED TradeStatus = "CANCELLED"
-	TradeStatusCOMPLETED TradeStatus = "COMPLETED"
-	TradeStatusFAILED    TradeStatus = "FAILED"
+	TradeStatusSUBMITTED        TradeStatus = "SUBMITTED"
+	TradeStatusPARTIALLY_FILLED TradeStatus = "PARTIALLY_FILLED"
+	TradeStatusCANCELLED        TradeStatus = "CANCELLED"
+	TradeStatusCOMPLETED        TradeStatus = "COMPLETED"
+	TradeStatusFAILED           TradeStatus = "FAILED"
 )
 
 func (e *TradeStatus) Scan(src interface{}) error {
@@ -117,13 +245,16 @@ func (e *TradeStatus) Scan(src interface{}) error {
 }
 
 type Account struct {
-	AccountUuid uuid.UUID      `json:"account_uuid"`
-	Username    string         `json:"username"`
-	Email       string         `json:"email"`
-	CreatedDate sql.NullTime   `json:"created_date"`
-	UpdatedDate sql.NullTime   `json:"updated_date"`
-	CreatedBy   sql.NullString `json:"created_by"`
-	UpdatedBy   sql.NullString `json:"updated_by"`
+	AccountUuid    uuid.UUID      `json:"account_uuid"`
+	Username       string         `json:"username"`
+	Email          string         `json:"email"`
+	CreatedDate    sql.NullTime   `json:"created_date"`
+	UpdatedDate    sql.NullTime   `json:"updated_date"`
+	CreatedBy      sql.NullString `json:"created_by"`
+	UpdatedBy      sql.NullString `json:"updated_by"`
+	HashedPassword string         `json:"hashed_password"`
+	Role           AccountRole    `json:"role"`
+	Status         AccountStatus  `json:"status"`
 }
 
 type Address struct {
@@ -140,16 +271,183 @@ type Address struct {
 	UpdatedBy   sql.NullString `json:"updated_by"`
 }
 
+type AuditLog struct {
+	AuditLogUuid uuid.UUID       `json:"audit_log_uuid"`
+	Actor        string          `json:"actor"`
+	Action       string          `json:"action"`
+	EntityType   string          `json:"entity_type"`
+	EntityID     string          `json:"entity_id"`
+	Before       json.RawMessage `json:"before"`
+	After        json.RawMessage `json:"after"`
+	RequestID    sql.NullString  `json:"request_id"`
+	Ip           sql.NullString  `json:"ip"`
+	CreatedDate  time.Time       `json:"created_date"`
+}
+
+type IdempotencyKey struct {
+	Scope          string        `json:"scope"`
+	IdempotencyKey string        `json:"idempotency_key"`
+	RequestHash    string        `json:"request_hash"`
+	ResponseStatus sql.NullInt32 `json:"response_status"`
+	ResponseBody   []byte        `json:"response_body"`
+	CreatedDate    time.Time     `json:"created_date"`
+	ExpiresDate    time.Time     `json:"expires_date"`
+}
+
+type Instrument struct {
+	Symbol      string          `json:"symbol"`
+	Name        string          `json:"name"`
+	Exchange    string          `json:"exchange"`
+	TickSize    decimal.Decimal `json:"tick_size"`
+	LotSize     int64           `json:"lot_size"`
+	Tradable    bool            `json:"tradable"`
+	Currency    string          `json:"currency"`
+	CreatedDate time.Time       `json:"created_date"`
+	UpdatedDate time.Time       `json:"updated_date"`
+}
+
+type JournalEntry struct {
+	JournalEntryUuid uuid.UUID        `json:"journal_entry_uuid"`
+	AccountUuid      uuid.UUID        `json:"account_uuid"`
+	Type             JournalEntryType `json:"type"`
+	TradeUuid        uuid.UUID        `json:"trade_uuid"`
+	Description      string           `json:"description"`
+	CreatedDate      time.Time        `json:"created_date"`
+}
+
+type LedgerAccount struct {
+	LedgerAccountUuid uuid.UUID         `json:"ledger_account_uuid"`
+	AccountUuid       uuid.UUID         `json:"account_uuid"`
+	Type              LedgerAccountType `json:"type"`
+	Asset             string            `json:"asset"`
+	CreatedDate       time.Time         `json:"created_date"`
+}
+
+type LedgerPosting struct {
+	PostingUuid       uuid.UUID       `json:"posting_uuid"`
+	JournalEntryUuid  uuid.UUID       `json:"journal_entry_uuid"`
+	LedgerAccountUuid uuid.UUID       `json:"ledger_account_uuid"`
+	Amount            decimal.Decimal `json:"amount"`
+}
+
+type Outbox struct {
+	EventUuid     uuid.UUID       `json:"event_uuid"`
+	Sequence      int64           `json:"sequence"`
+	EventType     string          `json:"event_type"`
+	AggregateType string          `json:"aggregate_type"`
+	AggregateUuid uuid.UUID       `json:"aggregate_uuid"`
+	Payload       json.RawMessage `json:"payload"`
+	Attempts      int32           `json:"attempts"`
+	LastError     sql.NullString  `json:"last_error"`
+	LockedUntil   sql.NullTime    `json:"locked_until"`
+	PublishedDate sql.NullTime    `json:"published_date"`
+	CreatedDate   time.Time       `json:"created_date"`
+}
+
+type Position struct {
+	AccountUuid uuid.UUID       `json:"account_uuid"`
+	Symbol      string          `json:"symbol"`
+	Quantity    int64           `json:"quantity"`
+	AverageCost decimal.Decimal `json:"average_cost"`
+	CreatedDate time.Time       `json:"created_date"`
+	UpdatedDate time.Time       `json:"updated_date"`
+}
+
+type RiskCheck struct {
+	RiskCheckUuid uuid.UUID           `json:"risk_check_uuid"`
+	AccountUuid   uuid.UUID           `json:"account_uuid"`
+	TradeUuid     uuid.UUID           `json:"trade_uuid"`
+	Symbol        string              `json:"symbol"`
+	Side          TradeSide           `json:"side"`
+	Quantity      int64               `json:"quantity"`
+	Notional      decimal.NullDecimal `json:"notional"`
+	Passed        bool                `json:"passed"`
+	Violations    json.RawMessage     `json:"violations"`
+	CreatedDate   time.Time           `json:"created_date"`
+}
+
+type RiskLimit struct {
+	AccountUuid         uuid.UUID           `json:"account_uuid"`
+	MaxOrderNotional    decimal.NullDecimal `json:"max_order_notional"`
+	MaxDailyNotional    decimal.NullDecimal `json:"max_daily_notional"`
+	MaxPositionQuantity sql.NullInt64       `json:"max_position_quantity"`
+	CreatedDate         time.Time           `json:"created_date"`
+	UpdatedDate         time.Time           `json:"updated_date"`
+}
+
 type Trade struct {
-	TradeUuid   uuid.UUID      `json:"trade_uuid"`
-	AccountUuid uuid.UUID      `json:"account_uuid"`
-	Symbol      string         `json:"symbol"`
-	Quantity    int64          `json:"quantity"`
-	Side        TradeSide      `json:"side"`
-	Price       float64        `json:"price"`
-	Status      TradeStatus    `json:"status"`
-	CreatedDate sql.NullTime   `json:"created_date"`
-	UpdatedDate sql.NullTime   `json:"updated_date"`
-	CreatedBy   sql.NullString `json:"created_by"`
-	UpdatedBy   sql.NullString `json:"updated_by"`
+	TradeUuid         uuid.UUID           `json:"trade_uuid"`
+	AccountUuid       uuid.UUID           `json:"account_uuid"`
+	Symbol            string              `json:"symbol"`
+	Quantity          int64               `json:"quantity"`
+	Side              TradeSide           `json:"side"`
+	Price             decimal.NullDecimal `json:"price"`
+	Status            TradeStatus         `json:"status"`
+	CreatedDate       sql.NullTime        `json:"created_date"`
+	UpdatedDate       sql.NullTime        `json:"updated_date"`
+	CreatedBy         sql.NullString      `json:"created_by"`
+	UpdatedBy         sql.NullString      `json:"updated_by"`
+	OrderType         OrderType           `json:"order_type"`
+	TimeInForce       TimeInForce         `json:"time_in_force"`
+	StopPrice         decimal.NullDecimal `json:"stop_price"`
+	FilledQuantity    int64               `json:"filled_quantity"`
+	RemainingQuantity int64               `json:"remaining_quantity"`
+	AverageFillPrice  decimal.NullDecimal `json:"average_fill_price"`
+	Version           int32               `json:"version"`
+}
+
+type TradeExecution struct {
+	ExecutionUuid uuid.UUID       `json:"execution_uuid"`
+	TradeUuid     uuid.UUID       `json:"trade_uuid"`
+	Quantity      int64           `json:"quantity"`
+	Price         decimal.Decimal `json:"price"`
+	ExecutedDate  time.Time       `json:"executed_date"`
+	Fee           decimal.Decimal `json:"fee"`
+}
+
+type TradeVersion struct {
+	TradeUuid   uuid.UUID           `json:"trade_uuid"`
+	Version     int32               `json:"version"`
+	Quantity    int64               `json:"quantity"`
+	Price       decimal.NullDecimal `json:"price"`
+	StopPrice   decimal.NullDecimal `json:"stop_price"`
+	CreatedDate time.Time           `json:"created_date"`
+}
+
+type WebhookDeadLetter struct {
+	WebhookDeadLetterUuid   uuid.UUID       `json:"webhook_dead_letter_uuid"`
+	WebhookSubscriptionUuid uuid.UUID       `json:"webhook_subscription_uuid"`
+	EventUuid               uuid.UUID       `json:"event_uuid"`
+	EventType               string          `json:"event_type"`
+	Payload                 json.RawMessage `json:"payload"`
+	Attempts                int32           `json:"attempts"`
+	ResponseStatus          sql.NullInt32   `json:"response_status"`
+	LastError               string          `json:"last_error"`
+	ReplayedDate            sql.NullTime    `json:"replayed_date"`
+	CreatedDate             time.Time       `json:"created_date"`
+}
+
+type WebhookDelivery struct {
+	WebhookDeliveryUuid     uuid.UUID       `json:"webhook_delivery_uuid"`
+	WebhookSubscriptionUuid uuid.UUID       `json:"webhook_subscription_uuid"`
+	EventUuid               uuid.UUID       `json:"event_uuid"`
+	EventType               string          `json:"event_type"`
+	Payload                 json.RawMessage `json:"payload"`
+	Attempts                int32           `json:"attempts"`
+	NextAttemptDate         time.Time       `json:"next_attempt_date"`
+	ResponseStatus          sql.NullInt32   `json:"response_status"`
+	LastError               sql.NullString  `json:"last_error"`
+	DeliveredDate           sql.NullTime    `json:"delivered_date"`
+	CreatedDate             time.Time       `json:"created_date"`
+}
+
+type WebhookSubscription struct {
+	WebhookSubscriptionUuid uuid.UUID `json:"webhook_subscription_uuid"`
+	AccountUuid             uuid.UUID `json:"account_uuid"`
+	Url                     string    `json:"url"`
+	Secret                  string    `json:"secret"`
+	EventTypes              []string  `json:"event_types"`
+	Active                  bool      `json:"active"`
+	CreatedDate             time.Time `json:"created_date"`
+	UpdatedDate             time.Time `json:"updated_date"`
 }
@@ -0,0 +1,102 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomOutboxEvent(t *testing.T, aggregateUUID uuid.UUID) Outbox {
+	arg := CreateOutboxEventParams{
+		EventUuid:     uuid.New(),
+		EventType:     "trade.created",
+		AggregateType: "trade",
+		AggregateUuid: aggregateUUID,
+		Payload:       json.RawMessage(`{"type": "trade.created"}`),
+	}
+	event, err := testQueries.CreateOutboxEvent(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.EventUuid, event.EventUuid)
+	require.Equal(t, arg.AggregateUuid, event.AggregateUuid)
+	require.NotZero(t, event.Sequence)
+	require.Zero(t, event.Attempts)
+	require.False(t, event.PublishedDate.Valid)
+	return event
+}
+
+// claimAggregateEvents claims the due events, keeping those of the given aggregate
+func claimAggregateEvents(t *testing.T, aggregateUUID uuid.UUID) []Outbox {
+	events, err := testQueries.ClaimOutboxEvents(context.Background(), ClaimOutboxEventsParams{
+		LeaseSeconds: 60,
+		BatchSize:    1000,
+	})
+	require.NoError(t, err)
+	var claimed []Outbox
+	for _, event := range events {
+		if event.AggregateUuid == aggregateUUID {
+			claimed = append(claimed, event)
+		}
+	}
+	return claimed
+}
+
+func TestClaimOutboxEventsInOrder(t *testing.T) {
+	aggregateUUID := uuid.New()
+	first := createRandomOutboxEvent(t, aggregateUUID)
+	second := createRandomOutboxEvent(t, aggregateUUID)
+	require.Greater(t, second.Sequence, first.Sequence)
+
+	claimed := claimAggregateEvents(t, aggregateUUID)
+	require.Len(t, claimed, 1)
+	require.Equal(t, first.EventUuid, claimed[0].EventUuid)
+	require.True(t, claimed[0].LockedUntil.Valid)
+
+	// the second event waits for the first one to be published
+	require.Empty(t, claimAggregateEvents(t, aggregateUUID))
+
+	err := testQueries.MarkOutboxEventPublished(context.Background(), first.EventUuid)
+	require.NoError(t, err)
+	published, err := testQueries.GetOutboxEvent(context.Background(), first.EventUuid)
+	require.NoError(t, err)
+	require.True(t, published.PublishedDate.Valid)
+	require.False(t, published.LockedUntil.Valid)
+	require.Equal(t, int32(1), published.Attempts)
+
+	claimed = claimAggregateEvents(t, aggregateUUID)
+	require.Len(t, claimed, 1)
+	require.Equal(t, second.EventUuid, claimed[0].EventUuid)
+}
+
+func TestRetryOutboxEvent(t *testing.T) {
+	aggregateUUID := uuid.New()
+	event := createRandomOutboxEvent(t, aggregateUUID)
+	require.Len(t, claimAggregateEvents(t, aggregateUUID), 1)
+
+	err := testQueries.RetryOutboxEvent(context.Background(), RetryOutboxEventParams{
+		LastError:    sql.NullString{String: "broker unavailable", Valid: true},
+		DelaySeconds: 0,
+		EventUuid:    event.EventUuid,
+	})
+	require.NoError(t, err)
+	retried, err := testQueries.GetOutboxEvent(context.Background(), event.EventUuid)
+	require.NoError(t, err)
+	require.Equal(t, int32(1), retried.Attempts)
+	require.Equal(t, "broker unavailable", retried.LastError.String)
+	require.False(t, retried.PublishedDate.Valid)
+
+	claimed := claimAggregateEvents(t, aggregateUUID)
+	require.Len(t, claimed, 1)
+	require.Equal(t, event.EventUuid, claimed[0].EventUuid)
+
+	err = testQueries.RetryOutboxEvent(context.Background(), RetryOutboxEventParams{
+		LastError:    sql.NullString{String: "broker unavailable", Valid: true},
+		DelaySeconds: 60,
+		EventUuid:    event.EventUuid,
+	})
+	require.NoError(t, err)
+	require.Empty(t, claimAggregateEvents(t, aggregateUUID))
+}
@@ -0,0 +1,90 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+	"github.com/valverdethiago/trading-api/util"
+)
+
+func createRandomPosition(t *testing.T, account Account) Position {
+	arg := UpsertPositionParams{
+		AccountUuid: account.AccountUuid,
+		Symbol:      util.RandomString(4),
+		Quantity:    util.RandomInt(1, 1000),
+		AverageCost: util.RandomPrice(1, 1000),
+	}
+
+	position, err := testQueries.UpsertPosition(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.AccountUuid, position.AccountUuid)
+	require.Equal(t, arg.Symbol, position.Symbol)
+	require.Equal(t, arg.Quantity, position.Quantity)
+	require.True(t, arg.AverageCost.Equal(position.AverageCost))
+	require.NotZero(t, position.CreatedDate)
+	require.NotZero(t, position.UpdatedDate)
+	return position
+}
+
+func TestUpsertPosition(t *testing.T) {
+	account := createRandomAccount(t)
+	position := createRandomPosition(t, account)
+
+	arg := UpsertPositionParams{
+		AccountUuid: account.AccountUuid,
+		Symbol:      position.Symbol,
+		Quantity:    position.Quantity + 10,
+		AverageCost: decimal.RequireFromString("12.34"),
+	}
+	updated, err := testQueries.UpsertPosition(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.Quantity, updated.Quantity)
+	require.True(t, arg.AverageCost.Equal(updated.AverageCost))
+	require.Equal(t, position.CreatedDate, updated.CreatedDate)
+}
+
+func TestGetPosition(t *testing.T) {
+	account := createRandomAccount(t)
+	position := createRandomPosition(t, account)
+
+	dbPosition, err := testQueries.GetPosition(context.Background(), GetPositionParams{
+		AccountUuid: account.AccountUuid,
+		Symbol:      position.Symbol,
+	})
+	require.NoError(t, err)
+	require.Equal(t, position.Quantity, dbPosition.Quantity)
+
+	_, err = testQueries.GetPositionForUpdate(context.Background(), GetPositionForUpdateParams{
+		AccountUuid: account.AccountUuid,
+		Symbol:      util.RandomString(5),
+	})
+	require.EqualError(t, err, sql.ErrNoRows.Error())
+}
+
+func TestListPositionsByAccount(t *testing.T) {
+	account := createRandomAccount(t)
+	for i := 0; i < 3; i++ {
+		createRandomPosition(t, account)
+	}
+	_, err := testQueries.UpsertPosition(context.Background(), UpsertPositionParams{
+		AccountUuid: account.AccountUuid,
+		Symbol:      util.RandomString(4),
+		Quantity:    0,
+		AverageCost: decimal.Zero,
+	})
+	require.NoError(t, err)
+
+	positions, err := testQueries.ListPositionsByAccount(context.Background(), account.AccountUuid)
+	require.NoError(t, err)
+	require.Len(t, positions, 3)
+	for i, position := range positions {
+		require.Equal(t, account.AccountUuid, position.AccountUuid)
+		require.NotZero(t, position.Quantity)
+		if i > 0 {
+			require.True(t, positions[i-1].Symbol < position.Symbol)
+		}
+	}
+}
@@ -6,24 +6,95 @@ import (
 	"context"
 
 	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
 )
 
 type Querier interface {
+	ClaimOutboxEvents(ctx context.Context, arg ClaimOutboxEventsParams) ([]Outbox, error)
+	ClaimWebhookDeliveries(ctx context.Context, arg ClaimWebhookDeliveriesParams) ([]ClaimWebhookDeliveriesRow, error)
+	CountAccounts(ctx context.Context, arg CountAccountsParams) (int64, error)
 	CreateAccount(ctx context.Context, arg CreateAccountParams) (Account, error)
 	CreateAddress(ctx context.Context, arg CreateAddressParams) (Address, error)
+	CreateAuditLog(ctx context.Context, arg CreateAuditLogParams) (AuditLog, error)
+	CreateIdempotencyKey(ctx context.Context, arg CreateIdempotencyKeyParams) (IdempotencyKey, error)
+	CreateInstrument(ctx context.Context, arg CreateInstrumentParams) (Instrument, error)
+	CreateJournalEntry(ctx context.Context, arg CreateJournalEntryParams) (JournalEntry, error)
+	CreateLedgerPosting(ctx context.Context, arg CreateLedgerPostingParams) (LedgerPosting, error)
+	CreateOutboxEvent(ctx context.Context, arg CreateOutboxEventParams) (Outbox, error)
+	CreateRiskCheck(ctx context.Context, arg CreateRiskCheckParams) (RiskCheck, error)
 	CreateTrade(ctx context.Context, arg CreateTradeParams) (Trade, error)
+	CreateTradeExecution(ctx context.Context, arg CreateTradeExecutionParams) (TradeExecution, error)
+	CreateTradeVersion(ctx context.Context, arg CreateTradeVersionParams) (TradeVersion, error)
+	CreateWebhookDeadLetter(ctx context.Context, arg CreateWebhookDeadLetterParams) (WebhookDeadLetter, error)
+	CreateWebhookDelivery(ctx context.Context, arg CreateWebhookDeliveryParams) (WebhookDelivery, error)
+	CreateWebhookSubscription(ctx context.Context, arg CreateWebhookSubscriptionParams) (WebhookSubscription, error)
 	DeleteAddressFromAccount(ctx context.Context, accountUuid uuid.UUID) error
+	DeleteExpiredIdempotencyKeys(ctx context.Context) error
+	DeleteIdempotencyKey(ctx context.Context, arg DeleteIdempotencyKeyParams) error
+	DeleteInstrument(ctx context.Context, symbol string) (Instrument, error)
+	DeleteWebhookDelivery(ctx context.Context, webhookDeliveryUuid uuid.UUID) error
+	DeleteWebhookSubscription(ctx context.Context, webhookSubscriptionUuid uuid.UUID) error
 	GetAccountById(ctx context.Context, accountUuid uuid.UUID) (Account, error)
 	GetAccountByUsername(ctx context.Context, username string) (Account, error)
+	GetAccountRole(ctx context.Context, accountUuid uuid.UUID) (AccountRole, error)
 	GetAddressByAccount(ctx context.Context, accountUuid uuid.UUID) (Address, error)
 	GetAddressById(ctx context.Context, addressUuid uuid.UUID) (Address, error)
+	GetIdempotencyKey(ctx context.Context, arg GetIdempotencyKeyParams) (IdempotencyKey, error)
+	GetInstrument(ctx context.Context, symbol string) (Instrument, error)
+	GetLedgerAccountBalance(ctx context.Context, ledgerAccountUuid uuid.UUID) (decimal.Decimal, error)
+	GetOpenBuyNotional(ctx context.Context, accountUuid uuid.UUID) (decimal.Decimal, error)
+	GetOpenBuyQuantity(ctx context.Context, arg GetOpenBuyQuantityParams) (int64, error)
+	GetOpenNotional(ctx context.Context, accountUuid uuid.UUID) (decimal.Decimal, error)
+	GetOpenSellQuantity(ctx context.Context, arg GetOpenSellQuantityParams) (int64, error)
+	GetOutboxEvent(ctx context.Context, eventUuid uuid.UUID) (Outbox, error)
+	GetPosition(ctx context.Context, arg GetPositionParams) (Position, error)
+	GetPositionForUpdate(ctx context.Context, arg GetPositionForUpdateParams) (Position, error)
+	GetRiskLimit(ctx context.Context, accountUuid uuid.UUID) (RiskLimit, error)
 	GetTradeById(ctx context.Context, tradeUuid uuid.UUID) (Trade, error)
+	GetTradeByIdForUpdate(ctx context.Context, tradeUuid uuid.UUID) (Trade, error)
+	GetTradedNotional(ctx context.Context, arg GetTradedNotionalParams) (decimal.Decimal, error)
+	GetWebhookDeadLetter(ctx context.Context, webhookDeadLetterUuid uuid.UUID) (WebhookDeadLetter, error)
+	GetWebhookSubscription(ctx context.Context, webhookSubscriptionUuid uuid.UUID) (WebhookSubscription, error)
+	ListAccountFills(ctx context.Context, arg ListAccountFillsParams) ([]ListAccountFillsRow, error)
 	ListAccounts(ctx context.Context) ([]Account, error)
+	ListAuditLog(ctx context.Context, arg ListAuditLogParams) ([]AuditLog, error)
+	ListInstruments(ctx context.Context) ([]Instrument, error)
+	ListJournalEntries(ctx context.Context, arg ListJournalEntriesParams) ([]JournalEntry, error)
+	ListLatestPrices(ctx context.Context, arg ListLatestPricesParams) ([]ListLatestPricesRow, error)
+	ListLedgerBalances(ctx context.Context, accountUuid uuid.UUID) ([]ListLedgerBalancesRow, error)
+	ListLedgerPostings(ctx context.Context, journalEntryUuids []uuid.UUID) ([]ListLedgerPostingsRow, error)
+	ListOpenTradesByAccount(ctx context.Context, accountUuid uuid.UUID) ([]Trade, error)
+	ListPositionsByAccount(ctx context.Context, accountUuid uuid.UUID) ([]Position, error)
+	ListRiskChecksByAccount(ctx context.Context, arg ListRiskChecksByAccountParams) ([]RiskCheck, error)
+	ListTradeExecutions(ctx context.Context, tradeUuid uuid.UUID) ([]TradeExecution, error)
+	ListTradeVersions(ctx context.Context, tradeUuid uuid.UUID) ([]TradeVersion, error)
 	ListTradesByAccount(ctx context.Context, accountUuid uuid.UUID) ([]Trade, error)
+	ListTradesByAccountAsc(ctx context.Context, arg ListTradesByAccountAscParams) ([]Trade, error)
+	ListTradesByAccountDesc(ctx context.Context, arg ListTradesByAccountDescParams) ([]Trade, error)
+	ListTradesByStatus(ctx context.Context, status TradeStatus) ([]Trade, error)
+	ListWebhookDeadLetters(ctx context.Context, arg ListWebhookDeadLettersParams) ([]WebhookDeadLetter, error)
+	ListWebhookSubscriptionsByAccount(ctx context.Context, accountUuid uuid.UUID) ([]WebhookSubscription, error)
+	ListWebhookSubscriptionsForEvent(ctx context.Context, arg ListWebhookSubscriptionsForEventParams) ([]WebhookSubscription, error)
+	MarkOutboxEventPublished(ctx context.Context, eventUuid uuid.UUID) error
+	MarkWebhookDeadLetterReplayed(ctx context.Context, webhookDeadLetterUuid uuid.UUID) (WebhookDeadLetter, error)
+	MarkWebhookDelivered(ctx context.Context, arg MarkWebhookDeliveredParams) error
+	RetryOutboxEvent(ctx context.Context, arg RetryOutboxEventParams) error
+	RetryWebhookDelivery(ctx context.Context, arg RetryWebhookDeliveryParams) error
+	SearchAccounts(ctx context.Context, arg SearchAccountsParams) ([]Account, error)
 	UpdateAccount(ctx context.Context, arg UpdateAccountParams) (Account, error)
+	UpdateAccountRole(ctx context.Context, arg UpdateAccountRoleParams) (Account, error)
+	UpdateAccountStatus(ctx context.Context, arg UpdateAccountStatusParams) (Account, error)
 	UpdateAddress(ctx context.Context, arg UpdateAddressParams) (Address, error)
+	UpdateIdempotencyKeyResponse(ctx context.Context, arg UpdateIdempotencyKeyResponseParams) (IdempotencyKey, error)
+	UpdateInstrument(ctx context.Context, arg UpdateInstrumentParams) (Instrument, error)
 	UpdateTrade(ctx context.Context, arg UpdateTradeParams) (Trade, error)
+	UpdateTradeFill(ctx context.Context, arg UpdateTradeFillParams) (Trade, error)
 	UpdateTradeStatus(ctx context.Context, arg UpdateTradeStatusParams) (Trade, error)
+	UpdateWebhookSubscription(ctx context.Context, arg UpdateWebhookSubscriptionParams) (WebhookSubscription, error)
+	UpsertInstrument(ctx context.Context, arg UpsertInstrumentParams) (Instrument, error)
+	UpsertLedgerAccount(ctx context.Context, arg UpsertLedgerAccountParams) (LedgerAccount, error)
+	UpsertPosition(ctx context.Context, arg UpsertPositionParams) (Position, error)
+	UpsertRiskLimit(ctx context.Context, arg UpsertRiskLimitParams) (RiskLimit, error)
 }
 
 var _ Querier = (*Queries)(nil)
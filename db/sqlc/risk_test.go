@@ -0,0 +1,91 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpsertRiskLimit(t *testing.T) {
+	account := createRandomAccount(t)
+
+	_, err := testQueries.GetRiskLimit(context.Background(), account.AccountUuid)
+	require.Equal(t, sql.ErrNoRows, err)
+
+	arg := UpsertRiskLimitParams{
+		AccountUuid:      account.AccountUuid,
+		MaxOrderNotional: decimal.NullDecimal{Decimal: decimal.NewFromInt(1000), Valid: true},
+	}
+	riskLimit, err := testQueries.UpsertRiskLimit(context.Background(), arg)
+	require.NoError(t, err)
+	require.True(t, arg.MaxOrderNotional.Decimal.Equal(riskLimit.MaxOrderNotional.Decimal))
+	require.False(t, riskLimit.MaxDailyNotional.Valid)
+	require.False(t, riskLimit.MaxPositionQuantity.Valid)
+
+	arg = UpsertRiskLimitParams{
+		AccountUuid:         account.AccountUuid,
+		MaxPositionQuantity: sql.NullInt64{Int64: 100, Valid: true},
+	}
+	updated, err := testQueries.UpsertRiskLimit(context.Background(), arg)
+	require.NoError(t, err)
+	require.False(t, updated.MaxOrderNotional.Valid)
+	require.Equal(t, arg.MaxPositionQuantity, updated.MaxPositionQuantity)
+	require.Equal(t, riskLimit.CreatedDate, updated.CreatedDate)
+
+	dbRiskLimit, err := testQueries.GetRiskLimit(context.Background(), account.AccountUuid)
+	require.NoError(t, err)
+	require.Equal(t, updated.MaxPositionQuantity, dbRiskLimit.MaxPositionQuantity)
+}
+
+func TestCreateRiskCheck(t *testing.T) {
+	account := createRandomAccount(t)
+	trade := createRandomTrade(t, account)
+
+	passed, err := testQueries.CreateRiskCheck(context.Background(), CreateRiskCheckParams{
+		AccountUuid: account.AccountUuid,
+		TradeUuid:   trade.TradeUuid,
+		Symbol:      trade.Symbol,
+		Side:        trade.Side,
+		Quantity:    trade.Quantity,
+		Notional:    decimal.NullDecimal{Decimal: decimal.NewFromInt(100), Valid: true},
+		Passed:      true,
+		Violations:  json.RawMessage(`[]`),
+	})
+	require.NoError(t, err)
+	require.Equal(t, trade.TradeUuid, passed.TradeUuid)
+	require.True(t, passed.Passed)
+
+	rejected, err := testQueries.CreateRiskCheck(context.Background(), CreateRiskCheckParams{
+		AccountUuid: account.AccountUuid,
+		TradeUuid:   uuid.Nil,
+		Symbol:      trade.Symbol,
+		Side:        trade.Side,
+		Quantity:    trade.Quantity,
+		Passed:      false,
+		Violations:  json.RawMessage(`[{"rule": "BUYING_POWER"}]`),
+	})
+	require.NoError(t, err)
+	require.Equal(t, uuid.Nil, rejected.TradeUuid)
+	require.False(t, rejected.Notional.Valid)
+	require.JSONEq(t, `[{"rule": "BUYING_POWER"}]`, string(rejected.Violations))
+
+	riskChecks, err := testQueries.ListRiskChecksByAccount(context.Background(), ListRiskChecksByAccountParams{
+		AccountUuid: account.AccountUuid,
+		Limit:       10,
+	})
+	require.NoError(t, err)
+	require.Len(t, riskChecks, 2)
+	require.Equal(t, rejected.RiskCheckUuid, riskChecks[0].RiskCheckUuid)
+
+	riskChecks, err = testQueries.ListRiskChecksByAccount(context.Background(), ListRiskChecksByAccountParams{
+		AccountUuid: account.AccountUuid,
+		Limit:       1,
+	})
+	require.NoError(t, err)
+	require.Len(t, riskChecks, 1)
+}
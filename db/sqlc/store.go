@@ -0,0 +1,101 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// Postgres error codes of transactions that can be safely retried
+const (
+	serializationFailureCode = "40001"
+	deadlockDetectedCode     = "40P01"
+)
+
+// Store provides all functions to execute db queries and transactions
+type Store interface {
+	Querier
+	// ExecTx runs fn inside a database transaction, committing it if fn succeeds
+	ExecTx(ctx context.Context, fn func(Querier) error) error
+}
+
+// TxOptions configures the transactions executed by the store
+type TxOptions struct {
+	Isolation  sql.IsolationLevel
+	MaxRetries int
+}
+
+// SQLStore provides all functions to execute SQL queries and transactions
+type SQLStore struct {
+	*Queries
+	db      *sql.DB
+	options TxOptions
+}
+
+// NewStore creates a new store
+func NewStore(db *sql.DB, options TxOptions) Store {
+	return &SQLStore{
+		Queries: New(db),
+		db:      db,
+		options: options,
+	}
+}
+
+// ExecTx runs fn inside a database transaction, retrying it when it fails
+// due to a serialization failure or a deadlock
+func (store *SQLStore) ExecTx(ctx context.Context, fn func(Querier) error) error {
+	var err error
+	for attempt := 0; attempt <= store.options.MaxRetries; attempt++ {
+		err = store.execTx(ctx, fn)
+		if !isRetryableTxError(err) {
+			return err
+		}
+	}
+	return err
+}
+
+func (store *SQLStore) execTx(ctx context.Context, fn func(Querier) error) error {
+	tx, err := store.db.BeginTx(ctx, &sql.TxOptions{Isolation: store.options.Isolation})
+	if err != nil {
+		return err
+	}
+	err = fn(store.WithTx(tx))
+	if err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return fmt.Errorf("tx err: %w, rb err: %v", err, rbErr)
+		}
+		return err
+	}
+	return tx.Commit()
+}
+
+func isRetryableTxError(err error) bool {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return pqErr.Code == serializationFailureCode || pqErr.Code == deadlockDetectedCode
+	}
+	return false
+}
+
+// ParseIsolationLevel converts an isolation level name like "serializable" or
+// "read committed" into a sql.IsolationLevel, the empty string being the driver default
+func ParseIsolationLevel(name string) (sql.IsolationLevel, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "":
+		return sql.LevelDefault, nil
+	case "read uncommitted":
+		return sql.LevelReadUncommitted, nil
+	case "read committed":
+		return sql.LevelReadCommitted, nil
+	case "repeatable read":
+		return sql.LevelRepeatableRead, nil
+	case "serializable":
+		return sql.LevelSerializable, nil
+	default:
+		return sql.LevelDefault, fmt.Errorf("unsupported isolation level %q", name)
+	}
+}
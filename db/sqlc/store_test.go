@@ -0,0 +1,54 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/valverdethiago/trading-api/util"
+)
+
+func TestExecTxCommit(t *testing.T) {
+	var account Account
+	err := testStore.ExecTx(context.Background(), func(q Querier) error {
+		var err error
+		account, err = q.CreateAccount(context.Background(), CreateAccountParams{
+			Username: util.RandomUsername(),
+			Email:    util.RandomEmail(),
+		})
+		return err
+	})
+	require.NoError(t, err)
+
+	dbAccount, err := testQueries.GetAccountById(context.Background(), account.AccountUuid)
+	require.NoError(t, err)
+	require.Equal(t, account.Username, dbAccount.Username)
+}
+
+func TestExecTxRollback(t *testing.T) {
+	expectedErr := errors.New("address failed")
+	var account Account
+	err := testStore.ExecTx(context.Background(), func(q Querier) error {
+		var err error
+		account, err = q.CreateAccount(context.Background(), CreateAccountParams{
+			Username: util.RandomUsername(),
+			Email:    util.RandomEmail(),
+		})
+		require.NoError(t, err)
+		return expectedErr
+	})
+	require.ErrorIs(t, err, expectedErr)
+
+	_, err = testQueries.GetAccountById(context.Background(), account.AccountUuid)
+	require.Error(t, err)
+}
+
+func TestParseIsolationLevel(t *testing.T) {
+	level, err := ParseIsolationLevel("Serializable")
+	require.NoError(t, err)
+	require.Equal(t, "Serializable", level.String())
+
+	_, err = ParseIsolationLevel("snapshot")
+	require.Error(t, err)
+}
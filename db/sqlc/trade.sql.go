@@ -5,22 +5,28 @@ package db
 
 import (
 	"context"
+	"database/sql"
 
 	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
 )
 
 const createTrade = `-- name: CreateTrade :one
-INSERT INTO trade (account_uuid, symbol, quantity, side          , price) 
-     VALUES       ($1          , $2    , $3      , $4::trade_side, $5   )
-RETURNING trade_uuid, account_uuid, symbol, quantity, side, price, status, created_date, updated_date, created_by, updated_by
+INSERT INTO trade (account_uuid, symbol, quantity, remaining_quantity, side          , price, order_type     , time_in_force     , stop_price, created_by, updated_by) 
+     VALUES       ($1          , $2    , $3      , $3                , $4::trade_side, $5   , $6::order_type, $7::time_in_force, $8        , $9        , $9        )
+RETURNING trade_uuid, account_uuid, symbol, quantity, side, price, status, created_date, updated_date, created_by, updated_by, order_type, time_in_force, stop_price, filled_quantity, remaining_quantity, average_fill_price, version
 `
 
 type CreateTradeParams struct {
-	AccountUuid uuid.UUID `json:"account_uuid"`
-	Symbol      string    `json:"symbol"`
-	Quantity    int64     `json:"quantity"`
-	Side        TradeSide `json:"side"`
-	Price       float64   `json:"price"`
+	AccountUuid uuid.UUID           `json:"account_uuid"`
+	Symbol      string              `json:"symbol"`
+	Quantity    int64               `json:"quantity"`
+	Side        TradeSide           `json:"side"`
+	Price       decimal.NullDecimal `json:"price"`
+	OrderType   OrderType           `json:"order_type"`
+	TimeInForce TimeInForce         `json:"time_in_force"`
+	StopPrice   decimal.NullDecimal `json:"stop_price"`
+	CreatedBy   sql.NullString      `json:"created_by"`
 }
 
 func (q *Queries) CreateTrade(ctx context.Context, arg CreateTradeParams) (Trade, error) {
@@ -30,6 +36,10 @@ func (q *Queries) CreateTrade(ctx context.Context, arg CreateTradeParams) (Trade
 		arg.Quantity,
 		arg.Side,
 		arg.Price,
+		arg.OrderType,
+		arg.TimeInForce,
+		arg.StopPrice,
+		arg.CreatedBy,
 	)
 	var i Trade
 	err := row.Scan(
@@ -44,12 +54,97 @@ func (q *Queries) CreateTrade(ctx context.Context, arg CreateTradeParams) (Trade
 		&i.UpdatedDate,
 		&i.CreatedBy,
 		&i.UpdatedBy,
+		&i.OrderType,
+		&i.TimeInForce,
+		&i.StopPrice,
+		&i.FilledQuantity,
+		&i.RemainingQuantity,
+		&i.AverageFillPrice,
+		&i.Version,
 	)
 	return i, err
 }
 
+const getOpenBuyNotional = `-- name: GetOpenBuyNotional :one
+SELECT COALESCE(SUM(trade.remaining_quantity * COALESCE(trade.price, trade.stop_price, (
+           SELECT trade_execution.price
+             FROM trade_execution
+       INNER JOIN trade AS traded ON traded.trade_uuid = trade_execution.trade_uuid
+            WHERE traded.symbol = trade.symbol
+         ORDER BY trade_execution.executed_date DESC, trade_execution.execution_uuid DESC
+            LIMIT 1
+       ), 0)), 0)::numeric AS notional
+  FROM trade
+ WHERE trade.account_uuid = $1
+   AND trade.side = 'BUY'
+   AND trade.status IN ('SUBMITTED', 'PARTIALLY_FILLED')
+`
+
+func (q *Queries) GetOpenBuyNotional(ctx context.Context, accountUuid uuid.UUID) (decimal.Decimal, error) {
+	row := q.db.QueryRowContext(ctx, getOpenBuyNotional, accountUuid)
+	var notional decimal.Decimal
+	err := row.Scan(&notional)
+	return notional, err
+}
+
+const getOpenBuyQuantity = `-- name: GetOpenBuyQuantity :one
+SELECT COALESCE(SUM(remaining_quantity), 0)::bigint AS quantity
+  FROM trade
+ WHERE account_uuid = $1
+   AND symbol = $2
+   AND side = 'BUY'
+   AND status IN ('SUBMITTED', 'PARTIALLY_FILLED')
+`
+
+type GetOpenBuyQuantityParams struct {
+	AccountUuid uuid.UUID `json:"account_uuid"`
+	Symbol      string    `json:"symbol"`
+}
+
+func (q *Queries) GetOpenBuyQuantity(ctx context.Context, arg GetOpenBuyQuantityParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getOpenBuyQuantity, arg.AccountUuid, arg.Symbol)
+	var quantity int64
+	err := row.Scan(&quantity)
+	return quantity, err
+}
+
+const getOpenNotional = `-- name: GetOpenNotional :one
+SELECT COALESCE(SUM(remaining_quantity * COALESCE(price, stop_price, 0)), 0)::numeric AS notional
+  FROM trade
+ WHERE account_uuid = $1
+   AND status IN ('SUBMITTED', 'PARTIALLY_FILLED')
+`
+
+func (q *Queries) GetOpenNotional(ctx context.Context, accountUuid uuid.UUID) (decimal.Decimal, error) {
+	row := q.db.QueryRowContext(ctx, getOpenNotional, accountUuid)
+	var notional decimal.Decimal
+	err := row.Scan(&notional)
+	return notional, err
+}
+
+const getOpenSellQuantity = `-- name: GetOpenSellQuantity :one
+SELECT COALESCE(SUM(remaining_quantity), 0)::bigint AS quantity
+  FROM trade
+ WHERE account_uuid = $1
+   AND symbol = $2
+   AND side = 'SELL'
+   AND status IN ('SUBMITTED', 'PARTIALLY_FILLED')
+`
+
+type GetOpenSellQuantityParams struct {
+	AccountUuid uuid.UUID `json:"account_uuid"`
+	Symbol      string    `json:"symbol"`
+}
+
+func (q *Queries) GetOpenSellQuantity(ctx context.Context, arg GetOpenSellQuantityParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getOpenSellQuantity, arg.AccountUuid, arg.Symbol)
+	var quantity int64
+	err := row.Scan(&quantity)
+	return quantity, err
+}
+
 const getTradeById = `-- name: GetTradeById :one
-SELECT trade_uuid, account_uuid, symbol, quantity, side, price, status, created_date, updated_date, created_by, updated_by 
+SELECT trade_uuid, account_uuid, symbol, quantity, side, price, status, created_date, updated_date, created_by, updated_by, order_type, time_in_force, stop_price, filled_quantity, remaining_quantity, average_fill_price, version 
   FROM trade
  WHERE trade_uuid = $1
 `
@@ -69,12 +164,102 @@ func (q *Queries) GetTradeById(ctx context.Context, tradeUuid uuid.UUID) (Trade,
 		&i.UpdatedDate,
 		&i.CreatedBy,
 		&i.UpdatedBy,
+		&i.OrderType,
+		&i.TimeInForce,
+		&i.StopPrice,
+		&i.FilledQuantity,
+		&i.RemainingQuantity,
+		&i.AverageFillPrice,
+		&i.Version,
 	)
 	return i, err
 }
 
+const getTradeByIdForUpdate = `-- name: GetTradeByIdForUpdate :one
+SELECT trade_uuid, account_uuid, symbol, quantity, side, price, status, created_date, updated_date, created_by, updated_by, order_type, time_in_force, stop_price, filled_quantity, remaining_quantity, average_fill_price, version 
+  FROM trade
+ WHERE trade_uuid = $1
+   FOR UPDATE
+`
+
+func (q *Queries) GetTradeByIdForUpdate(ctx context.Context, tradeUuid uuid.UUID) (Trade, error) {
+	row := q.db.QueryRowContext(ctx, getTradeByIdForUpdate, tradeUuid)
+	var i Trade
+	err := row.Scan(
+		&i.TradeUuid,
+		&i.AccountUuid,
+		&i.Symbol,
+		&i.Quantity,
+		&i.Side,
+		&i.Price,
+		&i.Status,
+		&i.CreatedDate,
+		&i.UpdatedDate,
+		&i.CreatedBy,
+		&i.UpdatedBy,
+		&i.OrderType,
+		&i.TimeInForce,
+		&i.StopPrice,
+		&i.FilledQuantity,
+		&i.RemainingQuantity,
+		&i.AverageFillPrice,
+		&i.Version,
+	)
+	return i, err
+}
+
+const listOpenTradesByAccount = `-- name: ListOpenTradesByAccount :many
+  SELECT trade_uuid, account_uuid, symbol, quantity, side, price, status, created_date, updated_date, created_by, updated_by, order_type, time_in_force, stop_price, filled_quantity, remaining_quantity, average_fill_price, version 
+    FROM trade
+   WHERE account_uuid = $1
+     AND status IN ('SUBMITTED'::trade_status, 'PARTIALLY_FILLED'::trade_status)
+ORDER BY created_date
+`
+
+func (q *Queries) ListOpenTradesByAccount(ctx context.Context, accountUuid uuid.UUID) ([]Trade, error) {
+	rows, err := q.db.QueryContext(ctx, listOpenTradesByAccount, accountUuid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Trade
+	for rows.Next() {
+		var i Trade
+		if err := rows.Scan(
+			&i.TradeUuid,
+			&i.AccountUuid,
+			&i.Symbol,
+			&i.Quantity,
+			&i.Side,
+			&i.Price,
+			&i.Status,
+			&i.CreatedDate,
+			&i.UpdatedDate,
+			&i.CreatedBy,
+			&i.UpdatedBy,
+			&i.OrderType,
+			&i.TimeInForce,
+			&i.StopPrice,
+			&i.FilledQuantity,
+			&i.RemainingQuantity,
+			&i.AverageFillPrice,
+			&i.Version,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listTradesByAccount = `-- name: ListTradesByAccount :many
-  SELECT trade_uuid, account_uuid, symbol, quantity, side, price, status, created_date, updated_date, created_by, updated_by 
+  SELECT trade_uuid, account_uuid, symbol, quantity, side, price, status, created_date, updated_date, created_by, updated_by, order_type, time_in_force, stop_price, filled_quantity, remaining_quantity, average_fill_price, version 
     FROM trade
    WHERE account_uuid = $1
 ORDER BY created_date
@@ -101,6 +286,220 @@ func (q *Queries) ListTradesByAccount(ctx context.Context, accountUuid uuid.UUID
 			&i.UpdatedDate,
 			&i.CreatedBy,
 			&i.UpdatedBy,
+			&i.OrderType,
+			&i.TimeInForce,
+			&i.StopPrice,
+			&i.FilledQuantity,
+			&i.RemainingQuantity,
+			&i.AverageFillPrice,
+			&i.Version,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTradesByAccountAsc = `-- name: ListTradesByAccountAsc :many
+  SELECT trade_uuid, account_uuid, symbol, quantity, side, price, status, created_date, updated_date, created_by, updated_by, order_type, time_in_force, stop_price, filled_quantity, remaining_quantity, average_fill_price, version 
+    FROM trade
+   WHERE account_uuid = $1
+     AND ($2::text = '' OR status::text = $2::text)
+     AND ($3::text = '' OR side::text = $3::text)
+     AND ($4::text = '' OR symbol = $4::text)
+     AND ($5::timestamp IS NULL OR created_date >= $5::timestamp)
+     AND ($6::timestamp IS NULL OR created_date < $6::timestamp)
+     AND ($7::timestamp IS NULL 
+          OR (created_date, trade_uuid) > ($7::timestamp, $8::uuid))
+ORDER BY created_date ASC, trade_uuid ASC
+   LIMIT $9
+`
+
+type ListTradesByAccountAscParams struct {
+	AccountUuid      uuid.UUID    `json:"account_uuid"`
+	Status           string       `json:"status"`
+	Side             string       `json:"side"`
+	Symbol           string       `json:"symbol"`
+	CreatedFrom      sql.NullTime `json:"created_from"`
+	CreatedTo        sql.NullTime `json:"created_to"`
+	AfterCreatedDate sql.NullTime `json:"after_created_date"`
+	AfterTradeUuid   uuid.UUID    `json:"after_trade_uuid"`
+	PageSize         int32        `json:"page_size"`
+}
+
+func (q *Queries) ListTradesByAccountAsc(ctx context.Context, arg ListTradesByAccountAscParams) ([]Trade, error) {
+	rows, err := q.db.QueryContext(ctx, listTradesByAccountAsc,
+		arg.AccountUuid,
+		arg.Status,
+		arg.Side,
+		arg.Symbol,
+		arg.CreatedFrom,
+		arg.CreatedTo,
+		arg.AfterCreatedDate,
+		arg.AfterTradeUuid,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Trade
+	for rows.Next() {
+		var i Trade
+		if err := rows.Scan(
+			&i.TradeUuid,
+			&i.AccountUuid,
+			&i.Symbol,
+			&i.Quantity,
+			&i.Side,
+			&i.Price,
+			&i.Status,
+			&i.CreatedDate,
+			&i.UpdatedDate,
+			&i.CreatedBy,
+			&i.UpdatedBy,
+			&i.OrderType,
+			&i.TimeInForce,
+			&i.StopPrice,
+			&i.FilledQuantity,
+			&i.RemainingQuantity,
+			&i.AverageFillPrice,
+			&i.Version,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTradesByAccountDesc = `-- name: ListTradesByAccountDesc :many
+  SELECT trade_uuid, account_uuid, symbol, quantity, side, price, status, created_date, updated_date, created_by, updated_by, order_type, time_in_force, stop_price, filled_quantity, remaining_quantity, average_fill_price, version 
+    FROM trade
+   WHERE account_uuid = $1
+     AND ($2::text = '' OR status::text = $2::text)
+     AND ($3::text = '' OR side::text = $3::text)
+     AND ($4::text = '' OR symbol = $4::text)
+     AND ($5::timestamp IS NULL OR created_date >= $5::timestamp)
+     AND ($6::timestamp IS NULL OR created_date < $6::timestamp)
+     AND ($7::timestamp IS NULL 
+          OR (created_date, trade_uuid) < ($7::timestamp, $8::uuid))
+ORDER BY created_date DESC, trade_uuid DESC
+   LIMIT $9
+`
+
+type ListTradesByAccountDescParams struct {
+	AccountUuid      uuid.UUID    `json:"account_uuid"`
+	Status           string       `json:"status"`
+	Side             string       `json:"side"`
+	Symbol           string       `json:"symbol"`
+	CreatedFrom      sql.NullTime `json:"created_from"`
+	CreatedTo        sql.NullTime `json:"created_to"`
+	AfterCreatedDate sql.NullTime `json:"after_created_date"`
+	AfterTradeUuid   uuid.UUID    `json:"after_trade_uuid"`
+	PageSize         int32        `json:"page_size"`
+}
+
+func (q *Queries) ListTradesByAccountDesc(ctx context.Context, arg ListTradesByAccountDescParams) ([]Trade, error) {
+	rows, err := q.db.QueryContext(ctx, listTradesByAccountDesc,
+		arg.AccountUuid,
+		arg.Status,
+		arg.Side,
+		arg.Symbol,
+		arg.CreatedFrom,
+		arg.CreatedTo,
+		arg.AfterCreatedDate,
+		arg.AfterTradeUuid,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Trade
+	for rows.Next() {
+		var i Trade
+		if err := rows.Scan(
+			&i.TradeUuid,
+			&i.AccountUuid,
+			&i.Symbol,
+			&i.Quantity,
+			&i.Side,
+			&i.Price,
+			&i.Status,
+			&i.CreatedDate,
+			&i.UpdatedDate,
+			&i.CreatedBy,
+			&i.UpdatedBy,
+			&i.OrderType,
+			&i.TimeInForce,
+			&i.StopPrice,
+			&i.FilledQuantity,
+			&i.RemainingQuantity,
+			&i.AverageFillPrice,
+			&i.Version,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTradesByStatus = `-- name: ListTradesByStatus :many
+  SELECT trade_uuid, account_uuid, symbol, quantity, side, price, status, created_date, updated_date, created_by, updated_by, order_type, time_in_force, stop_price, filled_quantity, remaining_quantity, average_fill_price, version 
+    FROM trade
+   WHERE status = $1::trade_status
+ORDER BY created_date
+`
+
+func (q *Queries) ListTradesByStatus(ctx context.Context, status TradeStatus) ([]Trade, error) {
+	rows, err := q.db.QueryContext(ctx, listTradesByStatus, status)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Trade
+	for rows.Next() {
+		var i Trade
+		if err := rows.Scan(
+			&i.TradeUuid,
+			&i.AccountUuid,
+			&i.Symbol,
+			&i.Quantity,
+			&i.Side,
+			&i.Price,
+			&i.Status,
+			&i.CreatedDate,
+			&i.UpdatedDate,
+			&i.CreatedBy,
+			&i.UpdatedBy,
+			&i.OrderType,
+			&i.TimeInForce,
+			&i.StopPrice,
+			&i.FilledQuantity,
+			&i.RemainingQuantity,
+			&i.AverageFillPrice,
+			&i.Version,
 		); err != nil {
 			return nil, err
 		}
@@ -119,21 +518,27 @@ const updateTrade = `-- name: UpdateTrade :one
 UPDATE trade 
    SET symbol = $1, 
        quantity = $2,
+       remaining_quantity = $2 - filled_quantity,
        side = $3::trade_side,
        price = $4, 
-       status = $5::trade_status,
+       stop_price = $5,
+       status = $6::trade_status,
+       version = version + 1,
+       updated_by = $7,
        updated_date = now()
- WHERE trade_uuid = $6
- RETURNING trade_uuid, account_uuid, symbol, quantity, side, price, status, created_date, updated_date, created_by, updated_by
+ WHERE trade_uuid = $8
+ RETURNING trade_uuid, account_uuid, symbol, quantity, side, price, status, created_date, updated_date, created_by, updated_by, order_type, time_in_force, stop_price, filled_quantity, remaining_quantity, average_fill_price, version
 `
 
 type UpdateTradeParams struct {
-	Symbol    string      `json:"symbol"`
-	Quantity  int64       `json:"quantity"`
-	Side      TradeSide   `json:"side"`
-	Price     float64     `json:"price"`
-	Status    TradeStatus `json:"status"`
-	TradeUuid uuid.UUID   `json:"trade_uuid"`
+	Symbol    string              `json:"symbol"`
+	Quantity  int64               `json:"quantity"`
+	Side      TradeSide           `json:"side"`
+	Price     decimal.NullDecimal `json:"price"`
+	StopPrice decimal.NullDecimal `json:"stop_price"`
+	Status    TradeStatus         `json:"status"`
+	UpdatedBy sql.NullString      `json:"updated_by"`
+	TradeUuid uuid.UUID           `json:"trade_uuid"`
 }
 
 func (q *Queries) UpdateTrade(ctx context.Context, arg UpdateTradeParams) (Trade, error) {
@@ -142,7 +547,9 @@ func (q *Queries) UpdateTrade(ctx context.Context, arg UpdateTradeParams) (Trade
 		arg.Quantity,
 		arg.Side,
 		arg.Price,
+		arg.StopPrice,
 		arg.Status,
+		arg.UpdatedBy,
 		arg.TradeUuid,
 	)
 	var i Trade
@@ -158,6 +565,13 @@ func (q *Queries) UpdateTrade(ctx context.Context, arg UpdateTradeParams) (Trade
 		&i.UpdatedDate,
 		&i.CreatedBy,
 		&i.UpdatedBy,
+		&i.OrderType,
+		&i.TimeInForce,
+		&i.StopPrice,
+		&i.FilledQuantity,
+		&i.RemainingQuantity,
+		&i.AverageFillPrice,
+		&i.Version,
 	)
 	return i, err
 }
@@ -165,18 +579,74 @@ func (q *Queries) UpdateTrade(ctx context.Context, arg UpdateTradeParams) (Trade
 const updateTradeStatus = `-- name: UpdateTradeStatus :one
 UPDATE trade 
    SET status = $1::trade_status,
+       updated_by = $2,
        updated_date = now()
- WHERE trade_uuid = $2
- RETURNING trade_uuid, account_uuid, symbol, quantity, side, price, status, created_date, updated_date, created_by, updated_by
+ WHERE trade_uuid = $3
+ RETURNING trade_uuid, account_uuid, symbol, quantity, side, price, status, created_date, updated_date, created_by, updated_by, order_type, time_in_force, stop_price, filled_quantity, remaining_quantity, average_fill_price, version
 `
 
 type UpdateTradeStatusParams struct {
-	Status    TradeStatus `json:"status"`
-	TradeUuid uuid.UUID   `json:"trade_uuid"`
+	Status    TradeStatus    `json:"status"`
+	UpdatedBy sql.NullString `json:"updated_by"`
+	TradeUuid uuid.UUID      `json:"trade_uuid"`
 }
 
 func (q *Queries) UpdateTradeStatus(ctx context.Context, arg UpdateTradeStatusParams) (Trade, error) {
-	row := q.db.QueryRowContext(ctx, updateTradeStatus, arg.Status, arg.TradeUuid)
+	row := q.db.QueryRowContext(ctx, updateTradeStatus, arg.Status, arg.UpdatedBy, arg.TradeUuid)
+	var i Trade
+	err := row.Scan(
+		&i.TradeUuid,
+		&i.AccountUuid,
+		&i.Symbol,
+		&i.Quantity,
+		&i.Side,
+		&i.Price,
+		&i.Status,
+		&i.CreatedDate,
+		&i.UpdatedDate,
+		&i.CreatedBy,
+		&i.UpdatedBy,
+		&i.OrderType,
+		&i.TimeInForce,
+		&i.StopPrice,
+		&i.FilledQuantity,
+		&i.RemainingQuantity,
+		&i.AverageFillPrice,
+		&i.Version,
+	)
+	return i, err
+}
+
+const updateTradeFill = `-- name: UpdateTradeFill :one
+UPDATE trade 
+   SET filled_quantity = $1,
+       remaining_quantity = $2,
+       average_fill_price = $3,
+       status = $4::trade_status,
+       updated_by = $5,
+       updated_date = now()
+ WHERE trade_uuid = $6
+ RETURNING trade_uuid, account_uuid, symbol, quantity, side, price, status, created_date, updated_date, created_by, updated_by, order_type, time_in_force, stop_price, filled_quantity, remaining_quantity, average_fill_price, version
+`
+
+type UpdateTradeFillParams struct {
+	FilledQuantity    int64               `json:"filled_quantity"`
+	RemainingQuantity int64               `json:"remaining_quantity"`
+	AverageFillPrice  decimal.NullDecimal `json:"average_fill_price"`
+	Status            TradeStatus         `json:"status"`
+	UpdatedBy         sql.NullString      `json:"updated_by"`
+	TradeUuid         uuid.UUID           `json:"trade_uuid"`
+}
+
+func (q *Queries) UpdateTradeFill(ctx context.Context, arg UpdateTradeFillParams) (Trade, error) {
+	row := q.db.QueryRowContext(ctx, updateTradeFill,
+		arg.FilledQuantity,
+		arg.RemainingQuantity,
+		arg.AverageFillPrice,
+		arg.Status,
+		arg.UpdatedBy,
+		arg.TradeUuid,
+	)
 	var i Trade
 	err := row.Scan(
 		&i.TradeUuid,
@@ -190,6 +660,13 @@ func (q *Queries) UpdateTradeStatus(ctx context.Context, arg UpdateTradeStatusPa
 		&i.UpdatedDate,
 		&i.CreatedBy,
 		&i.UpdatedBy,
+		&i.OrderType,
+		&i.TimeInForce,
+		&i.StopPrice,
+		&i.FilledQuantity,
+		&i.RemainingQuantity,
+		&i.AverageFillPrice,
+		&i.Version,
 	)
 	return i, err
 }
@@ -0,0 +1,106 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/valverdethiago/trading-api/util"
+)
+
+func createRandomTradeExecution(t *testing.T, trade Trade) TradeExecution {
+	arg := CreateTradeExecutionParams{
+		TradeUuid: trade.TradeUuid,
+		Quantity:  util.RandomInt(1, 10),
+		Price:     util.RandomPrice(1, 1000),
+		Fee:       util.RandomPrice(0, 10),
+	}
+
+	execution, err := testQueries.CreateTradeExecution(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotEmpty(t, execution.ExecutionUuid)
+	require.Equal(t, arg.TradeUuid, execution.TradeUuid)
+	require.Equal(t, arg.Quantity, execution.Quantity)
+	require.True(t, arg.Price.Equal(execution.Price))
+	require.True(t, arg.Fee.Equal(execution.Fee))
+	require.NotZero(t, execution.ExecutedDate)
+	return execution
+}
+
+func TestCreateTradeExecution(t *testing.T) {
+	account := createRandomAccount(t)
+	trade := createRandomTrade(t, account)
+	createRandomTradeExecution(t, trade)
+}
+
+func TestListTradeExecutions(t *testing.T) {
+	account := createRandomAccount(t)
+	trade := createRandomTrade(t, account)
+	otherTrade := createRandomTrade(t, account)
+	for i := 0; i < 3; i++ {
+		createRandomTradeExecution(t, trade)
+	}
+	createRandomTradeExecution(t, otherTrade)
+
+	executions, err := testQueries.ListTradeExecutions(context.Background(), trade.TradeUuid)
+	require.NoError(t, err)
+	require.Len(t, executions, 3)
+	for i, execution := range executions {
+		require.Equal(t, trade.TradeUuid, execution.TradeUuid)
+		if i > 0 {
+			require.False(t, execution.ExecutedDate.Before(executions[i-1].ExecutedDate))
+		}
+	}
+}
+
+func TestListAccountFills(t *testing.T) {
+	account := createRandomAccount(t)
+	trade := createRandomTrade(t, account)
+	otherTrade := createRandomTrade(t, createRandomAccount(t))
+	executions := make(map[uuid.UUID]TradeExecution)
+	for i := 0; i < 3; i++ {
+		execution := createRandomTradeExecution(t, trade)
+		executions[execution.ExecutionUuid] = execution
+	}
+	createRandomTradeExecution(t, otherTrade)
+
+	fills, err := testQueries.ListAccountFills(context.Background(), ListAccountFillsParams{
+		AccountUuid: account.AccountUuid,
+	})
+	require.NoError(t, err)
+	require.Len(t, fills, 3)
+	for i, fill := range fills {
+		require.Contains(t, executions, fill.ExecutionUuid)
+		require.True(t, executions[fill.ExecutionUuid].Fee.Equal(fill.Fee))
+		require.Equal(t, trade.TradeUuid, fill.TradeUuid)
+		require.Equal(t, trade.Symbol, fill.Symbol)
+		require.Equal(t, trade.Side, fill.Side)
+		if i > 0 {
+			require.False(t, fill.ExecutedDate.Before(fills[i-1].ExecutedDate))
+		}
+	}
+
+	fills, err = testQueries.ListAccountFills(context.Background(), ListAccountFillsParams{
+		AccountUuid: account.AccountUuid,
+		ExecutedTo:  sql.NullTime{Time: fills[0].ExecutedDate, Valid: true},
+	})
+	require.NoError(t, err)
+	require.Empty(t, fills)
+}
+
+func TestListLatestPrices(t *testing.T) {
+	account := createRandomAccount(t)
+	trade := createRandomTrade(t, account)
+	createRandomTradeExecution(t, trade)
+	latest := createRandomTradeExecution(t, trade)
+
+	prices, err := testQueries.ListLatestPrices(context.Background(), ListLatestPricesParams{
+		Symbols: []string{trade.Symbol, util.RandomString(8)},
+	})
+	require.NoError(t, err)
+	require.Len(t, prices, 1)
+	require.Equal(t, trade.Symbol, prices[0].Symbol)
+	require.False(t, prices[0].ExecutedDate.Before(latest.ExecutedDate))
+}
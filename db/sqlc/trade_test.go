@@ -4,6 +4,7 @@ import (
 	"context"
 	"testing"
 
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/require"
 	"github.com/valverdethiago/trading-api/util"
 )
@@ -14,7 +15,9 @@ func createRandomTrade(t *testing.T, account Account) Trade {
 		Symbol:      util.RandomString(3),
 		Quantity:    util.RandomInt(1, 1000),
 		Side:        TradeSideBUY,
-		Price:       util.RandomFloat(1, 1000),
+		Price:       decimal.NullDecimal{Decimal: util.RandomPrice(1, 1000), Valid: true},
+		OrderType:   OrderTypeLIMIT,
+		TimeInForce: TimeInForceGTC,
 	}
 
 	trade, err := testQueries.CreateTrade(context.Background(), arg)
@@ -24,7 +27,13 @@ func createRandomTrade(t *testing.T, account Account) Trade {
 	require.Equal(t, arg.Symbol, trade.Symbol)
 	require.Equal(t, arg.Quantity, trade.Quantity)
 	require.Equal(t, arg.Side, trade.Side)
-	require.Equal(t, arg.Price, trade.Price)
+	require.True(t, arg.Price.Decimal.Equal(trade.Price.Decimal))
+	require.Equal(t, arg.OrderType, trade.OrderType)
+	require.Equal(t, arg.TimeInForce, trade.TimeInForce)
+	require.False(t, trade.StopPrice.Valid)
+	require.Zero(t, trade.FilledQuantity)
+	require.Equal(t, arg.Quantity, trade.RemainingQuantity)
+	require.False(t, trade.AverageFillPrice.Valid)
 	require.Equal(t, trade.Status, TradeStatusSUBMITTED)
 	return trade
 }
@@ -57,6 +66,97 @@ func TestListTradesFromAccount(t *testing.T) {
 		require.NotEmpty(t, trade.TradeUuid)
 	}
 }
+func TestListTradesByAccountAscPages(t *testing.T) {
+	account := createRandomAccount(t)
+	for i := 0; i < 5; i++ {
+		createRandomTrade(t, account)
+	}
+
+	arg := ListTradesByAccountAscParams{
+		AccountUuid: account.AccountUuid,
+		PageSize:    2,
+	}
+	var all []Trade
+	for {
+		page, err := testQueries.ListTradesByAccountAsc(context.Background(), arg)
+		require.NoError(t, err)
+		if len(page) == 0 {
+			break
+		}
+		require.LessOrEqual(t, len(page), 2)
+		all = append(all, page...)
+		last := page[len(page)-1]
+		arg.AfterCreatedDate = last.CreatedDate
+		arg.AfterTradeUuid = last.TradeUuid
+	}
+	require.Len(t, all, 5)
+	for i := 1; i < len(all); i++ {
+		require.False(t, all[i].CreatedDate.Time.Before(all[i-1].CreatedDate.Time))
+		require.NotEqual(t, all[i].TradeUuid, all[i-1].TradeUuid)
+	}
+}
+
+func TestListTradesByAccountDescFiltered(t *testing.T) {
+	account := createRandomAccount(t)
+	var last Trade
+	for i := 0; i < 3; i++ {
+		last = createRandomTrade(t, account)
+	}
+
+	trades, err := testQueries.ListTradesByAccountDesc(context.Background(), ListTradesByAccountDescParams{
+		AccountUuid: account.AccountUuid,
+		Status:      string(TradeStatusSUBMITTED),
+		Side:        string(TradeSideBUY),
+		PageSize:    10,
+	})
+	require.NoError(t, err)
+	require.Len(t, trades, 3)
+	require.Equal(t, last.TradeUuid, trades[0].TradeUuid)
+
+	trades, err = testQueries.ListTradesByAccountDesc(context.Background(), ListTradesByAccountDescParams{
+		AccountUuid: account.AccountUuid,
+		Side:        string(TradeSideSELL),
+		PageSize:    10,
+	})
+	require.NoError(t, err)
+	require.Empty(t, trades)
+
+	trades, err = testQueries.ListTradesByAccountDesc(context.Background(), ListTradesByAccountDescParams{
+		AccountUuid: account.AccountUuid,
+		Symbol:      last.Symbol,
+		PageSize:    10,
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, trades)
+	for _, trade := range trades {
+		require.Equal(t, last.Symbol, trade.Symbol)
+	}
+}
+
+func TestListOpenTradesByAccount(t *testing.T) {
+	account := createRandomAccount(t)
+	submittedTrade := createRandomTrade(t, account)
+	partiallyFilledTrade := createRandomTrade(t, account)
+	_, err := testQueries.UpdateTradeStatus(context.Background(), UpdateTradeStatusParams{
+		Status:    TradeStatusPARTIALLY_FILLED,
+		TradeUuid: partiallyFilledTrade.TradeUuid,
+	})
+	require.NoError(t, err)
+	failedTrade := createRandomTrade(t, account)
+	_, err = testQueries.UpdateTradeStatus(context.Background(), UpdateTradeStatusParams{
+		Status:    TradeStatusFAILED,
+		TradeUuid: failedTrade.TradeUuid,
+	})
+	require.NoError(t, err)
+	createRandomTrade(t, createRandomAccount(t))
+
+	trades, err := testQueries.ListOpenTradesByAccount(context.Background(), account.AccountUuid)
+	require.NoError(t, err)
+	require.Len(t, trades, 2)
+	require.Equal(t, submittedTrade.TradeUuid, trades[0].TradeUuid)
+	require.Equal(t, partiallyFilledTrade.TradeUuid, trades[1].TradeUuid)
+}
+
 func TestGetTradeById(t *testing.T) {
 	account := createRandomAccount(t)
 	trade := createRandomTrade(t, account)
@@ -83,7 +183,7 @@ func TestUpdateTrade(t *testing.T) {
 		Symbol:    util.RandomAlphaNumericString(3),
 		Side:      TradeSideSELL,
 		Quantity:  trade.Quantity + 1,
-		Price:     trade.Price + 1,
+		Price:     decimal.NullDecimal{Decimal: trade.Price.Decimal.Add(decimal.NewFromInt(1)), Valid: true},
 		Status:    TradeStatusFAILED,
 		TradeUuid: trade.TradeUuid,
 	}
@@ -98,6 +198,8 @@ func TestUpdateTrade(t *testing.T) {
 	require.NotEqual(t, trade.Quantity, dbTrade.Quantity)
 	require.NotEqual(t, trade.Side, dbTrade.Side)
 	require.Equal(t, trade.TradeUuid, dbTrade.TradeUuid)
+	require.Equal(t, arg.Quantity-trade.FilledQuantity, dbTrade.RemainingQuantity)
+	require.Equal(t, trade.Version+1, dbTrade.Version)
 }
 func TestUpdateTradeStatus(t *testing.T) {
 	account := createRandomAccount(t)
@@ -120,3 +222,66 @@ func TestUpdateTradeStatus(t *testing.T) {
 	require.Equal(t, trade.TradeUuid, dbTrade.TradeUuid)
 	require.Equal(t, dbTrade.Status, TradeStatusCANCELLED)
 }
+
+func TestUpdateTradeFill(t *testing.T) {
+	account := createRandomAccount(t)
+	trade := createRandomTrade(t, account)
+
+	arg := UpdateTradeFillParams{
+		FilledQuantity:    1,
+		RemainingQuantity: trade.Quantity - 1,
+		AverageFillPrice:  trade.Price,
+		Status:            TradeStatusPARTIALLY_FILLED,
+		TradeUuid:         trade.TradeUuid,
+	}
+
+	dbTrade, err := testQueries.UpdateTradeFill(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.FilledQuantity, dbTrade.FilledQuantity)
+	require.Equal(t, arg.RemainingQuantity, dbTrade.RemainingQuantity)
+	require.True(t, arg.AverageFillPrice.Decimal.Equal(dbTrade.AverageFillPrice.Decimal))
+	require.Equal(t, arg.Status, dbTrade.Status)
+	require.Equal(t, trade.Quantity, dbTrade.Quantity)
+}
+
+func TestGetOpenBuyNotional(t *testing.T) {
+	account := createRandomAccount(t)
+	trade := createRandomTrade(t, account)
+	cancelled := createRandomTrade(t, account)
+	_, err := testQueries.UpdateTradeStatus(context.Background(), UpdateTradeStatusParams{
+		Status:    TradeStatusCANCELLED,
+		TradeUuid: cancelled.TradeUuid,
+	})
+	require.NoError(t, err)
+	// market buys are valued at the last price of their symbol, nothing when it never traded
+	traded := createRandomTrade(t, createRandomAccount(t))
+	execution := createRandomTradeExecution(t, traded)
+	for _, symbol := range []string{traded.Symbol, util.RandomString(4)} {
+		_, err = testQueries.CreateTrade(context.Background(), CreateTradeParams{
+			AccountUuid: account.AccountUuid,
+			Symbol:      symbol,
+			Quantity:    10,
+			Side:        TradeSideBUY,
+			OrderType:   OrderTypeMARKET,
+			TimeInForce: TimeInForceIOC,
+		})
+		require.NoError(t, err)
+	}
+
+	notional, err := testQueries.GetOpenBuyNotional(context.Background(), account.AccountUuid)
+	require.NoError(t, err)
+	expected := trade.Price.Decimal.Mul(decimal.NewFromInt(trade.RemainingQuantity)).Add(execution.Price.Mul(decimal.NewFromInt(10)))
+	require.True(t, expected.Equal(notional), "expected %s, got %s", expected, notional)
+}
+
+func TestGetOpenSellQuantity(t *testing.T) {
+	account := createRandomAccount(t)
+	createRandomTrade(t, account)
+
+	quantity, err := testQueries.GetOpenSellQuantity(context.Background(), GetOpenSellQuantityParams{
+		AccountUuid: account.AccountUuid,
+		Symbol:      util.RandomString(3),
+	})
+	require.NoError(t, err)
+	require.Zero(t, quantity)
+}
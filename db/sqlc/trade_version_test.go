@@ -0,0 +1,60 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomTradeVersion(t *testing.T, trade Trade) TradeVersion {
+	arg := CreateTradeVersionParams{
+		TradeUuid: trade.TradeUuid,
+		Version:   trade.Version,
+		Quantity:  trade.Quantity,
+		Price:     trade.Price,
+		StopPrice: trade.StopPrice,
+	}
+
+	version, err := testQueries.CreateTradeVersion(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.TradeUuid, version.TradeUuid)
+	require.Equal(t, arg.Version, version.Version)
+	require.Equal(t, arg.Quantity, version.Quantity)
+	require.True(t, arg.Price.Decimal.Equal(version.Price.Decimal))
+	require.Equal(t, arg.StopPrice.Valid, version.StopPrice.Valid)
+	require.NotZero(t, version.CreatedDate)
+	return version
+}
+
+func TestCreateTradeVersion(t *testing.T) {
+	account := createRandomAccount(t)
+	trade := createRandomTrade(t, account)
+	require.Equal(t, int32(1), trade.Version)
+	createRandomTradeVersion(t, trade)
+}
+
+func TestListTradeVersions(t *testing.T) {
+	account := createRandomAccount(t)
+	trade := createRandomTrade(t, account)
+	createRandomTradeVersion(t, trade)
+
+	amended, err := testQueries.UpdateTrade(context.Background(), UpdateTradeParams{
+		Symbol:    trade.Symbol,
+		Quantity:  trade.Quantity + 1,
+		Side:      trade.Side,
+		Price:     trade.Price,
+		StopPrice: trade.StopPrice,
+		Status:    trade.Status,
+		TradeUuid: trade.TradeUuid,
+	})
+	require.NoError(t, err)
+	createRandomTradeVersion(t, amended)
+
+	versions, err := testQueries.ListTradeVersions(context.Background(), trade.TradeUuid)
+	require.NoError(t, err)
+	require.Len(t, versions, 2)
+	require.Equal(t, trade.Quantity, versions[0].Quantity)
+	require.Equal(t, amended.Quantity, versions[1].Quantity)
+	require.Equal(t, int32(2), versions[1].Version)
+}
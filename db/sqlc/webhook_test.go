@@ -0,0 +1,159 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomWebhookSubscription(t *testing.T, account Account) WebhookSubscription {
+	arg := CreateWebhookSubscriptionParams{
+		AccountUuid: account.AccountUuid,
+		Url:         "https://example.com/hooks",
+		Secret:      "secret",
+		EventTypes:  []string{"trade.created", "trade.filled"},
+	}
+	subscription, err := testQueries.CreateWebhookSubscription(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotEqual(t, uuid.Nil, subscription.WebhookSubscriptionUuid)
+	require.Equal(t, arg.Url, subscription.Url)
+	require.Equal(t, arg.EventTypes, subscription.EventTypes)
+	require.True(t, subscription.Active)
+	return subscription
+}
+
+func createRandomWebhookDelivery(t *testing.T, subscription WebhookSubscription) WebhookDelivery {
+	arg := CreateWebhookDeliveryParams{
+		WebhookSubscriptionUuid: subscription.WebhookSubscriptionUuid,
+		EventUuid:               uuid.New(),
+		EventType:               "trade.created",
+		Payload:                 json.RawMessage(`{"type": "trade.created"}`),
+	}
+	delivery, err := testQueries.CreateWebhookDelivery(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.EventUuid, delivery.EventUuid)
+	require.Zero(t, delivery.Attempts)
+	require.False(t, delivery.DeliveredDate.Valid)
+	return delivery
+}
+
+func TestListWebhookSubscriptionsForEvent(t *testing.T) {
+	account := createRandomAccount(t)
+	subscription := createRandomWebhookSubscription(t, account)
+
+	subscriptions, err := testQueries.ListWebhookSubscriptionsForEvent(context.Background(), ListWebhookSubscriptionsForEventParams{
+		AccountUuid: account.AccountUuid,
+		EventType:   "trade.filled",
+	})
+	require.NoError(t, err)
+	require.Len(t, subscriptions, 1)
+	require.Equal(t, subscription.WebhookSubscriptionUuid, subscriptions[0].WebhookSubscriptionUuid)
+
+	subscriptions, err = testQueries.ListWebhookSubscriptionsForEvent(context.Background(), ListWebhookSubscriptionsForEventParams{
+		AccountUuid: account.AccountUuid,
+		EventType:   "trade.cancelled",
+	})
+	require.NoError(t, err)
+	require.Empty(t, subscriptions)
+
+	_, err = testQueries.UpdateWebhookSubscription(context.Background(), UpdateWebhookSubscriptionParams{
+		Url:                     subscription.Url,
+		EventTypes:              subscription.EventTypes,
+		Active:                  false,
+		WebhookSubscriptionUuid: subscription.WebhookSubscriptionUuid,
+	})
+	require.NoError(t, err)
+	subscriptions, err = testQueries.ListWebhookSubscriptionsForEvent(context.Background(), ListWebhookSubscriptionsForEventParams{
+		AccountUuid: account.AccountUuid,
+		EventType:   "trade.filled",
+	})
+	require.NoError(t, err)
+	require.Empty(t, subscriptions)
+}
+
+func TestClaimWebhookDeliveries(t *testing.T) {
+	account := createRandomAccount(t)
+	subscription := createRandomWebhookSubscription(t, account)
+	delivery := createRandomWebhookDelivery(t, subscription)
+
+	claimed := requireClaimedDelivery(t, delivery, true)
+	require.Equal(t, subscription.Url, claimed.Url)
+	require.Equal(t, subscription.Secret, claimed.Secret)
+	// the lease keeps the delivery off the other claims
+	requireClaimedDelivery(t, delivery, false)
+
+	err := testQueries.RetryWebhookDelivery(context.Background(), RetryWebhookDeliveryParams{
+		ResponseStatus:      sql.NullInt32{Int32: 500, Valid: true},
+		LastError:           sql.NullString{String: "unexpected status 500", Valid: true},
+		DelaySeconds:        0,
+		WebhookDeliveryUuid: delivery.WebhookDeliveryUuid,
+	})
+	require.NoError(t, err)
+	claimed = requireClaimedDelivery(t, delivery, true)
+	require.Equal(t, int32(1), claimed.Attempts)
+
+	err = testQueries.MarkWebhookDelivered(context.Background(), MarkWebhookDeliveredParams{
+		ResponseStatus:      sql.NullInt32{Int32: 200, Valid: true},
+		WebhookDeliveryUuid: delivery.WebhookDeliveryUuid,
+	})
+	require.NoError(t, err)
+	requireClaimedDelivery(t, delivery, false)
+}
+
+func TestWebhookDeadLetter(t *testing.T) {
+	account := createRandomAccount(t)
+	subscription := createRandomWebhookSubscription(t, account)
+	delivery := createRandomWebhookDelivery(t, subscription)
+
+	deadLetter, err := testQueries.CreateWebhookDeadLetter(context.Background(), CreateWebhookDeadLetterParams{
+		WebhookSubscriptionUuid: subscription.WebhookSubscriptionUuid,
+		EventUuid:               delivery.EventUuid,
+		EventType:               delivery.EventType,
+		Payload:                 delivery.Payload,
+		Attempts:                8,
+		LastError:               "connection refused",
+	})
+	require.NoError(t, err)
+	require.False(t, deadLetter.ResponseStatus.Valid)
+	require.False(t, deadLetter.ReplayedDate.Valid)
+	require.NoError(t, testQueries.DeleteWebhookDelivery(context.Background(), delivery.WebhookDeliveryUuid))
+
+	deadLetters, err := testQueries.ListWebhookDeadLetters(context.Background(), ListWebhookDeadLettersParams{
+		WebhookSubscriptionUuid: subscription.WebhookSubscriptionUuid,
+		Limit:                   10,
+	})
+	require.NoError(t, err)
+	require.Len(t, deadLetters, 1)
+	require.Equal(t, deadLetter.WebhookDeadLetterUuid, deadLetters[0].WebhookDeadLetterUuid)
+
+	replayed, err := testQueries.MarkWebhookDeadLetterReplayed(context.Background(), deadLetter.WebhookDeadLetterUuid)
+	require.NoError(t, err)
+	require.True(t, replayed.ReplayedDate.Valid)
+	_, err = testQueries.MarkWebhookDeadLetterReplayed(context.Background(), deadLetter.WebhookDeadLetterUuid)
+	require.Equal(t, sql.ErrNoRows, err)
+
+	require.NoError(t, testQueries.DeleteWebhookSubscription(context.Background(), subscription.WebhookSubscriptionUuid))
+	_, err = testQueries.GetWebhookDeadLetter(context.Background(), deadLetter.WebhookDeadLetterUuid)
+	require.Equal(t, sql.ErrNoRows, err)
+}
+
+// requireClaimedDelivery claims the due deliveries, checking whether the given one is among them
+func requireClaimedDelivery(t *testing.T, delivery WebhookDelivery, expected bool) ClaimWebhookDeliveriesRow {
+	claimed, err := testQueries.ClaimWebhookDeliveries(context.Background(), ClaimWebhookDeliveriesParams{
+		LeaseSeconds: 60,
+		BatchSize:    1000,
+	})
+	require.NoError(t, err)
+	for _, row := range claimed {
+		if row.WebhookDeliveryUuid == delivery.WebhookDeliveryUuid {
+			require.True(t, expected)
+			return row
+		}
+	}
+	require.False(t, expected)
+	return ClaimWebhookDeliveriesRow{}
+}
@@ -0,0 +1,50 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/valverdethiago/trading-api/execution (interfaces: Venue)
+
+// Package mockexecution is a generated GoMock package.
+package mockexecution
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	db "github.com/valverdethiago/trading-api/db/sqlc"
+)
+
+// MockVenue is a mock of Venue interface.
+type MockVenue struct {
+	ctrl     *gomock.Controller
+	recorder *MockVenueMockRecorder
+}
+
+// MockVenueMockRecorder is the mock recorder for MockVenue.
+type MockVenueMockRecorder struct {
+	mock *MockVenue
+}
+
+// NewMockVenue creates a new mock instance.
+func NewMockVenue(ctrl *gomock.Controller) *MockVenue {
+	mock := &MockVenue{ctrl: ctrl}
+	mock.recorder = &MockVenueMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockVenue) EXPECT() *MockVenueMockRecorder {
+	return m.recorder
+}
+
+// Submit mocks base method.
+func (m *MockVenue) Submit(arg0 context.Context, arg1 db.Trade) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Submit", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Submit indicates an expected call of Submit.
+func (mr *MockVenueMockRecorder) Submit(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Submit", reflect.TypeOf((*MockVenue)(nil).Submit), arg0, arg1)
+}
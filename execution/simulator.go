@@ -0,0 +1,303 @@
+package execution
+
+import (
+	"context"
+	"log"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/valverdethiago/trading-api/calendar"
+	db "github.com/valverdethiago/trading-api/db/sqlc"
+)
+
+// SimulatorConfig rules the simulated executions
+type SimulatorConfig struct {
+	// MinLatency and MaxLatency bound the random delay between two evaluations of a trade
+	MinLatency time.Duration
+	MaxLatency time.Duration
+	// FillProbability is the chance, from 0 to 1, of a trade being accepted instead of failing
+	FillProbability float64
+	// QueueSize is the number of trades waiting to be executed before Submit blocks
+	QueueSize int
+	// Liquidity is the largest quantity available at the market price on each evaluation, 0 meaning unlimited
+	Liquidity int64
+	// Volatility is the largest relative move of the simulated market price between two evaluations
+	Volatility float64
+	// ReferencePrice is the first market price of a symbol when the order doesn't bring one
+	ReferencePrice decimal.Decimal
+	// TickSize rounds the simulated market prices
+	TickSize decimal.Decimal
+	// Calendar tells the sessions trades execute in and when DAY orders expire, always open when nil
+	Calendar *calendar.Calendar
+}
+
+// Simulator is a venue that executes trades against a random walk of the market price.
+// Market orders fill right away, limit orders once the price crosses the limit and stop orders once
+// it reaches the stop price, each evaluation filling at most the available liquidity. Trades wait
+// for a session of the calendar they can execute in. IOC orders are cancelled after their first
+// evaluation, FOK orders unless fully filled by it, DAY orders at the regular close and GTC orders
+// wait until filled or cancelled.
+type Simulator struct {
+	config  SimulatorConfig
+	store   db.Querier
+	handler ReportHandler
+	trades  chan db.Trade
+	stopped chan struct{}
+	random  *rand.Rand
+	prices  map[string]decimal.Decimal
+	mutex   sync.Mutex
+}
+
+// NewSimulator creates a simulator reporting to the given handler, it executes nothing until started.
+// Trades are read again from the store before each evaluation, so cancelled trades aren't executed.
+func NewSimulator(config SimulatorConfig, store db.Querier, handler ReportHandler) *Simulator {
+	if config.MaxLatency < config.MinLatency {
+		config.MaxLatency = config.MinLatency
+	}
+	if config.Calendar == nil {
+		config.Calendar = calendar.AlwaysOpen()
+	}
+	return &Simulator{
+		config:  config,
+		store:   store,
+		handler: handler,
+		trades:  make(chan db.Trade, config.QueueSize),
+		stopped: make(chan struct{}),
+		random:  rand.New(rand.NewSource(time.Now().UnixNano())),
+		prices:  make(map[string]decimal.Decimal),
+	}
+}
+
+// Submit queues the trade for execution, waiting while the queue is full
+func (simulator *Simulator) Submit(ctx context.Context, trade db.Trade) error {
+	select {
+	case simulator.trades <- trade:
+		return nil
+	case <-simulator.stopped:
+		return ErrVenueUnavailable
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Start executes the queued trades until the context is done, it must be called only once
+func (simulator *Simulator) Start(ctx context.Context) {
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	defer close(simulator.stopped)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case trade := <-simulator.trades:
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				simulator.execute(ctx, trade)
+			}()
+		}
+	}
+}
+
+func (simulator *Simulator) execute(ctx context.Context, trade db.Trade) {
+	accepted, triggered := false, false
+	for simulator.wait(ctx) {
+		current, err := simulator.store.GetTradeById(ctx, trade.TradeUuid)
+		if err != nil {
+			log.Printf("Cannot read trade %s: %v", trade.TradeUuid, err)
+			return
+		}
+		if !IsOpen(current.Status) {
+			return
+		}
+		now := time.Now()
+		if simulator.expired(current, now) {
+			if err := simulator.report(ctx, current, db.TradeStatusCANCELLED); err != nil && simulator.retry(ctx, current, err) {
+				continue
+			}
+			return
+		}
+		if !CanExecute(current, simulator.config.Calendar.Session(now)) {
+			continue
+		}
+		if !accepted {
+			if !simulator.accepts() {
+				if err := simulator.report(ctx, current, db.TradeStatusFAILED); err != nil && simulator.retry(ctx, current, err) {
+					continue
+				}
+				return
+			}
+			accepted = true
+		}
+		market := simulator.marketPrice(current)
+		triggered = triggered || isTriggered(current, market)
+		if triggered && isMarketable(current, market) {
+			available := simulator.available(current.RemainingQuantity)
+			if current.TimeInForce == db.TimeInForceFOK && available < current.RemainingQuantity {
+				if err := simulator.report(ctx, current, db.TradeStatusCANCELLED); err != nil && simulator.retry(ctx, current, err) {
+					continue
+				}
+				return
+			}
+			if err := simulator.fill(ctx, current, available, market); err != nil {
+				if simulator.retry(ctx, current, err) {
+					continue
+				}
+				return
+			}
+			if available == current.RemainingQuantity {
+				return
+			}
+		}
+		if current.TimeInForce == db.TimeInForceIOC || current.TimeInForce == db.TimeInForceFOK {
+			if err := simulator.report(ctx, current, db.TradeStatusCANCELLED); err != nil && simulator.retry(ctx, current, err) {
+				continue
+			}
+			return
+		}
+	}
+}
+
+// retry tells whether to evaluate the trade again after its report failed. The trade is read again,
+// as it may have been cancelled or filled meanwhile: only a trade still open with a quantity left is
+// evaluated again, any other state stopping its execution
+func (simulator *Simulator) retry(ctx context.Context, trade db.Trade, err error) bool {
+	log.Printf("Cannot report execution of trade %s: %v", trade.TradeUuid, err)
+	current, err := simulator.store.GetTradeById(ctx, trade.TradeUuid)
+	if err != nil {
+		log.Printf("Cannot read trade %s: %v", trade.TradeUuid, err)
+		return false
+	}
+	if !IsOpen(current.Status) || current.RemainingQuantity <= 0 {
+		log.Printf("Stopping execution of trade %s, %s with %d left", trade.TradeUuid, current.Status, current.RemainingQuantity)
+		return false
+	}
+	return true
+}
+
+func (simulator *Simulator) fill(ctx context.Context, trade db.Trade, quantity int64, price decimal.Decimal) error {
+	status := db.TradeStatusPARTIALLY_FILLED
+	if quantity == trade.RemainingQuantity {
+		status = db.TradeStatusCOMPLETED
+	}
+	return simulator.handler.HandleReport(ctx, Report{
+		TradeUUID: trade.TradeUuid,
+		Status:    status,
+		Quantity:  quantity,
+		Price:     price,
+	})
+}
+
+func (simulator *Simulator) report(ctx context.Context, trade db.Trade, status db.TradeStatus) error {
+	return simulator.handler.HandleReport(ctx, Report{
+		TradeUUID: trade.TradeUuid,
+		Status:    status,
+	})
+}
+
+// wait sleeps for the simulated latency, returning false if the simulator is stopped meanwhile
+func (simulator *Simulator) wait(ctx context.Context) bool {
+	timer := time.NewTimer(simulator.latency())
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// expired tells whether a DAY order outlived the session it was submitted in
+func (simulator *Simulator) expired(trade db.Trade, now time.Time) bool {
+	if trade.TimeInForce != db.TimeInForceDAY || !trade.CreatedDate.Valid {
+		return false
+	}
+	closing := simulator.sessionClose(trade.CreatedDate.Time)
+	return !closing.IsZero() && !now.Before(closing)
+}
+
+// sessionClose returns the first regular close after the given time, zero if the market never closes
+func (simulator *Simulator) sessionClose(from time.Time) time.Time {
+	return simulator.config.Calendar.NextClose(from)
+}
+
+// marketPrice moves the simulated price of the trade's symbol and returns it
+func (simulator *Simulator) marketPrice(trade db.Trade) decimal.Decimal {
+	simulator.mutex.Lock()
+	defer simulator.mutex.Unlock()
+	price, ok := simulator.prices[trade.Symbol]
+	if !ok {
+		price = referencePrice(trade, simulator.config.ReferencePrice)
+	}
+	move := decimal.NewFromFloat(simulator.config.Volatility * (2*simulator.random.Float64() - 1))
+	price = price.Add(price.Mul(move))
+	if simulator.config.TickSize.IsPositive() {
+		price = price.Div(simulator.config.TickSize).Round(0).Mul(simulator.config.TickSize)
+	}
+	simulator.prices[trade.Symbol] = price
+	return price
+}
+
+func referencePrice(trade db.Trade, fallback decimal.Decimal) decimal.Decimal {
+	if trade.Price.Valid {
+		return trade.Price.Decimal
+	}
+	if trade.StopPrice.Valid {
+		return trade.StopPrice.Decimal
+	}
+	return fallback
+}
+
+// isTriggered tells whether the market reached the stop price, orders without stop are always triggered
+func isTriggered(trade db.Trade, market decimal.Decimal) bool {
+	if trade.OrderType != db.OrderTypeSTOP && trade.OrderType != db.OrderTypeSTOP_LIMIT {
+		return true
+	}
+	if trade.Side == db.TradeSideBUY {
+		return market.GreaterThanOrEqual(trade.StopPrice.Decimal)
+	}
+	return market.LessThanOrEqual(trade.StopPrice.Decimal)
+}
+
+// isMarketable tells whether the order can be filled at the market price
+func isMarketable(trade db.Trade, market decimal.Decimal) bool {
+	if trade.OrderType != db.OrderTypeLIMIT && trade.OrderType != db.OrderTypeSTOP_LIMIT {
+		return true
+	}
+	if trade.Side == db.TradeSideBUY {
+		return market.LessThanOrEqual(trade.Price.Decimal)
+	}
+	return market.GreaterThanOrEqual(trade.Price.Decimal)
+}
+
+func (simulator *Simulator) latency() time.Duration {
+	spread := int64(simulator.config.MaxLatency - simulator.config.MinLatency)
+	if spread <= 0 {
+		return simulator.config.MinLatency
+	}
+	simulator.mutex.Lock()
+	defer simulator.mutex.Unlock()
+	return simulator.config.MinLatency + time.Duration(simulator.random.Int63n(spread+1))
+}
+
+// available returns the quantity that can be filled on this evaluation, at most the remaining one
+func (simulator *Simulator) available(remaining int64) int64 {
+	if simulator.config.Liquidity <= 0 {
+		return remaining
+	}
+	simulator.mutex.Lock()
+	defer simulator.mutex.Unlock()
+	available := 1 + simulator.random.Int63n(simulator.config.Liquidity)
+	if available > remaining {
+		return remaining
+	}
+	return available
+}
+
+func (simulator *Simulator) accepts() bool {
+	simulator.mutex.Lock()
+	defer simulator.mutex.Unlock()
+	return simulator.random.Float64() < simulator.config.FillProbability
+}
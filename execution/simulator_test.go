@@ -0,0 +1,452 @@
+package execution
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+	"github.com/valverdethiago/trading-api/calendar"
+	mockdb "github.com/valverdethiago/trading-api/db/mock"
+	db "github.com/valverdethiago/trading-api/db/sqlc"
+	"github.com/valverdethiago/trading-api/util"
+)
+
+type reportRecorder struct {
+	reports chan Report
+	// trade, when set, receives the fills like the store would
+	trade *db.Trade
+	mutex sync.Mutex
+}
+
+func (recorder *reportRecorder) HandleReport(ctx context.Context, report Report) error {
+	if recorder.trade != nil {
+		recorder.mutex.Lock()
+		recorder.trade.FilledQuantity += report.Quantity
+		recorder.trade.RemainingQuantity -= report.Quantity
+		recorder.trade.Status = report.Status
+		recorder.mutex.Unlock()
+	}
+	recorder.reports <- report
+	return nil
+}
+
+func (recorder *reportRecorder) currentTrade(ctx context.Context, tradeUUID uuid.UUID) (db.Trade, error) {
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+	return *recorder.trade, nil
+}
+
+func TestSimulator(t *testing.T) {
+	testCases := []struct {
+		name            string
+		fillProbability float64
+		trade           db.Trade
+		expectedStatus  db.TradeStatus
+	}{
+		{
+			name:            "Market Filled",
+			fillProbability: 1,
+			trade:           newTestTrade(db.OrderTypeMARKET, db.TimeInForceIOC),
+			expectedStatus:  db.TradeStatusCOMPLETED,
+		}, {
+			name:            "Failed",
+			fillProbability: 0,
+			trade:           newTestTrade(db.OrderTypeMARKET, db.TimeInForceIOC),
+			expectedStatus:  db.TradeStatusFAILED,
+		}, {
+			name:            "Limit Filled",
+			fillProbability: 1,
+			trade:           withPrice(newTestTrade(db.OrderTypeLIMIT, db.TimeInForceGTC), "100"),
+			expectedStatus:  db.TradeStatusCOMPLETED,
+		}, {
+			name:            "Limit IOC Not Marketable",
+			fillProbability: 1,
+			trade:           withPrice(newTestTrade(db.OrderTypeLIMIT, db.TimeInForceIOC), "50"),
+			expectedStatus:  db.TradeStatusCANCELLED,
+		}, {
+			name:            "Limit FOK Not Marketable",
+			fillProbability: 1,
+			trade:           withPrice(newTestTrade(db.OrderTypeLIMIT, db.TimeInForceFOK), "50"),
+			expectedStatus:  db.TradeStatusCANCELLED,
+		}, {
+			name:            "Stop Triggered",
+			fillProbability: 1,
+			trade:           withStopPrice(newTestTrade(db.OrderTypeSTOP, db.TimeInForceGTC), "90"),
+			expectedStatus:  db.TradeStatusCOMPLETED,
+		}, {
+			name:            "Stop Limit Not Triggered",
+			fillProbability: 1,
+			trade:           withStopPrice(withPrice(newTestTrade(db.OrderTypeSTOP_LIMIT, db.TimeInForceIOC), "120"), "110"),
+			expectedStatus:  db.TradeStatusCANCELLED,
+		}, {
+			name:            "Day Order Expired",
+			fillProbability: 1,
+			trade:           withCreatedDate(newTestTrade(db.OrderTypeMARKET, db.TimeInForceDAY), time.Now().AddDate(0, 0, -2)),
+			expectedStatus:  db.TradeStatusCANCELLED,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().
+				GetTradeById(gomock.Any(), gomock.Eq(testCase.trade.TradeUuid)).
+				AnyTimes().
+				Return(testCase.trade, nil)
+
+			recorder := &reportRecorder{reports: make(chan Report, 1)}
+			simulator := NewSimulator(SimulatorConfig{
+				MinLatency:      time.Millisecond,
+				MaxLatency:      5 * time.Millisecond,
+				FillProbability: testCase.fillProbability,
+				QueueSize:       1,
+				TickSize:        decimal.RequireFromString("0.01"),
+			}, store, recorder)
+			simulator.prices[testCase.trade.Symbol] = decimal.NewFromInt(100)
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go simulator.Start(ctx)
+
+			submittedAt := time.Now()
+			require.NoError(t, simulator.Submit(context.Background(), testCase.trade))
+
+			select {
+			case report := <-recorder.reports:
+				require.Equal(t, testCase.trade.TradeUuid, report.TradeUUID)
+				require.Equal(t, testCase.expectedStatus, report.Status)
+				if report.Status == db.TradeStatusCOMPLETED {
+					require.Equal(t, testCase.trade.Quantity, report.Quantity)
+					require.True(t, report.Price.IsPositive())
+				} else {
+					require.Zero(t, report.Quantity)
+				}
+				require.GreaterOrEqual(t, int64(time.Since(submittedAt)), int64(time.Millisecond))
+			case <-time.After(time.Second):
+				t.Fatal("trade was not executed")
+			}
+		})
+	}
+}
+
+func TestSimulatorPartialFills(t *testing.T) {
+	testCases := []struct {
+		name           string
+		timeInForce    db.TimeInForce
+		expectedFills  int64
+		expectedStatus db.TradeStatus
+	}{
+		{
+			name:           "GTC Filled In Parts",
+			timeInForce:    db.TimeInForceGTC,
+			expectedFills:  10,
+			expectedStatus: db.TradeStatusCOMPLETED,
+		}, {
+			name:           "IOC Remainder Cancelled",
+			timeInForce:    db.TimeInForceIOC,
+			expectedFills:  -1,
+			expectedStatus: db.TradeStatusCANCELLED,
+		}, {
+			name:           "FOK Killed",
+			timeInForce:    db.TimeInForceFOK,
+			expectedFills:  0,
+			expectedStatus: db.TradeStatusCANCELLED,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			trade := newTestTrade(db.OrderTypeMARKET, testCase.timeInForce)
+			trade.Quantity, trade.RemainingQuantity = 10, 10
+			recorder := &reportRecorder{reports: make(chan Report), trade: &trade}
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().
+				GetTradeById(gomock.Any(), gomock.Eq(trade.TradeUuid)).
+				AnyTimes().
+				DoAndReturn(recorder.currentTrade)
+
+			simulator := NewSimulator(SimulatorConfig{
+				MinLatency:      time.Millisecond,
+				MaxLatency:      time.Millisecond,
+				FillProbability: 1,
+				QueueSize:       1,
+				Liquidity:       3,
+			}, store, recorder)
+			simulator.prices[trade.Symbol] = decimal.NewFromInt(100)
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go simulator.Start(ctx)
+			require.NoError(t, simulator.Submit(context.Background(), trade))
+
+			var filled int64
+			for {
+				select {
+				case report := <-recorder.reports:
+					filled += report.Quantity
+					if report.Status == db.TradeStatusPARTIALLY_FILLED {
+						require.LessOrEqual(t, report.Quantity, int64(3))
+						continue
+					}
+					require.Equal(t, testCase.expectedStatus, report.Status)
+					if testCase.expectedFills >= 0 {
+						require.Equal(t, testCase.expectedFills, filled)
+					} else {
+						require.Greater(t, filled, int64(0))
+						require.Less(t, filled, trade.Quantity)
+					}
+					return
+				case <-time.After(time.Second):
+					t.Fatal("trade was not executed")
+				}
+			}
+		})
+	}
+}
+
+func TestSimulatorSkipsCancelledTrade(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	trade := newTestTrade(db.OrderTypeMARKET, db.TimeInForceIOC)
+	cancelled := trade
+	cancelled.Status = db.TradeStatusCANCELLED
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		GetTradeById(gomock.Any(), gomock.Eq(trade.TradeUuid)).
+		Times(1).
+		Return(cancelled, nil)
+
+	recorder := &reportRecorder{reports: make(chan Report, 1)}
+	simulator := NewSimulator(SimulatorConfig{FillProbability: 1, QueueSize: 1}, store, recorder)
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		simulator.Start(ctx)
+		close(stopped)
+	}()
+
+	require.NoError(t, simulator.Submit(context.Background(), trade))
+	select {
+	case report := <-recorder.reports:
+		t.Fatalf("cancelled trade was executed with status %s", report.Status)
+	case <-time.After(50 * time.Millisecond):
+	}
+	cancel()
+	<-stopped
+}
+
+// failingHandler rejects the first reports, then hands them over to the recorder
+type failingHandler struct {
+	failures int
+	recorder *reportRecorder
+	attempts chan Report
+}
+
+func (handler *failingHandler) HandleReport(ctx context.Context, report Report) error {
+	handler.attempts <- report
+	if handler.failures > 0 {
+		handler.failures--
+		return errors.New("report rejected")
+	}
+	return handler.recorder.HandleReport(ctx, report)
+}
+
+func TestSimulatorReportFailed(t *testing.T) {
+	testCases := []struct {
+		name           string
+		status         db.TradeStatus
+		expectedReport bool
+	}{
+		{
+			name:           "Still Open",
+			status:         db.TradeStatusSUBMITTED,
+			expectedReport: true,
+		}, {
+			name:   "Cancelled Meanwhile",
+			status: db.TradeStatusCANCELLED,
+		}, {
+			name:   "Filled Meanwhile",
+			status: db.TradeStatusCOMPLETED,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			trade := newTestTrade(db.OrderTypeMARKET, db.TimeInForceIOC)
+			afterFailure := trade
+			afterFailure.Status = testCase.status
+			store := mockdb.NewMockStore(ctrl)
+			first := store.EXPECT().
+				GetTradeById(gomock.Any(), gomock.Eq(trade.TradeUuid)).
+				Times(1).
+				Return(trade, nil)
+			store.EXPECT().
+				GetTradeById(gomock.Any(), gomock.Eq(trade.TradeUuid)).
+				After(first).
+				AnyTimes().
+				Return(afterFailure, nil)
+
+			recorder := &reportRecorder{reports: make(chan Report, 1)}
+			handler := &failingHandler{failures: 1, recorder: recorder, attempts: make(chan Report, 10)}
+			simulator := NewSimulator(SimulatorConfig{
+				MinLatency:      time.Millisecond,
+				MaxLatency:      time.Millisecond,
+				FillProbability: 1,
+				QueueSize:       1,
+			}, store, handler)
+			simulator.prices[trade.Symbol] = decimal.NewFromInt(100)
+			ctx, cancel := context.WithCancel(context.Background())
+			stopped := make(chan struct{})
+			go func() {
+				simulator.Start(ctx)
+				close(stopped)
+			}()
+			require.NoError(t, simulator.Submit(context.Background(), trade))
+
+			select {
+			case report := <-recorder.reports:
+				require.True(t, testCase.expectedReport, "trade %s was executed again", testCase.status)
+				require.Equal(t, db.TradeStatusCOMPLETED, report.Status)
+			case <-time.After(50 * time.Millisecond):
+				require.False(t, testCase.expectedReport, "trade was not executed again")
+				require.Len(t, handler.attempts, 1)
+			}
+			cancel()
+			<-stopped
+		})
+	}
+}
+
+func TestSessionClose(t *testing.T) {
+	marketCalendar, err := calendar.New(calendar.Config{
+		Timezone: "America/New_York",
+		Regular:  calendar.HoursConfig{Open: "09:30", Close: "16:00"},
+	})
+	if err != nil {
+		t.Skip("timezone database not available")
+	}
+	location := marketCalendar.Location()
+	simulator := NewSimulator(SimulatorConfig{Calendar: marketCalendar}, nil, nil)
+
+	morning := time.Date(2021, time.March, 1, 10, 0, 0, 0, location)
+	require.Equal(t, time.Date(2021, time.March, 1, 16, 0, 0, 0, location), simulator.sessionClose(morning))
+
+	evening := time.Date(2021, time.March, 1, 17, 0, 0, 0, location)
+	require.Equal(t, time.Date(2021, time.March, 2, 16, 0, 0, 0, location), simulator.sessionClose(evening))
+}
+
+func newTestTrade(orderType db.OrderType, timeInForce db.TimeInForce) db.Trade {
+	quantity := util.RandomInt(1, 1000)
+	return db.Trade{
+		TradeUuid:         uuid.New(),
+		Symbol:            util.RandomString(4),
+		Quantity:          quantity,
+		RemainingQuantity: quantity,
+		Side:              db.TradeSideBUY,
+		Status:            db.TradeStatusSUBMITTED,
+		OrderType:         orderType,
+		TimeInForce:       timeInForce,
+		CreatedDate:       sql.NullTime{Time: time.Now(), Valid: true},
+	}
+}
+
+func withPrice(trade db.Trade, price string) db.Trade {
+	trade.Price = decimal.NullDecimal{Decimal: decimal.RequireFromString(price), Valid: true}
+	return trade
+}
+
+func withStopPrice(trade db.Trade, price string) db.Trade {
+	trade.StopPrice = decimal.NullDecimal{Decimal: decimal.RequireFromString(price), Valid: true}
+	return trade
+}
+
+func withCreatedDate(trade db.Trade, createdDate time.Time) db.Trade {
+	trade.CreatedDate = sql.NullTime{Time: createdDate, Valid: true}
+	return trade
+}
+
+func TestSimulatorStopped(t *testing.T) {
+	simulator := NewSimulator(SimulatorConfig{}, nil, &reportRecorder{})
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		simulator.Start(ctx)
+		close(stopped)
+	}()
+	cancel()
+	<-stopped
+
+	err := simulator.Submit(context.Background(), db.Trade{TradeUuid: uuid.New()})
+	require.ErrorIs(t, err, ErrVenueUnavailable)
+}
+
+func TestSimulatorWaitsForSession(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	trade := withPrice(newTestTrade(db.OrderTypeLIMIT, db.TimeInForceGTC), "100")
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		GetTradeById(gomock.Any(), gomock.Eq(trade.TradeUuid)).
+		AnyTimes().
+		Return(trade, nil)
+
+	closed, err := calendar.New(calendar.Config{
+		Timezone:    "UTC",
+		TradingDays: []string{},
+		Regular:     calendar.HoursConfig{Open: "09:30", Close: "16:00"},
+	})
+	require.NoError(t, err)
+	recorder := &reportRecorder{reports: make(chan Report, 1)}
+	simulator := NewSimulator(SimulatorConfig{
+		MinLatency:      time.Millisecond,
+		MaxLatency:      time.Millisecond,
+		FillProbability: 1,
+		QueueSize:       1,
+		Calendar:        closed,
+	}, store, recorder)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go simulator.Start(ctx)
+
+	require.NoError(t, simulator.Submit(context.Background(), trade))
+	select {
+	case report := <-recorder.reports:
+		t.Fatalf("trade was executed while the market is closed with status %s", report.Status)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestCanExecute(t *testing.T) {
+	limit := withPrice(newTestTrade(db.OrderTypeLIMIT, db.TimeInForceDAY), "100")
+	market := newTestTrade(db.OrderTypeMARKET, db.TimeInForceDAY)
+	stop := withStopPrice(newTestTrade(db.OrderTypeSTOP, db.TimeInForceGTC), "90")
+
+	for _, trade := range []db.Trade{limit, market, stop} {
+		require.True(t, CanExecute(trade, calendar.SessionRegular))
+		require.False(t, CanExecute(trade, calendar.SessionClosed))
+	}
+	require.True(t, CanExecute(limit, calendar.SessionPreMarket))
+	require.True(t, CanExecute(limit, calendar.SessionAfterHours))
+	require.False(t, CanExecute(market, calendar.SessionPreMarket))
+	require.False(t, CanExecute(stop, calendar.SessionAfterHours))
+
+	require.True(t, IsImmediate(market))
+	require.True(t, IsImmediate(withPrice(newTestTrade(db.OrderTypeLIMIT, db.TimeInForceIOC), "100")))
+	require.False(t, IsImmediate(limit))
+}
@@ -0,0 +1,57 @@
+package execution
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/valverdethiago/trading-api/calendar"
+	db "github.com/valverdethiago/trading-api/db/sqlc"
+)
+
+// ErrVenueUnavailable is returned when the venue can't accept more trades
+var ErrVenueUnavailable = errors.New("execution venue unavailable")
+
+// Venue receives newly created trades and executes them asynchronously
+type Venue interface {
+	Submit(ctx context.Context, trade db.Trade) error
+}
+
+// Report is the outcome of the execution of a trade. Fills carry the executed quantity and price,
+// their status is PARTIALLY_FILLED while some quantity remains and COMPLETED once it's all executed.
+type Report struct {
+	TradeUUID uuid.UUID
+	Status    db.TradeStatus
+	Quantity  int64
+	Price     decimal.Decimal
+}
+
+// IsOpen tells whether a trade in the given status may still be executed
+func IsOpen(status db.TradeStatus) bool {
+	return status == db.TradeStatusSUBMITTED || status == db.TradeStatusPARTIALLY_FILLED
+}
+
+// IsImmediate tells whether the order must execute as soon as it's placed: market orders and the
+// IOC and FOK ones can't wait for the market to open
+func IsImmediate(trade db.Trade) bool {
+	return trade.OrderType == db.OrderTypeMARKET ||
+		trade.TimeInForce == db.TimeInForceIOC || trade.TimeInForce == db.TimeInForceFOK
+}
+
+// CanExecute tells whether the trade may execute in the given session: any order in the regular
+// session, only limit orders in the extended hours and none while the market is closed
+func CanExecute(trade db.Trade, session calendar.Session) bool {
+	switch session {
+	case calendar.SessionRegular:
+		return true
+	case calendar.SessionPreMarket, calendar.SessionAfterHours:
+		return trade.OrderType == db.OrderTypeLIMIT
+	}
+	return false
+}
+
+// ReportHandler receives the reports produced by a venue
+type ReportHandler interface {
+	HandleReport(ctx context.Context, report Report) error
+}
@@ -1,23 +1,38 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"log"
+	"os"
+	"time"
 
 	_ "github.com/golang-migrate/migrate/v4/source/file"
 	_ "github.com/lib/pq"
+	"github.com/shopspring/decimal"
 	"github.com/valverdethiago/trading-api/api"
+	"github.com/valverdethiago/trading-api/calendar"
 	db "github.com/valverdethiago/trading-api/db/sqlc"
+	"github.com/valverdethiago/trading-api/execution"
+	"github.com/valverdethiago/trading-api/outbox"
+	"github.com/valverdethiago/trading-api/service"
+	"github.com/valverdethiago/trading-api/stream"
 	"github.com/valverdethiago/trading-api/util"
+	"github.com/valverdethiago/trading-api/webhook"
 )
 
-var queries *db.Queries
-
 func main() {
 	config := loadConfig()
 	conn := openDatabaseConnection(config)
-	queries = db.New(conn)
-	startServer(config, queries)
+	store := newStore(config, conn)
+	loadInstruments(config, store)
+	marketCalendar := loadCalendar(config)
+	hub := stream.NewHub(config.StreamHistorySize)
+	venue := startVenue(config, store, marketCalendar, hub)
+	go purgeIdempotencyKeys(config, store)
+	go startWebhookDispatcher(config, store)
+	go startOutboxRelay(config, store)
+	startServer(config, store, venue, marketCalendar, hub)
 }
 
 func loadConfig() util.Config {
@@ -36,9 +51,145 @@ func openDatabaseConnection(config util.Config) *sql.DB {
 	return conn
 }
 
-func startServer(config util.Config, querier db.Querier) {
-	server := api.NewServer(queries)
-	err := server.Start(config.ServerAddress)
+func newStore(config util.Config, conn *sql.DB) db.Store {
+	isolation, err := db.ParseIsolationLevel(config.DBTxIsolation)
+	if err != nil {
+		log.Fatal("Invalid transaction isolation level:", err)
+	}
+	return db.NewStore(conn, db.TxOptions{
+		Isolation:  isolation,
+		MaxRetries: config.DBTxMaxRetries,
+	})
+}
+
+// loadInstruments imports the security master from the CSV file of the config, when there is one
+func loadInstruments(config util.Config, store db.Store) {
+	if config.InstrumentsFile == "" {
+		return
+	}
+	file, err := os.Open(config.InstrumentsFile)
+	if err != nil {
+		log.Fatal("Cannot open the instruments file:", err)
+	}
+	defer file.Close()
+	tickSize, err := decimal.NewFromString(config.DefaultTickSize)
+	if err != nil {
+		log.Fatal("Invalid default tick size:", err)
+	}
+	instrumentService := service.NewInstrumentService(store, service.NewPolicy(), tickSize)
+	loaded, err := instrumentService.LoadInstruments(context.Background(), file)
+	if err != nil {
+		log.Fatal("Cannot load the instruments file:", err)
+	}
+	log.Printf("Loaded %d instruments from %s", loaded, config.InstrumentsFile)
+}
+
+// loadCalendar reads the market calendar of the config, the market is always open without one
+func loadCalendar(config util.Config) *calendar.Calendar {
+	if config.MarketCalendarFile == "" {
+		log.Println("No market calendar, the market is always open")
+		return calendar.AlwaysOpen()
+	}
+	marketCalendar, err := calendar.Load(config.MarketCalendarFile)
+	if err != nil {
+		log.Fatal("Cannot load the market calendar:", err)
+	}
+	return marketCalendar
+}
+
+// startVenue runs the execution simulator in background and hands it the trades left pending, the
+// events of the fills going to the hub
+func startVenue(config util.Config, store db.Store, marketCalendar *calendar.Calendar, hub *stream.Hub) execution.Venue {
+	feeRate, err := decimal.NewFromString(config.TradeFeeRate)
+	if err != nil {
+		log.Fatal("Invalid trade fee rate:", err)
+	}
+	executionService := service.NewExecutionService(store, feeRate, hub)
+	simulator := execution.NewSimulator(newSimulatorConfig(config, marketCalendar), store, executionService)
+	go simulator.Start(context.Background())
+	go func() {
+		if err := executionService.ResubmitPendingTrades(context.Background(), simulator); err != nil {
+			log.Println("Cannot resubmit pending trades:", err)
+		}
+	}()
+	return simulator
+}
+
+func newSimulatorConfig(config util.Config, marketCalendar *calendar.Calendar) execution.SimulatorConfig {
+	referencePrice, err := decimal.NewFromString(config.SimulatorReferencePrice)
+	if err != nil {
+		log.Fatal("Invalid simulator reference price:", err)
+	}
+	tickSize, err := decimal.NewFromString(config.DefaultTickSize)
+	if err != nil {
+		log.Fatal("Invalid default tick size:", err)
+	}
+	return execution.SimulatorConfig{
+		MinLatency:      config.SimulatorMinLatency,
+		MaxLatency:      config.SimulatorMaxLatency,
+		FillProbability: config.SimulatorFillProbability,
+		QueueSize:       config.ExecutionQueueSize,
+		Volatility:      config.SimulatorVolatility,
+		Liquidity:       config.SimulatorLiquidity,
+		ReferencePrice:  referencePrice,
+		TickSize:        tickSize,
+		Calendar:        marketCalendar,
+	}
+}
+
+// purgeIdempotencyKeys deletes the expired idempotency keys every hour
+func purgeIdempotencyKeys(config util.Config, store db.Store) {
+	idempotencyService := service.NewIdempotencyService(store, config.IdempotencyKeyTTL)
+	for range time.Tick(time.Hour) {
+		if err := idempotencyService.PurgeExpiredKeys(context.Background()); err != nil {
+			log.Println("Cannot purge expired idempotency keys:", err)
+		}
+	}
+}
+
+// startWebhookDispatcher sends the queued webhook deliveries in background
+func startWebhookDispatcher(config util.Config, store db.Store) {
+	webhookService := service.NewWebhookService(store, service.NewAccountService(store, service.NewPolicy()))
+	dispatcher := webhook.NewDispatcher(webhook.Config{
+		PollInterval:   config.WebhookPollInterval,
+		BatchSize:      config.WebhookBatchSize,
+		Timeout:        config.WebhookTimeout,
+		MaxAttempts:    config.WebhookMaxAttempts,
+		InitialBackoff: config.WebhookInitialBackoff,
+		MaxBackoff:     config.WebhookMaxBackoff,
+	}, webhookService, nil)
+	dispatcher.Start(context.Background())
+}
+
+// startOutboxRelay publishes the events of the outbox in background, as lines of JSON appended to the
+// outbox file of the config or written to the standard output without one
+func startOutboxRelay(config util.Config, store db.Store) {
+	output := os.Stdout
+	if config.OutboxFile != "" {
+		file, err := os.OpenFile(config.OutboxFile, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+		if err != nil {
+			log.Fatal("Cannot open the outbox file:", err)
+		}
+		defer file.Close()
+		output = file
+	}
+	relay := outbox.NewRelay(outbox.Config{
+		PollInterval:   config.OutboxPollInterval,
+		BatchSize:      config.OutboxBatchSize,
+		Timeout:        config.OutboxTimeout,
+		InitialBackoff: config.OutboxInitialBackoff,
+		MaxBackoff:     config.OutboxMaxBackoff,
+	}, service.NewOutboxService(store), outbox.NewLogPublisher(output))
+	relay.Start(context.Background())
+}
+
+func startServer(config util.Config, store db.Store, venue execution.Venue, marketCalendar *calendar.Calendar,
+	hub *stream.Hub) {
+	server, err := api.NewServer(config, store, venue, marketCalendar, hub)
+	if err != nil {
+		log.Fatal("Cannot create HTTP server:", err)
+	}
+	err = server.Start(config.ServerAddress)
 	if err != nil {
 		log.Fatal("Failed to start HTTP server")
 	}
@@ -0,0 +1,142 @@
+package openapi
+
+import (
+	"html/template"
+	"io"
+)
+
+// docsTemplate is a standalone page rendering the document fetched from the spec URL, without any
+// asset from elsewhere so that it works offline
+var docsTemplate = template.Must(template.New("docs").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 0; color: #222; }
+header { background: #1f2d3d; color: #fff; padding: 16px 32px; }
+header a { color: #9cc3ff; }
+main { padding: 16px 32px; max-width: 1100px; }
+h2 { border-bottom: 1px solid #ddd; padding-bottom: 4px; margin-top: 32px; }
+details { border: 1px solid #ddd; border-radius: 4px; margin: 8px 0; }
+summary { cursor: pointer; padding: 8px; font-family: monospace; font-size: 14px; }
+.method { display: inline-block; width: 64px; font-weight: bold; }
+.get { color: #0b7a3e; } .post { color: #1d5fbf; } .put { color: #a05a00; } .patch { color: #6d3fb5; } .delete { color: #b3261e; }
+.operation { padding: 0 16px 16px; }
+table { border-collapse: collapse; margin: 8px 0; }
+td, th { border: 1px solid #ddd; padding: 4px 8px; text-align: left; vertical-align: top; font-size: 13px; }
+pre { background: #f6f8fa; padding: 8px; overflow-x: auto; font-size: 12px; }
+</style>
+</head>
+<body>
+<header>
+<h1>{{.Title}}</h1>
+<a href="{{.SpecURL}}">{{.SpecURL}}</a>
+</header>
+<main id="docs">Loading...</main>
+<script>
+(function () {
+  var specURL = {{.SpecURL}};
+  var main = document.getElementById("docs");
+
+  function element(tag, attributes, children) {
+    var node = document.createElement(tag);
+    Object.keys(attributes || {}).forEach(function (name) { node.setAttribute(name, attributes[name]); });
+    (children || []).forEach(function (child) {
+      node.appendChild(typeof child === "string" ? document.createTextNode(child) : child);
+    });
+    return node;
+  }
+
+  function describe(spec, schema, depth) {
+    if (!schema) { return "any"; }
+    if (schema.$ref) {
+      var name = schema.$ref.split("/").pop();
+      if (depth > 4) { return name; }
+      return describe(spec, spec.components.schemas[name], depth);
+    }
+    var suffix = schema.nullable ? " | null" : "";
+    if (schema.oneOf) {
+      return schema.oneOf.map(function (one) { return describe(spec, one, depth); }).join(" | ") + suffix;
+    }
+    if (schema.type === "array") { return "[" + describe(spec, schema.items, depth + 1) + "]" + suffix; }
+    if (schema.type === "object") {
+      var indent = new Array(depth + 2).join("  ");
+      var lines = Object.keys(schema.properties || {}).map(function (name) {
+        var required = (schema.required || []).indexOf(name) >= 0 ? "" : "?";
+        return indent + name + required + ": " + describe(spec, schema.properties[name], depth + 1);
+      });
+      return "{\n" + lines.join(",\n") + "\n" + new Array(depth + 1).join("  ") + "}" + suffix;
+    }
+    if (schema.enum) { return schema.enum.join(" | ") + suffix; }
+    if (!schema.type) { return "any"; }
+    return schema.type + (schema.format ? " (" + schema.format + ")" : "") + suffix;
+  }
+
+  function renderOperation(spec, path, method, operation) {
+    var body = element("div", {"class": "operation"}, []);
+    if (operation.description) { body.appendChild(element("p", {}, [operation.description])); }
+    if (operation.security) { body.appendChild(element("p", {}, ["Requires a bearer token."])); }
+    if (operation.parameters) {
+      var rows = [element("tr", {}, [element("th", {}, ["Parameter"]), element("th", {}, ["In"]),
+        element("th", {}, ["Type"]), element("th", {}, ["Description"])])];
+      operation.parameters.forEach(function (parameter) {
+        rows.push(element("tr", {}, [
+          element("td", {}, [parameter.name + (parameter.required ? " *" : "")]),
+          element("td", {}, [parameter.in]),
+          element("td", {}, [describe(spec, parameter.schema, 0)]),
+          element("td", {}, [parameter.description || ""])]));
+      });
+      body.appendChild(element("table", {}, rows));
+    }
+    if (operation.requestBody) {
+      Object.keys(operation.requestBody.content).forEach(function (type) {
+        body.appendChild(element("h4", {}, ["Request body (" + type + ")"]));
+        body.appendChild(element("pre", {}, [describe(spec, operation.requestBody.content[type].schema, 0)]));
+      });
+    }
+    Object.keys(operation.responses).sort().forEach(function (status) {
+      var response = operation.responses[status];
+      body.appendChild(element("h4", {}, [status + " " + response.description]));
+      Object.keys(response.content || {}).forEach(function (type) {
+        body.appendChild(element("pre", {}, [describe(spec, response.content[type].schema, 0)]));
+      });
+    });
+    return element("details", {}, [element("summary", {}, [
+      element("span", {"class": "method " + method}, [method.toUpperCase()]),
+      path + "  " + (operation.summary || "")]), body]);
+  }
+
+  function render(spec) {
+    main.innerHTML = "";
+    if (spec.info.description) { main.appendChild(element("p", {}, [spec.info.description])); }
+    var tags = {};
+    Object.keys(spec.paths).sort().forEach(function (path) {
+      Object.keys(spec.paths[path]).forEach(function (method) {
+        var operation = spec.paths[path][method];
+        var tag = (operation.tags || ["default"])[0];
+        (tags[tag] = tags[tag] || []).push(renderOperation(spec, path, method, operation));
+      });
+    });
+    Object.keys(tags).sort().forEach(function (tag) {
+      main.appendChild(element("h2", {}, [tag]));
+      tags[tag].forEach(function (node) { main.appendChild(node); });
+    });
+  }
+
+  fetch(specURL).then(function (response) { return response.json(); }).then(render).catch(function (err) {
+    main.textContent = "Cannot load " + specURL + ": " + err;
+  });
+})();
+</script>
+</body>
+</html>
+`))
+
+// WriteDocs writes a page browsing the document served at the spec URL
+func WriteDocs(writer io.Writer, title string, specURL string) error {
+	return docsTemplate.Execute(writer, struct {
+		Title   string
+		SpecURL string
+	}{title, specURL})
+}
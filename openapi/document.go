@@ -0,0 +1,254 @@
+package openapi
+
+import (
+	"strings"
+)
+
+// Version is the version of the OpenAPI specification the documents follow
+const Version = "3.0.3"
+
+const (
+	// ContentTypeJSON is the media type of the JSON bodies, the only ones validated with the +json ones
+	ContentTypeJSON = "application/json"
+	// ContentTypeProblemJSON is the media type of the RFC 7807 problem details
+	ContentTypeProblemJSON = "application/problem+json"
+	schemaRefPrefix        = "#/components/schemas/"
+)
+
+// Document is an OpenAPI 3 document describing the operations of an HTTP API
+type Document struct {
+	OpenAPI    string              `json:"openapi"`
+	Info       Info                `json:"info"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components Components          `json:"components"`
+}
+
+// Info describes the API
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+// PathItem holds the operations of a path, keyed by lower case HTTP method
+type PathItem map[string]*Operation
+
+// Operation describes a method on a path, its parameters, request body and the responses of each status
+type Operation struct {
+	OperationID string                `json:"operationId"`
+	Summary     string                `json:"summary,omitempty"`
+	Description string                `json:"description,omitempty"`
+	Tags        []string              `json:"tags,omitempty"`
+	Security    []SecurityRequirement `json:"security,omitempty"`
+	Parameters  []Parameter           `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]Response   `json:"responses"`
+}
+
+// SecurityRequirement names the security schemes an operation accepts
+type SecurityRequirement map[string][]string
+
+// Parameter is a path, query or header parameter of an operation
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+// RequestBody describes the body of a request, keyed by media type
+type RequestBody struct {
+	Description string               `json:"description,omitempty"`
+	Required    bool                 `json:"required,omitempty"`
+	Content     map[string]MediaType `json:"content"`
+}
+
+// MediaType holds the schema of a body
+type MediaType struct {
+	Schema *Schema `json:"schema,omitempty"`
+}
+
+// Response describes the response of a status, a response without content having no body
+type Response struct {
+	Description string               `json:"description"`
+	Headers     map[string]Header    `json:"headers,omitempty"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// Header describes a response header
+type Header struct {
+	Description string  `json:"description,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+// Components holds the schemas and security schemes referenced by the operations
+type Components struct {
+	Schemas         map[string]*Schema        `json:"schemas,omitempty"`
+	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+// SecurityScheme describes how the operations are authenticated
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Description  string `json:"description,omitempty"`
+	Scheme       string `json:"scheme,omitempty"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+}
+
+// Schema is the subset of the OpenAPI schema objects used to describe the bodies and parameters. An
+// empty schema matches any value
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	MinLength            int                `json:"minLength,omitempty"`
+	MaxLength            int                `json:"maxLength,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	ExclusiveMinimum     bool               `json:"exclusiveMinimum,omitempty"`
+	MinItems             int                `json:"minItems,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *bool              `json:"additionalProperties,omitempty"`
+	OneOf                []*Schema          `json:"oneOf,omitempty"`
+}
+
+// NewDocument creates a document without operations
+func NewDocument(info Info) *Document {
+	return &Document{
+		OpenAPI: Version,
+		Info:    info,
+		Paths:   make(map[string]PathItem),
+		Components: Components{
+			Schemas:         make(map[string]*Schema),
+			SecuritySchemes: make(map[string]SecurityScheme),
+		},
+	}
+}
+
+// AddOperation documents the method on the path, written as a template like /accounts/{id}
+func (document *Document) AddOperation(method string, path string, operation *Operation) {
+	item, ok := document.Paths[path]
+	if !ok {
+		item = make(PathItem)
+		document.Paths[path] = item
+	}
+	item[strings.ToLower(method)] = operation
+}
+
+// Operation returns the operation of the method on the path template, nil when it isn't documented
+func (document *Document) Operation(method string, path string) *Operation {
+	return document.Paths[path][strings.ToLower(method)]
+}
+
+// Ref references a schema of the components
+func Ref(name string) *Schema {
+	return &Schema{Ref: schemaRefPrefix + name}
+}
+
+// Any matches any value, null included
+func Any(description string) *Schema {
+	return &Schema{Description: description}
+}
+
+// String matches a string
+func String() *Schema {
+	return &Schema{Type: "string"}
+}
+
+// NonEmptyString matches a string of at least one character
+func NonEmptyString() *Schema {
+	return &Schema{Type: "string", MinLength: 1}
+}
+
+// Enum matches one of the strings
+func Enum(values ...string) *Schema {
+	return &Schema{Type: "string", Enum: values}
+}
+
+// UUID matches a UUID written as a string
+func UUID() *Schema {
+	return &Schema{Type: "string", Format: "uuid"}
+}
+
+// DateTime matches an RFC 3339 date and time
+func DateTime() *Schema {
+	return &Schema{Type: "string", Format: "date-time"}
+}
+
+// Integer matches a 64 bits integer
+func Integer() *Schema {
+	return &Schema{Type: "integer", Format: "int64"}
+}
+
+// Number matches any number
+func Number() *Schema {
+	return &Schema{Type: "number"}
+}
+
+// Boolean matches true or false
+func Boolean() *Schema {
+	return &Schema{Type: "boolean"}
+}
+
+// ArrayOf matches an array whose items match the schema
+func ArrayOf(items *Schema) *Schema {
+	return &Schema{Type: "array", Items: items}
+}
+
+// Object matches an object with the given properties, the required ones being present, and no other
+func Object(properties map[string]*Schema, required ...string) *Schema {
+	closed := false
+	return &Schema{
+		Type:                 "object",
+		Properties:           properties,
+		Required:             required,
+		AdditionalProperties: &closed,
+	}
+}
+
+// OneOf matches a value matching exactly one of the schemas
+func OneOf(schemas ...*Schema) *Schema {
+	return &Schema{OneOf: schemas}
+}
+
+// WithNullable returns a copy of the schema also matching null
+func (schema *Schema) WithNullable() *Schema {
+	result := *schema
+	result.Nullable = true
+	return &result
+}
+
+// WithDescription returns a copy of the schema with the description
+func (schema *Schema) WithDescription(description string) *Schema {
+	result := *schema
+	result.Description = description
+	return &result
+}
+
+// WithMinimum returns a copy of the schema with an inclusive minimum
+func (schema *Schema) WithMinimum(minimum float64) *Schema {
+	result := *schema
+	result.Minimum = &minimum
+	return &result
+}
+
+// WithExclusiveMinimum returns a copy of the schema only matching numbers greater than the minimum
+func (schema *Schema) WithExclusiveMinimum(minimum float64) *Schema {
+	result := schema.WithMinimum(minimum)
+	result.ExclusiveMinimum = true
+	return result
+}
+
+// WithMaximum returns a copy of the schema with an inclusive maximum
+func (schema *Schema) WithMaximum(maximum float64) *Schema {
+	result := *schema
+	result.Maximum = &maximum
+	return &result
+}
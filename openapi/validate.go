@@ -0,0 +1,340 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// ErrUndocumentedStatus is returned when the status of a response isn't documented by its operation
+var ErrUndocumentedStatus = errors.New("undocumented status")
+
+// ValidationError tells where a value doesn't match its schema, the location being like body.account.role
+type ValidationError struct {
+	Location string
+	Reason   string
+}
+
+func (err *ValidationError) Error() string {
+	return fmt.Sprintf("%s %s", err.Location, err.Reason)
+}
+
+func invalid(location string, format string, args ...interface{}) error {
+	return &ValidationError{Location: location, Reason: fmt.Sprintf(format, args...)}
+}
+
+// ValidateRequest checks the path, query and header parameters of a request to the operation and its
+// JSON body, given apart since reading the request consumes it
+func (document *Document) ValidateRequest(operation *Operation, request *http.Request,
+	pathParams map[string]string, body []byte) error {
+	query := request.URL.Query()
+	for _, parameter := range operation.Parameters {
+		var values []string
+		switch parameter.In {
+		case "path":
+			if value, ok := pathParams[parameter.Name]; ok {
+				values = []string{value}
+			}
+		case "query":
+			values = query[parameter.Name]
+		case "header":
+			values = request.Header.Values(parameter.Name)
+		}
+		location := parameter.In + "." + parameter.Name
+		if len(values) == 0 || (len(values) == 1 && values[0] == "" && parameter.In != "path") {
+			if parameter.Required {
+				return invalid(location, "is required")
+			}
+			continue
+		}
+		if len(values) > 1 {
+			return invalid(location, "is given %d times", len(values))
+		}
+		if err := document.validateParameter(parameter.Schema, location, values[0]); err != nil {
+			return err
+		}
+	}
+	if operation.RequestBody == nil {
+		return nil
+	}
+	mediaType, ok := operation.RequestBody.Content[ContentTypeJSON]
+	if !ok {
+		return nil
+	}
+	if len(bytes.TrimSpace(body)) == 0 {
+		if operation.RequestBody.Required {
+			return invalid("body", "is required")
+		}
+		return nil
+	}
+	return document.validateJSON(mediaType.Schema, "body", body)
+}
+
+// ValidateResponse checks that the status of a response is documented by the operation and that its
+// body matches the documented content
+func (document *Document) ValidateResponse(operation *Operation, status int, header http.Header, body []byte) error {
+	response, ok := operation.Responses[strconv.Itoa(status)]
+	if !ok {
+		return fmt.Errorf("%w %d", ErrUndocumentedStatus, status)
+	}
+	if len(response.Content) == 0 {
+		if len(body) > 0 {
+			return invalid("body", "is not expected for status %d", status)
+		}
+		return nil
+	}
+	contentType, _, _ := mime.ParseMediaType(header.Get("Content-Type"))
+	mediaType, ok := response.Content[contentType]
+	if !ok {
+		return invalid("body", "has undocumented content type %q for status %d", contentType, status)
+	}
+	if !isJSON(contentType) {
+		return nil
+	}
+	return document.validateJSON(mediaType.Schema, "body", body)
+}
+
+// isJSON tells whether the media type is JSON or a JSON based one like application/problem+json
+func isJSON(contentType string) bool {
+	return contentType == ContentTypeJSON || strings.HasSuffix(contentType, "+json")
+}
+
+// ValidateValue checks a value decoded from JSON against the schema, numbers being json.Number
+func (document *Document) ValidateValue(schema *Schema, location string, value interface{}) error {
+	if schema == nil {
+		return nil
+	}
+	if schema.Ref != "" {
+		resolved, err := document.resolve(schema.Ref)
+		if err != nil {
+			return err
+		}
+		return document.ValidateValue(resolved, location, value)
+	}
+	if value == nil {
+		if schema.Nullable || (schema.Type == "" && len(schema.OneOf) == 0) {
+			return nil
+		}
+		return invalid(location, "must not be null")
+	}
+	if len(schema.OneOf) > 0 {
+		return document.validateOneOf(schema, location, value)
+	}
+	switch schema.Type {
+	case "":
+		return nil
+	case "object":
+		return document.validateObject(schema, location, value)
+	case "array":
+		return document.validateArray(schema, location, value)
+	case "string":
+		text, ok := value.(string)
+		if !ok {
+			return invalid(location, "must be a string")
+		}
+		return validateString(schema, location, text)
+	case "integer", "number":
+		number, ok := value.(json.Number)
+		if !ok {
+			return invalid(location, "must be a %s", schema.Type)
+		}
+		return validateNumber(schema, location, number)
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return invalid(location, "must be a boolean")
+		}
+		return nil
+	}
+	return fmt.Errorf("%s has unsupported schema type %q", location, schema.Type)
+}
+
+func (document *Document) validateJSON(schema *Schema, location string, data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return invalid(location, "is not valid JSON: %v", err)
+	}
+	if decoder.More() {
+		return invalid(location, "holds more than one JSON value")
+	}
+	return document.ValidateValue(schema, location, value)
+}
+
+// validateParameter converts the text of a parameter to the type of its schema before validating it
+func (document *Document) validateParameter(schema *Schema, location string, text string) error {
+	if schema.Ref != "" {
+		resolved, err := document.resolve(schema.Ref)
+		if err != nil {
+			return err
+		}
+		schema = resolved
+	}
+	var value interface{} = text
+	switch schema.Type {
+	case "integer", "number":
+		value = json.Number(text)
+	case "boolean":
+		parsed, err := strconv.ParseBool(text)
+		if err != nil {
+			return invalid(location, "must be a boolean")
+		}
+		value = parsed
+	}
+	return document.ValidateValue(schema, location, value)
+}
+
+func (document *Document) resolve(ref string) (*Schema, error) {
+	if !strings.HasPrefix(ref, schemaRefPrefix) {
+		return nil, fmt.Errorf("unsupported schema reference %q", ref)
+	}
+	schema, ok := document.Components.Schemas[strings.TrimPrefix(ref, schemaRefPrefix)]
+	if !ok {
+		return nil, fmt.Errorf("unknown schema reference %q", ref)
+	}
+	return schema, nil
+}
+
+func (document *Document) validateOneOf(schema *Schema, location string, value interface{}) error {
+	matches := 0
+	var firstErr error
+	for _, candidate := range schema.OneOf {
+		if err := document.ValidateValue(candidate, location, value); err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		matches++
+	}
+	if matches == 1 {
+		return nil
+	}
+	if matches == 0 {
+		return firstErr
+	}
+	return invalid(location, "matches %d schemas instead of one", matches)
+}
+
+func (document *Document) validateObject(schema *Schema, location string, value interface{}) error {
+	object, ok := value.(map[string]interface{})
+	if !ok {
+		return invalid(location, "must be an object")
+	}
+	for _, name := range schema.Required {
+		if _, ok := object[name]; !ok {
+			return invalid(location+"."+name, "is required")
+		}
+	}
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		property, ok := schema.Properties[name]
+		if !ok {
+			if schema.AdditionalProperties != nil && !*schema.AdditionalProperties {
+				return invalid(location+"."+name, "is not a documented property")
+			}
+			continue
+		}
+		if err := document.ValidateValue(property, location+"."+name, object[name]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (document *Document) validateArray(schema *Schema, location string, value interface{}) error {
+	items, ok := value.([]interface{})
+	if !ok {
+		return invalid(location, "must be an array")
+	}
+	if len(items) < schema.MinItems {
+		return invalid(location, "must have at least %d items", schema.MinItems)
+	}
+	for i, item := range items {
+		if err := document.ValidateValue(schema.Items, fmt.Sprintf("%s[%d]", location, i), item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateString(schema *Schema, location string, text string) error {
+	length := utf8.RuneCountInString(text)
+	if length < schema.MinLength {
+		return invalid(location, "must be at least %d characters long", schema.MinLength)
+	}
+	if schema.MaxLength > 0 && length > schema.MaxLength {
+		return invalid(location, "must be at most %d characters long", schema.MaxLength)
+	}
+	if len(schema.Enum) > 0 && !contains(schema.Enum, text) {
+		return invalid(location, "must be one of %s", strings.Join(schema.Enum, ", "))
+	}
+	if schema.Pattern != "" {
+		pattern, err := regexp.Compile(schema.Pattern)
+		if err != nil {
+			return fmt.Errorf("%s has an invalid pattern: %w", location, err)
+		}
+		if !pattern.MatchString(text) {
+			return invalid(location, "must match %s", schema.Pattern)
+		}
+	}
+	switch schema.Format {
+	case "uuid":
+		if _, err := uuid.Parse(text); err != nil {
+			return invalid(location, "must be a UUID")
+		}
+	case "date-time":
+		if _, err := time.Parse(time.RFC3339Nano, text); err != nil {
+			return invalid(location, "must be an RFC 3339 date and time")
+		}
+	}
+	return nil
+}
+
+func validateNumber(schema *Schema, location string, number json.Number) error {
+	if schema.Type == "integer" {
+		if _, err := number.Int64(); err != nil {
+			return invalid(location, "must be an integer")
+		}
+	}
+	value, err := number.Float64()
+	if err != nil {
+		return invalid(location, "must be a number")
+	}
+	if schema.Minimum != nil {
+		if schema.ExclusiveMinimum && value <= *schema.Minimum {
+			return invalid(location, "must be greater than %v", *schema.Minimum)
+		}
+		if value < *schema.Minimum {
+			return invalid(location, "must be at least %v", *schema.Minimum)
+		}
+	}
+	if schema.Maximum != nil && value > *schema.Maximum {
+		return invalid(location, "must be at most %v", *schema.Maximum)
+	}
+	return nil
+}
+
+func contains(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
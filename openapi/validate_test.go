@@ -0,0 +1,147 @@
+package openapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestDocument() *Document {
+	document := NewDocument(Info{Title: "Test", Version: "1"})
+	document.Components.Schemas["Item"] = Object(map[string]*Schema{
+		"item_uuid": UUID(),
+		"name":      NonEmptyString(),
+		"kind":      Enum("BOOK", "PEN"),
+		"price":     Number().WithExclusiveMinimum(0).WithNullable(),
+		"quantity":  Integer().WithMinimum(1).WithMaximum(10),
+		"tags":      &Schema{Type: "array", Items: String(), MinItems: 1},
+		"created":   DateTime(),
+		"code":      &Schema{Type: "string", Pattern: "^[A-Z]{3}$"},
+		"extra":     Any("Anything"),
+	}, "item_uuid", "name")
+	return document
+}
+
+func TestValidateValue(t *testing.T) {
+	document := newTestDocument()
+
+	testCases := []struct {
+		name     string
+		body     string
+		location string
+	}{
+		{name: "OK", body: `{"item_uuid":"0b6f5f4e-5f0e-4c1b-9a6a-7c1d2e3f4a5b","name":"pen","kind":"PEN",` +
+			`"price":null,"quantity":3,"tags":["blue"],"created":"2021-03-01T10:00:00Z","code":"ABC","extra":{"a":1}}`},
+		{name: "Missing Required", body: `{"item_uuid":"0b6f5f4e-5f0e-4c1b-9a6a-7c1d2e3f4a5b"}`, location: "body.name"},
+		{name: "Undocumented Property", body: `{"item_uuid":"0b6f5f4e-5f0e-4c1b-9a6a-7c1d2e3f4a5b","name":"pen","color":"blue"}`, location: "body.color"},
+		{name: "Invalid UUID", body: `{"item_uuid":"pen","name":"pen"}`, location: "body.item_uuid"},
+		{name: "Empty String", body: `{"item_uuid":"0b6f5f4e-5f0e-4c1b-9a6a-7c1d2e3f4a5b","name":""}`, location: "body.name"},
+		{name: "Unknown Enum", body: `{"item_uuid":"0b6f5f4e-5f0e-4c1b-9a6a-7c1d2e3f4a5b","name":"pen","kind":"CUP"}`, location: "body.kind"},
+		{name: "Zero Price", body: `{"item_uuid":"0b6f5f4e-5f0e-4c1b-9a6a-7c1d2e3f4a5b","name":"pen","price":0}`, location: "body.price"},
+		{name: "Fractional Integer", body: `{"item_uuid":"0b6f5f4e-5f0e-4c1b-9a6a-7c1d2e3f4a5b","name":"pen","quantity":1.5}`, location: "body.quantity"},
+		{name: "Integer Too Big", body: `{"item_uuid":"0b6f5f4e-5f0e-4c1b-9a6a-7c1d2e3f4a5b","name":"pen","quantity":11}`, location: "body.quantity"},
+		{name: "Empty Array", body: `{"item_uuid":"0b6f5f4e-5f0e-4c1b-9a6a-7c1d2e3f4a5b","name":"pen","tags":[]}`, location: "body.tags"},
+		{name: "Invalid Item", body: `{"item_uuid":"0b6f5f4e-5f0e-4c1b-9a6a-7c1d2e3f4a5b","name":"pen","tags":[1]}`, location: "body.tags[0]"},
+		{name: "Invalid Date", body: `{"item_uuid":"0b6f5f4e-5f0e-4c1b-9a6a-7c1d2e3f4a5b","name":"pen","created":"2021-03-01"}`, location: "body.created"},
+		{name: "Pattern Mismatch", body: `{"item_uuid":"0b6f5f4e-5f0e-4c1b-9a6a-7c1d2e3f4a5b","name":"pen","code":"abc"}`, location: "body.code"},
+		{name: "Not Nullable", body: `{"item_uuid":"0b6f5f4e-5f0e-4c1b-9a6a-7c1d2e3f4a5b","name":null}`, location: "body.name"},
+		{name: "Not An Object", body: `[]`, location: "body"},
+	}
+
+	for i := range testCases {
+		testCase := testCases[i]
+
+		t.Run(testCase.name, func(t *testing.T) {
+			err := document.validateJSON(Ref("Item"), "body", []byte(testCase.body))
+			if testCase.location == "" {
+				require.NoError(t, err)
+				return
+			}
+			var validationErr *ValidationError
+			require.True(t, errors.As(err, &validationErr), "unexpected error %v", err)
+			require.Equal(t, testCase.location, validationErr.Location)
+		})
+	}
+}
+
+func TestValidateOneOf(t *testing.T) {
+	document := newTestDocument()
+	decimal := OneOf(Number().WithExclusiveMinimum(0), &Schema{Type: "string", Pattern: `^[0-9]+(\.[0-9]+)?$`})
+
+	require.NoError(t, document.ValidateValue(decimal, "price", json.Number("10.5")))
+	require.NoError(t, document.ValidateValue(decimal, "price", "10.5"))
+	require.Error(t, document.ValidateValue(decimal, "price", json.Number("-1")))
+	require.Error(t, document.ValidateValue(decimal, "price", "ten"))
+	require.Error(t, document.ValidateValue(OneOf(String(), Any("Anything")), "value", "both"))
+}
+
+func TestValidateRequest(t *testing.T) {
+	document := newTestDocument()
+	operation := &Operation{
+		Parameters: []Parameter{
+			{Name: "id", In: "path", Required: true, Schema: UUID()},
+			{Name: "page_size", In: "query", Schema: Integer().WithMinimum(1).WithMaximum(100)},
+			{Name: "Idempotency-Key", In: "header", Schema: &Schema{Type: "string", MaxLength: 5}},
+		},
+		RequestBody: &RequestBody{
+			Required: true,
+			Content:  map[string]MediaType{ContentTypeJSON: {Schema: Ref("Item")}},
+		},
+	}
+	pathParams := map[string]string{"id": "0b6f5f4e-5f0e-4c1b-9a6a-7c1d2e3f4a5b"}
+	body := []byte(`{"item_uuid":"0b6f5f4e-5f0e-4c1b-9a6a-7c1d2e3f4a5b","name":"pen"}`)
+
+	request := httptest.NewRequest(http.MethodPost, "/items/1?page_size=20", nil)
+	request.Header.Set("Idempotency-Key", "abc")
+	require.NoError(t, document.ValidateRequest(operation, request, pathParams, body))
+
+	request = httptest.NewRequest(http.MethodPost, "/items/1?page_size=", nil)
+	require.NoError(t, document.ValidateRequest(operation, request, pathParams, body))
+
+	request = httptest.NewRequest(http.MethodPost, "/items/1?page_size=1000", nil)
+	require.Error(t, document.ValidateRequest(operation, request, pathParams, body))
+
+	request = httptest.NewRequest(http.MethodPost, "/items/1?page_size=1&page_size=2", nil)
+	require.Error(t, document.ValidateRequest(operation, request, pathParams, body))
+
+	request = httptest.NewRequest(http.MethodPost, "/items/1", nil)
+	request.Header.Set("Idempotency-Key", "abcdef")
+	require.Error(t, document.ValidateRequest(operation, request, pathParams, body))
+
+	request = httptest.NewRequest(http.MethodPost, "/items/1", nil)
+	require.Error(t, document.ValidateRequest(operation, request, map[string]string{"id": "1"}, body))
+	require.Error(t, document.ValidateRequest(operation, request, pathParams, nil))
+	require.Error(t, document.ValidateRequest(operation, request, pathParams, []byte(`{"name":`)))
+}
+
+func TestValidateResponse(t *testing.T) {
+	document := newTestDocument()
+	operation := &Operation{
+		Responses: map[string]Response{
+			"200": {Description: "The item", Content: map[string]MediaType{ContentTypeJSON: {Schema: Ref("Item")}}},
+			"204": {Description: "No content"},
+		},
+	}
+	header := http.Header{"Content-Type": []string{"application/json; charset=utf-8"}}
+	body := []byte(`{"item_uuid":"0b6f5f4e-5f0e-4c1b-9a6a-7c1d2e3f4a5b","name":"pen"}`)
+
+	require.NoError(t, document.ValidateResponse(operation, http.StatusOK, header, body))
+	require.NoError(t, document.ValidateResponse(operation, http.StatusNoContent, http.Header{}, nil))
+
+	err := document.ValidateResponse(operation, http.StatusCreated, header, body)
+	require.True(t, errors.Is(err, ErrUndocumentedStatus))
+	require.Error(t, document.ValidateResponse(operation, http.StatusNoContent, header, body))
+	require.Error(t, document.ValidateResponse(operation, http.StatusOK, http.Header{"Content-Type": []string{"text/plain"}}, body))
+	require.Error(t, document.ValidateResponse(operation, http.StatusOK, header, []byte(`{"name":"pen"}`)))
+
+	operation.Responses["404"] = Response{Description: "Not found", Content: map[string]MediaType{
+		ContentTypeProblemJSON: {Schema: Object(map[string]*Schema{"status": Integer()}, "status")},
+	}}
+	problemHeader := http.Header{"Content-Type": []string{ContentTypeProblemJSON}}
+	require.NoError(t, document.ValidateResponse(operation, http.StatusNotFound, problemHeader, []byte(`{"status":404}`)))
+	require.Error(t, document.ValidateResponse(operation, http.StatusNotFound, problemHeader, []byte(`{"status":"404"}`)))
+}
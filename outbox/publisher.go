@@ -0,0 +1,98 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	db "github.com/valverdethiago/trading-api/db/sqlc"
+)
+
+// Message is an event of the outbox as handed over to the other systems. Sequence orders the events
+// of the same aggregate, which are published one after the other
+type Message struct {
+	EventUUID     uuid.UUID       `json:"event_uuid"`
+	Sequence      int64           `json:"sequence"`
+	EventType     string          `json:"event_type"`
+	AggregateType string          `json:"aggregate_type"`
+	AggregateUUID uuid.UUID       `json:"aggregate_uuid"`
+	Payload       json.RawMessage `json:"payload"`
+	CreatedDate   time.Time       `json:"created_date"`
+}
+
+// NewMessage builds the message of an event of the outbox
+func NewMessage(event db.Outbox) Message {
+	return Message{
+		EventUUID:     event.EventUuid,
+		Sequence:      event.Sequence,
+		EventType:     event.EventType,
+		AggregateType: event.AggregateType,
+		AggregateUUID: event.AggregateUuid,
+		Payload:       event.Payload,
+		CreatedDate:   event.CreatedDate,
+	}
+}
+
+// EventPublisher hands the events over to the other systems. An event is published again when its
+// publication can't be recorded, so publishers may see an event more than once
+type EventPublisher interface {
+	Publish(ctx context.Context, message Message) error
+}
+
+// LogPublisher writes each event as a line of JSON, to a log or a file
+type LogPublisher struct {
+	mutex   sync.Mutex
+	encoder *json.Encoder
+}
+
+// NewLogPublisher creates a publisher writing the events to w
+func NewLogPublisher(w io.Writer) *LogPublisher {
+	return &LogPublisher{encoder: json.NewEncoder(w)}
+}
+
+// Publish writes the event on a line of its own
+func (publisher *LogPublisher) Publish(ctx context.Context, message Message) error {
+	publisher.mutex.Lock()
+	defer publisher.mutex.Unlock()
+	return publisher.encoder.Encode(message)
+}
+
+// MemoryPublisher keeps the events published in memory, for the tests
+type MemoryPublisher struct {
+	mutex    sync.Mutex
+	messages []Message
+	err      error
+}
+
+// NewMemoryPublisher creates an empty in-memory publisher
+func NewMemoryPublisher() *MemoryPublisher {
+	return &MemoryPublisher{}
+}
+
+// Publish keeps the event, unless the publisher is set to fail
+func (publisher *MemoryPublisher) Publish(ctx context.Context, message Message) error {
+	publisher.mutex.Lock()
+	defer publisher.mutex.Unlock()
+	if publisher.err != nil {
+		return publisher.err
+	}
+	publisher.messages = append(publisher.messages, message)
+	return nil
+}
+
+// FailWith makes the publications fail with err until it's set back to nil
+func (publisher *MemoryPublisher) FailWith(err error) {
+	publisher.mutex.Lock()
+	defer publisher.mutex.Unlock()
+	publisher.err = err
+}
+
+// Messages returns the events published so far, in the order they were published
+func (publisher *MemoryPublisher) Messages() []Message {
+	publisher.mutex.Lock()
+	defer publisher.mutex.Unlock()
+	return append([]Message(nil), publisher.messages...)
+}
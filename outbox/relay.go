@@ -0,0 +1,134 @@
+package outbox
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	db "github.com/valverdethiago/trading-api/db/sqlc"
+)
+
+// Queue holds the events of the outbox waiting to be published and records their publication
+type Queue interface {
+	// ClaimEvents takes the next event of each aggregate whose earlier events are all published,
+	// keeping the other relays off them for the lease
+	ClaimEvents(ctx context.Context, batchSize int32, lease time.Duration) ([]db.Outbox, error)
+	MarkPublished(ctx context.Context, event db.Outbox) error
+	RetryEvent(ctx context.Context, event db.Outbox, reason string, delay time.Duration) error
+}
+
+// Config rules the publication of the events of the outbox
+type Config struct {
+	// PollInterval is the time between two looks for events once the outbox is drained
+	PollInterval time.Duration
+	// BatchSize is the largest number of events, of different aggregates, published at once
+	BatchSize int32
+	// Timeout bounds each publication
+	Timeout time.Duration
+	// InitialBackoff is the delay before publishing a failed event again, doubling after each failure
+	// up to MaxBackoff
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// Relay publishes the events written to the outbox by the transactions of the services. Events are
+// published at least once, in order within each aggregate: an event is only claimed once the earlier
+// ones of its aggregate are published, and a failed one holds the following ones back until it's
+// published on a later attempt. An event whose publication couldn't be recorded is published again
+// once its lease expires.
+type Relay struct {
+	config    Config
+	queue     Queue
+	publisher EventPublisher
+}
+
+// NewRelay creates a relay of the events of the queue to the publisher, it publishes nothing until started
+func NewRelay(config Config, queue Queue, publisher EventPublisher) *Relay {
+	if config.PollInterval <= 0 {
+		config.PollInterval = time.Second
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 10 * time.Second
+	}
+	if config.BatchSize < 1 {
+		config.BatchSize = 1
+	}
+	if config.MaxBackoff < config.InitialBackoff {
+		config.MaxBackoff = config.InitialBackoff
+	}
+	return &Relay{
+		config:    config,
+		queue:     queue,
+		publisher: publisher,
+	}
+}
+
+// Start publishes the events until the context is done, draining the outbox before waiting for the
+// poll interval
+func (relay *Relay) Start(ctx context.Context) {
+	ticker := time.NewTicker(relay.config.PollInterval)
+	defer ticker.Stop()
+	for {
+		claimed, err := relay.RelayDue(ctx)
+		if err != nil {
+			log.Println("Cannot relay the outbox events:", err)
+		}
+		if claimed > 0 && err == nil {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RelayDue publishes a batch of the events due, returning how many were claimed
+func (relay *Relay) RelayDue(ctx context.Context) (int, error) {
+	// the lease outlasts the publications, which run side by side
+	lease := 2 * relay.config.Timeout
+	events, err := relay.queue.ClaimEvents(ctx, relay.config.BatchSize, lease)
+	if err != nil {
+		return 0, err
+	}
+	var wg sync.WaitGroup
+	for _, event := range events {
+		wg.Add(1)
+		go func(event db.Outbox) {
+			defer wg.Done()
+			relay.publish(ctx, event)
+		}(event)
+	}
+	wg.Wait()
+	return len(events), nil
+}
+
+// Backoff is the delay after the given number of failed attempts
+func (relay *Relay) Backoff(attempts int32) time.Duration {
+	delay := relay.config.InitialBackoff
+	for i := int32(1); i < attempts && delay < relay.config.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > relay.config.MaxBackoff {
+		delay = relay.config.MaxBackoff
+	}
+	return delay
+}
+
+func (relay *Relay) publish(ctx context.Context, event db.Outbox) {
+	publishCtx, cancel := context.WithTimeout(ctx, relay.config.Timeout)
+	defer cancel()
+	err := relay.publisher.Publish(publishCtx, NewMessage(event))
+	if err == nil {
+		err = relay.queue.MarkPublished(ctx, event)
+	} else {
+		log.Printf("Cannot publish outbox event %s of %s %s: %v", event.EventUuid, event.AggregateType,
+			event.AggregateUuid, err)
+		err = relay.queue.RetryEvent(ctx, event, err.Error(), relay.Backoff(event.Attempts+1))
+	}
+	if err != nil {
+		log.Printf("Cannot record the publication of outbox event %s: %v", event.EventUuid, err)
+	}
+}
@@ -0,0 +1,183 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	db "github.com/valverdethiago/trading-api/db/sqlc"
+)
+
+// memoryQueue is an outbox claiming the events like the database does, the next one of each aggregate
+// once the earlier ones are published, and ignoring the leases and delays
+type memoryQueue struct {
+	mutex     sync.Mutex
+	events    []db.Outbox
+	published map[uuid.UUID]bool
+	claimed   map[uuid.UUID]bool
+	delays    []time.Duration
+}
+
+func newMemoryQueue(events ...db.Outbox) *memoryQueue {
+	return &memoryQueue{
+		events:    events,
+		published: make(map[uuid.UUID]bool),
+		claimed:   make(map[uuid.UUID]bool),
+	}
+}
+
+func (queue *memoryQueue) ClaimEvents(ctx context.Context, batchSize int32, lease time.Duration) ([]db.Outbox, error) {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+	var claimed []db.Outbox
+	pending := make(map[uuid.UUID]bool)
+	for _, event := range queue.events {
+		if queue.published[event.EventUuid] || pending[event.AggregateUuid] {
+			continue
+		}
+		pending[event.AggregateUuid] = true
+		if !queue.claimed[event.EventUuid] && int32(len(claimed)) < batchSize {
+			queue.claimed[event.EventUuid] = true
+			claimed = append(claimed, event)
+		}
+	}
+	return claimed, nil
+}
+
+func (queue *memoryQueue) MarkPublished(ctx context.Context, event db.Outbox) error {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+	queue.published[event.EventUuid] = true
+	return nil
+}
+
+func (queue *memoryQueue) RetryEvent(ctx context.Context, event db.Outbox, reason string, delay time.Duration) error {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+	for i := range queue.events {
+		if queue.events[i].EventUuid == event.EventUuid {
+			queue.events[i].Attempts++
+		}
+	}
+	delete(queue.claimed, event.EventUuid)
+	queue.delays = append(queue.delays, delay)
+	return nil
+}
+
+func newTestEvent(aggregateUUID uuid.UUID, sequence int64) db.Outbox {
+	return db.Outbox{
+		EventUuid:     uuid.New(),
+		Sequence:      sequence,
+		EventType:     "trade.created",
+		AggregateType: "trade",
+		AggregateUuid: aggregateUUID,
+		Payload:       json.RawMessage(`{"type": "trade.created"}`),
+		CreatedDate:   time.Now().UTC(),
+	}
+}
+
+func newTestRelay(queue Queue, publisher EventPublisher) *Relay {
+	return NewRelay(Config{
+		PollInterval:   10 * time.Millisecond,
+		BatchSize:      10,
+		Timeout:        time.Second,
+		InitialBackoff: time.Second,
+		MaxBackoff:     4 * time.Second,
+	}, queue, publisher)
+}
+
+func TestRelayOrdersEventsPerAggregate(t *testing.T) {
+	trade, otherTrade := uuid.New(), uuid.New()
+	first, second, other := newTestEvent(trade, 1), newTestEvent(trade, 2), newTestEvent(otherTrade, 3)
+	queue := newMemoryQueue(first, second, other)
+	publisher := NewMemoryPublisher()
+	relay := newTestRelay(queue, publisher)
+
+	claimed, err := relay.RelayDue(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 2, claimed)
+	claimed, err = relay.RelayDue(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, claimed)
+	claimed, err = relay.RelayDue(context.Background())
+	require.NoError(t, err)
+	require.Zero(t, claimed)
+
+	var sequences []int64
+	for _, message := range publisher.Messages() {
+		if message.AggregateUUID == trade {
+			sequences = append(sequences, message.Sequence)
+		}
+	}
+	require.Equal(t, []int64{1, 2}, sequences)
+	require.Len(t, publisher.Messages(), 3)
+}
+
+func TestRelayRetriesFailedEvents(t *testing.T) {
+	trade := uuid.New()
+	first, second := newTestEvent(trade, 1), newTestEvent(trade, 2)
+	queue := newMemoryQueue(first, second)
+	publisher := NewMemoryPublisher()
+	relay := newTestRelay(queue, publisher)
+
+	publisher.FailWith(errors.New("broker unavailable"))
+	for i := 0; i < 3; i++ {
+		_, err := relay.RelayDue(context.Background())
+		require.NoError(t, err)
+	}
+	require.Empty(t, publisher.Messages())
+	require.Equal(t, []time.Duration{time.Second, 2 * time.Second, 4 * time.Second}, queue.delays)
+
+	// the failed event holds the next one of its aggregate back until it's published
+	publisher.FailWith(nil)
+	for i := 0; i < 2; i++ {
+		_, err := relay.RelayDue(context.Background())
+		require.NoError(t, err)
+	}
+	messages := publisher.Messages()
+	require.Len(t, messages, 2)
+	require.Equal(t, first.EventUuid, messages[0].EventUUID)
+	require.Equal(t, second.EventUuid, messages[1].EventUUID)
+}
+
+func TestRelayStart(t *testing.T) {
+	queue := newMemoryQueue(newTestEvent(uuid.New(), 1), newTestEvent(uuid.New(), 2))
+	publisher := NewMemoryPublisher()
+	relay := newTestRelay(queue, publisher)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		relay.Start(ctx)
+		close(done)
+	}()
+	require.Eventually(t, func() bool {
+		return len(publisher.Messages()) == 2
+	}, time.Second, 10*time.Millisecond)
+	cancel()
+	<-done
+}
+
+func TestLogPublisher(t *testing.T) {
+	var buffer bytes.Buffer
+	publisher := NewLogPublisher(&buffer)
+	event := newTestEvent(uuid.New(), 1)
+
+	require.NoError(t, publisher.Publish(context.Background(), NewMessage(event)))
+	require.NoError(t, publisher.Publish(context.Background(), NewMessage(event)))
+
+	decoder := json.NewDecoder(&buffer)
+	for i := 0; i < 2; i++ {
+		var message Message
+		require.NoError(t, decoder.Decode(&message))
+		require.Equal(t, event.EventUuid, message.EventUUID)
+		require.JSONEq(t, string(event.Payload), string(message.Payload))
+	}
+	require.False(t, decoder.More())
+}
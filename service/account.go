@@ -3,76 +3,165 @@ package service
 import (
 	"context"
 	"database/sql"
-	"errors"
 
 	"github.com/google/uuid"
 	db "github.com/valverdethiago/trading-api/db/sqlc"
+	"github.com/valverdethiago/trading-api/util"
 )
 
+// ErrInvalidCredentials is returned when the username or the password doesn't match
+var ErrInvalidCredentials = NewUnauthorizedError("INVALID_CREDENTIALS", "Invalid username or password")
+
+// ErrUsernameTaken is returned when an account is created with the username of another one
+var ErrUsernameTaken = NewConflictError("USERNAME_TAKEN", "Username already taken")
+
 // AccountService service to handle business rules for accounts
 type AccountService struct {
-	queries db.Querier
+	store  db.Store
+	policy *Policy
 }
 
 // NewAccountService Creates new service for account
-func NewAccountService(queries db.Querier) *AccountService {
+func NewAccountService(store db.Store, policy *Policy) *AccountService {
 	return &AccountService{
-		queries: queries,
+		store:  store,
+		policy: policy,
 	}
 }
 
-// CreateAccount creates an account with address if provided
-func (service *AccountService) CreateAccount(account db.Account, address *db.Address) (db.Account, db.Address, error) {
+// CreateAccount creates an account with address if provided, both in the same transaction
+func (service *AccountService) CreateAccount(ctx context.Context, account db.Account, password string, address *db.Address) (db.Account, db.Address, error) {
 	var dbAccount db.Account
 	var dbAddress db.Address
 
-	if service.isUsernameAlreadyTaken(account.Username) {
-		return dbAccount, dbAddress, errors.New("Username already taken")
-	}
-	arg := db.CreateAccountParams{
-		Username: account.Username,
-		Email:    account.Email,
-	}
-	dbAccount, err := service.queries.CreateAccount(context.Background(), arg)
+	hashedPassword, err := util.HashPassword(password)
 	if err != nil {
 		return dbAccount, dbAddress, err
 	}
-	if address != nil {
-		dbAddress, err = service.createAddressForAccount(dbAccount, address)
-	}
+	err = service.store.ExecTx(ctx, func(q db.Querier) error {
+		taken, err := isUsernameAlreadyTaken(ctx, q, account.Username)
+		if err != nil {
+			return err
+		}
+		if taken {
+			return ErrUsernameTaken
+		}
+		arg := db.CreateAccountParams{
+			Username:       account.Username,
+			Email:          account.Email,
+			HashedPassword: hashedPassword,
+			CreatedBy:      auditedBy(ctx),
+		}
+		dbAccount, err = q.CreateAccount(ctx, arg)
+		if err != nil {
+			return err
+		}
+		err = recordAudit(ctx, q, AuditCreate, EntityAccount, dbAccount.AccountUuid, nil, newAccountEventData(dbAccount))
+		if err != nil {
+			return err
+		}
+		if address != nil {
+			dbAddress, err = createAddressForAccount(ctx, q, dbAccount, *address)
+			if err != nil {
+				return err
+			}
+		}
+		return publishEvent(ctx, q, newAccountEvent(EventAccountCreated, dbAccount))
+	})
 	return dbAccount, dbAddress, err
 }
 
-// ListAccounts list all available accounts
-func (service *AccountService) ListAccounts() ([]db.Account, error) {
-	return service.queries.ListAccounts(context.Background())
+// Login returns the account matching the given credentials
+func (service *AccountService) Login(ctx context.Context, username string, password string) (db.Account, error) {
+	dbAccount, err := service.store.GetAccountByUsername(ctx, username)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return dbAccount, ErrInvalidCredentials
+		}
+		return dbAccount, err
+	}
+	if err = util.CheckPassword(password, dbAccount.HashedPassword); err != nil {
+		return db.Account{}, ErrInvalidCredentials
+	}
+	return dbAccount, nil
 }
 
-// GetAccountByID find account by id
-func (service *AccountService) GetAccountByID(id uuid.UUID) (db.Account, error) {
-	return service.queries.GetAccountById(context.Background(), id)
+// SearchAccounts returns one page of the accounts matching the filter, only staff members are allowed to
+func (service *AccountService) SearchAccounts(ctx context.Context, actor Actor, filter AccountFilter) (AccountPage, error) {
+	page := AccountPage{
+		Page:     filter.page(),
+		PageSize: filter.pageSize(),
+	}
+	if err := service.policy.CanManageAccounts(actor); err != nil {
+		return page, err
+	}
+	err := service.store.ExecTx(ctx, func(q db.Querier) error {
+		var err error
+		page.Total, err = q.CountAccounts(ctx, filter.countParams())
+		if err != nil {
+			return err
+		}
+		page.Accounts, err = q.SearchAccounts(ctx, filter.searchParams())
+		return err
+	})
+	if page.Accounts == nil {
+		page.Accounts = make([]db.Account, 0)
+	}
+	return page, err
+}
+
+// UpdateAccountRole changes the role of an account, only staff members are allowed to
+func (service *AccountService) UpdateAccountRole(ctx context.Context, actor Actor, ID uuid.UUID, role db.AccountRole) (db.Account, error) {
+	var dbAccount db.Account
+	if err := service.policy.CanManageAccounts(actor); err != nil {
+		return dbAccount, err
+	}
+	err := service.store.ExecTx(ctx, func(q db.Querier) error {
+		before, err := assertAccountExists(ctx, q, ID)
+		if err != nil {
+			return err
+		}
+		arg := db.UpdateAccountRoleParams{
+			Role:        role,
+			UpdatedBy:   auditedBy(ctx),
+			AccountUuid: before.AccountUuid,
+		}
+		dbAccount, err = q.UpdateAccountRole(ctx, arg)
+		if err != nil {
+			return err
+		}
+		err = recordAudit(ctx, q, AuditUpdate, EntityAccount, dbAccount.AccountUuid,
+			newAccountEventData(before), newAccountEventData(dbAccount))
+		if err != nil {
+			return err
+		}
+		return publishEvent(ctx, q, newAccountEvent(EventAccountUpdated, dbAccount))
+	})
+	return dbAccount, err
 }
 
-func (service *AccountService) isUsernameAlreadyTaken(Username string) bool {
-	_, err := service.queries.GetAccountByUsername(context.Background(), Username)
-	return err == nil || err != sql.ErrNoRows
+// GetAccountByID find account by id
+func (service *AccountService) GetAccountByID(ctx context.Context, id uuid.UUID) (db.Account, error) {
+	dbAccount, err := service.store.GetAccountById(ctx, id)
+	return dbAccount, orNotFound(err, ErrAccountNotFound)
 }
 
 // AssertAccountExists Returns the account with the given ID
-func (service *AccountService) AssertAccountExists(ID uuid.UUID) (db.Account, error) {
-	return service.queries.GetAccountById(context.Background(), ID)
+func (service *AccountService) AssertAccountExists(ctx context.Context, ID uuid.UUID) (db.Account, error) {
+	return assertAccountExists(ctx, service.store, ID)
 }
 
-func (service *AccountService) createAddressForAccount(account db.Account, address *db.Address) (db.Address, error) {
-	var dbAddress db.Address
-	addressArg := db.CreateAddressParams{
-		Name:        address.Name,
-		Street:      address.Street,
-		City:        address.City,
-		State:       address.State,
-		Zipcode:     address.Zipcode,
-		AccountUuid: account.AccountUuid,
+func assertAccountExists(ctx context.Context, q db.Querier, ID uuid.UUID) (db.Account, error) {
+	dbAccount, err := q.GetAccountById(ctx, ID)
+	return dbAccount, orNotFound(err, ErrAccountNotFound)
+}
+
+// isUsernameAlreadyTaken tells whether an account has the username, the errors of the lookup other
+// than a missing account being returned
+func isUsernameAlreadyTaken(ctx context.Context, q db.Querier, Username string) (bool, error) {
+	_, err := q.GetAccountByUsername(ctx, Username)
+	if err == sql.ErrNoRows {
+		return false, nil
 	}
-	dbAddress, err := service.queries.CreateAddress(context.Background(), addressArg)
-	return dbAddress, err
+	return err == nil, err
 }
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"database/sql"
+	"strings"
+
+	db "github.com/valverdethiago/trading-api/db/sqlc"
+)
+
+const (
+	// DefaultAccountPageSize is the page size used when the filter doesn't set one
+	DefaultAccountPageSize = 20
+	// MaxAccountPageSize is the largest page an account search may return
+	MaxAccountPageSize = 100
+)
+
+var likePatternEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// AccountFilter narrows the account search, empty fields match everything
+type AccountFilter struct {
+	UsernamePrefix string
+	Email          string
+	CreatedFrom    sql.NullTime
+	CreatedTo      sql.NullTime
+	State          db.State
+	// Query is matched against username and email, ranking the best matches first
+	Query    string
+	Page     int32
+	PageSize int32
+}
+
+// AccountPage is one page of the account search and the number of accounts matching it
+type AccountPage struct {
+	Accounts []db.Account
+	Total    int64
+	Page     int32
+	PageSize int32
+}
+
+// HasNextPage tells whether there are matching accounts after this page
+func (page AccountPage) HasNextPage() bool {
+	return int64(page.Page)*int64(page.PageSize) < page.Total
+}
+
+func (filter AccountFilter) page() int32 {
+	if filter.Page <= 0 {
+		return 1
+	}
+	return filter.Page
+}
+
+func (filter AccountFilter) pageSize() int32 {
+	if filter.PageSize <= 0 {
+		return DefaultAccountPageSize
+	}
+	if filter.PageSize > MaxAccountPageSize {
+		return MaxAccountPageSize
+	}
+	return filter.PageSize
+}
+
+func (filter AccountFilter) countParams() db.CountAccountsParams {
+	return db.CountAccountsParams{
+		UsernamePrefix: likePatternEscaper.Replace(filter.UsernamePrefix),
+		Email:          filter.Email,
+		CreatedFrom:    filter.CreatedFrom,
+		CreatedTo:      filter.CreatedTo,
+		State:          string(filter.State),
+		Query:          filter.Query,
+	}
+}
+
+func (filter AccountFilter) searchParams() db.SearchAccountsParams {
+	pageSize := filter.pageSize()
+	return db.SearchAccountsParams{
+		UsernamePrefix: likePatternEscaper.Replace(filter.UsernamePrefix),
+		Email:          filter.Email,
+		CreatedFrom:    filter.CreatedFrom,
+		CreatedTo:      filter.CreatedTo,
+		State:          string(filter.State),
+		Query:          filter.Query,
+		PageSize:       pageSize,
+		PageOffset:     (filter.page() - 1) * pageSize,
+	}
+}
@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/google/uuid"
+	db "github.com/valverdethiago/trading-api/db/sqlc"
+	"github.com/valverdethiago/trading-api/execution"
+)
+
+// Errors returned when an account status change breaks the lifecycle rules
+var (
+	ErrAddressRequired         = NewConflictError("ADDRESS_REQUIRED", "Account must have an address to be approved")
+	ErrInvalidStatusTransition = NewConflictError("INVALID_STATUS_TRANSITION", "Account status transition not allowed")
+)
+
+// accountStatusTransitions lists the statuses an account can move to from each status
+var accountStatusTransitions = map[db.AccountStatus][]db.AccountStatus{
+	db.AccountStatusPENDING:  {db.AccountStatusAPPROVED, db.AccountStatusINACTIVE},
+	db.AccountStatusAPPROVED: {db.AccountStatusINACTIVE},
+	db.AccountStatusINACTIVE: {db.AccountStatusAPPROVED},
+}
+
+// AccountStatusService service to handle the lifecycle of accounts
+type AccountStatusService struct {
+	store       db.Store
+	policy      *Policy
+	broadcaster EventBroadcaster
+}
+
+// NewAccountStatusService creates a new AccountStatusService instance, broadcasting the cancellations
+// of the trades of the deactivated accounts
+func NewAccountStatusService(store db.Store, policy *Policy, broadcaster EventBroadcaster) *AccountStatusService {
+	return &AccountStatusService{
+		store:       store,
+		policy:      policy,
+		broadcaster: broadcaster,
+	}
+}
+
+// ApproveAccount approves an account that already has an address, only staff members are allowed to
+func (service *AccountStatusService) ApproveAccount(ctx context.Context, actor Actor, ID uuid.UUID) (db.Account, error) {
+	var dbAccount db.Account
+	if err := service.policy.CanManageAccounts(actor); err != nil {
+		return dbAccount, err
+	}
+	err := service.store.ExecTx(ctx, func(q db.Querier) error {
+		before, err := assertTransitionAllowed(ctx, q, ID, db.AccountStatusAPPROVED)
+		if err != nil {
+			return err
+		}
+		_, err = getAddressByAccountID(ctx, q, before.AccountUuid)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return ErrAddressRequired
+			}
+			return err
+		}
+		arg := db.UpdateAccountStatusParams{
+			Status:      db.AccountStatusAPPROVED,
+			UpdatedBy:   auditedBy(ctx),
+			AccountUuid: before.AccountUuid,
+		}
+		dbAccount, err = q.UpdateAccountStatus(ctx, arg)
+		if err != nil {
+			return err
+		}
+		err = recordAudit(ctx, q, AuditUpdate, EntityAccount, dbAccount.AccountUuid,
+			newAccountEventData(before), newAccountEventData(dbAccount))
+		if err != nil {
+			return err
+		}
+		return publishEvent(ctx, q, newAccountEvent(EventAccountUpdated, dbAccount))
+	})
+	return dbAccount, err
+}
+
+// DeactivateAccount inactivates an account cancelling all of its open trades,
+// only staff members are allowed to
+func (service *AccountStatusService) DeactivateAccount(ctx context.Context, actor Actor, ID uuid.UUID) (db.Account, error) {
+	var dbAccount db.Account
+	if err := service.policy.CanManageAccounts(actor); err != nil {
+		return dbAccount, err
+	}
+	err := execTxAndBroadcast(ctx, service.store, service.broadcaster, func(q db.Querier) error {
+		before, err := assertTransitionAllowed(ctx, q, ID, db.AccountStatusINACTIVE)
+		if err != nil {
+			return err
+		}
+		if err := cancelOpenTrades(ctx, q, before.AccountUuid); err != nil {
+			return err
+		}
+		dbAccount, err = q.UpdateAccountStatus(ctx, db.UpdateAccountStatusParams{
+			Status:      db.AccountStatusINACTIVE,
+			UpdatedBy:   auditedBy(ctx),
+			AccountUuid: before.AccountUuid,
+		})
+		if err != nil {
+			return err
+		}
+		err = recordAudit(ctx, q, AuditUpdate, EntityAccount, dbAccount.AccountUuid,
+			newAccountEventData(before), newAccountEventData(dbAccount))
+		if err != nil {
+			return err
+		}
+		return publishEvent(ctx, q, newAccountEvent(EventAccountUpdated, dbAccount))
+	})
+	return dbAccount, err
+}
+
+// cancelOpenTrades cancels the open trades of the account one by one, auditing and publishing their
+// cancellation
+func cancelOpenTrades(ctx context.Context, q db.Querier, accountUUID uuid.UUID) error {
+	trades, err := q.ListOpenTradesByAccount(ctx, accountUUID)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+	for _, trade := range trades {
+		// the lock keeps a fill from completing the trade between the check and the cancellation
+		before, err := q.GetTradeByIdForUpdate(ctx, trade.TradeUuid)
+		if err != nil {
+			return err
+		}
+		if !execution.IsOpen(before.Status) {
+			continue
+		}
+		dbTrade, err := q.UpdateTradeStatus(ctx, db.UpdateTradeStatusParams{
+			TradeUuid: before.TradeUuid,
+			Status:    db.TradeStatusCANCELLED,
+			UpdatedBy: auditedBy(ctx),
+		})
+		if err != nil {
+			return err
+		}
+		if err := recordAudit(ctx, q, AuditUpdate, EntityTrade, dbTrade.TradeUuid, before, dbTrade); err != nil {
+			return err
+		}
+		if err := publishTradeStatus(ctx, q, dbTrade); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func assertTransitionAllowed(ctx context.Context, q db.Querier, ID uuid.UUID, status db.AccountStatus) (db.Account, error) {
+	dbAccount, err := assertAccountExists(ctx, q, ID)
+	if err != nil {
+		return dbAccount, err
+	}
+	for _, allowed := range accountStatusTransitions[dbAccount.Status] {
+		if allowed == status {
+			return dbAccount, nil
+		}
+	}
+	return dbAccount, fmt.Errorf("%w: from %s to %s", ErrInvalidStatusTransition, dbAccount.Status, status)
+}
@@ -3,87 +3,113 @@ package service
 import (
 	"context"
 	"database/sql"
-	"errors"
 
 	"github.com/google/uuid"
 	db "github.com/valverdethiago/trading-api/db/sqlc"
 )
 
+// ErrAddressExists is returned when an address is created for an account that already has one
+var ErrAddressExists = NewConflictError("ADDRESS_EXISTS", "Account has already an address")
+
 // AddressService service to handle business rules for addresses
 type AddressService struct {
-	queries        db.Querier
+	store          db.Store
 	accountService *AccountService
 }
 
 // NewAddressService Creates new service for address
-func NewAddressService(queries db.Querier, accountService *AccountService) *AddressService {
+func NewAddressService(store db.Store, accountService *AccountService) *AddressService {
 	return &AddressService{
-		queries:        queries,
+		store:          store,
 		accountService: accountService,
 	}
 }
 
 // GetAddressByAccountID find account by id
-func (service *AddressService) GetAddressByAccountID(ID uuid.UUID) (db.Address, error) {
-	return service.queries.GetAddressByAccount(context.Background(), ID)
+func (service *AddressService) GetAddressByAccountID(ctx context.Context, ID uuid.UUID) (db.Address, error) {
+	dbAddress, err := getAddressByAccountID(ctx, service.store, ID)
+	return dbAddress, orNotFound(err, ErrAddressNotFound)
 }
 
 // CreateAddressForAccount creates an address for an account only if there's no address yet
-func (service *AddressService) CreateAddressForAccount(ID uuid.UUID, address db.Address) (db.Address, error) {
+func (service *AddressService) CreateAddressForAccount(ctx context.Context, ID uuid.UUID, address db.Address) (db.Address, error) {
 	var dbAddress db.Address
-	dbAccount, err := service.accountService.AssertAccountExists(ID)
-	if err != nil {
-		return dbAddress, err
-	}
-	if service.accountAlreadyHasAddress(ID) {
-		return dbAddress, errors.New("Account has already an address")
-	}
-
-	arg := db.CreateAddressParams{
-		Name:        address.Name,
-		Street:      address.Street,
-		City:        address.City,
-		State:       address.State,
-		Zipcode:     address.Zipcode,
-		AccountUuid: dbAccount.AccountUuid,
-	}
-	return service.queries.CreateAddress(context.Background(), arg)
+	err := service.store.ExecTx(ctx, func(q db.Querier) error {
+		dbAccount, err := assertAccountExists(ctx, q, ID)
+		if err != nil {
+			return err
+		}
+		hasAddress, err := accountAlreadyHasAddress(ctx, q, ID)
+		if err != nil {
+			return err
+		}
+		if hasAddress {
+			return ErrAddressExists
+		}
+		dbAddress, err = createAddressForAccount(ctx, q, dbAccount, address)
+		return err
+	})
+	return dbAddress, err
 }
 
 // UpdateAddressForAccount creates an address for an account only if there's no address yet
-func (service *AddressService) UpdateAddressForAccount(ID uuid.UUID, address db.Address) (db.Address, error) {
+func (service *AddressService) UpdateAddressForAccount(ctx context.Context, ID uuid.UUID, address db.Address) (db.Address, error) {
 	var dbAddress db.Address
-	_, err := service.accountService.AssertAccountExists(ID)
-	if err != nil {
-		return dbAddress, err
-	}
-	dbAddress, err = service.GetAddressByAccountID(ID)
-	if err != nil && err == sql.ErrNoRows {
-		return dbAddress, err
+	err := service.store.ExecTx(ctx, func(q db.Querier) error {
+		_, err := assertAccountExists(ctx, q, ID)
+		if err != nil {
+			return err
+		}
+		before, err := getAddressByAccountID(ctx, q, ID)
+		if err != nil {
+			return orNotFound(err, ErrAddressNotFound)
+		}
+
+		arg := db.UpdateAddressParams{
+			Name:        address.Name,
+			Street:      address.Street,
+			City:        address.City,
+			State:       address.State,
+			Zipcode:     address.Zipcode,
+			UpdatedBy:   auditedBy(ctx),
+			AddressUuid: before.AddressUuid,
+		}
+		dbAddress, err = q.UpdateAddress(ctx, arg)
+		if err != nil {
+			return err
+		}
+		return recordAudit(ctx, q, AuditUpdate, EntityAddress, dbAddress.AddressUuid, before, dbAddress)
+	})
+	return dbAddress, err
+}
+
+func getAddressByAccountID(ctx context.Context, q db.Querier, ID uuid.UUID) (db.Address, error) {
+	return q.GetAddressByAccount(ctx, ID)
+}
+
+// accountAlreadyHasAddress tells whether the account has an address, the errors of the lookup other
+// than a missing address being returned
+func accountAlreadyHasAddress(ctx context.Context, q db.Querier, ID uuid.UUID) (bool, error) {
+	_, err := getAddressByAccountID(ctx, q, ID)
+	if err == sql.ErrNoRows {
+		return false, nil
 	}
+	return err == nil, err
+}
 
-	arg := db.UpdateAddressParams{
+func createAddressForAccount(ctx context.Context, q db.Querier, account db.Account, address db.Address) (db.Address, error) {
+	arg := db.CreateAddressParams{
 		Name:        address.Name,
 		Street:      address.Street,
 		City:        address.City,
 		State:       address.State,
 		Zipcode:     address.Zipcode,
-		AddressUuid: dbAddress.AddressUuid,
+		AccountUuid: account.AccountUuid,
+		CreatedBy:   auditedBy(ctx),
 	}
-	return service.queries.UpdateAddress(context.Background(), arg)
-}
-
-// getAddressByAccountID Returns the address attached to the account with the given ID
-func (service *AddressService) getAddressByAccountID(ID uuid.UUID) (db.Address, error) {
-	var dbAddress db.Address
-	dbAccount, err := service.accountService.AssertAccountExists(ID)
+	dbAddress, err := q.CreateAddress(ctx, arg)
 	if err != nil {
 		return dbAddress, err
 	}
-	return service.queries.GetAddressByAccount(context.Background(), dbAccount.AccountUuid)
-}
-
-func (service *AddressService) accountAlreadyHasAddress(ID uuid.UUID) bool {
-	_, err := service.GetAddressByAccountID(ID)
-	return err == nil || err != sql.ErrNoRows
+	return dbAddress, recordAudit(ctx, q, AuditCreate, EntityAddress, dbAddress.AddressUuid, nil, dbAddress)
 }
@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	db "github.com/valverdethiago/trading-api/db/sqlc"
+)
+
+const (
+	// SystemActor makes the changes that no request asked for, like the execution reports of the venue
+	SystemActor = "system"
+	// AnonymousActor makes the changes of the requests without a token, like the sign ups
+	AnonymousActor = "anonymous"
+	// DefaultAuditPageSize is the page size used when the filter doesn't set one
+	DefaultAuditPageSize = 50
+	// MaxAuditPageSize is the largest page of the audit log a query may return
+	MaxAuditPageSize = 100
+)
+
+// AuditAction names what a change did to an entity
+type AuditAction string
+
+const (
+	AuditCreate AuditAction = "create"
+	AuditUpdate AuditAction = "update"
+	AuditDelete AuditAction = "delete"
+)
+
+// Entities whose changes are recorded in the audit log
+const (
+	EntityAccount           = "account"
+	EntityAddress           = "address"
+	EntityTrade             = "trade"
+	EntityInstrument        = "instrument"
+	EntityRiskLimit         = "risk_limit"
+	EntityWebhook           = "webhook"
+	EntityWebhookDeadLetter = "webhook_dead_letter"
+	EntityJournalEntry      = "journal_entry"
+)
+
+// Origin tells who made a change and where the request came from, recorded along with the change
+type Origin struct {
+	Actor     string
+	RequestID string
+	IP        string
+}
+
+type originKey struct{}
+
+// WithOrigin returns a copy of the context carrying the origin of the changes made with it
+func WithOrigin(ctx context.Context, origin Origin) context.Context {
+	return context.WithValue(ctx, originKey{}, origin)
+}
+
+// OriginFromContext returns the origin carried by the context, the changes made without one being
+// made by the system
+func OriginFromContext(ctx context.Context) Origin {
+	origin, _ := ctx.Value(originKey{}).(Origin)
+	if origin.Actor == "" {
+		origin.Actor = SystemActor
+	}
+	return origin
+}
+
+// AuditFilter criteria to query the audit log, an empty criterion matching every change
+type AuditFilter struct {
+	EntityType string
+	EntityID   string
+	Actor      string
+	Page       int32
+	PageSize   int32
+}
+
+func (filter AuditFilter) page() int32 {
+	if filter.Page <= 0 {
+		return 1
+	}
+	return filter.Page
+}
+
+func (filter AuditFilter) pageSize() int32 {
+	if filter.PageSize <= 0 {
+		return DefaultAuditPageSize
+	}
+	if filter.PageSize > MaxAuditPageSize {
+		return MaxAuditPageSize
+	}
+	return filter.PageSize
+}
+
+// AuditService service to query the audit log
+type AuditService struct {
+	store  db.Store
+	policy *Policy
+}
+
+// NewAuditService creates a new AuditService instance
+func NewAuditService(store db.Store, policy *Policy) *AuditService {
+	return &AuditService{
+		store:  store,
+		policy: policy,
+	}
+}
+
+// AuditPage is one page of the changes matching an audit log query
+type AuditPage struct {
+	Entries  []db.AuditLog
+	Page     int32
+	PageSize int32
+}
+
+// HasNextPage tells whether there may be matching changes after this page, which is the case when it's full
+func (page AuditPage) HasNextPage() bool {
+	return int32(len(page.Entries)) == page.PageSize
+}
+
+// ListAuditLog lists one page of the changes matching the filter, newest first, only staff members
+// are allowed to
+func (service *AuditService) ListAuditLog(ctx context.Context, actor Actor, filter AuditFilter) (AuditPage, error) {
+	page := AuditPage{
+		Page:     filter.page(),
+		PageSize: filter.pageSize(),
+	}
+	if err := service.policy.CanViewAuditLog(actor); err != nil {
+		return page, err
+	}
+	var err error
+	page.Entries, err = service.store.ListAuditLog(ctx, db.ListAuditLogParams{
+		EntityType: filter.EntityType,
+		EntityID:   filter.EntityID,
+		Actor:      filter.Actor,
+		PageSize:   page.PageSize,
+		PageOffset: (page.Page - 1) * page.PageSize,
+	})
+	if err == sql.ErrNoRows {
+		err = nil
+	}
+	if page.Entries == nil {
+		page.Entries = make([]db.AuditLog, 0)
+	}
+	return page, err
+}
+
+// auditedBy is the actor of the context, as recorded in the created_by and updated_by columns
+func auditedBy(ctx context.Context) sql.NullString {
+	return sql.NullString{String: OriginFromContext(ctx).Actor, Valid: true}
+}
+
+// recordAudit appends a change of an entity to the audit log in the transaction of the change, before
+// being nil for creations and after for deletions
+func recordAudit(ctx context.Context, q db.Querier, action AuditAction, entityType string, entityID interface{},
+	before interface{}, after interface{}) error {
+	beforeJSON, err := json.Marshal(before)
+	if err != nil {
+		return err
+	}
+	afterJSON, err := json.Marshal(after)
+	if err != nil {
+		return err
+	}
+	origin := OriginFromContext(ctx)
+	_, err = q.CreateAuditLog(ctx, db.CreateAuditLogParams{
+		Actor:      origin.Actor,
+		Action:     string(action),
+		EntityType: entityType,
+		EntityID:   fmt.Sprint(entityID),
+		Before:     beforeJSON,
+		After:      afterJSON,
+		RequestID:  sql.NullString{String: origin.RequestID, Valid: origin.RequestID != ""},
+		Ip:         sql.NullString{String: origin.IP, Valid: origin.IP != ""},
+	})
+	return err
+}
+
+// auditedWebhook is the webhook as recorded in the audit log, without its secret
+func auditedWebhook(dbSubscription db.WebhookSubscription) db.WebhookSubscription {
+	dbSubscription.Secret = ""
+	return dbSubscription
+}
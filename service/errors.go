@@ -0,0 +1,126 @@
+package service
+
+import (
+	"database/sql"
+)
+
+// ErrorKind is the class of a domain error, telling the clients whether to fix the request, look
+// for another resource or try again later
+type ErrorKind string
+
+const (
+	// KindValidation is a request that can't be valid, whatever the state of the system
+	KindValidation ErrorKind = "VALIDATION"
+	// KindUnauthorized is a request whose credentials don't identify an account
+	KindUnauthorized ErrorKind = "UNAUTHORIZED"
+	// KindForbidden is a request the actor isn't allowed to make
+	KindForbidden ErrorKind = "FORBIDDEN"
+	// KindNotFound is a request on a resource that doesn't exist
+	KindNotFound ErrorKind = "NOT_FOUND"
+	// KindConflict is a request the current state of the resource doesn't allow
+	KindConflict ErrorKind = "CONFLICT"
+	// KindRejected is a valid request the business rules turn down, like an order the market can't take
+	KindRejected ErrorKind = "REJECTED"
+)
+
+// FieldError tells which field of a request is invalid and why
+type FieldError struct {
+	Field  string `json:"field"`
+	Reason string `json:"reason"`
+}
+
+// Error is a domain error, identified by a stable code the clients can rely on. The sentinels are
+// matched by code with errors.Is, whatever the details wrapped around them
+type Error struct {
+	Kind    ErrorKind
+	Code    string
+	Message string
+	Fields  []FieldError
+	cause   error
+}
+
+func (err *Error) Error() string {
+	return err.Message
+}
+
+// Unwrap returns the error that caused the domain error, if any
+func (err *Error) Unwrap() error {
+	return err.cause
+}
+
+// Is matches the domain errors of the same code
+func (err *Error) Is(target error) bool {
+	other, ok := target.(*Error)
+	return ok && other.Code == err.Code
+}
+
+// Wrap returns a copy of the error caused by another one
+func (err *Error) Wrap(cause error) *Error {
+	result := *err
+	result.cause = cause
+	return &result
+}
+
+// WithFields returns a copy of the error detailing the invalid fields
+func (err *Error) WithFields(fields ...FieldError) *Error {
+	result := *err
+	result.Fields = fields
+	return &result
+}
+
+// NewValidationError creates an error for a request that can't be valid
+func NewValidationError(code string, message string, fields ...FieldError) *Error {
+	return &Error{Kind: KindValidation, Code: code, Message: message, Fields: fields}
+}
+
+// NewUnauthorizedError creates an error for a request without valid credentials
+func NewUnauthorizedError(code string, message string) *Error {
+	return &Error{Kind: KindUnauthorized, Code: code, Message: message}
+}
+
+// NewForbiddenError creates an error for a request the actor isn't allowed to make
+func NewForbiddenError(code string, message string) *Error {
+	return &Error{Kind: KindForbidden, Code: code, Message: message}
+}
+
+// NewNotFoundError creates an error for a request on a missing resource
+func NewNotFoundError(code string, message string) *Error {
+	return &Error{Kind: KindNotFound, Code: code, Message: message}
+}
+
+// NewConflictError creates an error for a request the state of the resource doesn't allow
+func NewConflictError(code string, message string) *Error {
+	return &Error{Kind: KindConflict, Code: code, Message: message}
+}
+
+// NewRejectedError creates an error for a request turned down by the business rules
+func NewRejectedError(code string, message string) *Error {
+	return &Error{Kind: KindRejected, Code: code, Message: message}
+}
+
+// Errors returned when the resource of a request doesn't exist
+var (
+	ErrAccountNotFound    = NewNotFoundError("ACCOUNT_NOT_FOUND", "No account found for this id")
+	ErrAddressNotFound    = NewNotFoundError("ADDRESS_NOT_FOUND", "The account has no address")
+	ErrTradeNotFound      = NewNotFoundError("TRADE_NOT_FOUND", "No trade found for this id")
+	ErrInstrumentNotFound = NewNotFoundError("INSTRUMENT_NOT_FOUND", "No instrument found for this symbol")
+	ErrPositionNotFound   = NewNotFoundError("POSITION_NOT_FOUND", "The account never traded this symbol")
+	ErrWebhookNotFound    = NewNotFoundError("WEBHOOK_NOT_FOUND", "No webhook found for this id")
+	ErrDeadLetterNotFound = NewNotFoundError("DEAD_LETTER_NOT_FOUND", "No dead letter found for this id")
+)
+
+// orNotFound replaces sql.ErrNoRows with the not found error of the resource, keeping it as the cause
+func orNotFound(err error, notFound *Error) error {
+	if err == sql.ErrNoRows {
+		return notFound.Wrap(err)
+	}
+	return err
+}
+
+// invalidField details the validation error with the invalid field of the request, the result
+// matching the sentinel with errors.Is
+func invalidField(sentinel *Error, field string, reason string) *Error {
+	result := sentinel.WithFields(FieldError{Field: field, Reason: reason})
+	result.Message = sentinel.Message + ": " + field + " " + reason
+	return result
+}
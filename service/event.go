@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	db "github.com/valverdethiago/trading-api/db/sqlc"
+)
+
+// EventType names a change of state of an account or of its trades
+type EventType string
+
+const (
+	EventTradeCreated   EventType = "trade.created"
+	EventTradeAmended   EventType = "trade.amended"
+	EventTradeFilled    EventType = "trade.filled"
+	EventTradeCompleted EventType = "trade.completed"
+	EventTradeCancelled EventType = "trade.cancelled"
+	EventTradeFailed    EventType = "trade.failed"
+	EventAccountCreated EventType = "account.created"
+	EventAccountUpdated EventType = "account.updated"
+)
+
+// Aggregates are the entities whose events are published in order
+const (
+	AggregateTrade   = "trade"
+	AggregateAccount = "account"
+)
+
+// EventTypes are all the events published
+var EventTypes = []EventType{
+	EventTradeCreated,
+	EventTradeAmended,
+	EventTradeFilled,
+	EventTradeCompleted,
+	EventTradeCancelled,
+	EventTradeFailed,
+	EventAccountCreated,
+	EventAccountUpdated,
+}
+
+// IsEventType tells whether the name is one of the published events
+func IsEventType(name string) bool {
+	for _, eventType := range EventTypes {
+		if string(eventType) == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Event is a change of state of an account or of one of its trades, Data holding the trade or the
+// account once changed
+type Event struct {
+	EventUUID   uuid.UUID   `json:"event_uuid"`
+	Type        EventType   `json:"type"`
+	AccountUUID uuid.UUID   `json:"account_uuid"`
+	CreatedDate time.Time   `json:"created_date"`
+	Data        interface{} `json:"data"`
+	// AggregateType and AggregateUUID name the entity that changed, whose events are kept in order
+	AggregateType string    `json:"-"`
+	AggregateUUID uuid.UUID `json:"-"`
+}
+
+// EventBroadcaster relays the events of the accounts to their live listeners
+type EventBroadcaster interface {
+	Publish(accountUUID uuid.UUID, event interface{})
+}
+
+// accountEventData is the account published in the events, without its password
+type accountEventData struct {
+	AccountUUID uuid.UUID        `json:"account_uuid"`
+	Username    string           `json:"username"`
+	Email       string           `json:"email"`
+	Role        db.AccountRole   `json:"role"`
+	Status      db.AccountStatus `json:"status"`
+}
+
+func newEvent(eventType EventType, accountUUID uuid.UUID, aggregateType string, aggregateUUID uuid.UUID,
+	data interface{}) Event {
+	return Event{
+		EventUUID:     uuid.New(),
+		Type:          eventType,
+		AccountUUID:   accountUUID,
+		CreatedDate:   time.Now().UTC(),
+		Data:          data,
+		AggregateType: aggregateType,
+		AggregateUUID: aggregateUUID,
+	}
+}
+
+func newTradeEvent(eventType EventType, dbTrade db.Trade) Event {
+	return newEvent(eventType, dbTrade.AccountUuid, AggregateTrade, dbTrade.TradeUuid, dbTrade)
+}
+
+func newAccountEvent(eventType EventType, dbAccount db.Account) Event {
+	return newEvent(eventType, dbAccount.AccountUuid, AggregateAccount, dbAccount.AccountUuid,
+		newAccountEventData(dbAccount))
+}
+
+func newAccountEventData(dbAccount db.Account) accountEventData {
+	return accountEventData{
+		AccountUUID: dbAccount.AccountUuid,
+		Username:    dbAccount.Username,
+		Email:       dbAccount.Email,
+		Role:        dbAccount.Role,
+		Status:      dbAccount.Status,
+	}
+}
+
+// tradeStatusEvent is the event of a trade reaching the status, if any
+func tradeStatusEvent(status db.TradeStatus) (EventType, bool) {
+	switch status {
+	case db.TradeStatusPARTIALLY_FILLED:
+		return EventTradeFilled, true
+	case db.TradeStatusCOMPLETED:
+		return EventTradeCompleted, true
+	case db.TradeStatusCANCELLED:
+		return EventTradeCancelled, true
+	case db.TradeStatusFAILED:
+		return EventTradeFailed, true
+	}
+	return "", false
+}
+
+// publishTradeStatus publishes the event of the status the trade reached, if any
+func publishTradeStatus(ctx context.Context, q db.Querier, dbTrade db.Trade) error {
+	eventType, ok := tradeStatusEvent(dbTrade.Status)
+	if !ok {
+		return nil
+	}
+	return publishEvent(ctx, q, newTradeEvent(eventType, dbTrade))
+}
+
+// publishEvent publishes the event in the transaction of the change, writing it to the outbox and
+// queueing its delivery to the webhooks of the account subscribed to it. Transactions run by
+// execTxAndBroadcast also broadcast it once committed
+func publishEvent(ctx context.Context, q db.Querier, event Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = q.CreateOutboxEvent(ctx, db.CreateOutboxEventParams{
+		EventUuid:     event.EventUUID,
+		EventType:     string(event.Type),
+		AggregateType: event.AggregateType,
+		AggregateUuid: event.AggregateUUID,
+		Payload:       payload,
+	})
+	if err != nil {
+		return err
+	}
+	if err := enqueueWebhookDeliveries(ctx, q, event, payload); err != nil {
+		return err
+	}
+	if broadcasting, ok := q.(broadcastingQuerier); ok {
+		*broadcasting.events = append(*broadcasting.events, event)
+	}
+	return nil
+}
+
+// broadcastingQuerier collects the events published in a transaction
+type broadcastingQuerier struct {
+	db.Querier
+	events *[]Event
+}
+
+// execTxAndBroadcast runs fn in a transaction of the store, broadcasting the events it published
+// once committed, so that live listeners never see a change that was rolled back
+func execTxAndBroadcast(ctx context.Context, store db.Store, broadcaster EventBroadcaster,
+	fn func(q db.Querier) error) error {
+	var events []Event
+	err := store.ExecTx(ctx, func(q db.Querier) error {
+		// a retried transaction publishes its events again
+		events = nil
+		return fn(broadcastingQuerier{Querier: q, events: &events})
+	})
+	if err != nil || broadcaster == nil {
+		return err
+	}
+	for _, event := range events {
+		broadcaster.Publish(event.AccountUUID, event)
+	}
+	return nil
+}
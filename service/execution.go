@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/shopspring/decimal"
+	db "github.com/valverdethiago/trading-api/db/sqlc"
+	"github.com/valverdethiago/trading-api/execution"
+)
+
+// ErrInvalidReport is returned when an execution report doesn't match the trade it refers to
+var ErrInvalidReport = NewValidationError("INVALID_REPORT", "Invalid execution report")
+
+// averageFillPriceScale is the number of decimal places kept on the average fill price
+const averageFillPriceScale = 8
+
+// ExecutionService applies the reports of the execution venue to the trades
+type ExecutionService struct {
+	store       db.Store
+	feeRate     decimal.Decimal
+	broadcaster EventBroadcaster
+}
+
+// NewExecutionService creates a new ExecutionService instance, charging feeRate on the notional of the fills
+// and broadcasting the events of the trades once committed
+func NewExecutionService(store db.Store, feeRate decimal.Decimal, broadcaster EventBroadcaster) *ExecutionService {
+	return &ExecutionService{
+		store:       store,
+		feeRate:     feeRate,
+		broadcaster: broadcaster,
+	}
+}
+
+// HandleReport records the fills, posts them to the ledger and the positions and updates the status
+// of the reported trade, unless it was cancelled in the meantime
+func (service *ExecutionService) HandleReport(ctx context.Context, report execution.Report) error {
+	return execTxAndBroadcast(ctx, service.store, service.broadcaster, func(q db.Querier) error {
+		before, err := q.GetTradeByIdForUpdate(ctx, report.TradeUUID)
+		if err != nil {
+			return err
+		}
+		if !execution.IsOpen(before.Status) {
+			log.Printf("Ignoring %s report for trade %s on %s status", report.Status, before.TradeUuid, before.Status)
+			return nil
+		}
+		fill := report.Status == db.TradeStatusCOMPLETED || report.Status == db.TradeStatusPARTIALLY_FILLED
+		if report.Quantity <= 0 && fill {
+			return fmt.Errorf("%w: %s report for trade %s without quantity", ErrInvalidReport, report.Status, before.TradeUuid)
+		}
+		var dbTrade db.Trade
+		if report.Quantity > 0 {
+			dbTrade, err = applyFill(ctx, q, before, report, service.feeRate)
+		} else {
+			dbTrade, err = q.UpdateTradeStatus(ctx, db.UpdateTradeStatusParams{
+				TradeUuid: before.TradeUuid,
+				Status:    report.Status,
+				UpdatedBy: auditedBy(ctx),
+			})
+		}
+		if err != nil {
+			return err
+		}
+		if err := recordAudit(ctx, q, AuditUpdate, EntityTrade, dbTrade.TradeUuid, before, dbTrade); err != nil {
+			return err
+		}
+		return publishTradeStatus(ctx, q, dbTrade)
+	})
+}
+
+// applyFill records the execution with the fee charged on it, posts it to the ledger, moves the position
+// and updates the filled quantity and average price of the trade
+func applyFill(ctx context.Context, q db.Querier, dbTrade db.Trade, report execution.Report, feeRate decimal.Decimal) (db.Trade, error) {
+	if report.Quantity > dbTrade.RemainingQuantity {
+		return dbTrade, fmt.Errorf("%w: fill of %d exceeds the remaining %d of trade %s",
+			ErrInvalidReport, report.Quantity, dbTrade.RemainingQuantity, dbTrade.TradeUuid)
+	}
+	dbExecution, err := q.CreateTradeExecution(ctx, db.CreateTradeExecutionParams{
+		TradeUuid: dbTrade.TradeUuid,
+		Quantity:  report.Quantity,
+		Price:     report.Price,
+		Fee:       tradeFee(report.Price.Mul(decimal.NewFromInt(report.Quantity)), feeRate),
+	})
+	if err != nil {
+		return dbTrade, err
+	}
+	if err := postFill(ctx, q, dbTrade, dbExecution); err != nil {
+		return dbTrade, err
+	}
+	if _, err := updatePosition(ctx, q, dbTrade, dbExecution); err != nil {
+		return dbTrade, err
+	}
+	filled := dbTrade.FilledQuantity + report.Quantity
+	notional := report.Price.Mul(decimal.NewFromInt(report.Quantity))
+	if dbTrade.AverageFillPrice.Valid {
+		notional = notional.Add(dbTrade.AverageFillPrice.Decimal.Mul(decimal.NewFromInt(dbTrade.FilledQuantity)))
+	}
+	status := db.TradeStatusPARTIALLY_FILLED
+	if filled == dbTrade.Quantity {
+		status = db.TradeStatusCOMPLETED
+	}
+	return q.UpdateTradeFill(ctx, db.UpdateTradeFillParams{
+		FilledQuantity:    filled,
+		RemainingQuantity: dbTrade.Quantity - filled,
+		AverageFillPrice: decimal.NullDecimal{
+			Decimal: notional.DivRound(decimal.NewFromInt(filled), averageFillPriceScale),
+			Valid:   true,
+		},
+		Status:    status,
+		UpdatedBy: auditedBy(ctx),
+		TradeUuid: dbTrade.TradeUuid,
+	})
+}
+
+// ResubmitPendingTrades sends the trades left open to the venue, e.g. after a restart
+func (service *ExecutionService) ResubmitPendingTrades(ctx context.Context, venue execution.Venue) error {
+	for _, status := range []db.TradeStatus{db.TradeStatusSUBMITTED, db.TradeStatusPARTIALLY_FILLED} {
+		dbTrades, err := service.store.ListTradesByStatus(ctx, status)
+		if err != nil {
+			return err
+		}
+		for _, dbTrade := range dbTrades {
+			if err := venue.Submit(ctx, dbTrade); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
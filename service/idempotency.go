@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	db "github.com/valverdethiago/trading-api/db/sqlc"
+)
+
+var (
+	// ErrIdempotencyKeyReused is returned when a key is replayed with a different request
+	ErrIdempotencyKeyReused = NewRejectedError("IDEMPOTENCY_KEY_REUSED", "Idempotency key already used for a different request")
+	// ErrIdempotencyKeyInProgress is returned when a key is replayed before its first request is answered
+	ErrIdempotencyKeyInProgress = NewConflictError("IDEMPOTENCY_KEY_IN_PROGRESS", "A request with this idempotency key is still in progress")
+)
+
+// StoredResponse is the response recorded for an idempotency key
+type StoredResponse struct {
+	Status int
+	Body   []byte
+}
+
+// IdempotencyService records the responses of requests carrying an idempotency key, so retries get
+// the original response instead of running the request again
+type IdempotencyService struct {
+	store db.Store
+	ttl   time.Duration
+}
+
+// NewIdempotencyService creates a new IdempotencyService instance keeping keys for the given time
+func NewIdempotencyService(store db.Store, ttl time.Duration) *IdempotencyService {
+	return &IdempotencyService{
+		store: store,
+		ttl:   ttl,
+	}
+}
+
+// Begin reserves the key for the request with the given hash. It returns nil when the request must
+// run, or the response stored for the same request.
+func (service *IdempotencyService) Begin(ctx context.Context, scope string, key string, requestHash string) (*StoredResponse, error) {
+	_, err := service.store.CreateIdempotencyKey(ctx, db.CreateIdempotencyKeyParams{
+		Scope:          scope,
+		IdempotencyKey: key,
+		RequestHash:    requestHash,
+		TtlSeconds:     service.ttl.Seconds(),
+	})
+	if err == nil {
+		return nil, nil
+	}
+	if err != sql.ErrNoRows {
+		return nil, err
+	}
+	// the key is taken and not expired yet
+	dbKey, err := service.store.GetIdempotencyKey(ctx, db.GetIdempotencyKeyParams{
+		Scope:          scope,
+		IdempotencyKey: key,
+	})
+	if err == sql.ErrNoRows {
+		return nil, ErrIdempotencyKeyInProgress
+	}
+	if err != nil {
+		return nil, err
+	}
+	if dbKey.RequestHash != requestHash {
+		return nil, ErrIdempotencyKeyReused
+	}
+	if !dbKey.ResponseStatus.Valid {
+		return nil, ErrIdempotencyKeyInProgress
+	}
+	return &StoredResponse{
+		Status: int(dbKey.ResponseStatus.Int32),
+		Body:   dbKey.ResponseBody,
+	}, nil
+}
+
+// Complete stores the response of the request holding the key
+func (service *IdempotencyService) Complete(ctx context.Context, scope string, key string, response StoredResponse) error {
+	_, err := service.store.UpdateIdempotencyKeyResponse(ctx, db.UpdateIdempotencyKeyResponseParams{
+		Scope:          scope,
+		IdempotencyKey: key,
+		ResponseStatus: sql.NullInt32{Int32: int32(response.Status), Valid: true},
+		ResponseBody:   response.Body,
+	})
+	return err
+}
+
+// Release frees the key of a request that failed, so it can be retried
+func (service *IdempotencyService) Release(ctx context.Context, scope string, key string) error {
+	return service.store.DeleteIdempotencyKey(ctx, db.DeleteIdempotencyKeyParams{
+		Scope:          scope,
+		IdempotencyKey: key,
+	})
+}
+
+// PurgeExpiredKeys deletes the keys past their time to live
+func (service *IdempotencyService) PurgeExpiredKeys(ctx context.Context) error {
+	return service.store.DeleteExpiredIdempotencyKeys(ctx)
+}
@@ -0,0 +1,269 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/shopspring/decimal"
+	db "github.com/valverdethiago/trading-api/db/sqlc"
+)
+
+// ErrUnknownSymbol is returned when an order is placed on a symbol missing from the security master
+var ErrUnknownSymbol = NewRejectedError("UNKNOWN_SYMBOL", "Unknown symbol")
+
+// ErrSymbolNotTradable is returned when an order is placed on an instrument that is halted
+var ErrSymbolNotTradable = NewRejectedError("SYMBOL_NOT_TRADABLE", "The symbol is not tradable")
+
+// ErrInvalidLotSize is returned when the quantity of an order isn't a multiple of the lot size
+var ErrInvalidLotSize = NewValidationError("INVALID_LOT_SIZE", "Quantity doesn't match the lot size")
+
+// ErrInstrumentExists is returned when creating an instrument with a symbol already taken
+var ErrInstrumentExists = NewConflictError("INSTRUMENT_EXISTS", "There is already an instrument with this symbol")
+
+// ErrInvalidInstrument is returned when the reference data of an instrument is incomplete or inconsistent
+var ErrInvalidInstrument = NewValidationError("INVALID_INSTRUMENT", "Invalid instrument")
+
+var (
+	symbolPattern   = regexp.MustCompile(`^[A-Z0-9.\-]{1,12}$`)
+	currencyPattern = regexp.MustCompile(`^[A-Z]{3}$`)
+)
+
+// InstrumentService service to keep the security master, the reference data of the tradable symbols
+type InstrumentService struct {
+	store           db.Store
+	policy          *Policy
+	defaultTickSize decimal.Decimal
+}
+
+// NewInstrumentService creates a new InstrumentService instance, instruments without a tick size get
+// the default one
+func NewInstrumentService(store db.Store, policy *Policy, defaultTickSize decimal.Decimal) *InstrumentService {
+	return &InstrumentService{
+		store:           store,
+		policy:          policy,
+		defaultTickSize: defaultTickSize,
+	}
+}
+
+// ListInstruments lists every instrument ordered by symbol
+func (service *InstrumentService) ListInstruments(ctx context.Context) ([]db.Instrument, error) {
+	dbInstruments, err := service.store.ListInstruments(ctx)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	if dbInstruments == nil {
+		dbInstruments = make([]db.Instrument, 0)
+	}
+	return dbInstruments, nil
+}
+
+// GetInstrument returns the instrument of the symbol, ErrInstrumentNotFound if there is none
+func (service *InstrumentService) GetInstrument(ctx context.Context, symbol string) (db.Instrument, error) {
+	dbInstrument, err := service.store.GetInstrument(ctx, normalizeSymbol(symbol))
+	return dbInstrument, orNotFound(err, ErrInstrumentNotFound)
+}
+
+// CreateInstrument adds an instrument to the security master, only staff members are allowed to
+func (service *InstrumentService) CreateInstrument(ctx context.Context, actor Actor, instrument db.Instrument) (db.Instrument, error) {
+	if err := service.policy.CanManageInstruments(actor); err != nil {
+		return db.Instrument{}, err
+	}
+	instrument, err := service.normalize(instrument)
+	if err != nil {
+		return instrument, err
+	}
+	var dbInstrument db.Instrument
+	err = service.store.ExecTx(ctx, func(q db.Querier) error {
+		var err error
+		dbInstrument, err = q.CreateInstrument(ctx, db.CreateInstrumentParams{
+			Symbol:   instrument.Symbol,
+			Name:     instrument.Name,
+			Exchange: instrument.Exchange,
+			TickSize: instrument.TickSize,
+			LotSize:  instrument.LotSize,
+			Tradable: instrument.Tradable,
+			Currency: instrument.Currency,
+		})
+		// nothing is returned when the symbol conflicts with an existing one
+		if err == sql.ErrNoRows {
+			return ErrInstrumentExists
+		}
+		if err != nil {
+			return err
+		}
+		return recordAudit(ctx, q, AuditCreate, EntityInstrument, dbInstrument.Symbol, nil, dbInstrument)
+	})
+	return dbInstrument, err
+}
+
+// UpdateInstrument replaces the reference data of an instrument, only staff members are allowed to
+func (service *InstrumentService) UpdateInstrument(ctx context.Context, actor Actor, instrument db.Instrument) (db.Instrument, error) {
+	if err := service.policy.CanManageInstruments(actor); err != nil {
+		return db.Instrument{}, err
+	}
+	instrument, err := service.normalize(instrument)
+	if err != nil {
+		return instrument, err
+	}
+	var dbInstrument db.Instrument
+	err = service.store.ExecTx(ctx, func(q db.Querier) error {
+		before, err := q.GetInstrument(ctx, instrument.Symbol)
+		if err != nil {
+			return orNotFound(err, ErrInstrumentNotFound)
+		}
+		dbInstrument, err = q.UpdateInstrument(ctx, db.UpdateInstrumentParams{
+			Symbol:   instrument.Symbol,
+			Name:     instrument.Name,
+			Exchange: instrument.Exchange,
+			TickSize: instrument.TickSize,
+			LotSize:  instrument.LotSize,
+			Tradable: instrument.Tradable,
+			Currency: instrument.Currency,
+		})
+		if err != nil {
+			return err
+		}
+		return recordAudit(ctx, q, AuditUpdate, EntityInstrument, dbInstrument.Symbol, before, dbInstrument)
+	})
+	return dbInstrument, err
+}
+
+// DeleteInstrument removes an instrument from the security master, only staff members are allowed to.
+// Trades already placed on the symbol are kept
+func (service *InstrumentService) DeleteInstrument(ctx context.Context, actor Actor, symbol string) (db.Instrument, error) {
+	if err := service.policy.CanManageInstruments(actor); err != nil {
+		return db.Instrument{}, err
+	}
+	var dbInstrument db.Instrument
+	err := service.store.ExecTx(ctx, func(q db.Querier) error {
+		var err error
+		dbInstrument, err = q.DeleteInstrument(ctx, normalizeSymbol(symbol))
+		if err != nil {
+			return err
+		}
+		return recordAudit(ctx, q, AuditDelete, EntityInstrument, dbInstrument.Symbol, dbInstrument, nil)
+	})
+	return dbInstrument, err
+}
+
+// ImportInstruments loads the instruments of a CSV file, only staff members are allowed to
+func (service *InstrumentService) ImportInstruments(ctx context.Context, actor Actor, reader io.Reader) (int, error) {
+	if err := service.policy.CanManageInstruments(actor); err != nil {
+		return 0, err
+	}
+	return service.LoadInstruments(ctx, reader)
+}
+
+// LoadInstruments creates or replaces the instruments of a CSV file in a single transaction, returning
+// how many were loaded. Nothing is loaded when any line is invalid
+func (service *InstrumentService) LoadInstruments(ctx context.Context, reader io.Reader) (int, error) {
+	instruments, err := parseInstrumentsCSV(reader)
+	if err != nil {
+		return 0, err
+	}
+	for i := range instruments {
+		instruments[i], err = service.normalize(instruments[i])
+		if err != nil {
+			return 0, fmt.Errorf("line %d: %w", i+2, err)
+		}
+	}
+	err = service.store.ExecTx(ctx, func(q db.Querier) error {
+		for _, instrument := range instruments {
+			if err := upsertInstrument(ctx, q, instrument); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(instruments), nil
+}
+
+// upsertInstrument creates or replaces the instrument, recording the change in the audit log
+func upsertInstrument(ctx context.Context, q db.Querier, instrument db.Instrument) error {
+	var before interface{}
+	dbBefore, err := q.GetInstrument(ctx, instrument.Symbol)
+	if err == nil {
+		before = dbBefore
+	} else if err != sql.ErrNoRows {
+		return err
+	}
+	dbInstrument, err := q.UpsertInstrument(ctx, db.UpsertInstrumentParams{
+		Symbol:   instrument.Symbol,
+		Name:     instrument.Name,
+		Exchange: instrument.Exchange,
+		TickSize: instrument.TickSize,
+		LotSize:  instrument.LotSize,
+		Tradable: instrument.Tradable,
+		Currency: instrument.Currency,
+	})
+	if err != nil {
+		return err
+	}
+	action := AuditUpdate
+	if before == nil {
+		action = AuditCreate
+	}
+	return recordAudit(ctx, q, action, EntityInstrument, dbInstrument.Symbol, before, dbInstrument)
+}
+
+// normalize fills the defaults of an instrument and checks its reference data
+func (service *InstrumentService) normalize(instrument db.Instrument) (db.Instrument, error) {
+	instrument.Symbol = normalizeSymbol(instrument.Symbol)
+	instrument.Name = strings.TrimSpace(instrument.Name)
+	instrument.Exchange = strings.ToUpper(strings.TrimSpace(instrument.Exchange))
+	instrument.Currency = strings.ToUpper(strings.TrimSpace(instrument.Currency))
+	if instrument.TickSize.IsZero() {
+		instrument.TickSize = service.defaultTickSize
+	}
+	if instrument.LotSize == 0 {
+		instrument.LotSize = 1
+	}
+	if instrument.Currency == "" {
+		instrument.Currency = CashAsset
+	}
+	switch {
+	case !symbolPattern.MatchString(instrument.Symbol):
+		return instrument, invalidField(ErrInvalidInstrument, "symbol",
+			fmt.Sprintf("%q must be up to 12 letters, digits, dots or dashes", instrument.Symbol))
+	case instrument.Name == "":
+		return instrument, invalidField(ErrInvalidInstrument, "name", "is required")
+	case instrument.Exchange == "":
+		return instrument, invalidField(ErrInvalidInstrument, "exchange", "is required")
+	case !instrument.TickSize.IsPositive():
+		return instrument, invalidField(ErrInvalidInstrument, "tick_size", "must be positive")
+	case instrument.LotSize < 1:
+		return instrument, invalidField(ErrInvalidInstrument, "lot_size", "must be positive")
+	case !currencyPattern.MatchString(instrument.Currency):
+		return instrument, invalidField(ErrInvalidInstrument, "currency",
+			fmt.Sprintf("%q must be an ISO 4217 code", instrument.Currency))
+	}
+	return instrument, nil
+}
+
+// normalizeSymbol writes the symbol the way the security master keeps it, in upper case without spaces
+// around it
+func normalizeSymbol(symbol string) string {
+	return strings.ToUpper(strings.TrimSpace(symbol))
+}
+
+// assertTradable returns the instrument of the symbol when orders can be placed on it
+func assertTradable(ctx context.Context, q db.Querier, symbol string) (db.Instrument, error) {
+	instrument, err := q.GetInstrument(ctx, symbol)
+	if err == sql.ErrNoRows {
+		return instrument, fmt.Errorf("%w: %s", ErrUnknownSymbol, symbol)
+	}
+	if err != nil {
+		return instrument, err
+	}
+	if !instrument.Tradable {
+		return instrument, fmt.Errorf("%w: %s", ErrSymbolNotTradable, symbol)
+	}
+	return instrument, nil
+}
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/shopspring/decimal"
+	db "github.com/valverdethiago/trading-api/db/sqlc"
+)
+
+// instrumentColumns are the columns of the instruments CSV file, the header names them in any order and
+// only symbol, name and exchange are required
+var instrumentColumns = []string{"symbol", "name", "exchange", "tick_size", "lot_size", "tradable", "currency"}
+
+// parseInstrumentsCSV reads the instruments of a CSV file with a header line, empty cells keep the defaults
+func parseInstrumentsCSV(reader io.Reader) ([]db.Instrument, error) {
+	csvReader := csv.NewReader(reader)
+	csvReader.TrimLeadingSpace = true
+	header, err := csvReader.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("%w: the file is empty", ErrInvalidInstrument)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidInstrument, err)
+	}
+	columns, err := instrumentColumnIndexes(header)
+	if err != nil {
+		return nil, err
+	}
+	instruments := make([]db.Instrument, 0)
+	// the header is the first line of the file
+	for line := 2; ; line++ {
+		record, err := csvReader.Read()
+		if err == io.EOF {
+			return instruments, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidInstrument, err)
+		}
+		instrument, err := parseInstrumentRecord(record, columns)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		instruments = append(instruments, instrument)
+	}
+}
+
+func instrumentColumnIndexes(header []string) (map[string]int, error) {
+	columns := make(map[string]int)
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if _, ok := columns[name]; ok {
+			return nil, fmt.Errorf("%w: duplicate column %q", ErrInvalidInstrument, name)
+		}
+		columns[name] = i
+	}
+	for _, name := range instrumentColumns[:3] {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("%w: missing column %q", ErrInvalidInstrument, name)
+		}
+	}
+	return columns, nil
+}
+
+func parseInstrumentRecord(record []string, columns map[string]int) (db.Instrument, error) {
+	cell := func(name string) string {
+		if i, ok := columns[name]; ok {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+	instrument := db.Instrument{
+		Symbol:   cell("symbol"),
+		Name:     cell("name"),
+		Exchange: cell("exchange"),
+		Currency: cell("currency"),
+		Tradable: true,
+	}
+	var err error
+	if value := cell("tick_size"); value != "" {
+		if instrument.TickSize, err = decimal.NewFromString(value); err != nil {
+			return instrument, fmt.Errorf("%w: invalid tick size %q", ErrInvalidInstrument, value)
+		}
+	}
+	if value := cell("lot_size"); value != "" {
+		if instrument.LotSize, err = strconv.ParseInt(value, 10, 64); err != nil {
+			return instrument, fmt.Errorf("%w: invalid lot size %q", ErrInvalidInstrument, value)
+		}
+	}
+	if value := cell("tradable"); value != "" {
+		if instrument.Tradable, err = strconv.ParseBool(value); err != nil {
+			return instrument, fmt.Errorf("%w: invalid tradable flag %q", ErrInvalidInstrument, value)
+		}
+	}
+	return instrument, nil
+}
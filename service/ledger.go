@@ -0,0 +1,325 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	db "github.com/valverdethiago/trading-api/db/sqlc"
+)
+
+const (
+	// CashAsset is the currency cash balances are kept in
+	CashAsset = "USD"
+	// cashScale is the number of decimal places of deposits, withdrawals and fees
+	cashScale = 2
+	// DefaultJournalPageSize is the page size used when the filter doesn't set one
+	DefaultJournalPageSize = 50
+	// MaxJournalPageSize is the largest page of journal entries a listing may return
+	MaxJournalPageSize = 100
+)
+
+// brokerAccountUUID owns the ledger accounts of the broker itself: bank, market and fees
+var brokerAccountUUID = uuid.Nil
+
+// ErrInvalidAmount is returned when a deposit or withdrawal amount isn't a positive amount of cents
+var ErrInvalidAmount = NewValidationError("INVALID_AMOUNT", "The amount must be positive with at most 2 decimal places",
+	FieldError{Field: "amount", Reason: "must be positive with at most 2 decimal places"})
+
+// ErrInsufficientFunds is returned when the account doesn't have the cash to pay for the operation
+var ErrInsufficientFunds = NewRejectedError("INSUFFICIENT_FUNDS", "Insufficient funds")
+
+// ErrDepositAccountNotApproved is returned when depositing into an account that isn't approved
+var ErrDepositAccountNotApproved = NewConflictError("DEPOSIT_ACCOUNT_NOT_APPROVED", "Deposits can be made only into approved accounts")
+
+// ErrInsufficientShares is returned when the account doesn't hold the shares it is selling
+var ErrInsufficientShares = NewRejectedError("INSUFFICIENT_SHARES", "Insufficient shares")
+
+// ErrUnbalancedEntry is returned when the postings of a journal entry don't sum to zero
+var ErrUnbalancedEntry = errors.New("The journal entry is not balanced")
+
+// LedgerPosting moves an amount of an asset in (positive, debit) or out (negative, credit) of a ledger account
+type LedgerPosting struct {
+	AccountUUID uuid.UUID
+	Type        db.LedgerAccountType
+	Asset       string
+	Amount      decimal.Decimal
+}
+
+// LedgerEntry is a journal entry with its postings, as recorded in the audit log
+type LedgerEntry struct {
+	Entry    db.JournalEntry            `json:"entry"`
+	Postings []db.ListLedgerPostingsRow `json:"postings"`
+}
+
+// JournalFilter pages the journal of an account
+type JournalFilter struct {
+	Page     int32
+	PageSize int32
+}
+
+// JournalPage is one page of the journal of an account, oldest entries first
+type JournalPage struct {
+	Entries     []LedgerEntry
+	Page        int32
+	PageSize    int32
+	HasNextPage bool
+}
+
+func (filter JournalFilter) page() int32 {
+	if filter.Page <= 0 {
+		return 1
+	}
+	return filter.Page
+}
+
+func (filter JournalFilter) pageSize() int32 {
+	if filter.PageSize <= 0 {
+		return DefaultJournalPageSize
+	}
+	if filter.PageSize > MaxJournalPageSize {
+		return MaxJournalPageSize
+	}
+	return filter.PageSize
+}
+
+// LedgerService service to move cash in and out of the accounts and to read their ledger
+type LedgerService struct {
+	store          db.Store
+	accountService *AccountService
+	policy         *Policy
+	feeRate        decimal.Decimal
+}
+
+// NewLedgerService creates a new LedgerService instance
+func NewLedgerService(store db.Store, accountService *AccountService, policy *Policy, feeRate decimal.Decimal) *LedgerService {
+	return &LedgerService{
+		store:          store,
+		accountService: accountService,
+		policy:         policy,
+		feeRate:        feeRate,
+	}
+}
+
+// Deposit credits the account with cash coming from its bank, only staff members are allowed to and
+// only approved accounts can be funded
+func (service *LedgerService) Deposit(ctx context.Context, actor Actor, accountUUID uuid.UUID, amount decimal.Decimal) (LedgerEntry, error) {
+	var entry LedgerEntry
+	if err := service.policy.CanDeposit(actor); err != nil {
+		return entry, err
+	}
+	if err := assertCashAmount(amount); err != nil {
+		return entry, err
+	}
+	err := service.store.ExecTx(ctx, func(q db.Querier) error {
+		dbAccount, err := assertAccountExists(ctx, q, accountUUID)
+		if err != nil {
+			return err
+		}
+		if dbAccount.Status != db.AccountStatusAPPROVED {
+			return ErrDepositAccountNotApproved
+		}
+		entry, err = postJournalEntry(ctx, q, db.CreateJournalEntryParams{
+			AccountUuid: dbAccount.AccountUuid,
+			Type:        db.JournalEntryTypeDEPOSIT,
+			Description: fmt.Sprintf("Deposit of %s %s", amount.StringFixed(cashScale), CashAsset),
+		}, []LedgerPosting{
+			{AccountUUID: dbAccount.AccountUuid, Type: db.LedgerAccountTypeCASH, Asset: CashAsset, Amount: amount},
+			{AccountUUID: brokerAccountUUID, Type: db.LedgerAccountTypeBANK, Asset: CashAsset, Amount: amount.Neg()},
+		})
+		if err != nil {
+			return err
+		}
+		return recordAudit(ctx, q, AuditCreate, EntityJournalEntry, entry.Entry.JournalEntryUuid, nil, entry)
+	})
+	return entry, err
+}
+
+// Withdraw sends cash of the account to its bank, keeping what the open buy orders may need
+func (service *LedgerService) Withdraw(ctx context.Context, accountUUID uuid.UUID, amount decimal.Decimal) (LedgerEntry, error) {
+	var entry LedgerEntry
+	if err := assertCashAmount(amount); err != nil {
+		return entry, err
+	}
+	err := service.store.ExecTx(ctx, func(q db.Querier) error {
+		dbAccount, err := assertAccountExists(ctx, q, accountUUID)
+		if err != nil {
+			return err
+		}
+		available, err := availableCash(ctx, q, dbAccount.AccountUuid, service.feeRate)
+		if err != nil {
+			return err
+		}
+		if amount.GreaterThan(available) {
+			return ErrInsufficientFunds
+		}
+		entry, err = postJournalEntry(ctx, q, db.CreateJournalEntryParams{
+			AccountUuid: dbAccount.AccountUuid,
+			Type:        db.JournalEntryTypeWITHDRAWAL,
+			Description: fmt.Sprintf("Withdrawal of %s %s", amount.StringFixed(cashScale), CashAsset),
+		}, []LedgerPosting{
+			{AccountUUID: dbAccount.AccountUuid, Type: db.LedgerAccountTypeCASH, Asset: CashAsset, Amount: amount.Neg()},
+			{AccountUUID: brokerAccountUUID, Type: db.LedgerAccountTypeBANK, Asset: CashAsset, Amount: amount},
+		})
+		if err != nil {
+			return err
+		}
+		return recordAudit(ctx, q, AuditCreate, EntityJournalEntry, entry.Entry.JournalEntryUuid, nil, entry)
+	})
+	return entry, err
+}
+
+// ListBalances sums the postings of every ledger account of the account
+func (service *LedgerService) ListBalances(ctx context.Context, accountUUID uuid.UUID) ([]db.ListLedgerBalancesRow, error) {
+	dbAccount, err := service.accountService.AssertAccountExists(ctx, accountUUID)
+	if err != nil {
+		return nil, err
+	}
+	balances, err := service.store.ListLedgerBalances(ctx, dbAccount.AccountUuid)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	if balances == nil {
+		balances = make([]db.ListLedgerBalancesRow, 0)
+	}
+	return balances, nil
+}
+
+// ListJournal lists one page of the journal entries of the account with their postings
+func (service *LedgerService) ListJournal(ctx context.Context, accountUUID uuid.UUID, filter JournalFilter) (JournalPage, error) {
+	page := JournalPage{
+		Page:     filter.page(),
+		PageSize: filter.pageSize(),
+		Entries:  make([]LedgerEntry, 0),
+	}
+	dbAccount, err := service.accountService.AssertAccountExists(ctx, accountUUID)
+	if err != nil {
+		return page, err
+	}
+	// one extra row tells whether there is a next page
+	dbEntries, err := service.store.ListJournalEntries(ctx, db.ListJournalEntriesParams{
+		AccountUuid: dbAccount.AccountUuid,
+		PageSize:    page.PageSize + 1,
+		PageOffset:  (page.Page - 1) * page.PageSize,
+	})
+	if err != nil && err != sql.ErrNoRows {
+		return page, err
+	}
+	if len(dbEntries) > int(page.PageSize) {
+		dbEntries = dbEntries[:page.PageSize]
+		page.HasNextPage = true
+	}
+	if len(dbEntries) == 0 {
+		return page, nil
+	}
+	entryUUIDs := make([]uuid.UUID, len(dbEntries))
+	for i, dbEntry := range dbEntries {
+		entryUUIDs[i] = dbEntry.JournalEntryUuid
+	}
+	dbPostings, err := service.store.ListLedgerPostings(ctx, entryUUIDs)
+	if err != nil && err != sql.ErrNoRows {
+		return page, err
+	}
+	postings := make(map[uuid.UUID][]db.ListLedgerPostingsRow)
+	for _, dbPosting := range dbPostings {
+		postings[dbPosting.JournalEntryUuid] = append(postings[dbPosting.JournalEntryUuid], dbPosting)
+	}
+	for _, dbEntry := range dbEntries {
+		page.Entries = append(page.Entries, LedgerEntry{Entry: dbEntry, Postings: postings[dbEntry.JournalEntryUuid]})
+	}
+	return page, nil
+}
+
+// postJournalEntry records the entry and its postings, which must sum to zero for every asset
+func postJournalEntry(ctx context.Context, q db.Querier, arg db.CreateJournalEntryParams, postings []LedgerPosting) (LedgerEntry, error) {
+	var entry LedgerEntry
+	if err := assertBalanced(postings); err != nil {
+		return entry, err
+	}
+	var err error
+	entry.Entry, err = q.CreateJournalEntry(ctx, arg)
+	if err != nil {
+		return entry, err
+	}
+	for _, posting := range postings {
+		ledgerAccount, err := upsertLedgerAccount(ctx, q, posting.AccountUUID, posting.Type, posting.Asset)
+		if err != nil {
+			return entry, err
+		}
+		dbPosting, err := q.CreateLedgerPosting(ctx, db.CreateLedgerPostingParams{
+			JournalEntryUuid:  entry.Entry.JournalEntryUuid,
+			LedgerAccountUuid: ledgerAccount.LedgerAccountUuid,
+			Amount:            posting.Amount,
+		})
+		if err != nil {
+			return entry, err
+		}
+		entry.Postings = append(entry.Postings, db.ListLedgerPostingsRow{
+			PostingUuid:      dbPosting.PostingUuid,
+			JournalEntryUuid: dbPosting.JournalEntryUuid,
+			AccountUuid:      ledgerAccount.AccountUuid,
+			Type:             ledgerAccount.Type,
+			Asset:            ledgerAccount.Asset,
+			Amount:           dbPosting.Amount,
+		})
+	}
+	return entry, nil
+}
+
+func assertBalanced(postings []LedgerPosting) error {
+	sums := make(map[string]decimal.Decimal)
+	for _, posting := range postings {
+		sums[posting.Asset] = sums[posting.Asset].Add(posting.Amount)
+	}
+	for asset, sum := range sums {
+		if !sum.IsZero() {
+			return fmt.Errorf("%w: %s postings sum to %s", ErrUnbalancedEntry, asset, sum)
+		}
+	}
+	return nil
+}
+
+func assertCashAmount(amount decimal.Decimal) error {
+	if !amount.IsPositive() || !amount.Equal(amount.Truncate(cashScale)) {
+		return ErrInvalidAmount
+	}
+	return nil
+}
+
+// upsertLedgerAccount returns the ledger account, creating it on first use; the row stays locked
+// until the end of the transaction, so concurrent operations on a balance run one after the other
+func upsertLedgerAccount(ctx context.Context, q db.Querier, accountUUID uuid.UUID,
+	accountType db.LedgerAccountType, asset string) (db.LedgerAccount, error) {
+	return q.UpsertLedgerAccount(ctx, db.UpsertLedgerAccountParams{
+		AccountUuid: accountUUID,
+		Type:        accountType,
+		Asset:       asset,
+	})
+}
+
+// lockedBalance locks the ledger account and sums its postings
+func lockedBalance(ctx context.Context, q db.Querier, accountUUID uuid.UUID,
+	accountType db.LedgerAccountType, asset string) (decimal.Decimal, error) {
+	ledgerAccount, err := upsertLedgerAccount(ctx, q, accountUUID, accountType, asset)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return q.GetLedgerAccountBalance(ctx, ledgerAccount.LedgerAccountUuid)
+}
+
+// availableCash is the cash balance of the account minus what its open buy orders may still spend,
+// fees included
+func availableCash(ctx context.Context, q db.Querier, accountUUID uuid.UUID, feeRate decimal.Decimal) (decimal.Decimal, error) {
+	cash, err := lockedBalance(ctx, q, accountUUID, db.LedgerAccountTypeCASH, CashAsset)
+	if err != nil {
+		return cash, err
+	}
+	committed, err := q.GetOpenBuyNotional(ctx, accountUUID)
+	if err != nil {
+		return cash, err
+	}
+	return cash.Sub(committed).Sub(tradeFee(committed, feeRate)), nil
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/shopspring/decimal"
+	db "github.com/valverdethiago/trading-api/db/sqlc"
+)
+
+// ErrInvalidTickSize is returned when the price has more precision than the tick size allows
+var ErrInvalidTickSize = NewValidationError("INVALID_TICK_SIZE", "Price doesn't match the tick size")
+
+// ErrInvalidOrder is returned when the prices of an order don't match its type or time in force
+var ErrInvalidOrder = NewValidationError("INVALID_ORDER", "Invalid order")
+
+// withOrderDefaults normalizes the symbol of the order and makes orders without type or time in force
+// limit orders good till cancelled, market orders without time in force being day orders since they
+// can't be good till cancelled
+func withOrderDefaults(trade db.Trade) db.Trade {
+	trade.Symbol = normalizeSymbol(trade.Symbol)
+	if trade.OrderType == "" {
+		trade.OrderType = db.OrderTypeLIMIT
+	}
+	if trade.TimeInForce == "" {
+		trade.TimeInForce = db.TimeInForceGTC
+		if trade.OrderType == db.OrderTypeMARKET {
+			trade.TimeInForce = db.TimeInForceDAY
+		}
+	}
+	return trade
+}
+
+// validateOrder checks that an order has a symbol and carries the prices required by its type and
+// nothing else
+func validateOrder(trade db.Trade) error {
+	if trade.Symbol == "" {
+		return invalidField(ErrInvalidOrder, "symbol", "is required")
+	}
+	requiresPrice, requiresStopPrice := false, false
+	switch trade.OrderType {
+	case db.OrderTypeMARKET:
+		if trade.TimeInForce == db.TimeInForceGTC {
+			return fmt.Errorf("%w: market orders can't be good till cancelled", ErrInvalidOrder)
+		}
+	case db.OrderTypeLIMIT:
+		requiresPrice = true
+	case db.OrderTypeSTOP:
+		requiresStopPrice = true
+	case db.OrderTypeSTOP_LIMIT:
+		requiresPrice, requiresStopPrice = true, true
+	default:
+		return fmt.Errorf("%w: unknown order type %s", ErrInvalidOrder, trade.OrderType)
+	}
+	switch trade.TimeInForce {
+	case db.TimeInForceDAY, db.TimeInForceGTC, db.TimeInForceIOC, db.TimeInForceFOK:
+	default:
+		return fmt.Errorf("%w: unknown time in force %s", ErrInvalidOrder, trade.TimeInForce)
+	}
+	if err := assertOptionalPrice("price", trade.OrderType, trade.Price, requiresPrice); err != nil {
+		return err
+	}
+	return assertOptionalPrice("stop price", trade.OrderType, trade.StopPrice, requiresStopPrice)
+}
+
+func assertOptionalPrice(name string, orderType db.OrderType, price decimal.NullDecimal, required bool) error {
+	if required && !price.Valid {
+		return fmt.Errorf("%w: %s orders require a %s", ErrInvalidOrder, orderType, name)
+	}
+	if !required && price.Valid {
+		return fmt.Errorf("%w: %s orders carry no %s", ErrInvalidOrder, orderType, name)
+	}
+	if !price.Valid {
+		return nil
+	}
+	if !price.Decimal.IsPositive() {
+		return fmt.Errorf("%w: %s must be positive", ErrInvalidOrder, name)
+	}
+	return nil
+}
+
+// validateOrderForInstrument checks the quantity of an order against the lot size of its instrument and
+// the prices against the tick size
+func validateOrderForInstrument(trade db.Trade, instrument db.Instrument) error {
+	if trade.Quantity%instrument.LotSize != 0 {
+		return fmt.Errorf("%w: %d is not a multiple of %d", ErrInvalidLotSize, trade.Quantity, instrument.LotSize)
+	}
+	for _, price := range []decimal.NullDecimal{trade.Price, trade.StopPrice} {
+		if !price.Valid {
+			continue
+		}
+		if err := assertPriceMatchesTickSize(price.Decimal, instrument.TickSize); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func assertPriceMatchesTickSize(price decimal.Decimal, tickSize decimal.Decimal) error {
+	if tickSize.IsPositive() && !price.Mod(tickSize).IsZero() {
+		return fmt.Errorf("%w: %s is not a multiple of %s", ErrInvalidTickSize, price, tickSize)
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	db "github.com/valverdethiago/trading-api/db/sqlc"
+)
+
+// OutboxService service to keep track of the publication of the events written to the outbox
+type OutboxService struct {
+	store db.Store
+}
+
+// NewOutboxService creates a new OutboxService instance
+func NewOutboxService(store db.Store) *OutboxService {
+	return &OutboxService{
+		store: store,
+	}
+}
+
+// ClaimEvents takes the next event of each aggregate whose earlier events are all published, for the
+// lease during which no other relay claims them
+func (service *OutboxService) ClaimEvents(ctx context.Context, batchSize int32, lease time.Duration) ([]db.Outbox, error) {
+	dbEvents, err := service.store.ClaimOutboxEvents(ctx, db.ClaimOutboxEventsParams{
+		LeaseSeconds: lease.Seconds(),
+		BatchSize:    batchSize,
+	})
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return dbEvents, err
+}
+
+// MarkPublished records the publication of the event, letting the next one of its aggregate through
+func (service *OutboxService) MarkPublished(ctx context.Context, event db.Outbox) error {
+	return service.store.MarkOutboxEventPublished(ctx, event.EventUuid)
+}
+
+// RetryEvent records a failed publication of the event, to be attempted again after the delay
+func (service *OutboxService) RetryEvent(ctx context.Context, event db.Outbox, reason string, delay time.Duration) error {
+	return service.store.RetryOutboxEvent(ctx, db.RetryOutboxEventParams{
+		LastError:    sql.NullString{String: reason, Valid: true},
+		DelaySeconds: delay.Seconds(),
+		EventUuid:    event.EventUuid,
+	})
+}
@@ -0,0 +1,273 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	db "github.com/valverdethiago/trading-api/db/sqlc"
+)
+
+// ErrInvalidPeriod is returned when the period ends before it starts
+var ErrInvalidPeriod = NewValidationError("INVALID_PERIOD", "The period must end after it starts")
+
+// Period bounds a report, from inclusive and to exclusive, empty bounds are open
+type Period struct {
+	From sql.NullTime
+	To   sql.NullTime
+}
+
+func (period Period) contains(date time.Time) bool {
+	return (!period.From.Valid || !date.Before(period.From.Time)) && (!period.To.Valid || date.Before(period.To.Time))
+}
+
+// Fill is an execution as seen by the P&L calculator, with the fee charged on it
+type Fill struct {
+	Symbol       string
+	Side         db.TradeSide
+	Quantity     int64
+	Price        decimal.Decimal
+	Fee          decimal.Decimal
+	ExecutedDate time.Time
+}
+
+// Lot is the open quantity of a fill, negative for short lots, and the part of the fee paid opening it
+type Lot struct {
+	Quantity   int64
+	Price      decimal.Decimal
+	Fee        decimal.Decimal
+	OpenedDate time.Time
+}
+
+// ClosedLot is the quantity of a lot closed by a later fill, negative for short lots, and the P&L it
+// realized net of the fees of both fills on that quantity
+type ClosedLot struct {
+	Quantity    int64
+	OpenPrice   decimal.Decimal
+	ClosePrice  decimal.Decimal
+	OpenedDate  time.Time
+	ClosedDate  time.Time
+	Fees        decimal.Decimal
+	RealizedPnL decimal.Decimal
+}
+
+// SymbolPnL is the P&L of one symbol: realized by the lots closed in the period, net of their fees,
+// and unrealized by the lots still open at its end, marked against the latest known price before fees.
+// The cost basis is the one of the open lots, which differs from the average cost of the position
+type SymbolPnL struct {
+	Symbol        string
+	Quantity      int64
+	CostBasis     decimal.Decimal
+	MarkPrice     decimal.NullDecimal
+	Fees          decimal.Decimal
+	RealizedPnL   decimal.Decimal
+	UnrealizedPnL decimal.Decimal
+	ClosedLots    []ClosedLot
+}
+
+// PnLReport is the P&L of an account per symbol, ordered by symbol, and its totals
+type PnLReport struct {
+	Period        Period
+	Symbols       []SymbolPnL
+	RealizedPnL   decimal.Decimal
+	UnrealizedPnL decimal.Decimal
+	TotalPnL      decimal.Decimal
+}
+
+// PnLCalculator matches the fills of an account first in first out: each fill closes the oldest lots
+// of the opposite direction and opens a lot with what is left. The fees are split between the lots
+// in proportion to their quantity and realized along with them
+type PnLCalculator struct {
+	period Period
+	lots   map[string][]Lot
+	pnl    map[string]*SymbolPnL
+}
+
+// NewPnLCalculator creates a calculator for the lots closed in the period
+func NewPnLCalculator(period Period) *PnLCalculator {
+	return &PnLCalculator{
+		period: period,
+		lots:   make(map[string][]Lot),
+		pnl:    make(map[string]*SymbolPnL),
+	}
+}
+
+// Add applies a fill, fills must be added in execution order and those after the period are ignored
+func (calculator *PnLCalculator) Add(fill Fill) {
+	if calculator.period.To.Valid && !fill.ExecutedDate.Before(calculator.period.To.Time) {
+		return
+	}
+	remaining := fill.Quantity
+	if fill.Side == db.TradeSideSELL {
+		remaining = -remaining
+	}
+	// the fee of the part of the fill not matched yet
+	remainingFee := fill.Fee
+	symbolPnL := calculator.symbol(fill.Symbol)
+	lots := calculator.lots[fill.Symbol]
+	for len(lots) > 0 && remaining != 0 && (lots[0].Quantity > 0) != (remaining > 0) {
+		closed := min(abs(lots[0].Quantity), abs(remaining))
+		openFee := feeShare(lots[0].Fee, closed, abs(lots[0].Quantity))
+		closeFee := feeShare(remainingFee, closed, abs(remaining))
+		if lots[0].Quantity < 0 {
+			closed = -closed
+		}
+		fees := openFee.Add(closeFee)
+		lot := ClosedLot{
+			Quantity:    closed,
+			OpenPrice:   lots[0].Price,
+			ClosePrice:  fill.Price,
+			OpenedDate:  lots[0].OpenedDate,
+			ClosedDate:  fill.ExecutedDate,
+			Fees:        fees,
+			RealizedPnL: fill.Price.Sub(lots[0].Price).Mul(decimal.NewFromInt(closed)).Sub(fees),
+		}
+		if calculator.period.contains(fill.ExecutedDate) {
+			symbolPnL.ClosedLots = append(symbolPnL.ClosedLots, lot)
+			symbolPnL.Fees = symbolPnL.Fees.Add(lot.Fees)
+			symbolPnL.RealizedPnL = symbolPnL.RealizedPnL.Add(lot.RealizedPnL)
+		}
+		lots[0].Quantity -= closed
+		lots[0].Fee = lots[0].Fee.Sub(openFee)
+		remaining += closed
+		remainingFee = remainingFee.Sub(closeFee)
+		if lots[0].Quantity == 0 {
+			lots = lots[1:]
+		}
+	}
+	if remaining != 0 {
+		lots = append(lots, Lot{Quantity: remaining, Price: fill.Price, Fee: remainingFee, OpenedDate: fill.ExecutedDate})
+	}
+	calculator.lots[fill.Symbol] = lots
+}
+
+// OpenSymbols lists the symbols with open lots
+func (calculator *PnLCalculator) OpenSymbols() []string {
+	symbols := make([]string, 0)
+	for symbol, lots := range calculator.lots {
+		if len(lots) > 0 {
+			symbols = append(symbols, symbol)
+		}
+	}
+	sort.Strings(symbols)
+	return symbols
+}
+
+// Report marks the open lots against the given prices and sums the P&L of every symbol; symbols without
+// a price keep their unrealized P&L at zero
+func (calculator *PnLCalculator) Report(marks map[string]decimal.Decimal) PnLReport {
+	report := PnLReport{Period: calculator.period, Symbols: make([]SymbolPnL, 0, len(calculator.pnl))}
+	for symbol, symbolPnL := range calculator.pnl {
+		mark, marked := marks[symbol]
+		if marked {
+			symbolPnL.MarkPrice = decimal.NullDecimal{Decimal: mark, Valid: true}
+		}
+		for _, lot := range calculator.lots[symbol] {
+			quantity := decimal.NewFromInt(lot.Quantity)
+			symbolPnL.Quantity += lot.Quantity
+			symbolPnL.CostBasis = symbolPnL.CostBasis.Add(lot.Price.Mul(quantity))
+			if marked {
+				symbolPnL.UnrealizedPnL = symbolPnL.UnrealizedPnL.Add(mark.Sub(lot.Price).Mul(quantity))
+			}
+		}
+		if symbolPnL.Quantity == 0 && len(symbolPnL.ClosedLots) == 0 {
+			continue
+		}
+		report.RealizedPnL = report.RealizedPnL.Add(symbolPnL.RealizedPnL)
+		report.UnrealizedPnL = report.UnrealizedPnL.Add(symbolPnL.UnrealizedPnL)
+		report.Symbols = append(report.Symbols, *symbolPnL)
+	}
+	sort.Slice(report.Symbols, func(i, j int) bool {
+		return report.Symbols[i].Symbol < report.Symbols[j].Symbol
+	})
+	report.TotalPnL = report.RealizedPnL.Add(report.UnrealizedPnL)
+	return report
+}
+
+func (calculator *PnLCalculator) symbol(symbol string) *SymbolPnL {
+	symbolPnL, ok := calculator.pnl[symbol]
+	if !ok {
+		symbolPnL = &SymbolPnL{Symbol: symbol}
+		calculator.pnl[symbol] = symbolPnL
+	}
+	return symbolPnL
+}
+
+// feeShare is the part of a fee paid on quantity falling to part of it, rounded to cents, the whole fee
+// for the whole quantity so that the shares add up to the fee
+func feeShare(fee decimal.Decimal, part int64, quantity int64) decimal.Decimal {
+	if part == quantity {
+		return fee
+	}
+	return fee.Mul(decimal.NewFromInt(part)).Div(decimal.NewFromInt(quantity)).Round(cashScale)
+}
+
+func min(a int64, b int64) int64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// PnLService service to report the P&L of the accounts
+type PnLService struct {
+	store          db.Store
+	accountService *AccountService
+}
+
+// NewPnLService creates a new PnLService instance
+func NewPnLService(store db.Store, accountService *AccountService) *PnLService {
+	return &PnLService{
+		store:          store,
+		accountService: accountService,
+	}
+}
+
+// GetAccountPnL reports the P&L of the account in the period, marking the lots open at its end against
+// the latest fill price of each symbol before then. The fills are charged the fee recorded with their
+// execution, whatever the fee rate is now
+func (service *PnLService) GetAccountPnL(ctx context.Context, accountUUID uuid.UUID, period Period) (PnLReport, error) {
+	var report PnLReport
+	if period.From.Valid && period.To.Valid && !period.From.Time.Before(period.To.Time) {
+		return report, ErrInvalidPeriod
+	}
+	dbAccount, err := service.accountService.AssertAccountExists(ctx, accountUUID)
+	if err != nil {
+		return report, err
+	}
+	dbFills, err := service.store.ListAccountFills(ctx, db.ListAccountFillsParams{
+		AccountUuid: dbAccount.AccountUuid,
+		ExecutedTo:  period.To,
+	})
+	if err != nil && err != sql.ErrNoRows {
+		return report, err
+	}
+	calculator := NewPnLCalculator(period)
+	for _, dbFill := range dbFills {
+		calculator.Add(Fill{
+			Symbol:       dbFill.Symbol,
+			Side:         dbFill.Side,
+			Quantity:     dbFill.Quantity,
+			Price:        dbFill.Price,
+			Fee:          dbFill.Fee,
+			ExecutedDate: dbFill.ExecutedDate,
+		})
+	}
+	marks := make(map[string]decimal.Decimal)
+	if symbols := calculator.OpenSymbols(); len(symbols) > 0 {
+		dbPrices, err := service.store.ListLatestPrices(ctx, db.ListLatestPricesParams{
+			Symbols:    symbols,
+			ExecutedTo: period.To,
+		})
+		if err != nil && err != sql.ErrNoRows {
+			return report, err
+		}
+		for _, dbPrice := range dbPrices {
+			marks[dbPrice.Symbol] = dbPrice.Price
+		}
+	}
+	return calculator.Report(marks), nil
+}
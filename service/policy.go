@@ -0,0 +1,77 @@
+package service
+
+import (
+	"github.com/google/uuid"
+	db "github.com/valverdethiago/trading-api/db/sqlc"
+)
+
+// ErrForbidden is returned when the actor isn't allowed to perform the operation
+var ErrForbidden = NewForbiddenError("FORBIDDEN", "Operation not allowed for the authenticated account")
+
+// Actor is the authenticated account performing an operation
+type Actor struct {
+	AccountUUID uuid.UUID
+	Role        db.AccountRole
+}
+
+// IsStaff tells if the actor is an internal staff member
+func (actor Actor) IsStaff() bool {
+	return actor.Role == db.AccountRoleSTAFF
+}
+
+// Policy holds the authorization rules of the API in a single place
+type Policy struct{}
+
+// NewPolicy creates a new authorization policy
+func NewPolicy() *Policy {
+	return &Policy{}
+}
+
+// CanAccessAccount allows staff members and the owner of the account
+func (policy *Policy) CanAccessAccount(actor Actor, accountUUID uuid.UUID) error {
+	if actor.IsStaff() || actor.AccountUUID == accountUUID {
+		return nil
+	}
+	return ErrForbidden
+}
+
+// CanSubmitTrade allows only customer users to submit trades on their own account
+func (policy *Policy) CanSubmitTrade(actor Actor, accountUUID uuid.UUID) error {
+	if actor.Role != db.AccountRoleUSER || actor.AccountUUID != accountUUID {
+		return ErrForbidden
+	}
+	return nil
+}
+
+// CanManageAccounts allows only staff members to manage accounts
+func (policy *Policy) CanManageAccounts(actor Actor) error {
+	if !actor.IsStaff() {
+		return ErrForbidden
+	}
+	return nil
+}
+
+// CanDeposit allows only staff members to credit accounts with cash, which comes from the bank
+// through the back office
+func (policy *Policy) CanDeposit(actor Actor) error {
+	if !actor.IsStaff() {
+		return ErrForbidden
+	}
+	return nil
+}
+
+// CanManageInstruments allows only staff members to change the security master
+func (policy *Policy) CanManageInstruments(actor Actor) error {
+	if !actor.IsStaff() {
+		return ErrForbidden
+	}
+	return nil
+}
+
+// CanViewAuditLog allows only staff members to read the audit log
+func (policy *Policy) CanViewAuditLog(actor Actor) error {
+	if !actor.IsStaff() {
+		return ErrForbidden
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	db "github.com/valverdethiago/trading-api/db/sqlc"
+)
+
+// averageCostScale is the number of decimal places kept on the average cost of a position
+const averageCostScale = 8
+
+// PositionService service to read what the accounts hold
+type PositionService struct {
+	store          db.Store
+	accountService *AccountService
+}
+
+// NewPositionService creates a new PositionService instance
+func NewPositionService(store db.Store, accountService *AccountService) *PositionService {
+	return &PositionService{
+		store:          store,
+		accountService: accountService,
+	}
+}
+
+// ListPositions lists the open positions of the account ordered by symbol
+func (service *PositionService) ListPositions(ctx context.Context, accountUUID uuid.UUID) ([]db.Position, error) {
+	dbAccount, err := service.accountService.AssertAccountExists(ctx, accountUUID)
+	if err != nil {
+		return nil, err
+	}
+	dbPositions, err := service.store.ListPositionsByAccount(ctx, dbAccount.AccountUuid)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	if dbPositions == nil {
+		dbPositions = make([]db.Position, 0)
+	}
+	return dbPositions, nil
+}
+
+// GetPosition returns the position of the account on the symbol, ErrPositionNotFound if it was never traded
+func (service *PositionService) GetPosition(ctx context.Context, accountUUID uuid.UUID, symbol string) (db.Position, error) {
+	dbAccount, err := service.accountService.AssertAccountExists(ctx, accountUUID)
+	if err != nil {
+		return db.Position{}, err
+	}
+	dbPosition, err := service.store.GetPosition(ctx, db.GetPositionParams{
+		AccountUuid: dbAccount.AccountUuid,
+		Symbol:      normalizeSymbol(symbol),
+	})
+	return dbPosition, orNotFound(err, ErrPositionNotFound)
+}
+
+// updatePosition applies a fill of the trade to the position of its account on the symbol
+func updatePosition(ctx context.Context, q db.Querier, dbTrade db.Trade, execution db.TradeExecution) (db.Position, error) {
+	position, err := q.GetPositionForUpdate(ctx, db.GetPositionForUpdateParams{
+		AccountUuid: dbTrade.AccountUuid,
+		Symbol:      dbTrade.Symbol,
+	})
+	if err != nil && err != sql.ErrNoRows {
+		return position, err
+	}
+	position = applyFillToPosition(position, dbTrade.Side, execution.Quantity, execution.Price)
+	return q.UpsertPosition(ctx, db.UpsertPositionParams{
+		AccountUuid: dbTrade.AccountUuid,
+		Symbol:      dbTrade.Symbol,
+		Quantity:    position.Quantity,
+		AverageCost: position.AverageCost,
+	})
+}
+
+// applyFillToPosition moves the position by a fill. Adding to the position averages its cost, reducing
+// it keeps the cost and going past zero starts over at the fill price
+func applyFillToPosition(position db.Position, side db.TradeSide, quantity int64, price decimal.Decimal) db.Position {
+	signed := quantity
+	if side == db.TradeSideSELL {
+		signed = -quantity
+	}
+	held := position.Quantity
+	position.Quantity = held + signed
+	switch {
+	case position.Quantity == 0:
+		position.AverageCost = decimal.Zero
+	case held == 0 || (held > 0) == (signed > 0):
+		cost := position.AverageCost.Mul(decimal.NewFromInt(abs(held))).Add(price.Mul(decimal.NewFromInt(quantity)))
+		position.AverageCost = cost.DivRound(decimal.NewFromInt(abs(held)+quantity), averageCostScale)
+	case (position.Quantity > 0) != (held > 0):
+		position.AverageCost = price
+	}
+	return position
+}
+
+func abs(value int64) int64 {
+	if value < 0 {
+		return -value
+	}
+	return value
+}
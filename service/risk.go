@@ -0,0 +1,234 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	db "github.com/valverdethiago/trading-api/db/sqlc"
+)
+
+// ErrRiskRejected is wrapped by the RiskRejection of an order failing the pre-trade risk checks
+var ErrRiskRejected = NewRejectedError("RISK_REJECTED", "The order failed the pre-trade risk checks")
+
+// RiskRule names a pre-trade risk check
+type RiskRule string
+
+const (
+	RiskRuleMaxOrderNotional    RiskRule = "MAX_ORDER_NOTIONAL"
+	RiskRuleMaxDailyNotional    RiskRule = "MAX_DAILY_NOTIONAL"
+	RiskRuleMaxPositionQuantity RiskRule = "MAX_POSITION_QUANTITY"
+	RiskRuleBuyingPower         RiskRule = "BUYING_POWER"
+	RiskRuleAvailableShares     RiskRule = "AVAILABLE_SHARES"
+)
+
+// RiskViolation is a rule an order failed, with the limit of the rule and the value the order reaches
+type RiskViolation struct {
+	Rule    RiskRule        `json:"rule"`
+	Message string          `json:"message"`
+	Limit   decimal.Decimal `json:"limit"`
+	Value   decimal.Decimal `json:"value"`
+}
+
+// RiskRejection is returned when an order fails the pre-trade risk checks, listing every failed rule
+type RiskRejection struct {
+	Violations []RiskViolation
+}
+
+func (rejection *RiskRejection) Error() string {
+	rules := make([]string, len(rejection.Violations))
+	for i, violation := range rejection.Violations {
+		rules[i] = string(violation.Rule)
+	}
+	return fmt.Sprintf("%s: %s", ErrRiskRejected, strings.Join(rules, ", "))
+}
+
+// Unwrap makes the rejections match ErrRiskRejected
+func (rejection *RiskRejection) Unwrap() error {
+	return ErrRiskRejected
+}
+
+// RiskLimits are the limits of the risk checks, an invalid limit meaning no limit
+type RiskLimits struct {
+	MaxOrderNotional    decimal.NullDecimal
+	MaxDailyNotional    decimal.NullDecimal
+	MaxPositionQuantity sql.NullInt64
+}
+
+// Override returns the limits with the valid ones of the account replacing them
+func (limits RiskLimits) Override(accountLimits RiskLimits) RiskLimits {
+	if accountLimits.MaxOrderNotional.Valid {
+		limits.MaxOrderNotional = accountLimits.MaxOrderNotional
+	}
+	if accountLimits.MaxDailyNotional.Valid {
+		limits.MaxDailyNotional = accountLimits.MaxDailyNotional
+	}
+	if accountLimits.MaxPositionQuantity.Valid {
+		limits.MaxPositionQuantity = accountLimits.MaxPositionQuantity
+	}
+	return limits
+}
+
+func newRiskLimits(dbRiskLimit db.RiskLimit) RiskLimits {
+	return RiskLimits{
+		MaxOrderNotional:    dbRiskLimit.MaxOrderNotional,
+		MaxDailyNotional:    dbRiskLimit.MaxDailyNotional,
+		MaxPositionQuantity: dbRiskLimit.MaxPositionQuantity,
+	}
+}
+
+// RiskOrder is an order going through the risk checks
+type RiskOrder struct {
+	Trade db.Trade
+	// Notional is the estimated value of the order, invalid when there is no price to estimate it with
+	Notional decimal.NullDecimal
+	Limits   RiskLimits
+	// DayStart is the start of the trading day in the time zone of the market
+	DayStart time.Time
+}
+
+// RiskCheck is a rule of the pre-trade risk engine, returning a violation when the order breaks it
+type RiskCheck interface {
+	Check(ctx context.Context, q db.Querier, order RiskOrder) (*RiskViolation, error)
+}
+
+// RiskCheckFunc adapts a function to a RiskCheck
+type RiskCheckFunc func(ctx context.Context, q db.Querier, order RiskOrder) (*RiskViolation, error)
+
+// Check calls the function
+func (check RiskCheckFunc) Check(ctx context.Context, q db.Querier, order RiskOrder) (*RiskViolation, error) {
+	return check(ctx, q, order)
+}
+
+// RiskEvaluation is the outcome of the risk checks of an order
+type RiskEvaluation struct {
+	Order      RiskOrder
+	Violations []RiskViolation
+}
+
+// Passed tells whether the order passed every check
+func (evaluation RiskEvaluation) Passed() bool {
+	return len(evaluation.Violations) == 0
+}
+
+// Err returns the rejection of the order, nil when it passed
+func (evaluation RiskEvaluation) Err() error {
+	if evaluation.Passed() {
+		return nil
+	}
+	return &RiskRejection{Violations: evaluation.Violations}
+}
+
+// RiskEngine runs a chain of risk checks on new orders, against global limits accounts may override
+type RiskEngine struct {
+	limits   RiskLimits
+	location *time.Location
+	checks   []RiskCheck
+}
+
+// NewRiskEngine creates a risk engine running the checks in order, the trading day starting at
+// midnight in the location of the market
+func NewRiskEngine(limits RiskLimits, location *time.Location, checks ...RiskCheck) *RiskEngine {
+	return &RiskEngine{
+		limits:   limits,
+		location: location,
+		checks:   checks,
+	}
+}
+
+// DefaultRiskChecks are the checks of the limits, of the buying power of buys and of the shares
+// available to sells
+func DefaultRiskChecks(feeRate decimal.Decimal) []RiskCheck {
+	return []RiskCheck{
+		RiskCheckFunc(checkMaxOrderNotional),
+		RiskCheckFunc(checkMaxDailyNotional),
+		RiskCheckFunc(checkMaxPositionQuantity),
+		checkBuyingPower(feeRate),
+		RiskCheckFunc(checkAvailableShares),
+	}
+}
+
+// Limits returns the limits applying to the account, its own ones replacing the global ones
+func (engine *RiskEngine) Limits(ctx context.Context, q db.Querier, accountUUID uuid.UUID) (RiskLimits, error) {
+	dbRiskLimit, err := q.GetRiskLimit(ctx, accountUUID)
+	if err == sql.ErrNoRows {
+		return engine.limits, nil
+	}
+	if err != nil {
+		return engine.limits, err
+	}
+	return engine.limits.Override(newRiskLimits(dbRiskLimit)), nil
+}
+
+// Evaluate runs every check on the order, going on after a failed one so that the evaluation lists
+// all the violations
+func (engine *RiskEngine) Evaluate(ctx context.Context, q db.Querier, trade db.Trade, now time.Time) (RiskEvaluation, error) {
+	evaluation := RiskEvaluation{Violations: make([]RiskViolation, 0)}
+	limits, err := engine.Limits(ctx, q, trade.AccountUuid)
+	if err != nil {
+		return evaluation, err
+	}
+	notional, err := estimateNotional(ctx, q, trade)
+	if err != nil {
+		return evaluation, err
+	}
+	local := now.In(engine.location)
+	evaluation.Order = RiskOrder{
+		Trade:    trade,
+		Notional: notional,
+		Limits:   limits,
+		DayStart: time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, engine.location),
+	}
+	for _, check := range engine.checks {
+		violation, err := check.Check(ctx, q, evaluation.Order)
+		if err != nil {
+			return evaluation, err
+		}
+		if violation != nil {
+			evaluation.Violations = append(evaluation.Violations, *violation)
+		}
+	}
+	return evaluation, nil
+}
+
+// estimateNotional values the order at its limit, or stop, price and market orders at the last fill
+// on the symbol, leaving it invalid when the symbol never traded
+func estimateNotional(ctx context.Context, q db.Querier, trade db.Trade) (decimal.NullDecimal, error) {
+	quantity := decimal.NewFromInt(openQuantity(trade))
+	if notional := committedNotional(trade); !notional.IsZero() {
+		return decimal.NullDecimal{Decimal: notional.Round(cashScale), Valid: true}, nil
+	}
+	prices, err := q.ListLatestPrices(ctx, db.ListLatestPricesParams{Symbols: []string{trade.Symbol}})
+	if err != nil && err != sql.ErrNoRows {
+		return decimal.NullDecimal{}, err
+	}
+	if len(prices) == 0 {
+		return decimal.NullDecimal{}, nil
+	}
+	return decimal.NullDecimal{Decimal: prices[0].Price.Mul(quantity).Round(cashScale), Valid: true}, nil
+}
+
+// logRiskEvaluation keeps the evaluation of an order for audit, with the trade it created when it passed
+func logRiskEvaluation(ctx context.Context, q db.Querier, evaluation RiskEvaluation, tradeUUID uuid.UUID) error {
+	violations, err := json.Marshal(evaluation.Violations)
+	if err != nil {
+		return err
+	}
+	trade := evaluation.Order.Trade
+	_, err = q.CreateRiskCheck(ctx, db.CreateRiskCheckParams{
+		AccountUuid: trade.AccountUuid,
+		TradeUuid:   tradeUUID,
+		Symbol:      trade.Symbol,
+		Side:        trade.Side,
+		Quantity:    trade.Quantity,
+		Notional:    evaluation.Order.Notional,
+		Passed:      evaluation.Passed(),
+		Violations:  violations,
+	})
+	return err
+}
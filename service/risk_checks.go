@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+	db "github.com/valverdethiago/trading-api/db/sqlc"
+)
+
+// checkMaxOrderNotional limits the estimated value of a single order
+func checkMaxOrderNotional(ctx context.Context, q db.Querier, order RiskOrder) (*RiskViolation, error) {
+	limit := order.Limits.MaxOrderNotional
+	if !limit.Valid || !order.Notional.Valid || !order.Notional.Decimal.GreaterThan(limit.Decimal) {
+		return nil, nil
+	}
+	return &RiskViolation{
+		Rule:    RiskRuleMaxOrderNotional,
+		Message: fmt.Sprintf("The order notional %s exceeds the limit of %s", order.Notional.Decimal.StringFixed(cashScale), limit.Decimal.StringFixed(cashScale)),
+		Limit:   limit.Decimal,
+		Value:   order.Notional.Decimal,
+	}, nil
+}
+
+// checkMaxDailyNotional limits the value traded by the account during the trading day: the fills since
+// the start of the day, the open orders and the order itself
+func checkMaxDailyNotional(ctx context.Context, q db.Querier, order RiskOrder) (*RiskViolation, error) {
+	limit := order.Limits.MaxDailyNotional
+	if !limit.Valid || !order.Notional.Valid {
+		return nil, nil
+	}
+	traded, err := q.GetTradedNotional(ctx, db.GetTradedNotionalParams{
+		AccountUuid:  order.Trade.AccountUuid,
+		ExecutedFrom: order.DayStart.UTC(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	open, err := q.GetOpenNotional(ctx, order.Trade.AccountUuid)
+	if err != nil {
+		return nil, err
+	}
+	total := traded.Add(open).Add(order.Notional.Decimal).Round(cashScale)
+	if !total.GreaterThan(limit.Decimal) {
+		return nil, nil
+	}
+	return &RiskViolation{
+		Rule:    RiskRuleMaxDailyNotional,
+		Message: fmt.Sprintf("The daily traded notional %s exceeds the limit of %s", total.StringFixed(cashScale), limit.Decimal.StringFixed(cashScale)),
+		Limit:   limit.Decimal,
+		Value:   total,
+	}, nil
+}
+
+// checkMaxPositionQuantity limits the size of the position on the symbol once the open orders on the
+// same side and the order itself are filled. Orders reducing the position always pass
+func checkMaxPositionQuantity(ctx context.Context, q db.Querier, order RiskOrder) (*RiskViolation, error) {
+	limit := order.Limits.MaxPositionQuantity
+	if !limit.Valid {
+		return nil, nil
+	}
+	trade := order.Trade
+	position, err := q.GetPosition(ctx, db.GetPositionParams{AccountUuid: trade.AccountUuid, Symbol: trade.Symbol})
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	var projected int64
+	if trade.Side == db.TradeSideBUY {
+		buying, err := q.GetOpenBuyQuantity(ctx, db.GetOpenBuyQuantityParams{AccountUuid: trade.AccountUuid, Symbol: trade.Symbol})
+		if err != nil {
+			return nil, err
+		}
+		projected = position.Quantity + buying + openQuantity(trade)
+	} else {
+		selling, err := q.GetOpenSellQuantity(ctx, db.GetOpenSellQuantityParams{AccountUuid: trade.AccountUuid, Symbol: trade.Symbol})
+		if err != nil {
+			return nil, err
+		}
+		projected = position.Quantity - selling - openQuantity(trade)
+	}
+	size := abs(projected)
+	if size <= limit.Int64 || size <= abs(position.Quantity) {
+		return nil, nil
+	}
+	return &RiskViolation{
+		Rule:    RiskRuleMaxPositionQuantity,
+		Message: fmt.Sprintf("The position on %s would reach %d, over the limit of %d", trade.Symbol, size, limit.Int64),
+		Limit:   decimal.NewFromInt(limit.Int64),
+		Value:   decimal.NewFromInt(size),
+	}, nil
+}
+
+// checkBuyingPower checks that the account can pay for a buy order, at its estimated notional plus
+// fees, on top of its other open orders. Buys that can't be valued are rejected
+func checkBuyingPower(feeRate decimal.Decimal) RiskCheck {
+	return RiskCheckFunc(func(ctx context.Context, q db.Querier, order RiskOrder) (*RiskViolation, error) {
+		if order.Trade.Side != db.TradeSideBUY {
+			return nil, nil
+		}
+		available, required, err := cashRequirement(ctx, q, order.Trade, db.Trade{}, order.Notional, feeRate)
+		if errors.Is(err, ErrNoPriceEstimate) {
+			return &RiskViolation{
+				Rule:    RiskRuleBuyingPower,
+				Message: err.Error(),
+				Limit:   available,
+				Value:   required,
+			}, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !required.GreaterThan(available) {
+			return nil, nil
+		}
+		return &RiskViolation{
+			Rule:    RiskRuleBuyingPower,
+			Message: fmt.Sprintf("%s: the order needs %s and %s is available", ErrInsufficientFunds, required.StringFixed(cashScale), available.StringFixed(cashScale)),
+			Limit:   available,
+			Value:   required,
+		}, nil
+	})
+}
+
+// checkAvailableShares checks that the account holds the shares of a sell order on top of its other
+// open sells
+func checkAvailableShares(ctx context.Context, q db.Querier, order RiskOrder) (*RiskViolation, error) {
+	if order.Trade.Side != db.TradeSideSELL {
+		return nil, nil
+	}
+	available, required, err := shareRequirement(ctx, q, order.Trade, db.Trade{})
+	if err != nil {
+		return nil, err
+	}
+	if !required.GreaterThan(available) {
+		return nil, nil
+	}
+	return &RiskViolation{
+		Rule:    RiskRuleAvailableShares,
+		Message: fmt.Sprintf("%s: the order sells %s %s and %s are available", ErrInsufficientShares, required, order.Trade.Symbol, available),
+		Limit:   available,
+		Value:   required,
+	}, nil
+}
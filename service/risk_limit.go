@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	db "github.com/valverdethiago/trading-api/db/sqlc"
+)
+
+// DefaultRiskCheckPageSize is the number of risk checks listed when the page size is omitted
+const DefaultRiskCheckPageSize = 50
+
+// AccountRiskLimits are the limits set on an account and the limits applying to it once the global
+// ones fill the gaps
+type AccountRiskLimits struct {
+	Account   RiskLimits
+	Effective RiskLimits
+}
+
+// RiskService service to configure the risk limits of the accounts and read their risk checks
+type RiskService struct {
+	store          db.Store
+	policy         *Policy
+	accountService *AccountService
+	engine         *RiskEngine
+}
+
+// NewRiskService creates a new RiskService instance
+func NewRiskService(store db.Store, policy *Policy, accountService *AccountService, engine *RiskEngine) *RiskService {
+	return &RiskService{
+		store:          store,
+		policy:         policy,
+		accountService: accountService,
+		engine:         engine,
+	}
+}
+
+// GetRiskLimits returns the risk limits of the account
+func (service *RiskService) GetRiskLimits(ctx context.Context, accountUUID uuid.UUID) (AccountRiskLimits, error) {
+	dbAccount, err := service.accountService.AssertAccountExists(ctx, accountUUID)
+	if err != nil {
+		return AccountRiskLimits{}, err
+	}
+	dbRiskLimit, err := service.store.GetRiskLimit(ctx, dbAccount.AccountUuid)
+	if err != nil && err != sql.ErrNoRows {
+		return AccountRiskLimits{}, err
+	}
+	limits := newRiskLimits(dbRiskLimit)
+	return AccountRiskLimits{
+		Account:   limits,
+		Effective: service.engine.limits.Override(limits),
+	}, nil
+}
+
+// UpdateRiskLimits replaces the risk limits of the account, the missing ones falling back to the global
+// limits. Only staff members are allowed to
+func (service *RiskService) UpdateRiskLimits(ctx context.Context, actor Actor, accountUUID uuid.UUID,
+	limits RiskLimits) (AccountRiskLimits, error) {
+	if err := service.policy.CanManageAccounts(actor); err != nil {
+		return AccountRiskLimits{}, err
+	}
+	var dbRiskLimit db.RiskLimit
+	err := service.store.ExecTx(ctx, func(q db.Querier) error {
+		dbAccount, err := assertAccountExists(ctx, q, accountUUID)
+		if err != nil {
+			return err
+		}
+		var before interface{}
+		dbBefore, err := q.GetRiskLimit(ctx, dbAccount.AccountUuid)
+		if err == nil {
+			before = dbBefore
+		} else if err != sql.ErrNoRows {
+			return err
+		}
+		dbRiskLimit, err = q.UpsertRiskLimit(ctx, db.UpsertRiskLimitParams{
+			AccountUuid:         dbAccount.AccountUuid,
+			MaxOrderNotional:    limits.MaxOrderNotional,
+			MaxDailyNotional:    limits.MaxDailyNotional,
+			MaxPositionQuantity: limits.MaxPositionQuantity,
+		})
+		if err != nil {
+			return err
+		}
+		action := AuditUpdate
+		if before == nil {
+			action = AuditCreate
+		}
+		return recordAudit(ctx, q, action, EntityRiskLimit, dbRiskLimit.AccountUuid, before, dbRiskLimit)
+	})
+	if err != nil {
+		return AccountRiskLimits{}, err
+	}
+	limits = newRiskLimits(dbRiskLimit)
+	return AccountRiskLimits{
+		Account:   limits,
+		Effective: service.engine.limits.Override(limits),
+	}, nil
+}
+
+// ListRiskChecks lists the latest risk evaluations of the orders of the account, newest first
+func (service *RiskService) ListRiskChecks(ctx context.Context, accountUUID uuid.UUID, pageSize int32) ([]db.RiskCheck, error) {
+	if pageSize == 0 {
+		pageSize = DefaultRiskCheckPageSize
+	}
+	dbAccount, err := service.accountService.AssertAccountExists(ctx, accountUUID)
+	if err != nil {
+		return nil, err
+	}
+	dbRiskChecks, err := service.store.ListRiskChecksByAccount(ctx, db.ListRiskChecksByAccountParams{
+		AccountUuid: dbAccount.AccountUuid,
+		Limit:       pageSize,
+	})
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	if dbRiskChecks == nil {
+		dbRiskChecks = make([]db.RiskCheck, 0)
+	}
+	return dbRiskChecks, nil
+}
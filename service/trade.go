@@ -4,95 +4,406 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"log"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/valverdethiago/trading-api/calendar"
 	db "github.com/valverdethiago/trading-api/db/sqlc"
+	"github.com/valverdethiago/trading-api/execution"
 )
 
+// ErrAccountNotApproved is returned when a trade is submitted for an account that isn't approved
+var ErrAccountNotApproved = NewConflictError("ACCOUNT_NOT_APPROVED", "Trades can be submitted only for approved accounts")
+
+// ErrMarketClosed is returned when an order that must execute right away is placed outside the
+// sessions it can execute in
+var ErrMarketClosed = NewRejectedError("MARKET_CLOSED", "The market is closed for this order")
+
+// ErrTradeNotInAccount is returned when the trade exists but is attached to another account
+var ErrTradeNotInAccount = NewNotFoundError("TRADE_NOT_IN_ACCOUNT", "The trade is not attached to the given account")
+
+// ErrTradeNotCancellable is returned when cancelling a trade that isn't open anymore
+var ErrTradeNotCancellable = NewConflictError("TRADE_NOT_CANCELLABLE",
+	"It's not allowed to cancel a trade that are not on submitted or partially filled status")
+
+// venueSubmitTimeout bounds the hand over of a committed trade to the venue, which outlives the request
+const venueSubmitTimeout = 10 * time.Second
+
 // TradeService service to handle business rules for trades
 type TradeService struct {
-	queries        db.Querier
+	store          db.Store
 	accountService *AccountService
+	policy         *Policy
+	feeRate        decimal.Decimal
+	venue          execution.Venue
+	calendar       *calendar.Calendar
+	riskEngine     *RiskEngine
+	broadcaster    EventBroadcaster
 }
 
-// NewTradeService creates a new TradeService instance
-func NewTradeService(queries db.Querier, accountService *AccountService) *TradeService {
+// NewTradeService creates a new TradeService instance, broadcasting the events of the trades once committed
+func NewTradeService(store db.Store, accountService *AccountService, policy *Policy, feeRate decimal.Decimal,
+	venue execution.Venue, marketCalendar *calendar.Calendar, riskEngine *RiskEngine, broadcaster EventBroadcaster) *TradeService {
 	return &TradeService{
-		queries:        queries,
+		store:          store,
 		accountService: accountService,
+		policy:         policy,
+		feeRate:        feeRate,
+		venue:          venue,
+		calendar:       marketCalendar,
+		riskEngine:     riskEngine,
+		broadcaster:    broadcaster,
 	}
 }
 
-// CreateTrade Creates a new trade for the account
-func (service *TradeService) CreateTrade(trade db.Trade, accountUUID uuid.UUID) (db.Trade, error) {
+// CreateTrade Creates a new trade for the account, only customer users are allowed to, on a tradable
+// instrument and the order must pass the pre-trade risk checks, whose evaluation is logged either way.
+// Orders that must execute right away are rejected outside the sessions they can execute in, the
+// others wait for the market
+func (service *TradeService) CreateTrade(ctx context.Context, actor Actor, trade db.Trade, accountUUID uuid.UUID) (db.Trade, error) {
 	var dbTrade db.Trade
-	dbAccount, err := service.accountService.AssertAccountExists(accountUUID)
+	var evaluation RiskEvaluation
+	if err := service.policy.CanSubmitTrade(actor, accountUUID); err != nil {
+		return dbTrade, err
+	}
+	trade = withOrderDefaults(trade)
+	if err := validateOrder(trade); err != nil {
+		return dbTrade, err
+	}
+	if execution.IsImmediate(trade) && !execution.CanExecute(trade, service.calendar.Session(time.Now())) {
+		return dbTrade, ErrMarketClosed
+	}
+	err := execTxAndBroadcast(ctx, service.store, service.broadcaster, func(q db.Querier) error {
+		dbAccount, err := assertAccountExists(ctx, q, accountUUID)
+		if err != nil {
+			return err
+		}
+		if dbAccount.Status != db.AccountStatusAPPROVED {
+			return ErrAccountNotApproved
+		}
+		instrument, err := assertTradable(ctx, q, trade.Symbol)
+		if err != nil {
+			return err
+		}
+		if err := validateOrderForInstrument(trade, instrument); err != nil {
+			return err
+		}
+		trade.AccountUuid = dbAccount.AccountUuid
+		evaluation, err = service.riskEngine.Evaluate(ctx, q, trade, time.Now())
+		if err != nil {
+			return err
+		}
+		if !evaluation.Passed() {
+			return evaluation.Err()
+		}
+		arg := db.CreateTradeParams{
+			AccountUuid: dbAccount.AccountUuid,
+			Symbol:      trade.Symbol,
+			Quantity:    trade.Quantity,
+			Side:        trade.Side,
+			Price:       trade.Price,
+			OrderType:   trade.OrderType,
+			TimeInForce: trade.TimeInForce,
+			StopPrice:   trade.StopPrice,
+			CreatedBy:   auditedBy(ctx),
+		}
+		dbTrade, err = q.CreateTrade(ctx, arg)
+		if err != nil {
+			return err
+		}
+		if err := recordAudit(ctx, q, AuditCreate, EntityTrade, dbTrade.TradeUuid, nil, dbTrade); err != nil {
+			return err
+		}
+		if err := createTradeVersion(ctx, q, dbTrade); err != nil {
+			return err
+		}
+		if err := logRiskEvaluation(ctx, q, evaluation, dbTrade.TradeUuid); err != nil {
+			return err
+		}
+		return publishEvent(ctx, q, newTradeEvent(EventTradeCreated, dbTrade))
+	})
+	if errors.Is(err, ErrRiskRejected) {
+		// the rejection is rolled back with the transaction, so it's logged on its own
+		if logErr := logRiskEvaluation(ctx, service.store, evaluation, uuid.Nil); logErr != nil {
+			log.Printf("Cannot log the risk evaluation of the order for account %s: %v", accountUUID, logErr)
+		}
+	}
 	if err != nil {
 		return dbTrade, err
 	}
-	arg := db.CreateTradeParams{
-		AccountUuid: dbAccount.AccountUuid,
-		Symbol:      trade.Symbol,
-		Quantity:    trade.Quantity,
-		Side:        trade.Side,
-		Price:       trade.Price,
+	return service.submitToVenue(ctx, dbTrade)
+}
+
+// submitToVenue hands the trade over for execution, failing it when the venue doesn't take it. The trade
+// is committed already, so a request cancelled meanwhile must neither stop the hand over nor leave it
+// SUBMITTED without a venue working it: both run detached from the request, keeping its origin
+func (service *TradeService) submitToVenue(requestCtx context.Context, dbTrade db.Trade) (db.Trade, error) {
+	ctx, cancel := context.WithTimeout(WithOrigin(context.Background(), OriginFromContext(requestCtx)), venueSubmitTimeout)
+	defer cancel()
+	err := service.venue.Submit(ctx, dbTrade)
+	if err == nil {
+		return dbTrade, nil
+	}
+	log.Printf("Venue refused trade %s: %v", dbTrade.TradeUuid, err)
+	before := dbTrade
+	err = execTxAndBroadcast(ctx, service.store, service.broadcaster, func(q db.Querier) error {
+		arg := db.UpdateTradeStatusParams{
+			TradeUuid: before.TradeUuid,
+			Status:    db.TradeStatusFAILED,
+			UpdatedBy: auditedBy(ctx),
+		}
+		dbTrade, err = q.UpdateTradeStatus(ctx, arg)
+		if err != nil {
+			return err
+		}
+		if err := recordAudit(ctx, q, AuditUpdate, EntityTrade, dbTrade.TradeUuid, before, dbTrade); err != nil {
+			return err
+		}
+		return publishTradeStatus(ctx, q, dbTrade)
+	})
+	return dbTrade, err
+}
+
+// ListTradesByAccount lists one page of the trades of a given account matching the filter
+func (service *TradeService) ListTradesByAccount(ctx context.Context, accountUUID uuid.UUID, filter TradeFilter) (TradePage, error) {
+	var page TradePage
+	dbAccount, err := service.accountService.AssertAccountExists(ctx, accountUUID)
+	if err != nil {
+		return page, err
+	}
+	pageSize := filter.pageSize()
+	// one extra row tells whether there is a next page
+	dbTrades, err := service.listTrades(ctx, dbAccount.AccountUuid, filter, pageSize+1)
+	if err != nil && err != sql.ErrNoRows {
+		return page, err
+	}
+	if len(dbTrades) > int(pageSize) {
+		dbTrades = dbTrades[:pageSize]
+		last := dbTrades[len(dbTrades)-1]
+		page.NextCursor = &TradeCursor{
+			CreatedDate: last.CreatedDate.Time,
+			TradeUUID:   last.TradeUuid,
+		}
 	}
-	return service.queries.CreateTrade(context.Background(), arg)
+	page.Trades = dbTrades
+	if page.Trades == nil {
+		page.Trades = make([]db.Trade, 0)
+	}
+	return page, nil
+}
+
+func (service *TradeService) listTrades(ctx context.Context, accountUUID uuid.UUID, filter TradeFilter, limit int32) ([]db.Trade, error) {
+	var after sql.NullTime
+	var afterTradeUUID uuid.UUID
+	if filter.Cursor != nil {
+		after = sql.NullTime{Time: filter.Cursor.CreatedDate, Valid: true}
+		afterTradeUUID = filter.Cursor.TradeUUID
+	}
+	if filter.Descending {
+		return service.store.ListTradesByAccountDesc(ctx, db.ListTradesByAccountDescParams{
+			AccountUuid:      accountUUID,
+			Status:           string(filter.Status),
+			Side:             string(filter.Side),
+			Symbol:           normalizeSymbol(filter.Symbol),
+			CreatedFrom:      filter.CreatedFrom,
+			CreatedTo:        filter.CreatedTo,
+			AfterCreatedDate: after,
+			AfterTradeUuid:   afterTradeUUID,
+			PageSize:         limit,
+		})
+	}
+	return service.store.ListTradesByAccountAsc(ctx, db.ListTradesByAccountAscParams{
+		AccountUuid:      accountUUID,
+		Status:           string(filter.Status),
+		Side:             string(filter.Side),
+		Symbol:           normalizeSymbol(filter.Symbol),
+		CreatedFrom:      filter.CreatedFrom,
+		CreatedTo:        filter.CreatedTo,
+		AfterCreatedDate: after,
+		AfterTradeUuid:   afterTradeUUID,
+		PageSize:         limit,
+	})
 }
 
-//ListTradesByAccount list all trades for a given account
-func (service *TradeService) ListTradesByAccount(accountUUID uuid.UUID) ([]db.Trade, error) {
-	var dbTrades []db.Trade
-	dbAccount, err := service.accountService.AssertAccountExists(accountUUID)
+// FindByIDAndAccountID finds a trade by its ID and account ID
+func (service *TradeService) FindByIDAndAccountID(ctx context.Context, ID uuid.UUID, accountUUID uuid.UUID) (db.Trade, error) {
+	return assertTradeExistsAndBelongToTheAccount(ctx, service.store, ID, accountUUID)
+}
+
+// ListTradeExecutions lists the fills of a trade of the given account, oldest first
+func (service *TradeService) ListTradeExecutions(ctx context.Context, ID uuid.UUID, accountUUID uuid.UUID) ([]db.TradeExecution, error) {
+	dbTrade, err := assertTradeExistsAndBelongToTheAccount(ctx, service.store, ID, accountUUID)
 	if err != nil {
-		return dbTrades, err
+		return nil, err
+	}
+	dbExecutions, err := service.store.ListTradeExecutions(ctx, dbTrade.TradeUuid)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
 	}
-	dbTrades, err = service.queries.ListTradesByAccount(context.Background(), dbAccount.AccountUuid)
-	if err != nil && err == sql.ErrNoRows {
-		return make([]db.Trade, 0), nil
+	if dbExecutions == nil {
+		dbExecutions = make([]db.TradeExecution, 0)
 	}
-	return dbTrades, err
+	return dbExecutions, nil
 }
 
-//FindByIDAndAccountID finds a trade by its ID and account ID
-func (service *TradeService) FindByIDAndAccountID(ID uuid.UUID, accountUUID uuid.UUID) (db.Trade, error) {
-	return service.assertTradeExistsAndBelongToTheAccount(ID, accountUUID)
+// AmendTradeByIDAndAccountID changes the quantity and prices of an open trade, keeping the new terms
+// as the next version of the trade
+func (service *TradeService) AmendTradeByIDAndAccountID(ctx context.Context, ID uuid.UUID, accountUUID uuid.UUID,
+	amendment TradeAmendment) (db.Trade, error) {
+	var dbTrade db.Trade
+	err := execTxAndBroadcast(ctx, service.store, service.broadcaster, func(q db.Querier) error {
+		var err error
+		if _, err = assertAccountExists(ctx, q, accountUUID); err != nil {
+			return err
+		}
+		// the lock keeps the filled quantity from changing while the amendment is checked
+		dbTrade, err = q.GetTradeByIdForUpdate(ctx, ID)
+		if err != nil {
+			return orNotFound(err, ErrTradeNotFound)
+		}
+		if dbTrade.AccountUuid != accountUUID {
+			return ErrTradeNotInAccount
+		}
+		if !execution.IsOpen(dbTrade.Status) {
+			return ErrTradeNotAmendable
+		}
+		amended := amendment.apply(dbTrade)
+		if err := validateAmendment(dbTrade, amended); err != nil {
+			return err
+		}
+		instrument, err := assertTradable(ctx, q, dbTrade.Symbol)
+		if err != nil {
+			return err
+		}
+		if err := validateOrderForInstrument(amended, instrument); err != nil {
+			return err
+		}
+		if err := assertBuyingPower(ctx, q, amended, dbTrade, service.feeRate); err != nil {
+			return err
+		}
+		status := dbTrade.Status
+		if amended.Quantity == dbTrade.FilledQuantity {
+			status = db.TradeStatusCOMPLETED
+		}
+		before := dbTrade
+		arg := db.UpdateTradeParams{
+			Symbol:    dbTrade.Symbol,
+			Quantity:  amended.Quantity,
+			Side:      dbTrade.Side,
+			Price:     amended.Price,
+			StopPrice: amended.StopPrice,
+			Status:    status,
+			UpdatedBy: auditedBy(ctx),
+			TradeUuid: dbTrade.TradeUuid,
+		}
+		dbTrade, err = q.UpdateTrade(ctx, arg)
+		if err != nil {
+			return err
+		}
+		if err := recordAudit(ctx, q, AuditUpdate, EntityTrade, dbTrade.TradeUuid, before, dbTrade); err != nil {
+			return err
+		}
+		if err := createTradeVersion(ctx, q, dbTrade); err != nil {
+			return err
+		}
+		if err := publishEvent(ctx, q, newTradeEvent(EventTradeAmended, dbTrade)); err != nil {
+			return err
+		}
+		if status == db.TradeStatusCOMPLETED {
+			return publishTradeStatus(ctx, q, dbTrade)
+		}
+		return nil
+	})
+	return dbTrade, err
 }
 
-//CancelTradeByIDAndAccountID cancels a trade with the given id
-func (service *TradeService) CancelTradeByIDAndAccountID(ID uuid.UUID, accountUUID uuid.UUID) (db.Trade, error) {
-	dbTrade, err := service.assertTradeExistsAndBelongToTheAccount(ID, accountUUID)
+// ListTradeVersions lists the terms of a trade of the given account, from the original to the current ones
+func (service *TradeService) ListTradeVersions(ctx context.Context, ID uuid.UUID, accountUUID uuid.UUID) ([]db.TradeVersion, error) {
+	dbTrade, err := assertTradeExistsAndBelongToTheAccount(ctx, service.store, ID, accountUUID)
 	if err != nil {
-		return dbTrade, err
+		return nil, err
 	}
-	if dbTrade.Status != db.TradeStatusSUBMITTED {
-		return dbTrade, errors.New("It's not allowed to cancel a trade that are not on submitted status")
+	dbVersions, err := service.store.ListTradeVersions(ctx, dbTrade.TradeUuid)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
 	}
-	arg := db.UpdateTradeStatusParams{
-		TradeUuid: dbTrade.TradeUuid,
-		Status:    db.TradeStatusCANCELLED,
+	if dbVersions == nil {
+		dbVersions = make([]db.TradeVersion, 0)
 	}
-	return service.queries.UpdateTradeStatus(context.Background(), arg)
+	return dbVersions, nil
+}
 
+// CancelTradeByIDAndAccountID cancels a trade with the given id
+func (service *TradeService) CancelTradeByIDAndAccountID(ctx context.Context, ID uuid.UUID, accountUUID uuid.UUID) (db.Trade, error) {
+	var dbTrade db.Trade
+	err := execTxAndBroadcast(ctx, service.store, service.broadcaster, func(q db.Querier) error {
+		if _, err := assertAccountExists(ctx, q, accountUUID); err != nil {
+			return err
+		}
+		// the lock keeps a fill from completing the trade between the check and the cancellation
+		before, err := q.GetTradeByIdForUpdate(ctx, ID)
+		if err != nil {
+			return orNotFound(err, ErrTradeNotFound)
+		}
+		if before.AccountUuid != accountUUID {
+			return ErrTradeNotInAccount
+		}
+		if !execution.IsOpen(before.Status) {
+			return ErrTradeNotCancellable
+		}
+		arg := db.UpdateTradeStatusParams{
+			TradeUuid: before.TradeUuid,
+			Status:    db.TradeStatusCANCELLED,
+			UpdatedBy: auditedBy(ctx),
+		}
+		dbTrade, err = q.UpdateTradeStatus(ctx, arg)
+		if err != nil {
+			return err
+		}
+		if err := recordAudit(ctx, q, AuditUpdate, EntityTrade, dbTrade.TradeUuid, before, dbTrade); err != nil {
+			return err
+		}
+		return publishTradeStatus(ctx, q, dbTrade)
+	})
+	return dbTrade, err
 }
 
 // AssertTradeExists Returns the trade with the given ID
-func (service *TradeService) AssertTradeExists(ID uuid.UUID) (db.Trade, error) {
-	return service.queries.GetTradeById(context.Background(), ID)
+func (service *TradeService) AssertTradeExists(ctx context.Context, ID uuid.UUID) (db.Trade, error) {
+	return assertTradeExists(ctx, service.store, ID)
+}
+
+// createTradeVersion keeps the current terms of the trade in its history
+func createTradeVersion(ctx context.Context, q db.Querier, dbTrade db.Trade) error {
+	_, err := q.CreateTradeVersion(ctx, db.CreateTradeVersionParams{
+		TradeUuid: dbTrade.TradeUuid,
+		Version:   dbTrade.Version,
+		Quantity:  dbTrade.Quantity,
+		Price:     dbTrade.Price,
+		StopPrice: dbTrade.StopPrice,
+	})
+	return err
+}
+
+func assertTradeExists(ctx context.Context, q db.Querier, ID uuid.UUID) (db.Trade, error) {
+	dbTrade, err := q.GetTradeById(ctx, ID)
+	return dbTrade, orNotFound(err, ErrTradeNotFound)
 }
 
-func (service *TradeService) assertTradeExistsAndBelongToTheAccount(ID uuid.UUID, accountUUID uuid.UUID) (db.Trade, error) {
+func assertTradeExistsAndBelongToTheAccount(ctx context.Context, q db.Querier, ID uuid.UUID, accountUUID uuid.UUID) (db.Trade, error) {
 	var dbTrade db.Trade
-	dbAccount, err := service.accountService.AssertAccountExists(accountUUID)
+	dbAccount, err := assertAccountExists(ctx, q, accountUUID)
 	if err != nil {
 		return dbTrade, err
 	}
-	dbTrade, err = service.AssertTradeExists(ID)
+	dbTrade, err = assertTradeExists(ctx, q, ID)
 	if err != nil {
 		return dbTrade, err
 	}
 	if dbAccount.AccountUuid != dbTrade.AccountUuid {
-		return dbTrade, errors.New("The trade is not attached to the given account")
+		return dbTrade, ErrTradeNotInAccount
 	}
 	return dbTrade, err
 }
@@ -0,0 +1,43 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/shopspring/decimal"
+	db "github.com/valverdethiago/trading-api/db/sqlc"
+)
+
+// ErrTradeNotAmendable is returned when amending a trade that isn't open anymore
+var ErrTradeNotAmendable = NewConflictError("TRADE_NOT_AMENDABLE", "Only submitted or partially filled trades can be amended")
+
+// TradeAmendment holds the new terms of a trade, nil fields are left unchanged
+type TradeAmendment struct {
+	Quantity  *int64
+	Price     *decimal.NullDecimal
+	StopPrice *decimal.NullDecimal
+}
+
+// apply returns the trade with the amended terms
+func (amendment TradeAmendment) apply(trade db.Trade) db.Trade {
+	if amendment.Quantity != nil {
+		trade.Quantity = *amendment.Quantity
+	}
+	if amendment.Price != nil {
+		trade.Price = *amendment.Price
+	}
+	if amendment.StopPrice != nil {
+		trade.StopPrice = *amendment.StopPrice
+	}
+	return trade
+}
+
+// validateAmendment checks the amended trade like a new order and keeps the executed quantity
+func validateAmendment(current db.Trade, amended db.Trade) error {
+	if amended.Quantity < 1 {
+		return invalidField(ErrInvalidOrder, "quantity", "must be positive")
+	}
+	if amended.Quantity < current.FilledQuantity {
+		return invalidField(ErrInvalidOrder, "quantity", fmt.Sprintf("can't drop below the filled %d", current.FilledQuantity))
+	}
+	return validateOrder(amended)
+}
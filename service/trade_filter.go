@@ -0,0 +1,50 @@
+package service
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	db "github.com/valverdethiago/trading-api/db/sqlc"
+)
+
+const (
+	// DefaultTradePageSize is the page size used when the filter doesn't set one
+	DefaultTradePageSize = 50
+	// MaxTradePageSize is the largest page a listing may return
+	MaxTradePageSize = 100
+)
+
+// TradeCursor is the position of the last trade of a page, ordered by created date and trade ID
+type TradeCursor struct {
+	CreatedDate time.Time `json:"created_date"`
+	TradeUUID   uuid.UUID `json:"trade_uuid"`
+}
+
+// TradeFilter narrows and pages the trades of an account, empty fields match everything
+type TradeFilter struct {
+	Status      db.TradeStatus
+	Side        db.TradeSide
+	Symbol      string
+	CreatedFrom sql.NullTime
+	CreatedTo   sql.NullTime
+	Descending  bool
+	Cursor      *TradeCursor
+	PageSize    int32
+}
+
+// TradePage is one page of trades and the cursor of the next one, nil on the last page
+type TradePage struct {
+	Trades     []db.Trade
+	NextCursor *TradeCursor
+}
+
+func (filter TradeFilter) pageSize() int32 {
+	if filter.PageSize <= 0 {
+		return DefaultTradePageSize
+	}
+	if filter.PageSize > MaxTradePageSize {
+		return MaxTradePageSize
+	}
+	return filter.PageSize
+}
@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+	db "github.com/valverdethiago/trading-api/db/sqlc"
+)
+
+// tradeFee is the fee charged on a notional, rounded to cents
+func tradeFee(notional decimal.Decimal, feeRate decimal.Decimal) decimal.Decimal {
+	return notional.Mul(feeRate).Round(cashScale)
+}
+
+// openQuantity is the quantity of the order still to be filled
+func openQuantity(trade db.Trade) int64 {
+	return trade.Quantity - trade.FilledQuantity
+}
+
+// committedNotional is what the open quantity of a buy order may still cost at its limit, or stop,
+// price; market orders have no price of their own and are zero
+func committedNotional(trade db.Trade) decimal.Decimal {
+	price := trade.Price
+	if !price.Valid {
+		price = trade.StopPrice
+	}
+	if !price.Valid {
+		return decimal.Zero
+	}
+	return price.Decimal.Mul(decimal.NewFromInt(openQuantity(trade)))
+}
+
+// ErrNoPriceEstimate is returned when a buy order has no price of its own and the symbol never traded,
+// leaving nothing to value what the order may cost
+var ErrNoPriceEstimate = NewRejectedError("NO_PRICE_ESTIMATE", "The order can't be valued: the symbol has no price yet")
+
+// assertBuyingPower checks that the account can pay for a buy order, or deliver the shares of a sell
+// order, on top of its other open orders. replaced holds the current terms of an amended order, which
+// stop counting as open, and is empty for new orders
+func assertBuyingPower(ctx context.Context, q db.Querier, order db.Trade, replaced db.Trade, feeRate decimal.Decimal) error {
+	if openQuantity(order) == 0 {
+		return nil
+	}
+	if order.Side == db.TradeSideSELL {
+		available, required, err := shareRequirement(ctx, q, order, replaced)
+		if err != nil {
+			return err
+		}
+		if required.GreaterThan(available) {
+			return ErrInsufficientShares
+		}
+		return nil
+	}
+	notional, err := estimateNotional(ctx, q, order)
+	if err != nil {
+		return err
+	}
+	available, required, err := cashRequirement(ctx, q, order, replaced, notional, feeRate)
+	if err != nil {
+		return err
+	}
+	if required.GreaterThan(available) {
+		return ErrInsufficientFunds
+	}
+	return nil
+}
+
+// cashRequirement returns the cash available to a buy order on top of the other open orders and the
+// cash the order commits at its estimated notional with the fee. Without an estimate, like for market
+// buys on symbols that never traded, the order can't be covered and ErrNoPriceEstimate is returned
+func cashRequirement(ctx context.Context, q db.Querier, order db.Trade, replaced db.Trade,
+	notional decimal.NullDecimal, feeRate decimal.Decimal) (decimal.Decimal, decimal.Decimal, error) {
+	available, err := availableCash(ctx, q, order.AccountUuid, feeRate)
+	if err != nil {
+		return available, decimal.Zero, err
+	}
+	if openQuantity(replaced) > 0 {
+		// the open buys count the replaced order at the same estimate
+		replacedNotional, err := estimateNotional(ctx, q, replaced)
+		if err != nil {
+			return available, decimal.Zero, err
+		}
+		available = available.Add(replacedNotional.Decimal).Add(tradeFee(replacedNotional.Decimal, feeRate))
+	}
+	if !notional.Valid {
+		return available, decimal.Zero, fmt.Errorf("%w: %s", ErrNoPriceEstimate, order.Symbol)
+	}
+	return available, notional.Decimal.Add(tradeFee(notional.Decimal, feeRate)), nil
+}
+
+// shareRequirement returns the shares available to a sell order on top of the other open sells and the
+// quantity the order sells
+func shareRequirement(ctx context.Context, q db.Querier, order db.Trade,
+	replaced db.Trade) (decimal.Decimal, decimal.Decimal, error) {
+	shares, err := lockedBalance(ctx, q, order.AccountUuid, db.LedgerAccountTypeSECURITIES, order.Symbol)
+	if err != nil {
+		return shares, decimal.Zero, err
+	}
+	selling, err := q.GetOpenSellQuantity(ctx, db.GetOpenSellQuantityParams{
+		AccountUuid: order.AccountUuid,
+		Symbol:      order.Symbol,
+	})
+	if err != nil {
+		return shares, decimal.Zero, err
+	}
+	available := shares.Sub(decimal.NewFromInt(selling - openQuantity(replaced)))
+	return available, decimal.NewFromInt(openQuantity(order)), nil
+}
+
+// postFill moves the cash and the shares of a fill between the account and the market and charges
+// the fee recorded with the execution, when there is one, in a separate entry
+func postFill(ctx context.Context, q db.Querier, dbTrade db.Trade, execution db.TradeExecution) error {
+	quantity := decimal.NewFromInt(execution.Quantity)
+	notional := execution.Price.Mul(quantity)
+	if dbTrade.Side == db.TradeSideBUY {
+		notional = notional.Neg()
+	} else {
+		quantity = quantity.Neg()
+	}
+	_, err := postJournalEntry(ctx, q, db.CreateJournalEntryParams{
+		AccountUuid: dbTrade.AccountUuid,
+		Type:        db.JournalEntryTypeFILL,
+		TradeUuid:   dbTrade.TradeUuid,
+		Description: fmt.Sprintf("%s %d %s at %s", dbTrade.Side, execution.Quantity, dbTrade.Symbol, execution.Price),
+	}, []LedgerPosting{
+		{AccountUUID: dbTrade.AccountUuid, Type: db.LedgerAccountTypeCASH, Asset: CashAsset, Amount: notional},
+		{AccountUUID: brokerAccountUUID, Type: db.LedgerAccountTypeMARKET, Asset: CashAsset, Amount: notional.Neg()},
+		{AccountUUID: dbTrade.AccountUuid, Type: db.LedgerAccountTypeSECURITIES, Asset: dbTrade.Symbol, Amount: quantity},
+		{AccountUUID: brokerAccountUUID, Type: db.LedgerAccountTypeMARKET, Asset: dbTrade.Symbol, Amount: quantity.Neg()},
+	})
+	if err != nil {
+		return err
+	}
+	if execution.Fee.IsZero() {
+		return nil
+	}
+	_, err = postJournalEntry(ctx, q, db.CreateJournalEntryParams{
+		AccountUuid: dbTrade.AccountUuid,
+		Type:        db.JournalEntryTypeFEE,
+		TradeUuid:   dbTrade.TradeUuid,
+		Description: fmt.Sprintf("Fee on the %s of %d %s", dbTrade.Side, execution.Quantity, dbTrade.Symbol),
+	}, []LedgerPosting{
+		{AccountUUID: dbTrade.AccountUuid, Type: db.LedgerAccountTypeCASH, Asset: CashAsset, Amount: execution.Fee.Neg()},
+		{AccountUUID: brokerAccountUUID, Type: db.LedgerAccountTypeFEES, Asset: CashAsset, Amount: execution.Fee},
+	})
+	return err
+}
@@ -0,0 +1,336 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	db "github.com/valverdethiago/trading-api/db/sqlc"
+	"github.com/valverdethiago/trading-api/webhook"
+)
+
+// Errors returned when the settings of a webhook are invalid
+var (
+	ErrInvalidEventType  = NewValidationError("INVALID_EVENT_TYPE", "Invalid event type")
+	ErrInvalidWebhookURL = NewValidationError("INVALID_WEBHOOK_URL", "Invalid webhook URL")
+)
+
+// ErrDeadLetterReplayed is returned when a dead letter is replayed again
+var ErrDeadLetterReplayed = NewConflictError("DEAD_LETTER_ALREADY_REPLAYED", "Dead letter already replayed")
+
+// DefaultDeadLetterPageSize is the number of dead letters listed when the page size is omitted
+const DefaultDeadLetterPageSize = 50
+
+// webhookSecretSize is the number of random bytes of the secrets signing the payloads
+const webhookSecretSize = 32
+
+// WebhookSubscription are the settings of a webhook of an account
+type WebhookSubscription struct {
+	URL        string
+	EventTypes []string
+	Active     bool
+}
+
+// WebhookService service to manage the webhooks of the accounts and to keep track of their deliveries
+type WebhookService struct {
+	store          db.Store
+	accountService *AccountService
+}
+
+// NewWebhookService creates a new WebhookService instance
+func NewWebhookService(store db.Store, accountService *AccountService) *WebhookService {
+	return &WebhookService{
+		store:          store,
+		accountService: accountService,
+	}
+}
+
+// CreateSubscription subscribes a webhook of the account to the given events, with a new secret
+// signing its payloads
+func (service *WebhookService) CreateSubscription(ctx context.Context, accountUUID uuid.UUID,
+	subscription WebhookSubscription) (db.WebhookSubscription, error) {
+	if err := validateSubscription(subscription); err != nil {
+		return db.WebhookSubscription{}, err
+	}
+	dbAccount, err := service.accountService.AssertAccountExists(ctx, accountUUID)
+	if err != nil {
+		return db.WebhookSubscription{}, err
+	}
+	secret, err := newWebhookSecret()
+	if err != nil {
+		return db.WebhookSubscription{}, err
+	}
+	var dbSubscription db.WebhookSubscription
+	err = service.store.ExecTx(ctx, func(q db.Querier) error {
+		var err error
+		dbSubscription, err = q.CreateWebhookSubscription(ctx, db.CreateWebhookSubscriptionParams{
+			AccountUuid: dbAccount.AccountUuid,
+			Url:         subscription.URL,
+			Secret:      secret,
+			EventTypes:  subscription.EventTypes,
+		})
+		if err != nil {
+			return err
+		}
+		return recordAudit(ctx, q, AuditCreate, EntityWebhook, dbSubscription.WebhookSubscriptionUuid, nil,
+			auditedWebhook(dbSubscription))
+	})
+	return dbSubscription, err
+}
+
+// ListSubscriptions lists the webhooks of the account, oldest first
+func (service *WebhookService) ListSubscriptions(ctx context.Context, accountUUID uuid.UUID) ([]db.WebhookSubscription, error) {
+	dbAccount, err := service.accountService.AssertAccountExists(ctx, accountUUID)
+	if err != nil {
+		return nil, err
+	}
+	dbSubscriptions, err := service.store.ListWebhookSubscriptionsByAccount(ctx, dbAccount.AccountUuid)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	if dbSubscriptions == nil {
+		dbSubscriptions = make([]db.WebhookSubscription, 0)
+	}
+	return dbSubscriptions, nil
+}
+
+// GetSubscription returns a webhook of the account
+func (service *WebhookService) GetSubscription(ctx context.Context, accountUUID uuid.UUID, ID uuid.UUID) (db.WebhookSubscription, error) {
+	return assertSubscriptionBelongsToTheAccount(ctx, service.store, ID, accountUUID)
+}
+
+// UpdateSubscription replaces the settings of a webhook of the account, an inactive webhook keeping
+// its deliveries until it's active again
+func (service *WebhookService) UpdateSubscription(ctx context.Context, accountUUID uuid.UUID, ID uuid.UUID,
+	subscription WebhookSubscription) (db.WebhookSubscription, error) {
+	if err := validateSubscription(subscription); err != nil {
+		return db.WebhookSubscription{}, err
+	}
+	var dbSubscription db.WebhookSubscription
+	err := service.store.ExecTx(ctx, func(q db.Querier) error {
+		before, err := assertSubscriptionBelongsToTheAccount(ctx, q, ID, accountUUID)
+		if err != nil {
+			return err
+		}
+		dbSubscription, err = q.UpdateWebhookSubscription(ctx, db.UpdateWebhookSubscriptionParams{
+			Url:                     subscription.URL,
+			EventTypes:              subscription.EventTypes,
+			Active:                  subscription.Active,
+			WebhookSubscriptionUuid: before.WebhookSubscriptionUuid,
+		})
+		if err != nil {
+			return err
+		}
+		return recordAudit(ctx, q, AuditUpdate, EntityWebhook, dbSubscription.WebhookSubscriptionUuid,
+			auditedWebhook(before), auditedWebhook(dbSubscription))
+	})
+	return dbSubscription, err
+}
+
+// DeleteSubscription deletes a webhook of the account with its deliveries and dead letters
+func (service *WebhookService) DeleteSubscription(ctx context.Context, accountUUID uuid.UUID, ID uuid.UUID) error {
+	return service.store.ExecTx(ctx, func(q db.Querier) error {
+		dbSubscription, err := assertSubscriptionBelongsToTheAccount(ctx, q, ID, accountUUID)
+		if err != nil {
+			return err
+		}
+		if err := q.DeleteWebhookSubscription(ctx, dbSubscription.WebhookSubscriptionUuid); err != nil {
+			return err
+		}
+		return recordAudit(ctx, q, AuditDelete, EntityWebhook, dbSubscription.WebhookSubscriptionUuid,
+			auditedWebhook(dbSubscription), nil)
+	})
+}
+
+// ListDeadLetters lists the latest deliveries of a webhook of the account that failed every attempt,
+// newest first
+func (service *WebhookService) ListDeadLetters(ctx context.Context, accountUUID uuid.UUID, ID uuid.UUID,
+	pageSize int32) ([]db.WebhookDeadLetter, error) {
+	if pageSize == 0 {
+		pageSize = DefaultDeadLetterPageSize
+	}
+	dbSubscription, err := assertSubscriptionBelongsToTheAccount(ctx, service.store, ID, accountUUID)
+	if err != nil {
+		return nil, err
+	}
+	dbDeadLetters, err := service.store.ListWebhookDeadLetters(ctx, db.ListWebhookDeadLettersParams{
+		WebhookSubscriptionUuid: dbSubscription.WebhookSubscriptionUuid,
+		Limit:                   pageSize,
+	})
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	if dbDeadLetters == nil {
+		dbDeadLetters = make([]db.WebhookDeadLetter, 0)
+	}
+	return dbDeadLetters, nil
+}
+
+// ReplayDeadLetter queues a dead letter of a webhook of the account for delivery again, as a new
+// delivery of the same event. Each dead letter is replayed once, a failed replay ending in a dead
+// letter of its own
+func (service *WebhookService) ReplayDeadLetter(ctx context.Context, accountUUID uuid.UUID, ID uuid.UUID,
+	deadLetterUUID uuid.UUID) (db.WebhookDelivery, error) {
+	var dbDelivery db.WebhookDelivery
+	err := service.store.ExecTx(ctx, func(q db.Querier) error {
+		dbSubscription, err := assertSubscriptionBelongsToTheAccount(ctx, q, ID, accountUUID)
+		if err != nil {
+			return err
+		}
+		dbDeadLetter, err := q.GetWebhookDeadLetter(ctx, deadLetterUUID)
+		if err != nil {
+			return orNotFound(err, ErrDeadLetterNotFound)
+		}
+		if dbDeadLetter.WebhookSubscriptionUuid != dbSubscription.WebhookSubscriptionUuid {
+			return ErrDeadLetterNotFound
+		}
+		// the update only matches a dead letter not replayed yet, even when replays race
+		replayed, err := q.MarkWebhookDeadLetterReplayed(ctx, dbDeadLetter.WebhookDeadLetterUuid)
+		if err == sql.ErrNoRows {
+			return ErrDeadLetterReplayed
+		}
+		if err != nil {
+			return err
+		}
+		dbDelivery, err = q.CreateWebhookDelivery(ctx, db.CreateWebhookDeliveryParams{
+			WebhookSubscriptionUuid: dbSubscription.WebhookSubscriptionUuid,
+			EventUuid:               dbDeadLetter.EventUuid,
+			EventType:               dbDeadLetter.EventType,
+			Payload:                 dbDeadLetter.Payload,
+		})
+		if err != nil {
+			return err
+		}
+		return recordAudit(ctx, q, AuditUpdate, EntityWebhookDeadLetter, replayed.WebhookDeadLetterUuid,
+			dbDeadLetter, replayed)
+	})
+	return dbDelivery, err
+}
+
+// ClaimDeliveries takes the deliveries due for the lease, during which no other dispatcher claims them
+func (service *WebhookService) ClaimDeliveries(ctx context.Context, batchSize int32,
+	lease time.Duration) ([]db.ClaimWebhookDeliveriesRow, error) {
+	dbDeliveries, err := service.store.ClaimWebhookDeliveries(ctx, db.ClaimWebhookDeliveriesParams{
+		LeaseSeconds: lease.Seconds(),
+		BatchSize:    batchSize,
+	})
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return dbDeliveries, err
+}
+
+// MarkDelivered records that the webhook accepted the delivery
+func (service *WebhookService) MarkDelivered(ctx context.Context, delivery db.ClaimWebhookDeliveriesRow, status int) error {
+	return service.store.MarkWebhookDelivered(ctx, db.MarkWebhookDeliveredParams{
+		ResponseStatus:      responseStatus(status),
+		WebhookDeliveryUuid: delivery.WebhookDeliveryUuid,
+	})
+}
+
+// RetryDelivery records a failed attempt of the delivery and schedules the next one after the delay
+func (service *WebhookService) RetryDelivery(ctx context.Context, delivery db.ClaimWebhookDeliveriesRow, status int,
+	reason string, delay time.Duration) error {
+	return service.store.RetryWebhookDelivery(ctx, db.RetryWebhookDeliveryParams{
+		ResponseStatus:      responseStatus(status),
+		LastError:           sql.NullString{String: reason, Valid: true},
+		DelaySeconds:        delay.Seconds(),
+		WebhookDeliveryUuid: delivery.WebhookDeliveryUuid,
+	})
+}
+
+// DeadLetter moves the delivery, which failed its last attempt, to the dead letters of the webhook
+func (service *WebhookService) DeadLetter(ctx context.Context, delivery db.ClaimWebhookDeliveriesRow, status int,
+	reason string) error {
+	return service.store.ExecTx(ctx, func(q db.Querier) error {
+		_, err := q.CreateWebhookDeadLetter(ctx, db.CreateWebhookDeadLetterParams{
+			WebhookSubscriptionUuid: delivery.WebhookSubscriptionUuid,
+			EventUuid:               delivery.EventUuid,
+			EventType:               delivery.EventType,
+			Payload:                 delivery.Payload,
+			Attempts:                delivery.Attempts + 1,
+			ResponseStatus:          responseStatus(status),
+			LastError:               reason,
+		})
+		if err != nil {
+			return err
+		}
+		return q.DeleteWebhookDelivery(ctx, delivery.WebhookDeliveryUuid)
+	})
+}
+
+// enqueueWebhookDeliveries queues a delivery of the event to every active webhook of its account
+// subscribed to it
+func enqueueWebhookDeliveries(ctx context.Context, q db.Querier, event Event, payload json.RawMessage) error {
+	dbSubscriptions, err := q.ListWebhookSubscriptionsForEvent(ctx, db.ListWebhookSubscriptionsForEventParams{
+		AccountUuid: event.AccountUUID,
+		EventType:   string(event.Type),
+	})
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+	for _, dbSubscription := range dbSubscriptions {
+		_, err := q.CreateWebhookDelivery(ctx, db.CreateWebhookDeliveryParams{
+			WebhookSubscriptionUuid: dbSubscription.WebhookSubscriptionUuid,
+			EventUuid:               event.EventUUID,
+			EventType:               string(event.Type),
+			Payload:                 payload,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func assertSubscriptionBelongsToTheAccount(ctx context.Context, q db.Querier, ID uuid.UUID,
+	accountUUID uuid.UUID) (db.WebhookSubscription, error) {
+	dbSubscription, err := q.GetWebhookSubscription(ctx, ID)
+	if err != nil {
+		return dbSubscription, orNotFound(err, ErrWebhookNotFound)
+	}
+	if dbSubscription.AccountUuid != accountUUID {
+		// the webhooks of the other accounts aren't disclosed
+		return db.WebhookSubscription{}, ErrWebhookNotFound
+	}
+	return dbSubscription, nil
+}
+
+// validateSubscription checks that the webhook aims at an URL the webhooks may be sent to and subscribes
+// to published events
+func validateSubscription(subscription WebhookSubscription) error {
+	if err := webhook.ValidateURL(subscription.URL); err != nil {
+		return invalidField(ErrInvalidWebhookURL, "url", "must be an http or https URL of a public host")
+	}
+	return validateEventTypes(subscription.EventTypes)
+}
+
+func validateEventTypes(eventTypes []string) error {
+	if len(eventTypes) == 0 {
+		return fmt.Errorf("%w: at least one event is required", ErrInvalidEventType)
+	}
+	for _, eventType := range eventTypes {
+		if !IsEventType(eventType) {
+			return fmt.Errorf("%w: %q", ErrInvalidEventType, eventType)
+		}
+	}
+	return nil
+}
+
+func newWebhookSecret() (string, error) {
+	secret := make([]byte, webhookSecretSize)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(secret), nil
+}
+
+// responseStatus is the status of the response of a webhook, null when it didn't answer
+func responseStatus(status int) sql.NullInt32 {
+	return sql.NullInt32{Int32: int32(status), Valid: status != 0}
+}
@@ -0,0 +1,175 @@
+package stream
+
+import (
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// MessageEvent carries an event of the account
+	MessageEvent = "event"
+	// MessageHeartbeat is sent while the account is idle, with the last sequence sent
+	MessageHeartbeat = "heartbeat"
+	// MessageReset tells a resuming listener that some events were lost, it should reload the state of
+	// the account and go on from the given sequence
+	MessageReset = "reset"
+)
+
+// DefaultHistorySize is the number of events kept per account for the listeners resuming
+const DefaultHistorySize = 100
+
+// subscriptionBufferSize is the number of messages a listener can fall behind before it's dropped
+const subscriptionBufferSize = 64
+
+// Message is what the listeners of an account receive, events being numbered per account from 1
+type Message struct {
+	Type     string          `json:"type"`
+	Sequence uint64          `json:"sequence"`
+	Event    json.RawMessage `json:"event,omitempty"`
+	Date     time.Time       `json:"date"`
+}
+
+// Hub is an in-process pub/sub of the events of the accounts. It keeps the latest events of each
+// account so that listeners reconnecting can resume after the last sequence they got. Sequences
+// start over when the process does.
+type Hub struct {
+	historySize int
+	mutex       sync.Mutex
+	topics      map[uuid.UUID]*topic
+}
+
+type topic struct {
+	sequence      uint64
+	history       []Message
+	subscriptions map[*Subscription]struct{}
+}
+
+// Subscription receives the events of an account published after it was made
+type Subscription struct {
+	hub         *Hub
+	accountUUID uuid.UUID
+	sequence    uint64
+	messages    chan Message
+}
+
+// NewHub creates a hub keeping the last historySize events of each account
+func NewHub(historySize int) *Hub {
+	if historySize < 1 {
+		historySize = DefaultHistorySize
+	}
+	return &Hub{
+		historySize: historySize,
+		topics:      make(map[uuid.UUID]*topic),
+	}
+}
+
+// Publish sends the event to the listeners of the account. Listeners too far behind are dropped,
+// their messages channel closed, rather than holding the publisher up
+func (hub *Hub) Publish(accountUUID uuid.UUID, event interface{}) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("Cannot publish event of account %s: %v", accountUUID, err)
+		return
+	}
+	hub.mutex.Lock()
+	defer hub.mutex.Unlock()
+	topic := hub.topic(accountUUID)
+	topic.sequence++
+	message := Message{
+		Type:     MessageEvent,
+		Sequence: topic.sequence,
+		Event:    payload,
+		Date:     time.Now().UTC(),
+	}
+	if len(topic.history) == hub.historySize {
+		copy(topic.history, topic.history[1:])
+		topic.history = topic.history[:len(topic.history)-1]
+	}
+	topic.history = append(topic.history, message)
+	for subscription := range topic.subscriptions {
+		select {
+		case subscription.messages <- message:
+		default:
+			log.Printf("Dropping listener of account %s behind on sequence %d", accountUUID, message.Sequence)
+			hub.unsubscribe(topic, subscription)
+		}
+	}
+}
+
+// Subscribe listens to the events of the account published from now on
+func (hub *Hub) Subscribe(accountUUID uuid.UUID) *Subscription {
+	hub.mutex.Lock()
+	defer hub.mutex.Unlock()
+	return hub.subscribe(accountUUID)
+}
+
+// Resume listens to the events of the account published after the given sequence, returning those
+// already published. It returns false when some of them are no longer kept, or the sequence is
+// unknown to the hub, in which case none are returned.
+func (hub *Hub) Resume(accountUUID uuid.UUID, after uint64) (*Subscription, []Message, bool) {
+	hub.mutex.Lock()
+	defer hub.mutex.Unlock()
+	subscription := hub.subscribe(accountUUID)
+	topic := hub.topics[accountUUID]
+	if after > topic.sequence {
+		return subscription, nil, false
+	}
+	if after == topic.sequence {
+		return subscription, nil, true
+	}
+	if len(topic.history) == 0 || topic.history[0].Sequence > after+1 {
+		return subscription, nil, false
+	}
+	missed := topic.history[after+1-topic.history[0].Sequence:]
+	return subscription, append([]Message(nil), missed...), true
+}
+
+func (hub *Hub) subscribe(accountUUID uuid.UUID) *Subscription {
+	topic := hub.topic(accountUUID)
+	subscription := &Subscription{
+		hub:         hub,
+		accountUUID: accountUUID,
+		sequence:    topic.sequence,
+		messages:    make(chan Message, subscriptionBufferSize),
+	}
+	topic.subscriptions[subscription] = struct{}{}
+	return subscription
+}
+
+func (hub *Hub) unsubscribe(topic *topic, subscription *Subscription) {
+	if _, ok := topic.subscriptions[subscription]; ok {
+		delete(topic.subscriptions, subscription)
+		close(subscription.messages)
+	}
+}
+
+func (hub *Hub) topic(accountUUID uuid.UUID) *topic {
+	accountTopic, ok := hub.topics[accountUUID]
+	if !ok {
+		accountTopic = &topic{subscriptions: make(map[*Subscription]struct{})}
+		hub.topics[accountUUID] = accountTopic
+	}
+	return accountTopic
+}
+
+// Messages are the events published after the subscription, closed when the listener is dropped
+func (subscription *Subscription) Messages() <-chan Message {
+	return subscription.messages
+}
+
+// Sequence is the last sequence published to the account before the subscription
+func (subscription *Subscription) Sequence() uint64 {
+	return subscription.sequence
+}
+
+// Close stops listening
+func (subscription *Subscription) Close() {
+	hub := subscription.hub
+	hub.mutex.Lock()
+	defer hub.mutex.Unlock()
+	hub.unsubscribe(hub.topics[subscription.accountUUID], subscription)
+}
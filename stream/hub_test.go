@@ -0,0 +1,82 @@
+package stream
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPublishToSubscribers(t *testing.T) {
+	hub := NewHub(10)
+	accountUUID := uuid.New()
+	subscription := hub.Subscribe(accountUUID)
+	defer subscription.Close()
+	other := hub.Subscribe(uuid.New())
+	defer other.Close()
+
+	hub.Publish(accountUUID, map[string]string{"type": "trade.created"})
+
+	message := <-subscription.Messages()
+	require.Equal(t, MessageEvent, message.Type)
+	require.Equal(t, uint64(1), message.Sequence)
+	require.JSONEq(t, `{"type": "trade.created"}`, string(message.Event))
+	require.Empty(t, other.Messages())
+}
+
+func TestResume(t *testing.T) {
+	hub := NewHub(3)
+	accountUUID := uuid.New()
+	for i := 1; i <= 5; i++ {
+		hub.Publish(accountUUID, i)
+	}
+
+	testCases := []struct {
+		name      string
+		after     uint64
+		complete  bool
+		sequences []uint64
+	}{
+		{name: "Kept Events", after: 3, complete: true, sequences: []uint64{4, 5}},
+		{name: "Up To Date", after: 5, complete: true},
+		{name: "Lost Events", after: 1, complete: false},
+		{name: "Unknown Sequence", after: 8, complete: false},
+	}
+
+	for i := range testCases {
+		testCase := testCases[i]
+
+		t.Run(testCase.name, func(t *testing.T) {
+			subscription, missed, complete := hub.Resume(accountUUID, testCase.after)
+			defer subscription.Close()
+			require.Equal(t, testCase.complete, complete)
+			require.Equal(t, uint64(5), subscription.Sequence())
+			require.Len(t, missed, len(testCase.sequences))
+			for i, message := range missed {
+				require.Equal(t, testCase.sequences[i], message.Sequence)
+				var value int
+				require.NoError(t, json.Unmarshal(message.Event, &value))
+				require.Equal(t, int(message.Sequence), value)
+			}
+		})
+	}
+}
+
+func TestDropSlowSubscriber(t *testing.T) {
+	hub := NewHub(10)
+	accountUUID := uuid.New()
+	subscription := hub.Subscribe(accountUUID)
+
+	for i := 0; i <= subscriptionBufferSize; i++ {
+		hub.Publish(accountUUID, i)
+	}
+
+	received := 0
+	for range subscription.Messages() {
+		received++
+	}
+	require.Equal(t, subscriptionBufferSize, received)
+	// closing a dropped subscription is harmless
+	subscription.Close()
+}
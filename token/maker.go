@@ -0,0 +1,16 @@
+package token
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Maker is an interface for managing tokens
+type Maker interface {
+	// CreateToken creates a new token for a specific account, role and duration
+	CreateToken(accountUUID uuid.UUID, username string, role string, duration time.Duration) (string, error)
+
+	// VerifyToken checks if the token is valid or not
+	VerifyToken(token string) (*Payload, error)
+}
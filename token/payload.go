@@ -0,0 +1,49 @@
+package token
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Different types of error returned by the VerifyToken function
+var (
+	ErrInvalidToken = errors.New("token is invalid")
+	ErrExpiredToken = errors.New("token has expired")
+)
+
+// Payload contains the payload data of the token
+type Payload struct {
+	ID          uuid.UUID `json:"id"`
+	AccountUUID uuid.UUID `json:"account_uuid"`
+	Username    string    `json:"username"`
+	Role        string    `json:"role"`
+	IssuedAt    time.Time `json:"issued_at"`
+	ExpiredAt   time.Time `json:"expired_at"`
+}
+
+// NewPayload creates a new token payload with a specific account, role and duration
+func NewPayload(accountUUID uuid.UUID, username string, role string, duration time.Duration) (*Payload, error) {
+	tokenID, err := uuid.NewRandom()
+	if err != nil {
+		return nil, err
+	}
+	payload := &Payload{
+		ID:          tokenID,
+		AccountUUID: accountUUID,
+		Username:    username,
+		Role:        role,
+		IssuedAt:    time.Now(),
+		ExpiredAt:   time.Now().Add(duration),
+	}
+	return payload, nil
+}
+
+// Valid checks if the token payload is valid or not
+func (payload *Payload) Valid() error {
+	if time.Now().After(payload.ExpiredAt) {
+		return ErrExpiredToken
+	}
+	return nil
+}
@@ -1,11 +1,51 @@
 package util
 
-import "github.com/spf13/viper"
+import (
+	"time"
+
+	"github.com/spf13/viper"
+)
 
 type Config struct {
-	DBDriver      string `mapstructure:"DB_DRIVER"`
-	DBSource      string `mapstructure:"DB_SOURCE"`
-	ServerAddress string `mapstructure:"SERVER_ADDRESS"`
+	DBDriver                 string        `mapstructure:"DB_DRIVER"`
+	DBSource                 string        `mapstructure:"DB_SOURCE"`
+	DBTxIsolation            string        `mapstructure:"DB_TX_ISOLATION"`
+	DBTxMaxRetries           int           `mapstructure:"DB_TX_MAX_RETRIES"`
+	ServerAddress            string        `mapstructure:"SERVER_ADDRESS"`
+	TokenSymmetricKey        string        `mapstructure:"TOKEN_SYMMETRIC_KEY"`
+	AccessTokenDuration      time.Duration `mapstructure:"ACCESS_TOKEN_DURATION"`
+	RequestTimeout           time.Duration `mapstructure:"REQUEST_TIMEOUT"`
+	RouteTimeouts            string        `mapstructure:"ROUTE_TIMEOUTS"`
+	DefaultTickSize          string        `mapstructure:"DEFAULT_TICK_SIZE"`
+	TradeFeeRate             string        `mapstructure:"TRADE_FEE_RATE"`
+	InstrumentsFile          string        `mapstructure:"INSTRUMENTS_FILE"`
+	RiskMaxOrderNotional     string        `mapstructure:"RISK_MAX_ORDER_NOTIONAL"`
+	RiskMaxDailyNotional     string        `mapstructure:"RISK_MAX_DAILY_NOTIONAL"`
+	RiskMaxPositionQuantity  int64         `mapstructure:"RISK_MAX_POSITION_QUANTITY"`
+	ExecutionQueueSize       int           `mapstructure:"EXECUTION_QUEUE_SIZE"`
+	SimulatorMinLatency      time.Duration `mapstructure:"SIMULATOR_MIN_LATENCY"`
+	SimulatorMaxLatency      time.Duration `mapstructure:"SIMULATOR_MAX_LATENCY"`
+	SimulatorFillProbability float64       `mapstructure:"SIMULATOR_FILL_PROBABILITY"`
+	SimulatorVolatility      float64       `mapstructure:"SIMULATOR_VOLATILITY"`
+	SimulatorReferencePrice  string        `mapstructure:"SIMULATOR_REFERENCE_PRICE"`
+	SimulatorLiquidity       int64         `mapstructure:"SIMULATOR_LIQUIDITY"`
+	MarketCalendarFile       string        `mapstructure:"MARKET_CALENDAR_FILE"`
+	IdempotencyKeyTTL        time.Duration `mapstructure:"IDEMPOTENCY_KEY_TTL"`
+	WebhookPollInterval      time.Duration `mapstructure:"WEBHOOK_POLL_INTERVAL"`
+	WebhookBatchSize         int32         `mapstructure:"WEBHOOK_BATCH_SIZE"`
+	WebhookTimeout           time.Duration `mapstructure:"WEBHOOK_TIMEOUT"`
+	WebhookMaxAttempts       int32         `mapstructure:"WEBHOOK_MAX_ATTEMPTS"`
+	WebhookInitialBackoff    time.Duration `mapstructure:"WEBHOOK_INITIAL_BACKOFF"`
+	WebhookMaxBackoff        time.Duration `mapstructure:"WEBHOOK_MAX_BACKOFF"`
+	StreamHeartbeatInterval  time.Duration `mapstructure:"STREAM_HEARTBEAT_INTERVAL"`
+	StreamHistorySize        int           `mapstructure:"STREAM_HISTORY_SIZE"`
+	OutboxPollInterval       time.Duration `mapstructure:"OUTBOX_POLL_INTERVAL"`
+	OutboxBatchSize          int32         `mapstructure:"OUTBOX_BATCH_SIZE"`
+	OutboxTimeout            time.Duration `mapstructure:"OUTBOX_TIMEOUT"`
+	OutboxInitialBackoff     time.Duration `mapstructure:"OUTBOX_INITIAL_BACKOFF"`
+	OutboxMaxBackoff         time.Duration `mapstructure:"OUTBOX_MAX_BACKOFF"`
+	OutboxFile               string        `mapstructure:"OUTBOX_FILE"`
+	OpenAPIValidation        string        `mapstructure:"OPENAPI_VALIDATION"`
 }
 
 // LoadConfig loads configuration from env file
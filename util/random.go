@@ -5,10 +5,13 @@ import (
 	"math/rand"
 	"strings"
 	"time"
+
+	"github.com/shopspring/decimal"
 )
 
 const (
 	alphabet   = "abcdefghijklmnopqrstuvwxyz "
+	uppercase  = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
 	digits     = "1234567890"
 	characters = alphabet + digits
 	mailSuffix = "@gmail.com"
@@ -29,6 +32,11 @@ func RandomFloat(min, max float64) float64 {
 	return math.Floor(result*100) / 100
 }
 
+// RandomPrice generates a random price with two decimal places between min and max
+func RandomPrice(min, max int64) decimal.Decimal {
+	return decimal.New(RandomInt(min*100, max*100), -2)
+}
+
 // RandomString generates a random string of length n
 func RandomString(n int) string {
 	return RandomFromSource(n, alphabet)
@@ -56,6 +64,11 @@ func RandomFromSource(n int, source string) string {
 	return sb.String()
 }
 
+// RandomSymbol generates a random ticker symbol
+func RandomSymbol() string {
+	return RandomFromSource(4, uppercase)
+}
+
 // RandomUsername generates a random username
 func RandomUsername() string {
 	return RandomString(8)
@@ -0,0 +1,164 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	db "github.com/valverdethiago/trading-api/db/sqlc"
+)
+
+// maxResponseSize is the part of the responses of the webhooks read before closing them
+const maxResponseSize = 64 * 1024
+
+// Queue holds the deliveries waiting to be sent and records their outcome
+type Queue interface {
+	// ClaimDeliveries takes the deliveries due, keeping the other dispatchers off them for the lease
+	ClaimDeliveries(ctx context.Context, batchSize int32, lease time.Duration) ([]db.ClaimWebhookDeliveriesRow, error)
+	MarkDelivered(ctx context.Context, delivery db.ClaimWebhookDeliveriesRow, status int) error
+	RetryDelivery(ctx context.Context, delivery db.ClaimWebhookDeliveriesRow, status int, reason string, delay time.Duration) error
+	DeadLetter(ctx context.Context, delivery db.ClaimWebhookDeliveriesRow, status int, reason string) error
+}
+
+// Config rules the deliveries of the webhooks
+type Config struct {
+	// PollInterval is the time between two looks for due deliveries
+	PollInterval time.Duration
+	// BatchSize is the largest number of deliveries sent at once
+	BatchSize int32
+	// Timeout bounds each attempt
+	Timeout time.Duration
+	// MaxAttempts is the number of attempts before a delivery goes to the dead letters
+	MaxAttempts int32
+	// InitialBackoff is the delay before the second attempt, doubling after each failure up to MaxBackoff
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// Dispatcher sends the queued deliveries to the webhooks, signing each payload with the secret of its
+// webhook. A delivery succeeds on any 2xx response, failed ones are retried with an exponential
+// backoff and go to the dead letters after the last attempt. Deliveries are sent at least once: one
+// whose outcome couldn't be recorded is sent again once its lease expires.
+type Dispatcher struct {
+	config Config
+	queue  Queue
+	client *http.Client
+}
+
+// NewDispatcher creates a dispatcher of the deliveries of the queue, it sends nothing until started.
+// Without a client, the webhooks are sent with the one of NewClient
+func NewDispatcher(config Config, queue Queue, client *http.Client) *Dispatcher {
+	if config.PollInterval <= 0 {
+		config.PollInterval = time.Second
+	}
+	if config.MaxAttempts < 1 {
+		config.MaxAttempts = 1
+	}
+	if config.MaxBackoff < config.InitialBackoff {
+		config.MaxBackoff = config.InitialBackoff
+	}
+	if client == nil {
+		client = NewClient()
+	}
+	return &Dispatcher{
+		config: config,
+		queue:  queue,
+		client: client,
+	}
+}
+
+// Start sends the due deliveries every poll interval until the context is done
+func (dispatcher *Dispatcher) Start(ctx context.Context) {
+	ticker := time.NewTicker(dispatcher.config.PollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := dispatcher.DispatchDue(ctx); err != nil {
+				log.Println("Cannot dispatch the webhook deliveries:", err)
+			}
+		}
+	}
+}
+
+// DispatchDue sends a batch of the due deliveries, returning how many were claimed
+func (dispatcher *Dispatcher) DispatchDue(ctx context.Context) (int, error) {
+	// the lease outlasts the attempts, which run side by side
+	lease := 2 * dispatcher.config.Timeout
+	deliveries, err := dispatcher.queue.ClaimDeliveries(ctx, dispatcher.config.BatchSize, lease)
+	if err != nil {
+		return 0, err
+	}
+	var wg sync.WaitGroup
+	for _, delivery := range deliveries {
+		wg.Add(1)
+		go func(delivery db.ClaimWebhookDeliveriesRow) {
+			defer wg.Done()
+			dispatcher.dispatch(ctx, delivery)
+		}(delivery)
+	}
+	wg.Wait()
+	return len(deliveries), nil
+}
+
+// Backoff is the delay after the given number of failed attempts
+func (dispatcher *Dispatcher) Backoff(attempts int32) time.Duration {
+	delay := dispatcher.config.InitialBackoff
+	for i := int32(1); i < attempts && delay < dispatcher.config.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > dispatcher.config.MaxBackoff {
+		delay = dispatcher.config.MaxBackoff
+	}
+	return delay
+}
+
+func (dispatcher *Dispatcher) dispatch(ctx context.Context, delivery db.ClaimWebhookDeliveriesRow) {
+	status, err := dispatcher.send(ctx, delivery)
+	if err == nil {
+		err = dispatcher.queue.MarkDelivered(ctx, delivery, status)
+	} else if attempts := delivery.Attempts + 1; attempts < dispatcher.config.MaxAttempts {
+		err = dispatcher.queue.RetryDelivery(ctx, delivery, status, err.Error(), dispatcher.Backoff(attempts))
+	} else {
+		log.Printf("Webhook delivery %s failed %d times: %v", delivery.WebhookDeliveryUuid, attempts, err)
+		err = dispatcher.queue.DeadLetter(ctx, delivery, status, err.Error())
+	}
+	if err != nil {
+		log.Printf("Cannot record the outcome of webhook delivery %s: %v", delivery.WebhookDeliveryUuid, err)
+	}
+}
+
+// send posts the payload to the webhook, returning the status of the response, 0 without one
+func (dispatcher *Dispatcher) send(ctx context.Context, delivery db.ClaimWebhookDeliveriesRow) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, dispatcher.config.Timeout)
+	defer cancel()
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.Url, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	timestamp := time.Now().Unix()
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(EventHeader, delivery.EventType)
+	request.Header.Set(EventIDHeader, delivery.EventUuid.String())
+	request.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+	request.Header.Set(SignatureHeader, Sign(delivery.Secret, timestamp, delivery.Payload))
+	response, err := dispatcher.client.Do(request)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+	io.Copy(ioutil.Discard, io.LimitReader(response.Body, maxResponseSize))
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return response.StatusCode, fmt.Errorf("unexpected status %d", response.StatusCode)
+	}
+	return response.StatusCode, nil
+}
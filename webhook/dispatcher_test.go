@@ -0,0 +1,228 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	db "github.com/valverdethiago/trading-api/db/sqlc"
+)
+
+// memoryQueue is a queue of deliveries that are always due, recording their outcome
+type memoryQueue struct {
+	deliveries  []db.ClaimWebhookDeliveriesRow
+	delivered   []int
+	delays      []time.Duration
+	deadLetters []string
+	mutex       sync.Mutex
+}
+
+func (queue *memoryQueue) ClaimDeliveries(ctx context.Context, batchSize int32,
+	lease time.Duration) ([]db.ClaimWebhookDeliveriesRow, error) {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+	claimed := queue.deliveries
+	queue.deliveries = nil
+	return claimed, nil
+}
+
+func (queue *memoryQueue) MarkDelivered(ctx context.Context, delivery db.ClaimWebhookDeliveriesRow, status int) error {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+	queue.delivered = append(queue.delivered, status)
+	return nil
+}
+
+func (queue *memoryQueue) RetryDelivery(ctx context.Context, delivery db.ClaimWebhookDeliveriesRow, status int,
+	reason string, delay time.Duration) error {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+	delivery.Attempts++
+	queue.deliveries = append(queue.deliveries, delivery)
+	queue.delays = append(queue.delays, delay)
+	return nil
+}
+
+func (queue *memoryQueue) DeadLetter(ctx context.Context, delivery db.ClaimWebhookDeliveriesRow, status int,
+	reason string) error {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+	queue.deadLetters = append(queue.deadLetters, reason)
+	return nil
+}
+
+func newTestDelivery(url string) db.ClaimWebhookDeliveriesRow {
+	payload, _ := json.Marshal(map[string]string{"type": "trade.created"})
+	return db.ClaimWebhookDeliveriesRow{
+		WebhookDeliveryUuid:     uuid.New(),
+		WebhookSubscriptionUuid: uuid.New(),
+		EventUuid:               uuid.New(),
+		EventType:               "trade.created",
+		Payload:                 payload,
+		Url:                     url,
+		Secret:                  "secret",
+	}
+}
+
+// newTestDispatcher creates a dispatcher with a plain client, the test servers listening on the
+// loopback the client of NewClient refuses to dial
+func newTestDispatcher(queue Queue) *Dispatcher {
+	return NewDispatcher(Config{
+		PollInterval:   10 * time.Millisecond,
+		BatchSize:      10,
+		Timeout:        time.Second,
+		MaxAttempts:    3,
+		InitialBackoff: time.Second,
+		MaxBackoff:     time.Minute,
+	}, queue, &http.Client{})
+}
+
+func TestDispatchSignsPayload(t *testing.T) {
+	delivery := newTestDelivery("")
+	received := make(chan *http.Request, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		timestamp, err := strconv.ParseInt(r.Header.Get(TimestampHeader), 10, 64)
+		require.NoError(t, err)
+		require.True(t, Verify(delivery.Secret, timestamp, body, r.Header.Get(SignatureHeader)))
+		require.JSONEq(t, string(delivery.Payload), string(body))
+		received <- r
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	delivery.Url = server.URL
+
+	queue := &memoryQueue{deliveries: []db.ClaimWebhookDeliveriesRow{delivery}}
+	dispatched, err := newTestDispatcher(queue).DispatchDue(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, dispatched)
+
+	request := <-received
+	require.Equal(t, "application/json", request.Header.Get("Content-Type"))
+	require.Equal(t, delivery.EventType, request.Header.Get(EventHeader))
+	require.Equal(t, delivery.EventUuid.String(), request.Header.Get(EventIDHeader))
+	require.Equal(t, []int{http.StatusNoContent}, queue.delivered)
+	require.Empty(t, queue.deadLetters)
+}
+
+func TestDispatchRetriesThenDeadLetters(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	queue := &memoryQueue{deliveries: []db.ClaimWebhookDeliveriesRow{newTestDelivery(server.URL)}}
+	dispatcher := newTestDispatcher(queue)
+	for i := 0; i < 3; i++ {
+		dispatched, err := dispatcher.DispatchDue(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, 1, dispatched)
+	}
+
+	require.Equal(t, 3, attempts)
+	require.Equal(t, []time.Duration{time.Second, 2 * time.Second}, queue.delays)
+	require.Equal(t, []string{"unexpected status 500"}, queue.deadLetters)
+	require.Empty(t, queue.delivered)
+	require.Empty(t, queue.deliveries)
+}
+
+func TestDispatchUnreachableWebhook(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	url := server.URL
+	server.Close()
+
+	queue := &memoryQueue{deliveries: []db.ClaimWebhookDeliveriesRow{newTestDelivery(url)}}
+	_, err := newTestDispatcher(queue).DispatchDue(context.Background())
+	require.NoError(t, err)
+	require.Len(t, queue.deliveries, 1)
+	require.Equal(t, []time.Duration{time.Second}, queue.delays)
+}
+
+func TestDispatchRefusesLoopback(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("the webhook on the loopback was dialled")
+	}))
+	defer server.Close()
+
+	queue := &memoryQueue{deliveries: []db.ClaimWebhookDeliveriesRow{newTestDelivery(server.URL)}}
+	dispatcher := NewDispatcher(Config{Timeout: time.Second, MaxAttempts: 3, InitialBackoff: time.Second}, queue, nil)
+	_, err := dispatcher.DispatchDue(context.Background())
+	require.NoError(t, err)
+	require.Len(t, queue.deliveries, 1)
+	require.Empty(t, queue.delivered)
+}
+
+func TestNewClientFollowsNoRedirect(t *testing.T) {
+	client := NewClient()
+	require.Equal(t, http.ErrUseLastResponse, client.CheckRedirect(nil, nil))
+}
+
+func TestValidateURL(t *testing.T) {
+	testCases := []struct {
+		url     string
+		allowed bool
+	}{
+		{url: "https://example.com/hooks", allowed: true},
+		{url: "http://203.0.113.10:8080/hooks", allowed: true},
+		{url: "ftp://example.com/hooks"},
+		{url: "file:///etc/passwd"},
+		{url: "gopher://example.com"},
+		{url: "/hooks"},
+		{url: "http://localhost/hooks"},
+		{url: "http://api.localhost/hooks"},
+		{url: "http://127.0.0.1/hooks"},
+		{url: "http://[::1]/hooks"},
+		{url: "http://0.0.0.0/hooks"},
+		{url: "http://169.254.169.254/latest/meta-data"},
+		{url: "http://10.1.2.3/hooks"},
+		{url: "http://172.20.0.1/hooks"},
+		{url: "http://192.168.1.1/hooks"},
+		{url: "http://[fd00::1]/hooks"},
+		{url: "http://[::ffff:127.0.0.1]/hooks"},
+	}
+
+	for i := range testCases {
+		testCase := testCases[i]
+
+		t.Run(testCase.url, func(t *testing.T) {
+			err := ValidateURL(testCase.url)
+			if testCase.allowed {
+				require.NoError(t, err)
+			} else {
+				require.True(t, errors.Is(err, ErrForbiddenTarget))
+			}
+		})
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	dispatcher := newTestDispatcher(&memoryQueue{})
+	require.Equal(t, time.Second, dispatcher.Backoff(1))
+	require.Equal(t, 2*time.Second, dispatcher.Backoff(2))
+	require.Equal(t, 32*time.Second, dispatcher.Backoff(6))
+	require.Equal(t, time.Minute, dispatcher.Backoff(7))
+	require.Equal(t, time.Minute, dispatcher.Backoff(100))
+}
+
+func TestSign(t *testing.T) {
+	payload := []byte(`{"type":"trade.created"}`)
+	signature := Sign("secret", 1600000000, payload)
+
+	require.True(t, Verify("secret", 1600000000, payload, signature))
+	require.False(t, Verify("other", 1600000000, payload, signature))
+	require.False(t, Verify("secret", 1600000001, payload, signature))
+	require.False(t, Verify("secret", 1600000000, []byte(`{}`), signature))
+	require.False(t, Verify("secret", 1600000000, payload, signature[len("sha256="):]))
+}
@@ -0,0 +1,40 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+)
+
+const (
+	// SignatureHeader carries the signature of the payload, as sha256=<hex HMAC>
+	SignatureHeader = "X-Webhook-Signature"
+	// TimestampHeader carries the Unix time the payload was signed at, which is part of the signature
+	TimestampHeader = "X-Webhook-Timestamp"
+	// EventHeader carries the type of the event
+	EventHeader = "X-Webhook-Event"
+	// EventIDHeader carries the id of the event, the same on every attempt so receivers can drop duplicates
+	EventIDHeader = "X-Webhook-Event-Id"
+
+	signaturePrefix = "sha256="
+)
+
+// Sign returns the signature of a payload sent at the timestamp: the HMAC-SHA256, keyed with the
+// secret of the webhook, of the timestamp and the payload joined by a dot
+func Sign(secret string, timestamp int64, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify tells whether the signature matches the payload sent at the timestamp, as receivers check it
+func Verify(secret string, timestamp int64, payload []byte, signature string) bool {
+	if !strings.HasPrefix(signature, signaturePrefix) {
+		return false
+	}
+	return hmac.Equal([]byte(Sign(secret, timestamp, payload)), []byte(signature))
+}